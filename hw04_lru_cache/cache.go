@@ -1,6 +1,9 @@
 package hw04lrucache
 
-import "fmt"
+import (
+	"fmt"
+	"sync"
+)
 
 type Key string
 
@@ -16,7 +19,9 @@ type cacheItem struct {
 	key   Key
 }
 
+// lruCache безопасен для одновременного использования из нескольких горутин.
 type lruCache struct {
+	mu       sync.Mutex // Get тоже меняет очередь, поэтому RWMutex не подходит
 	capacity int
 	queue    List
 	items    map[Key]*ListItem
@@ -32,6 +37,9 @@ func NewCache(capacity int) Cache {
 
 // Set добавляет или обновляет элемент в кэше.
 func (c *lruCache) Set(key Key, value interface{}) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if item, exists := c.items[key]; exists {
 		// Если элемент существует, обновляем его значение
 		item.Value = cacheItem{value, key}
@@ -59,6 +67,9 @@ func (c *lruCache) Set(key Key, value interface{}) bool {
 
 // Get возвращает элемент из кэша по ключу.
 func (c *lruCache) Get(key Key) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if item, exists := c.items[key]; exists {
 		c.queue.MoveToFront(item)
 
@@ -70,12 +81,18 @@ func (c *lruCache) Get(key Key) (interface{}, bool) {
 }
 
 func (c *lruCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.queue = NewList()                           // Перезапускаем очередь
 	c.items = make(map[Key]*ListItem, c.capacity) // Очищаем словарь
 }
 
 // Print печатает элементы кэша для отладки.
 func (c *lruCache) Print() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	// Печатаем элементы кэша (содержимое очереди)
 	fmt.Println("Состояние кэша:")
 	// Получаем все элементы из очереди
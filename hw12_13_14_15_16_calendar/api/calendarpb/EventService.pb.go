@@ -7,6 +7,7 @@
 package calendarpb
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
@@ -22,14 +23,132 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type RSVPStatus int32
+
+const (
+	RSVPStatus_RSVP_STATUS_UNSPECIFIED  RSVPStatus = 0
+	RSVPStatus_RSVP_STATUS_NEEDS_ACTION RSVPStatus = 1
+	RSVPStatus_RSVP_STATUS_ACCEPTED     RSVPStatus = 2
+	RSVPStatus_RSVP_STATUS_DECLINED     RSVPStatus = 3
+	RSVPStatus_RSVP_STATUS_TENTATIVE    RSVPStatus = 4
+)
+
+// Enum value maps for RSVPStatus.
+var (
+	RSVPStatus_name = map[int32]string{
+		0: "RSVP_STATUS_UNSPECIFIED",
+		1: "RSVP_STATUS_NEEDS_ACTION",
+		2: "RSVP_STATUS_ACCEPTED",
+		3: "RSVP_STATUS_DECLINED",
+		4: "RSVP_STATUS_TENTATIVE",
+	}
+	RSVPStatus_value = map[string]int32{
+		"RSVP_STATUS_UNSPECIFIED":  0,
+		"RSVP_STATUS_NEEDS_ACTION": 1,
+		"RSVP_STATUS_ACCEPTED":     2,
+		"RSVP_STATUS_DECLINED":     3,
+		"RSVP_STATUS_TENTATIVE":    4,
+	}
+)
+
+func (x RSVPStatus) Enum() *RSVPStatus {
+	p := new(RSVPStatus)
+	*p = x
+	return p
+}
+
+func (x RSVPStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RSVPStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_EventService_proto_enumTypes[0].Descriptor()
+}
+
+func (RSVPStatus) Type() protoreflect.EnumType {
+	return &file_api_EventService_proto_enumTypes[0]
+}
+
+func (x RSVPStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RSVPStatus.Descriptor instead.
+func (RSVPStatus) EnumDescriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{0}
+}
+
+type Permission int32
+
+const (
+	Permission_PERMISSION_UNSPECIFIED Permission = 0
+	Permission_PERMISSION_FREE_BUSY   Permission = 1
+	Permission_PERMISSION_READ        Permission = 2
+	Permission_PERMISSION_WRITE       Permission = 3
+)
+
+// Enum value maps for Permission.
+var (
+	Permission_name = map[int32]string{
+		0: "PERMISSION_UNSPECIFIED",
+		1: "PERMISSION_FREE_BUSY",
+		2: "PERMISSION_READ",
+		3: "PERMISSION_WRITE",
+	}
+	Permission_value = map[string]int32{
+		"PERMISSION_UNSPECIFIED": 0,
+		"PERMISSION_FREE_BUSY":   1,
+		"PERMISSION_READ":        2,
+		"PERMISSION_WRITE":       3,
+	}
+)
+
+func (x Permission) Enum() *Permission {
+	p := new(Permission)
+	*p = x
+	return p
+}
+
+func (x Permission) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Permission) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_EventService_proto_enumTypes[1].Descriptor()
+}
+
+func (Permission) Type() protoreflect.EnumType {
+	return &file_api_EventService_proto_enumTypes[1]
+}
+
+func (x Permission) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Permission.Descriptor instead.
+func (Permission) EnumDescriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{1}
+}
+
 type Event struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	StartAt       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=start_at,json=startAt,proto3" json:"start_at,omitempty"`
-	Duration      string                 `protobuf:"bytes,5,opt,name=duration,proto3" json:"duration,omitempty"`
-	NoticeBefore  int32                  `protobuf:"varint,6,opt,name=notice_before,json=noticeBefore,proto3" json:"notice_before,omitempty"`
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	UserId       string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Title        string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description  string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	StartAt      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=start_at,json=startAt,proto3" json:"start_at,omitempty"`
+	Duration     string                 `protobuf:"bytes,5,opt,name=duration,proto3" json:"duration,omitempty"`
+	NoticeBefore int32                  `protobuf:"varint,6,opt,name=notice_before,json=noticeBefore,proto3" json:"notice_before,omitempty"`
+	// Заполняются сервером, при создании и обновлении игнорируются.
+	Id        int64                  `protobuf:"varint,7,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Приглашённые кроме владельца. При создании события участники получают
+	// статус NEEDS_ACTION, при обновлении поле игнорируется.
+	Attendees []*Attendee `protobuf:"bytes,9,rep,name=attendees,proto3" json:"attendees,omitempty"`
+	// Календарь события, 0 при создании - календарь владельца по умолчанию.
+	// При обновлении поле игнорируется.
+	CalendarId int64 `protobuf:"varint,10,opt,name=calendar_id,json=calendarId,proto3" json:"calendar_id,omitempty"`
+	// Заполняется только у событий в корзине.
+	DeletedAt     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -106,26 +225,63 @@ func (x *Event) GetNoticeBefore() int32 {
 	return 0
 }
 
-type Empty struct {
+func (x *Event) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Event) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Event) GetAttendees() []*Attendee {
+	if x != nil {
+		return x.Attendees
+	}
+	return nil
+}
+
+func (x *Event) GetCalendarId() int64 {
+	if x != nil {
+		return x.CalendarId
+	}
+	return 0
+}
+
+func (x *Event) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+type Attendee struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Status        RSVPStatus             `protobuf:"varint,2,opt,name=status,proto3,enum=event.RSVPStatus" json:"status,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Empty) Reset() {
-	*x = Empty{}
+func (x *Attendee) Reset() {
+	*x = Attendee{}
 	mi := &file_api_EventService_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Empty) String() string {
+func (x *Attendee) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Empty) ProtoMessage() {}
+func (*Attendee) ProtoMessage() {}
 
-func (x *Empty) ProtoReflect() protoreflect.Message {
+func (x *Attendee) ProtoReflect() protoreflect.Message {
 	mi := &file_api_EventService_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -137,32 +293,58 @@ func (x *Empty) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Empty.ProtoReflect.Descriptor instead.
-func (*Empty) Descriptor() ([]byte, []int) {
+// Deprecated: Use Attendee.ProtoReflect.Descriptor instead.
+func (*Attendee) Descriptor() ([]byte, []int) {
 	return file_api_EventService_proto_rawDescGZIP(), []int{1}
 }
 
-type EventRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Event         *Event                 `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *Attendee) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
 }
 
-func (x *EventRequest) Reset() {
-	*x = EventRequest{}
+func (x *Attendee) GetStatus() RSVPStatus {
+	if x != nil {
+		return x.Status
+	}
+	return RSVPStatus_RSVP_STATUS_UNSPECIFIED
+}
+
+type Calendar struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	OwnerId   string                 `protobuf:"bytes,2,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	Name      string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Color     string                 `protobuf:"bytes,4,opt,name=color,proto3" json:"color,omitempty"`                       // #rrggbb
+	TimeZone  string                 `protobuf:"bytes,5,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"` // IANA, по умолчанию UTC
+	IsDefault bool                   `protobuf:"varint,6,opt,name=is_default,json=isDefault,proto3" json:"is_default,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Владелец видит все права, остальные - только свои.
+	Shares []*CalendarShare `protobuf:"bytes,8,rep,name=shares,proto3" json:"shares,omitempty"`
+	// Код страны праздничного календаря (RU, US), пусто - только выходные.
+	HolidayCountry string `protobuf:"bytes,9,opt,name=holiday_country,json=holidayCountry,proto3" json:"holiday_country,omitempty"`
+	// Не напоминать в нерабочие дни о событиях после ближайшего рабочего дня.
+	DeferReminders bool `protobuf:"varint,10,opt,name=defer_reminders,json=deferReminders,proto3" json:"defer_reminders,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Calendar) Reset() {
+	*x = Calendar{}
 	mi := &file_api_EventService_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *EventRequest) String() string {
+func (x *Calendar) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*EventRequest) ProtoMessage() {}
+func (*Calendar) ProtoMessage() {}
 
-func (x *EventRequest) ProtoReflect() protoreflect.Message {
+func (x *Calendar) ProtoReflect() protoreflect.Message {
 	mi := &file_api_EventService_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -174,40 +356,103 @@ func (x *EventRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use EventRequest.ProtoReflect.Descriptor instead.
-func (*EventRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use Calendar.ProtoReflect.Descriptor instead.
+func (*Calendar) Descriptor() ([]byte, []int) {
 	return file_api_EventService_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *EventRequest) GetEvent() *Event {
+func (x *Calendar) GetId() int64 {
 	if x != nil {
-		return x.Event
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Calendar) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *Calendar) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Calendar) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+func (x *Calendar) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *Calendar) GetIsDefault() bool {
+	if x != nil {
+		return x.IsDefault
+	}
+	return false
+}
+
+func (x *Calendar) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
 	}
 	return nil
 }
 
-type DeleteRequest struct {
+func (x *Calendar) GetShares() []*CalendarShare {
+	if x != nil {
+		return x.Shares
+	}
+	return nil
+}
+
+func (x *Calendar) GetHolidayCountry() string {
+	if x != nil {
+		return x.HolidayCountry
+	}
+	return ""
+}
+
+func (x *Calendar) GetDeferReminders() bool {
+	if x != nil {
+		return x.DeferReminders
+	}
+	return false
+}
+
+type CalendarShare struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Start         *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	Permission    Permission             `protobuf:"varint,2,opt,name=permission,proto3,enum=event.Permission" json:"permission,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteRequest) Reset() {
-	*x = DeleteRequest{}
+func (x *CalendarShare) Reset() {
+	*x = CalendarShare{}
 	mi := &file_api_EventService_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteRequest) String() string {
+func (x *CalendarShare) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteRequest) ProtoMessage() {}
+func (*CalendarShare) ProtoMessage() {}
 
-func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+func (x *CalendarShare) ProtoReflect() protoreflect.Message {
 	mi := &file_api_EventService_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -219,46 +464,45 @@ func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
-func (*DeleteRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use CalendarShare.ProtoReflect.Descriptor instead.
+func (*CalendarShare) Descriptor() ([]byte, []int) {
 	return file_api_EventService_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *DeleteRequest) GetUserId() string {
+func (x *CalendarShare) GetUserId() string {
 	if x != nil {
 		return x.UserId
 	}
 	return ""
 }
 
-func (x *DeleteRequest) GetStart() *timestamppb.Timestamp {
+func (x *CalendarShare) GetPermission() Permission {
 	if x != nil {
-		return x.Start
+		return x.Permission
 	}
-	return nil
+	return Permission_PERMISSION_UNSPECIFIED
 }
 
-type EventsResponse struct {
+type Empty struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Events        []*Event               `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *EventsResponse) Reset() {
-	*x = EventsResponse{}
+func (x *Empty) Reset() {
+	*x = Empty{}
 	mi := &file_api_EventService_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *EventsResponse) String() string {
+func (x *Empty) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*EventsResponse) ProtoMessage() {}
+func (*Empty) ProtoMessage() {}
 
-func (x *EventsResponse) ProtoReflect() protoreflect.Message {
+func (x *Empty) ProtoReflect() protoreflect.Message {
 	mi := &file_api_EventService_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -270,40 +514,77 @@ func (x *EventsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use EventsResponse.ProtoReflect.Descriptor instead.
-func (*EventsResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use Empty.ProtoReflect.Descriptor instead.
+func (*Empty) Descriptor() ([]byte, []int) {
 	return file_api_EventService_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *EventsResponse) GetEvents() []*Event {
+type EventRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Event         *Event                 `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EventRequest) Reset() {
+	*x = EventRequest{}
+	mi := &file_api_EventService_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventRequest) ProtoMessage() {}
+
+func (x *EventRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[5]
 	if x != nil {
-		return x.Events
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventRequest.ProtoReflect.Descriptor instead.
+func (*EventRequest) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *EventRequest) GetEvent() *Event {
+	if x != nil {
+		return x.Event
 	}
 	return nil
 }
 
-type DateRequest struct {
+type AddEventResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Date          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DateRequest) Reset() {
-	*x = DateRequest{}
-	mi := &file_api_EventService_proto_msgTypes[5]
+func (x *AddEventResponse) Reset() {
+	*x = AddEventResponse{}
+	mi := &file_api_EventService_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DateRequest) String() string {
+func (x *AddEventResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DateRequest) ProtoMessage() {}
+func (*AddEventResponse) ProtoMessage() {}
 
-func (x *DateRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_EventService_proto_msgTypes[5]
+func (x *AddEventResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -314,48 +595,2958 @@ func (x *DateRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DateRequest.ProtoReflect.Descriptor instead.
-func (*DateRequest) Descriptor() ([]byte, []int) {
-	return file_api_EventService_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use AddEventResponse.ProtoReflect.Descriptor instead.
+func (*AddEventResponse) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *DateRequest) GetDate() *timestamppb.Timestamp {
+func (x *AddEventResponse) GetId() int64 {
 	if x != nil {
-		return x.Date
+		return x.Id
 	}
-	return nil
+	return 0
 }
 
-var File_api_EventService_proto protoreflect.FileDescriptor
+type DeleteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Start         *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_api_EventService_proto_rawDesc = "" +
-	"\n" +
-	"\x16api/EventService.proto\x12\x05event\x1a\x1fgoogle/protobuf/timestamp.proto\"\xd0\x01\n" +
-	"\x05Event\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
-	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription\x125\n" +
-	"\bstart_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\astartAt\x12\x1a\n" +
-	"\bduration\x18\x05 \x01(\tR\bduration\x12#\n" +
-	"\rnotice_before\x18\x06 \x01(\x05R\fnoticeBefore\"\a\n" +
-	"\x05Empty\"2\n" +
-	"\fEventRequest\x12\"\n" +
-	"\x05event\x18\x01 \x01(\v2\f.event.EventR\x05event\"Z\n" +
-	"\rDeleteRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\x120\n" +
-	"\x05start\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\"6\n" +
-	"\x0eEventsResponse\x12$\n" +
-	"\x06events\x18\x01 \x03(\v2\f.event.EventR\x06events\"=\n" +
-	"\vDateRequest\x12.\n" +
-	"\x04date\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04date2\x91\x03\n" +
-	"\x0fCalendarService\x12-\n" +
-	"\bAddEvent\x12\x13.event.EventRequest\x1a\f.event.Empty\x120\n" +
-	"\vUpdateEvent\x12\x13.event.EventRequest\x1a\f.event.Empty\x121\n" +
-	"\vDeleteEvent\x12\x14.event.DeleteRequest\x1a\f.event.Empty\x120\n" +
-	"\tGetEvents\x12\f.event.Empty\x1a\x15.event.EventsResponse\x12;\n" +
-	"\x0eGetEventsByDay\x12\x12.event.DateRequest\x1a\x15.event.EventsResponse\x12<\n" +
-	"\x0fGetEventsByWeek\x12\x12.event.DateRequest\x1a\x15.event.EventsResponse\x12=\n" +
-	"\x10GetEventsByMonth\x12\x12.event.DateRequest\x1a\x15.event.EventsResponseB\rZ\vcalendarpb/b\x06proto3"
+func (x *DeleteRequest) Reset() {
+	*x = DeleteRequest{}
+	mi := &file_api_EventService_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRequest) ProtoMessage() {}
+
+func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DeleteRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *DeleteRequest) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+type EventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*Event               `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EventsResponse) Reset() {
+	*x = EventsResponse{}
+	mi := &file_api_EventService_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventsResponse) ProtoMessage() {}
+
+func (x *EventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventsResponse.ProtoReflect.Descriptor instead.
+func (*EventsResponse) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *EventsResponse) GetEvents() []*Event {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type DateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Date  *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	// Если задан - только события, где пользователь владелец или участник.
+	UserId        string `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DateRequest) Reset() {
+	*x = DateRequest{}
+	mi := &file_api_EventService_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DateRequest) ProtoMessage() {}
+
+func (x *DateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DateRequest.ProtoReflect.Descriptor instead.
+func (*DateRequest) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DateRequest) GetDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+func (x *DateRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type GetEventRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEventRequest) Reset() {
+	*x = GetEventRequest{}
+	mi := &file_api_EventService_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEventRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEventRequest) ProtoMessage() {}
+
+func (x *GetEventRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEventRequest.ProtoReflect.Descriptor instead.
+func (*GetEventRequest) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetEventRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// Пустые поля не ограничивают выборку.
+type ListEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"` // начало события >= from
+	To            *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`     // начало события < to
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEventsRequest) Reset() {
+	*x = ListEventsRequest{}
+	mi := &file_api_EventService_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEventsRequest) ProtoMessage() {}
+
+func (x *ListEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListEventsRequest) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListEventsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListEventsRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ListEventsRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type UpcomingEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpcomingEventsRequest) Reset() {
+	*x = UpcomingEventsRequest{}
+	mi := &file_api_EventService_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpcomingEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpcomingEventsRequest) ProtoMessage() {}
+
+func (x *UpcomingEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpcomingEventsRequest.ProtoReflect.Descriptor instead.
+func (*UpcomingEventsRequest) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UpcomingEventsRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+type DeleteOldEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Before        *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=before,proto3" json:"before,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteOldEventsRequest) Reset() {
+	*x = DeleteOldEventsRequest{}
+	mi := &file_api_EventService_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteOldEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteOldEventsRequest) ProtoMessage() {}
+
+func (x *DeleteOldEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteOldEventsRequest.ProtoReflect.Descriptor instead.
+func (*DeleteOldEventsRequest) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DeleteOldEventsRequest) GetBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Before
+	}
+	return nil
+}
+
+type BatchCreateEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*Event               `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateEventsRequest) Reset() {
+	*x = BatchCreateEventsRequest{}
+	mi := &file_api_EventService_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateEventsRequest) ProtoMessage() {}
+
+func (x *BatchCreateEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateEventsRequest.ProtoReflect.Descriptor instead.
+func (*BatchCreateEventsRequest) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *BatchCreateEventsRequest) GetEvents() []*Event {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type BatchDeleteEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []int64                `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchDeleteEventsRequest) Reset() {
+	*x = BatchDeleteEventsRequest{}
+	mi := &file_api_EventService_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchDeleteEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchDeleteEventsRequest) ProtoMessage() {}
+
+func (x *BatchDeleteEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchDeleteEventsRequest.ProtoReflect.Descriptor instead.
+func (*BatchDeleteEventsRequest) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *BatchDeleteEventsRequest) GetIds() []int64 {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type InviteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       int64                  `protobuf:"varint,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	UserIds       []string               `protobuf:"bytes,2,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InviteRequest) Reset() {
+	*x = InviteRequest{}
+	mi := &file_api_EventService_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InviteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InviteRequest) ProtoMessage() {}
+
+func (x *InviteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InviteRequest.ProtoReflect.Descriptor instead.
+func (*InviteRequest) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *InviteRequest) GetEventId() int64 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *InviteRequest) GetUserIds() []string {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+// Отвечает пользователь из x-user-id, user_id можно не указывать или указать его же.
+type RespondRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       int64                  `protobuf:"varint,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Status        RSVPStatus             `protobuf:"varint,3,opt,name=status,proto3,enum=event.RSVPStatus" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RespondRequest) Reset() {
+	*x = RespondRequest{}
+	mi := &file_api_EventService_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RespondRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RespondRequest) ProtoMessage() {}
+
+func (x *RespondRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RespondRequest.ProtoReflect.Descriptor instead.
+func (*RespondRequest) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *RespondRequest) GetEventId() int64 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *RespondRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *RespondRequest) GetStatus() RSVPStatus {
+	if x != nil {
+		return x.Status
+	}
+	return RSVPStatus_RSVP_STATUS_UNSPECIFIED
+}
+
+// Поиск времени для встречи. Рабочие часы задаются как "HH:MM" в часовом поясе
+// time_zone (IANA, по умолчанию UTC), пустые - 09:00-18:00.
+type FreeBusyRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserIds         []string               `protobuf:"bytes,1,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
+	From            *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To              *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	Duration        string                 `protobuf:"bytes,4,opt,name=duration,proto3" json:"duration,omitempty"` // длительность встречи, например "1h"
+	WorkDayStart    string                 `protobuf:"bytes,5,opt,name=work_day_start,json=workDayStart,proto3" json:"work_day_start,omitempty"`
+	WorkDayEnd      string                 `protobuf:"bytes,6,opt,name=work_day_end,json=workDayEnd,proto3" json:"work_day_end,omitempty"`
+	TimeZone        string                 `protobuf:"bytes,7,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	IncludeWeekends bool                   `protobuf:"varint,8,opt,name=include_weekends,json=includeWeekends,proto3" json:"include_weekends,omitempty"`
+	Limit           int32                  `protobuf:"varint,9,opt,name=limit,proto3" json:"limit,omitempty"` // сколько слотов вернуть, по умолчанию 10
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *FreeBusyRequest) Reset() {
+	*x = FreeBusyRequest{}
+	mi := &file_api_EventService_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FreeBusyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FreeBusyRequest) ProtoMessage() {}
+
+func (x *FreeBusyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FreeBusyRequest.ProtoReflect.Descriptor instead.
+func (*FreeBusyRequest) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *FreeBusyRequest) GetUserIds() []string {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+func (x *FreeBusyRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *FreeBusyRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *FreeBusyRequest) GetDuration() string {
+	if x != nil {
+		return x.Duration
+	}
+	return ""
+}
+
+func (x *FreeBusyRequest) GetWorkDayStart() string {
+	if x != nil {
+		return x.WorkDayStart
+	}
+	return ""
+}
+
+func (x *FreeBusyRequest) GetWorkDayEnd() string {
+	if x != nil {
+		return x.WorkDayEnd
+	}
+	return ""
+}
+
+func (x *FreeBusyRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *FreeBusyRequest) GetIncludeWeekends() bool {
+	if x != nil {
+		return x.IncludeWeekends
+	}
+	return false
+}
+
+func (x *FreeBusyRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type Interval struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	End           *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Interval) Reset() {
+	*x = Interval{}
+	mi := &file_api_EventService_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Interval) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Interval) ProtoMessage() {}
+
+func (x *Interval) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Interval.ProtoReflect.Descriptor instead.
+func (*Interval) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Interval) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *Interval) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+type UserBusy struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Busy          []*Interval            `protobuf:"bytes,2,rep,name=busy,proto3" json:"busy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserBusy) Reset() {
+	*x = UserBusy{}
+	mi := &file_api_EventService_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserBusy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserBusy) ProtoMessage() {}
+
+func (x *UserBusy) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserBusy.ProtoReflect.Descriptor instead.
+func (*UserBusy) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *UserBusy) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserBusy) GetBusy() []*Interval {
+	if x != nil {
+		return x.Busy
+	}
+	return nil
+}
+
+type Slot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	End           *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	Available     []string               `protobuf:"bytes,3,rep,name=available,proto3" json:"available,omitempty"`
+	Busy          []string               `protobuf:"bytes,4,rep,name=busy,proto3" json:"busy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Slot) Reset() {
+	*x = Slot{}
+	mi := &file_api_EventService_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Slot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Slot) ProtoMessage() {}
+
+func (x *Slot) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Slot.ProtoReflect.Descriptor instead.
+func (*Slot) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Slot) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *Slot) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *Slot) GetAvailable() []string {
+	if x != nil {
+		return x.Available
+	}
+	return nil
+}
+
+func (x *Slot) GetBusy() []string {
+	if x != nil {
+		return x.Busy
+	}
+	return nil
+}
+
+// Занятость в порядке user_ids запроса, слоты - от лучшего к худшему.
+type FreeBusyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Busy          []*UserBusy            `protobuf:"bytes,1,rep,name=busy,proto3" json:"busy,omitempty"`
+	Slots         []*Slot                `protobuf:"bytes,2,rep,name=slots,proto3" json:"slots,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FreeBusyResponse) Reset() {
+	*x = FreeBusyResponse{}
+	mi := &file_api_EventService_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FreeBusyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FreeBusyResponse) ProtoMessage() {}
+
+func (x *FreeBusyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FreeBusyResponse.ProtoReflect.Descriptor instead.
+func (*FreeBusyResponse) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *FreeBusyResponse) GetBusy() []*UserBusy {
+	if x != nil {
+		return x.Busy
+	}
+	return nil
+}
+
+func (x *FreeBusyResponse) GetSlots() []*Slot {
+	if x != nil {
+		return x.Slots
+	}
+	return nil
+}
+
+// owner_id календаря всегда берётся из x-user-id; другой owner_id отклоняется.
+type CalendarRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Calendar      *Calendar              `protobuf:"bytes,1,opt,name=calendar,proto3" json:"calendar,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CalendarRequest) Reset() {
+	*x = CalendarRequest{}
+	mi := &file_api_EventService_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalendarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalendarRequest) ProtoMessage() {}
+
+func (x *CalendarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalendarRequest.ProtoReflect.Descriptor instead.
+func (*CalendarRequest) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *CalendarRequest) GetCalendar() *Calendar {
+	if x != nil {
+		return x.Calendar
+	}
+	return nil
+}
+
+// user_id по умолчанию берётся из x-user-id.
+type ListCalendarsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCalendarsRequest) Reset() {
+	*x = ListCalendarsRequest{}
+	mi := &file_api_EventService_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCalendarsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCalendarsRequest) ProtoMessage() {}
+
+func (x *ListCalendarsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCalendarsRequest.ProtoReflect.Descriptor instead.
+func (*ListCalendarsRequest) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListCalendarsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type CalendarsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Calendars     []*Calendar            `protobuf:"bytes,1,rep,name=calendars,proto3" json:"calendars,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CalendarsResponse) Reset() {
+	*x = CalendarsResponse{}
+	mi := &file_api_EventService_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalendarsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalendarsResponse) ProtoMessage() {}
+
+func (x *CalendarsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalendarsResponse.ProtoReflect.Descriptor instead.
+func (*CalendarsResponse) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *CalendarsResponse) GetCalendars() []*Calendar {
+	if x != nil {
+		return x.Calendars
+	}
+	return nil
+}
+
+// Права меняет владелец календаря из x-user-id, PERMISSION_UNSPECIFIED отзывает доступ.
+type ShareCalendarRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CalendarId    int64                  `protobuf:"varint,1,opt,name=calendar_id,json=calendarId,proto3" json:"calendar_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Permission    Permission             `protobuf:"varint,3,opt,name=permission,proto3,enum=event.Permission" json:"permission,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShareCalendarRequest) Reset() {
+	*x = ShareCalendarRequest{}
+	mi := &file_api_EventService_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShareCalendarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShareCalendarRequest) ProtoMessage() {}
+
+func (x *ShareCalendarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShareCalendarRequest.ProtoReflect.Descriptor instead.
+func (*ShareCalendarRequest) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ShareCalendarRequest) GetCalendarId() int64 {
+	if x != nil {
+		return x.CalendarId
+	}
+	return 0
+}
+
+func (x *ShareCalendarRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ShareCalendarRequest) GetPermission() Permission {
+	if x != nil {
+		return x.Permission
+	}
+	return Permission_PERMISSION_UNSPECIFIED
+}
+
+// События календаря глазами пользователя из x-user-id. С правами FREE_BUSY
+// у событий заполнены только calendar_id, start_at и duration.
+type CalendarEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CalendarId    int64                  `protobuf:"varint,1,opt,name=calendar_id,json=calendarId,proto3" json:"calendar_id,omitempty"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"` // начало события >= from
+	To            *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`     // начало события < to
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CalendarEventsRequest) Reset() {
+	*x = CalendarEventsRequest{}
+	mi := &file_api_EventService_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalendarEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalendarEventsRequest) ProtoMessage() {}
+
+func (x *CalendarEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalendarEventsRequest.ProtoReflect.Descriptor instead.
+func (*CalendarEventsRequest) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *CalendarEventsRequest) GetCalendarId() int64 {
+	if x != nil {
+		return x.CalendarId
+	}
+	return 0
+}
+
+func (x *CalendarEventsRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *CalendarEventsRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+// Корзину видит только её владелец: user_id можно не указывать, другой
+// пользователь, чем в x-user-id, отклоняется.
+type TrashRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrashRequest) Reset() {
+	*x = TrashRequest{}
+	mi := &file_api_EventService_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrashRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrashRequest) ProtoMessage() {}
+
+func (x *TrashRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrashRequest.ProtoReflect.Descriptor instead.
+func (*TrashRequest) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *TrashRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type RestoreRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreRequest) Reset() {
+	*x = RestoreRequest{}
+	mi := &file_api_EventService_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreRequest) ProtoMessage() {}
+
+func (x *RestoreRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreRequest.ProtoReflect.Descriptor instead.
+func (*RestoreRequest) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *RestoreRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// Запись журнала изменений; before нет у создания, after - у удаления.
+type AuditRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	EventId       int64                  `protobuf:"varint,2,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	Actor         string                 `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"`
+	Action        string                 `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"` // create, update, delete, restore, invite, rsvp
+	Before        *Event                 `protobuf:"bytes,5,opt,name=before,proto3" json:"before,omitempty"`
+	After         *Event                 `protobuf:"bytes,6,opt,name=after,proto3" json:"after,omitempty"`
+	RequestId     string                 `protobuf:"bytes,7,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	At            *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=at,proto3" json:"at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditRecord) Reset() {
+	*x = AuditRecord{}
+	mi := &file_api_EventService_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditRecord) ProtoMessage() {}
+
+func (x *AuditRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditRecord.ProtoReflect.Descriptor instead.
+func (*AuditRecord) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *AuditRecord) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AuditRecord) GetEventId() int64 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *AuditRecord) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetBefore() *Event {
+	if x != nil {
+		return x.Before
+	}
+	return nil
+}
+
+func (x *AuditRecord) GetAfter() *Event {
+	if x != nil {
+		return x.After
+	}
+	return nil
+}
+
+func (x *AuditRecord) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+type EventHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Records       []*AuditRecord         `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EventHistoryResponse) Reset() {
+	*x = EventHistoryResponse{}
+	mi := &file_api_EventService_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventHistoryResponse) ProtoMessage() {}
+
+func (x *EventHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventHistoryResponse.ProtoReflect.Descriptor instead.
+func (*EventHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *EventHistoryResponse) GetRecords() []*AuditRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+// Результат одной операции пакетного запроса, error пустой при успехе.
+type BatchResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Id            int64                  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchResult) Reset() {
+	*x = BatchResult{}
+	mi := &file_api_EventService_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchResult) ProtoMessage() {}
+
+func (x *BatchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchResult.ProtoReflect.Descriptor instead.
+func (*BatchResult) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *BatchResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BatchResult) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *BatchResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type BatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*BatchResult         `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchResponse) Reset() {
+	*x = BatchResponse{}
+	mi := &file_api_EventService_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchResponse) ProtoMessage() {}
+
+func (x *BatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchResponse.ProtoReflect.Descriptor instead.
+func (*BatchResponse) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *BatchResponse) GetResults() []*BatchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// Подписка на уведомления об изменениях событий владельца и событий, куда его
+// пригласили. secret заполнен только в ответе CreateWebhook.
+type Webhook struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Id      int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	OwnerId string                 `protobuf:"bytes,2,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	Url     string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	// event.created, event.updated, event.deleted, event.reminder; пусто - все.
+	Events        []string               `protobuf:"bytes,4,rep,name=events,proto3" json:"events,omitempty"`
+	Secret        string                 `protobuf:"bytes,5,opt,name=secret,proto3" json:"secret,omitempty"`
+	Active        bool                   `protobuf:"varint,6,opt,name=active,proto3" json:"active,omitempty"`
+	Failures      int32                  `protobuf:"varint,7,opt,name=failures,proto3" json:"failures,omitempty"` // неудачных попыток подряд
+	DisabledAt    *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=disabled_at,json=disabledAt,proto3" json:"disabled_at,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Webhook) Reset() {
+	*x = Webhook{}
+	mi := &file_api_EventService_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Webhook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Webhook) ProtoMessage() {}
+
+func (x *Webhook) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Webhook.ProtoReflect.Descriptor instead.
+func (*Webhook) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *Webhook) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Webhook) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *Webhook) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Webhook) GetEvents() []string {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *Webhook) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *Webhook) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *Webhook) GetFailures() int32 {
+	if x != nil {
+		return x.Failures
+	}
+	return 0
+}
+
+func (x *Webhook) GetDisabledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DisabledAt
+	}
+	return nil
+}
+
+func (x *Webhook) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// owner_id берётся из x-user-id.
+type WebhookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Webhook       *Webhook               `protobuf:"bytes,1,opt,name=webhook,proto3" json:"webhook,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookRequest) Reset() {
+	*x = WebhookRequest{}
+	mi := &file_api_EventService_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookRequest) ProtoMessage() {}
+
+func (x *WebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookRequest.ProtoReflect.Descriptor instead.
+func (*WebhookRequest) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *WebhookRequest) GetWebhook() *Webhook {
+	if x != nil {
+		return x.Webhook
+	}
+	return nil
+}
+
+type WebhooksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Webhooks      []*Webhook             `protobuf:"bytes,1,rep,name=webhooks,proto3" json:"webhooks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhooksResponse) Reset() {
+	*x = WebhooksResponse{}
+	mi := &file_api_EventService_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhooksResponse) ProtoMessage() {}
+
+func (x *WebhooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhooksResponse.ProtoReflect.Descriptor instead.
+func (*WebhooksResponse) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *WebhooksResponse) GetWebhooks() []*Webhook {
+	if x != nil {
+		return x.Webhooks
+	}
+	return nil
+}
+
+// Подписка пользователя из x-user-id, с чужими - PERMISSION_DENIED.
+type WebhookIDRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookIDRequest) Reset() {
+	*x = WebhookIDRequest{}
+	mi := &file_api_EventService_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookIDRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookIDRequest) ProtoMessage() {}
+
+func (x *WebhookIDRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookIDRequest.ProtoReflect.Descriptor instead.
+func (*WebhookIDRequest) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *WebhookIDRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type Delivery struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	WebhookId     int64                  `protobuf:"varint,2,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	Event         string                 `protobuf:"bytes,3,opt,name=event,proto3" json:"event,omitempty"`
+	State         string                 `protobuf:"bytes,4,opt,name=state,proto3" json:"state,omitempty"` // pending, delivered, failed
+	Attempts      int32                  `protobuf:"varint,5,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	NextAttemptAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=next_attempt_at,json=nextAttemptAt,proto3" json:"next_attempt_at,omitempty"`
+	LastStatus    int32                  `protobuf:"varint,7,opt,name=last_status,json=lastStatus,proto3" json:"last_status,omitempty"` // HTTP статус, 0 - ответа не было
+	LastError     string                 `protobuf:"bytes,8,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	DeliveredAt   *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=delivered_at,json=deliveredAt,proto3" json:"delivered_at,omitempty"`
+	Payload       string                 `protobuf:"bytes,11,opt,name=payload,proto3" json:"payload,omitempty"` // JSON тело запроса
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Delivery) Reset() {
+	*x = Delivery{}
+	mi := &file_api_EventService_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Delivery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Delivery) ProtoMessage() {}
+
+func (x *Delivery) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Delivery.ProtoReflect.Descriptor instead.
+func (*Delivery) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *Delivery) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Delivery) GetWebhookId() int64 {
+	if x != nil {
+		return x.WebhookId
+	}
+	return 0
+}
+
+func (x *Delivery) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+func (x *Delivery) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Delivery) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *Delivery) GetNextAttemptAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextAttemptAt
+	}
+	return nil
+}
+
+func (x *Delivery) GetLastStatus() int32 {
+	if x != nil {
+		return x.LastStatus
+	}
+	return 0
+}
+
+func (x *Delivery) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *Delivery) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Delivery) GetDeliveredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeliveredAt
+	}
+	return nil
+}
+
+func (x *Delivery) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+type DeliveriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deliveries    []*Delivery            `protobuf:"bytes,1,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeliveriesResponse) Reset() {
+	*x = DeliveriesResponse{}
+	mi := &file_api_EventService_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeliveriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeliveriesResponse) ProtoMessage() {}
+
+func (x *DeliveriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeliveriesResponse.ProtoReflect.Descriptor instead.
+func (*DeliveriesResponse) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *DeliveriesResponse) GetDeliveries() []*Delivery {
+	if x != nil {
+		return x.Deliveries
+	}
+	return nil
+}
+
+// Файл или ссылка, прикреплённые к событию. Содержимое файла загружается и
+// скачивается потоком мимо gateway: POST /events/{event_id}/attachments?name=
+// и GET /attachments/{id}/content.
+type Attachment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	EventId       int64                  `protobuf:"varint,2,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	ContentType   string                 `protobuf:"bytes,4,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"` // у ссылки пустой
+	Size          int64                  `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`
+	Url           string                 `protobuf:"bytes,6,opt,name=url,proto3" json:"url,omitempty"` // только у ссылки
+	CreatedBy     string                 `protobuf:"bytes,7,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Attachment) Reset() {
+	*x = Attachment{}
+	mi := &file_api_EventService_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Attachment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Attachment) ProtoMessage() {}
+
+func (x *Attachment) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Attachment.ProtoReflect.Descriptor instead.
+func (*Attachment) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *Attachment) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Attachment) GetEventId() int64 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *Attachment) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Attachment) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *Attachment) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *Attachment) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Attachment) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *Attachment) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type AttachmentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       int64                  `protobuf:"varint,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttachmentsRequest) Reset() {
+	*x = AttachmentsRequest{}
+	mi := &file_api_EventService_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttachmentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttachmentsRequest) ProtoMessage() {}
+
+func (x *AttachmentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttachmentsRequest.ProtoReflect.Descriptor instead.
+func (*AttachmentsRequest) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *AttachmentsRequest) GetEventId() int64 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+type AttachmentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Attachments   []*Attachment          `protobuf:"bytes,1,rep,name=attachments,proto3" json:"attachments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttachmentsResponse) Reset() {
+	*x = AttachmentsResponse{}
+	mi := &file_api_EventService_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttachmentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttachmentsResponse) ProtoMessage() {}
+
+func (x *AttachmentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttachmentsResponse.ProtoReflect.Descriptor instead.
+func (*AttachmentsResponse) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *AttachmentsResponse) GetAttachments() []*Attachment {
+	if x != nil {
+		return x.Attachments
+	}
+	return nil
+}
+
+// name по умолчанию - сам url.
+type LinkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       int64                  `protobuf:"varint,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Url           string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinkRequest) Reset() {
+	*x = LinkRequest{}
+	mi := &file_api_EventService_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkRequest) ProtoMessage() {}
+
+func (x *LinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkRequest.ProtoReflect.Descriptor instead.
+func (*LinkRequest) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *LinkRequest) GetEventId() int64 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *LinkRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *LinkRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+type AttachmentIDRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttachmentIDRequest) Reset() {
+	*x = AttachmentIDRequest{}
+	mi := &file_api_EventService_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttachmentIDRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttachmentIDRequest) ProtoMessage() {}
+
+func (x *AttachmentIDRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttachmentIDRequest.ProtoReflect.Descriptor instead.
+func (*AttachmentIDRequest) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *AttachmentIDRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// Серия событий по образцу event, первое - в event.start_at. Повторы создаются
+// отдельными событиями в зоне календаря события, вся серия или ничего.
+type RecurringEventRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Event     *Event                 `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	Frequency string                 `protobuf:"bytes,2,opt,name=frequency,proto3" json:"frequency,omitempty"` // daily, weekly или monthly
+	Interval  int32                  `protobuf:"varint,3,opt,name=interval,proto3" json:"interval,omitempty"`  // каждые interval периодов, 0 - каждый
+	// Нужно одно из двух: сколько повторов, считая первый и пропущенные, или
+	// по какой день включительно, YYYY-MM-DD.
+	Count int32  `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	Until string `protobuf:"bytes,5,opt,name=until,proto3" json:"until,omitempty"`
+	// Не ставить повторы на выходные и праздники календаря события.
+	SkipHolidays  bool `protobuf:"varint,6,opt,name=skip_holidays,json=skipHolidays,proto3" json:"skip_holidays,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecurringEventRequest) Reset() {
+	*x = RecurringEventRequest{}
+	mi := &file_api_EventService_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecurringEventRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecurringEventRequest) ProtoMessage() {}
+
+func (x *RecurringEventRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecurringEventRequest.ProtoReflect.Descriptor instead.
+func (*RecurringEventRequest) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *RecurringEventRequest) GetEvent() *Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *RecurringEventRequest) GetFrequency() string {
+	if x != nil {
+		return x.Frequency
+	}
+	return ""
+}
+
+func (x *RecurringEventRequest) GetInterval() int32 {
+	if x != nil {
+		return x.Interval
+	}
+	return 0
+}
+
+func (x *RecurringEventRequest) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *RecurringEventRequest) GetUntil() string {
+	if x != nil {
+		return x.Until
+	}
+	return ""
+}
+
+func (x *RecurringEventRequest) GetSkipHolidays() bool {
+	if x != nil {
+		return x.SkipHolidays
+	}
+	return false
+}
+
+type RecurringEventResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Events []*Event               `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	// Начала повторов, пропущенных из-за нерабочих дней.
+	Skipped       []*timestamppb.Timestamp `protobuf:"bytes,2,rep,name=skipped,proto3" json:"skipped,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecurringEventResponse) Reset() {
+	*x = RecurringEventResponse{}
+	mi := &file_api_EventService_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecurringEventResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecurringEventResponse) ProtoMessage() {}
+
+func (x *RecurringEventResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecurringEventResponse.ProtoReflect.Descriptor instead.
+func (*RecurringEventResponse) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *RecurringEventResponse) GetEvents() []*Event {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *RecurringEventResponse) GetSkipped() []*timestamppb.Timestamp {
+	if x != nil {
+		return x.Skipped
+	}
+	return nil
+}
+
+// Строка вида "standup tomorrow 10:00 for 15m remind 10m before" или
+// "созвон завтра в 10 на 15 минут, напомнить за день".
+type QuickAddRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Text  string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	// Только разобрать строку, событие не создаётся.
+	Preview    bool  `protobuf:"varint,2,opt,name=preview,proto3" json:"preview,omitempty"`
+	CalendarId int64 `protobuf:"varint,3,opt,name=calendar_id,json=calendarId,proto3" json:"calendar_id,omitempty"` // 0 - календарь по умолчанию
+	// IANA зона для относительных дат, по умолчанию зона календаря.
+	TimeZone      string `protobuf:"bytes,4,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuickAddRequest) Reset() {
+	*x = QuickAddRequest{}
+	mi := &file_api_EventService_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuickAddRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuickAddRequest) ProtoMessage() {}
+
+func (x *QuickAddRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuickAddRequest.ProtoReflect.Descriptor instead.
+func (*QuickAddRequest) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *QuickAddRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *QuickAddRequest) GetPreview() bool {
+	if x != nil {
+		return x.Preview
+	}
+	return false
+}
+
+func (x *QuickAddRequest) GetCalendarId() int64 {
+	if x != nil {
+		return x.CalendarId
+	}
+	return 0
+}
+
+func (x *QuickAddRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+type QuickAddResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// При preview id и created_at не заполнены.
+	Event *Event `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	// Например, напоминание округлено до целых дней.
+	Warnings      []string `protobuf:"bytes,2,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuickAddResponse) Reset() {
+	*x = QuickAddResponse{}
+	mi := &file_api_EventService_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuickAddResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuickAddResponse) ProtoMessage() {}
+
+func (x *QuickAddResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuickAddResponse.ProtoReflect.Descriptor instead.
+func (*QuickAddResponse) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *QuickAddResponse) GetEvent() *Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *QuickAddResponse) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+// Даты в формате YYYY-MM-DD, не больше 366 дней. Страна и зона по умолчанию
+// берутся из calendar_id, без них - только выходные по UTC.
+type WorkingDaysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Country       string                 `protobuf:"bytes,3,opt,name=country,proto3" json:"country,omitempty"`
+	CalendarId    int64                  `protobuf:"varint,4,opt,name=calendar_id,json=calendarId,proto3" json:"calendar_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkingDaysRequest) Reset() {
+	*x = WorkingDaysRequest{}
+	mi := &file_api_EventService_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkingDaysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkingDaysRequest) ProtoMessage() {}
+
+func (x *WorkingDaysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkingDaysRequest.ProtoReflect.Descriptor instead.
+func (*WorkingDaysRequest) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *WorkingDaysRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *WorkingDaysRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *WorkingDaysRequest) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *WorkingDaysRequest) GetCalendarId() int64 {
+	if x != nil {
+		return x.CalendarId
+	}
+	return 0
+}
+
+type WorkingDay struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Date          string                 `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"` // YYYY-MM-DD
+	Working       bool                   `protobuf:"varint,2,opt,name=working,proto3" json:"working,omitempty"`
+	Weekend       bool                   `protobuf:"varint,3,opt,name=weekend,proto3" json:"weekend,omitempty"`
+	Holiday       string                 `protobuf:"bytes,4,opt,name=holiday,proto3" json:"holiday,omitempty"` // название праздника
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkingDay) Reset() {
+	*x = WorkingDay{}
+	mi := &file_api_EventService_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkingDay) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkingDay) ProtoMessage() {}
+
+func (x *WorkingDay) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkingDay.ProtoReflect.Descriptor instead.
+func (*WorkingDay) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *WorkingDay) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *WorkingDay) GetWorking() bool {
+	if x != nil {
+		return x.Working
+	}
+	return false
+}
+
+func (x *WorkingDay) GetWeekend() bool {
+	if x != nil {
+		return x.Weekend
+	}
+	return false
+}
+
+func (x *WorkingDay) GetHoliday() string {
+	if x != nil {
+		return x.Holiday
+	}
+	return ""
+}
+
+type WorkingDaysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Country       string                 `protobuf:"bytes,1,opt,name=country,proto3" json:"country,omitempty"`
+	Days          []*WorkingDay          `protobuf:"bytes,2,rep,name=days,proto3" json:"days,omitempty"`
+	WorkingCount  int32                  `protobuf:"varint,3,opt,name=working_count,json=workingCount,proto3" json:"working_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkingDaysResponse) Reset() {
+	*x = WorkingDaysResponse{}
+	mi := &file_api_EventService_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkingDaysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkingDaysResponse) ProtoMessage() {}
+
+func (x *WorkingDaysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_EventService_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkingDaysResponse.ProtoReflect.Descriptor instead.
+func (*WorkingDaysResponse) Descriptor() ([]byte, []int) {
+	return file_api_EventService_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *WorkingDaysResponse) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *WorkingDaysResponse) GetDays() []*WorkingDay {
+	if x != nil {
+		return x.Days
+	}
+	return nil
+}
+
+func (x *WorkingDaysResponse) GetWorkingCount() int32 {
+	if x != nil {
+		return x.WorkingCount
+	}
+	return 0
+}
+
+var File_api_EventService_proto protoreflect.FileDescriptor
+
+const file_api_EventService_proto_rawDesc = "" +
+	"\n" +
+	"\x16api/EventService.proto\x12\x05event\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa6\x03\n" +
+	"\x05Event\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x125\n" +
+	"\bstart_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\astartAt\x12\x1a\n" +
+	"\bduration\x18\x05 \x01(\tR\bduration\x12#\n" +
+	"\rnotice_before\x18\x06 \x01(\x05R\fnoticeBefore\x12\x0e\n" +
+	"\x02id\x18\a \x01(\x03R\x02id\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12-\n" +
+	"\tattendees\x18\t \x03(\v2\x0f.event.AttendeeR\tattendees\x12\x1f\n" +
+	"\vcalendar_id\x18\n" +
+	" \x01(\x03R\n" +
+	"calendarId\x129\n" +
+	"\n" +
+	"deleted_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\"N\n" +
+	"\bAttendee\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12)\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x11.event.RSVPStatusR\x06status\"\xd6\x02\n" +
+	"\bCalendar\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x19\n" +
+	"\bowner_id\x18\x02 \x01(\tR\aownerId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x14\n" +
+	"\x05color\x18\x04 \x01(\tR\x05color\x12\x1b\n" +
+	"\ttime_zone\x18\x05 \x01(\tR\btimeZone\x12\x1d\n" +
+	"\n" +
+	"is_default\x18\x06 \x01(\bR\tisDefault\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12,\n" +
+	"\x06shares\x18\b \x03(\v2\x14.event.CalendarShareR\x06shares\x12'\n" +
+	"\x0fholiday_country\x18\t \x01(\tR\x0eholidayCountry\x12'\n" +
+	"\x0fdefer_reminders\x18\n" +
+	" \x01(\bR\x0edeferReminders\"[\n" +
+	"\rCalendarShare\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x121\n" +
+	"\n" +
+	"permission\x18\x02 \x01(\x0e2\x11.event.PermissionR\n" +
+	"permission\"\a\n" +
+	"\x05Empty\"2\n" +
+	"\fEventRequest\x12\"\n" +
+	"\x05event\x18\x01 \x01(\v2\f.event.EventR\x05event\"\"\n" +
+	"\x10AddEventResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"Z\n" +
+	"\rDeleteRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x120\n" +
+	"\x05start\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\"6\n" +
+	"\x0eEventsResponse\x12$\n" +
+	"\x06events\x18\x01 \x03(\v2\f.event.EventR\x06events\"V\n" +
+	"\vDateRequest\x12.\n" +
+	"\x04date\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04date\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"!\n" +
+	"\x0fGetEventRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x88\x01\n" +
+	"\x11ListEventsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\"G\n" +
+	"\x15UpcomingEventsRequest\x12.\n" +
+	"\x04from\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\"L\n" +
+	"\x16DeleteOldEventsRequest\x122\n" +
+	"\x06before\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x06before\"@\n" +
+	"\x18BatchCreateEventsRequest\x12$\n" +
+	"\x06events\x18\x01 \x03(\v2\f.event.EventR\x06events\",\n" +
+	"\x18BatchDeleteEventsRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\x03R\x03ids\"E\n" +
+	"\rInviteRequest\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\x03R\aeventId\x12\x19\n" +
+	"\buser_ids\x18\x02 \x03(\tR\auserIds\"o\n" +
+	"\x0eRespondRequest\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\x03R\aeventId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12)\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x11.event.RSVPStatusR\x06status\"\xca\x02\n" +
+	"\x0fFreeBusyRequest\x12\x19\n" +
+	"\buser_ids\x18\x01 \x03(\tR\auserIds\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\x1a\n" +
+	"\bduration\x18\x04 \x01(\tR\bduration\x12$\n" +
+	"\x0ework_day_start\x18\x05 \x01(\tR\fworkDayStart\x12 \n" +
+	"\fwork_day_end\x18\x06 \x01(\tR\n" +
+	"workDayEnd\x12\x1b\n" +
+	"\ttime_zone\x18\a \x01(\tR\btimeZone\x12)\n" +
+	"\x10include_weekends\x18\b \x01(\bR\x0fincludeWeekends\x12\x14\n" +
+	"\x05limit\x18\t \x01(\x05R\x05limit\"j\n" +
+	"\bInterval\x120\n" +
+	"\x05start\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\"H\n" +
+	"\bUserBusy\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12#\n" +
+	"\x04busy\x18\x02 \x03(\v2\x0f.event.IntervalR\x04busy\"\x98\x01\n" +
+	"\x04Slot\x120\n" +
+	"\x05start\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\x12\x1c\n" +
+	"\tavailable\x18\x03 \x03(\tR\tavailable\x12\x12\n" +
+	"\x04busy\x18\x04 \x03(\tR\x04busy\"Z\n" +
+	"\x10FreeBusyResponse\x12#\n" +
+	"\x04busy\x18\x01 \x03(\v2\x0f.event.UserBusyR\x04busy\x12!\n" +
+	"\x05slots\x18\x02 \x03(\v2\v.event.SlotR\x05slots\">\n" +
+	"\x0fCalendarRequest\x12+\n" +
+	"\bcalendar\x18\x01 \x01(\v2\x0f.event.CalendarR\bcalendar\"/\n" +
+	"\x14ListCalendarsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"B\n" +
+	"\x11CalendarsResponse\x12-\n" +
+	"\tcalendars\x18\x01 \x03(\v2\x0f.event.CalendarR\tcalendars\"\x83\x01\n" +
+	"\x14ShareCalendarRequest\x12\x1f\n" +
+	"\vcalendar_id\x18\x01 \x01(\x03R\n" +
+	"calendarId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x121\n" +
+	"\n" +
+	"permission\x18\x03 \x01(\x0e2\x11.event.PermissionR\n" +
+	"permission\"\x94\x01\n" +
+	"\x15CalendarEventsRequest\x12\x1f\n" +
+	"\vcalendar_id\x18\x01 \x01(\x03R\n" +
+	"calendarId\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\"'\n" +
+	"\fTrashRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\" \n" +
+	"\x0eRestoreRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\xfb\x01\n" +
+	"\vAuditRecord\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x19\n" +
+	"\bevent_id\x18\x02 \x01(\x03R\aeventId\x12\x14\n" +
+	"\x05actor\x18\x03 \x01(\tR\x05actor\x12\x16\n" +
+	"\x06action\x18\x04 \x01(\tR\x06action\x12$\n" +
+	"\x06before\x18\x05 \x01(\v2\f.event.EventR\x06before\x12\"\n" +
+	"\x05after\x18\x06 \x01(\v2\f.event.EventR\x05after\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\a \x01(\tR\trequestId\x12*\n" +
+	"\x02at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x02at\"D\n" +
+	"\x14EventHistoryResponse\x12,\n" +
+	"\arecords\x18\x01 \x03(\v2\x12.event.AuditRecordR\arecords\"I\n" +
+	"\vBatchResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"=\n" +
+	"\rBatchResponse\x12,\n" +
+	"\aresults\x18\x01 \x03(\v2\x12.event.BatchResultR\aresults\"\xa2\x02\n" +
+	"\aWebhook\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x19\n" +
+	"\bowner_id\x18\x02 \x01(\tR\aownerId\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x12\x16\n" +
+	"\x06events\x18\x04 \x03(\tR\x06events\x12\x16\n" +
+	"\x06secret\x18\x05 \x01(\tR\x06secret\x12\x16\n" +
+	"\x06active\x18\x06 \x01(\bR\x06active\x12\x1a\n" +
+	"\bfailures\x18\a \x01(\x05R\bfailures\x12;\n" +
+	"\vdisabled_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"disabledAt\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\":\n" +
+	"\x0eWebhookRequest\x12(\n" +
+	"\awebhook\x18\x01 \x01(\v2\x0e.event.WebhookR\awebhook\">\n" +
+	"\x10WebhooksResponse\x12*\n" +
+	"\bwebhooks\x18\x01 \x03(\v2\x0e.event.WebhookR\bwebhooks\"\"\n" +
+	"\x10WebhookIDRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x99\x03\n" +
+	"\bDelivery\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x02 \x01(\x03R\twebhookId\x12\x14\n" +
+	"\x05event\x18\x03 \x01(\tR\x05event\x12\x14\n" +
+	"\x05state\x18\x04 \x01(\tR\x05state\x12\x1a\n" +
+	"\battempts\x18\x05 \x01(\x05R\battempts\x12B\n" +
+	"\x0fnext_attempt_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\rnextAttemptAt\x12\x1f\n" +
+	"\vlast_status\x18\a \x01(\x05R\n" +
+	"lastStatus\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\b \x01(\tR\tlastError\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12=\n" +
+	"\fdelivered_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\vdeliveredAt\x12\x18\n" +
+	"\apayload\x18\v \x01(\tR\apayload\"E\n" +
+	"\x12DeliveriesResponse\x12/\n" +
+	"\n" +
+	"deliveries\x18\x01 \x03(\v2\x0f.event.DeliveryR\n" +
+	"deliveries\"\xee\x01\n" +
+	"\n" +
+	"Attachment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x19\n" +
+	"\bevent_id\x18\x02 \x01(\x03R\aeventId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12!\n" +
+	"\fcontent_type\x18\x04 \x01(\tR\vcontentType\x12\x12\n" +
+	"\x04size\x18\x05 \x01(\x03R\x04size\x12\x10\n" +
+	"\x03url\x18\x06 \x01(\tR\x03url\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\a \x01(\tR\tcreatedBy\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"/\n" +
+	"\x12AttachmentsRequest\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\x03R\aeventId\"J\n" +
+	"\x13AttachmentsResponse\x123\n" +
+	"\vattachments\x18\x01 \x03(\v2\x11.event.AttachmentR\vattachments\"N\n" +
+	"\vLinkRequest\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\x03R\aeventId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\"%\n" +
+	"\x13AttachmentIDRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\xc6\x01\n" +
+	"\x15RecurringEventRequest\x12\"\n" +
+	"\x05event\x18\x01 \x01(\v2\f.event.EventR\x05event\x12\x1c\n" +
+	"\tfrequency\x18\x02 \x01(\tR\tfrequency\x12\x1a\n" +
+	"\binterval\x18\x03 \x01(\x05R\binterval\x12\x14\n" +
+	"\x05count\x18\x04 \x01(\x05R\x05count\x12\x14\n" +
+	"\x05until\x18\x05 \x01(\tR\x05until\x12#\n" +
+	"\rskip_holidays\x18\x06 \x01(\bR\fskipHolidays\"t\n" +
+	"\x16RecurringEventResponse\x12$\n" +
+	"\x06events\x18\x01 \x03(\v2\f.event.EventR\x06events\x124\n" +
+	"\askipped\x18\x02 \x03(\v2\x1a.google.protobuf.TimestampR\askipped\"}\n" +
+	"\x0fQuickAddRequest\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\x18\n" +
+	"\apreview\x18\x02 \x01(\bR\apreview\x12\x1f\n" +
+	"\vcalendar_id\x18\x03 \x01(\x03R\n" +
+	"calendarId\x12\x1b\n" +
+	"\ttime_zone\x18\x04 \x01(\tR\btimeZone\"R\n" +
+	"\x10QuickAddResponse\x12\"\n" +
+	"\x05event\x18\x01 \x01(\v2\f.event.EventR\x05event\x12\x1a\n" +
+	"\bwarnings\x18\x02 \x03(\tR\bwarnings\"s\n" +
+	"\x12WorkingDaysRequest\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\x12\x18\n" +
+	"\acountry\x18\x03 \x01(\tR\acountry\x12\x1f\n" +
+	"\vcalendar_id\x18\x04 \x01(\x03R\n" +
+	"calendarId\"n\n" +
+	"\n" +
+	"WorkingDay\x12\x12\n" +
+	"\x04date\x18\x01 \x01(\tR\x04date\x12\x18\n" +
+	"\aworking\x18\x02 \x01(\bR\aworking\x12\x18\n" +
+	"\aweekend\x18\x03 \x01(\bR\aweekend\x12\x18\n" +
+	"\aholiday\x18\x04 \x01(\tR\aholiday\"{\n" +
+	"\x13WorkingDaysResponse\x12\x18\n" +
+	"\acountry\x18\x01 \x01(\tR\acountry\x12%\n" +
+	"\x04days\x18\x02 \x03(\v2\x11.event.WorkingDayR\x04days\x12#\n" +
+	"\rworking_count\x18\x03 \x01(\x05R\fworkingCount*\x96\x01\n" +
+	"\n" +
+	"RSVPStatus\x12\x1b\n" +
+	"\x17RSVP_STATUS_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18RSVP_STATUS_NEEDS_ACTION\x10\x01\x12\x18\n" +
+	"\x14RSVP_STATUS_ACCEPTED\x10\x02\x12\x18\n" +
+	"\x14RSVP_STATUS_DECLINED\x10\x03\x12\x19\n" +
+	"\x15RSVP_STATUS_TENTATIVE\x10\x04*m\n" +
+	"\n" +
+	"Permission\x12\x1a\n" +
+	"\x16PERMISSION_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14PERMISSION_FREE_BUSY\x10\x01\x12\x13\n" +
+	"\x0fPERMISSION_READ\x10\x02\x12\x14\n" +
+	"\x10PERMISSION_WRITE\x10\x032\xd5\x18\n" +
+	"\x0fCalendarService\x12P\n" +
+	"\bAddEvent\x12\x13.event.EventRequest\x1a\x17.event.AddEventResponse\"\x16\x82\xd3\xe4\x93\x02\x10:\x05event\"\a/events\x12n\n" +
+	"\x11AddRecurringEvent\x12\x1c.event.RecurringEventRequest\x1a\x1d.event.RecurringEventResponse\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/events:recurring\x12U\n" +
+	"\bQuickAdd\x12\x16.event.QuickAddRequest\x1a\x17.event.QuickAddResponse\"\x18\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/events/quick\x12H\n" +
+	"\vUpdateEvent\x12\x13.event.EventRequest\x1a\f.event.Empty\"\x16\x82\xd3\xe4\x93\x02\x10:\x05event\x1a\a/events\x12B\n" +
+	"\vDeleteEvent\x12\x14.event.DeleteRequest\x1a\f.event.Empty\"\x0f\x82\xd3\xe4\x93\x02\t*\a/events\x12S\n" +
+	"\x0fDeleteOldEvents\x12\x1d.event.DeleteOldEventsRequest\x1a\f.event.Empty\"\x13\x82\xd3\xe4\x93\x02\r*\v/events/old\x12G\n" +
+	"\tListTrash\x12\x13.event.TrashRequest\x1a\x15.event.EventsResponse\"\x0e\x82\xd3\xe4\x93\x02\b\x12\x06/trash\x12S\n" +
+	"\fRestoreEvent\x12\x15.event.RestoreRequest\x1a\f.event.Event\"\x1e\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/trash/{id}:restore\x12F\n" +
+	"\bGetEvent\x12\x16.event.GetEventRequest\x1a\f.event.Event\"\x14\x82\xd3\xe4\x93\x02\x0e\x12\f/events/{id}\x12d\n" +
+	"\x0fGetEventHistory\x12\x16.event.GetEventRequest\x1a\x1b.event.EventHistoryResponse\"\x1c\x82\xd3\xe4\x93\x02\x16\x12\x14/events/{id}/history\x120\n" +
+	"\tGetEvents\x12\f.event.Empty\x1a\x15.event.EventsResponse\x12N\n" +
+	"\n" +
+	"ListEvents\x12\x18.event.ListEventsRequest\x1a\x15.event.EventsResponse\"\x0f\x82\xd3\xe4\x93\x02\t\x12\a/events\x12b\n" +
+	"\x11GetUpcomingEvents\x12\x1c.event.UpcomingEventsRequest\x1a\x15.event.EventsResponse\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/events/upcoming\x12P\n" +
+	"\x0eGetEventsByDay\x12\x12.event.DateRequest\x1a\x15.event.EventsResponse\"\x13\x82\xd3\xe4\x93\x02\r\x12\v/events/day\x12R\n" +
+	"\x0fGetEventsByWeek\x12\x12.event.DateRequest\x1a\x15.event.EventsResponse\"\x14\x82\xd3\xe4\x93\x02\x0e\x12\f/events/week\x12T\n" +
+	"\x10GetEventsByMonth\x12\x12.event.DateRequest\x1a\x15.event.EventsResponse\"\x15\x82\xd3\xe4\x93\x02\x0f\x12\r/events/month\x12^\n" +
+	"\x0fInviteAttendees\x12\x14.event.InviteRequest\x1a\f.event.Event\"'\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/events/{event_id}/attendees\x12Z\n" +
+	"\x0fRespondToInvite\x12\x15.event.RespondRequest\x1a\f.event.Event\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*\"\x17/events/{event_id}/rsvp\x12T\n" +
+	"\vGetFreeBusy\x12\x16.event.FreeBusyRequest\x1a\x17.event.FreeBusyResponse\"\x14\x82\xd3\xe4\x93\x02\x0e:\x01*\"\t/freebusy\x12W\n" +
+	"\x0eCreateCalendar\x12\x16.event.CalendarRequest\x1a\x0f.event.Calendar\"\x1c\x82\xd3\xe4\x93\x02\x16:\bcalendar\"\n" +
+	"/calendars\x12e\n" +
+	"\x0eUpdateCalendar\x12\x16.event.CalendarRequest\x1a\x0f.event.Calendar\"*\x82\xd3\xe4\x93\x02$:\bcalendar\x1a\x18/calendars/{calendar.id}\x12Z\n" +
+	"\rListCalendars\x12\x1b.event.ListCalendarsRequest\x1a\x18.event.CalendarsResponse\"\x12\x82\xd3\xe4\x93\x02\f\x12\n" +
+	"/calendars\x12i\n" +
+	"\rShareCalendar\x12\x1b.event.ShareCalendarRequest\x1a\x0f.event.Calendar\"*\x82\xd3\xe4\x93\x02$:\x01*\"\x1f/calendars/{calendar_id}/shares\x12r\n" +
+	"\x12ListCalendarEvents\x12\x1c.event.CalendarEventsRequest\x1a\x15.event.EventsResponse\"'\x82\xd3\xe4\x93\x02!\x12\x1f/calendars/{calendar_id}/events\x12Z\n" +
+	"\x0eGetWorkingDays\x12\x19.event.WorkingDaysRequest\x1a\x1a.event.WorkingDaysResponse\"\x11\x82\xd3\xe4\x93\x02\v\x12\t/workdays\x12j\n" +
+	"\x11BatchCreateEvents\x12\x1f.event.BatchCreateEventsRequest\x1a\x14.event.BatchResponse\"\x1e\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/events:batchCreate\x12j\n" +
+	"\x11BatchDeleteEvents\x12\x1f.event.BatchDeleteEventsRequest\x1a\x14.event.BatchResponse\"\x1e\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/events:batchDelete\x12R\n" +
+	"\rCreateWebhook\x12\x15.event.WebhookRequest\x1a\x0e.event.Webhook\"\x1a\x82\xd3\xe4\x93\x02\x14:\awebhook\"\t/webhooks\x12H\n" +
+	"\fListWebhooks\x12\f.event.Empty\x1a\x17.event.WebhooksResponse\"\x11\x82\xd3\xe4\x93\x02\v\x12\t/webhooks\x12N\n" +
+	"\rDeleteWebhook\x12\x17.event.WebhookIDRequest\x1a\f.event.Empty\"\x16\x82\xd3\xe4\x93\x02\x10*\x0e/webhooks/{id}\x12W\n" +
+	"\rEnableWebhook\x12\x17.event.WebhookIDRequest\x1a\x0e.event.Webhook\"\x1d\x82\xd3\xe4\x93\x02\x17\"\x15/webhooks/{id}:enable\x12n\n" +
+	"\x15ListWebhookDeliveries\x12\x17.event.WebhookIDRequest\x1a\x19.event.DeliveriesResponse\"!\x82\xd3\xe4\x93\x02\x1b\x12\x19/webhooks/{id}/deliveries\x12p\n" +
+	"\x0fListAttachments\x12\x19.event.AttachmentsRequest\x1a\x1a.event.AttachmentsResponse\"&\x82\xd3\xe4\x93\x02 \x12\x1e/events/{event_id}/attachments\x12U\n" +
+	"\aAddLink\x12\x12.event.LinkRequest\x1a\x11.event.Attachment\"#\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/events/{event_id}/links\x12W\n" +
+	"\x10DeleteAttachment\x12\x1a.event.AttachmentIDRequest\x1a\f.event.Empty\"\x19\x82\xd3\xe4\x93\x02\x13*\x11/attachments/{id}B\rZ\vcalendarpb/b\x06proto3"
 
 var (
 	file_api_EventService_proto_rawDescOnce sync.Once
@@ -369,41 +3560,193 @@ func file_api_EventService_proto_rawDescGZIP() []byte {
 	return file_api_EventService_proto_rawDescData
 }
 
-var file_api_EventService_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_api_EventService_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_api_EventService_proto_msgTypes = make([]protoimpl.MessageInfo, 52)
 var file_api_EventService_proto_goTypes = []any{
-	(*Event)(nil),                 // 0: event.Event
-	(*Empty)(nil),                 // 1: event.Empty
-	(*EventRequest)(nil),          // 2: event.EventRequest
-	(*DeleteRequest)(nil),         // 3: event.DeleteRequest
-	(*EventsResponse)(nil),        // 4: event.EventsResponse
-	(*DateRequest)(nil),           // 5: event.DateRequest
-	(*timestamppb.Timestamp)(nil), // 6: google.protobuf.Timestamp
+	(RSVPStatus)(0),                  // 0: event.RSVPStatus
+	(Permission)(0),                  // 1: event.Permission
+	(*Event)(nil),                    // 2: event.Event
+	(*Attendee)(nil),                 // 3: event.Attendee
+	(*Calendar)(nil),                 // 4: event.Calendar
+	(*CalendarShare)(nil),            // 5: event.CalendarShare
+	(*Empty)(nil),                    // 6: event.Empty
+	(*EventRequest)(nil),             // 7: event.EventRequest
+	(*AddEventResponse)(nil),         // 8: event.AddEventResponse
+	(*DeleteRequest)(nil),            // 9: event.DeleteRequest
+	(*EventsResponse)(nil),           // 10: event.EventsResponse
+	(*DateRequest)(nil),              // 11: event.DateRequest
+	(*GetEventRequest)(nil),          // 12: event.GetEventRequest
+	(*ListEventsRequest)(nil),        // 13: event.ListEventsRequest
+	(*UpcomingEventsRequest)(nil),    // 14: event.UpcomingEventsRequest
+	(*DeleteOldEventsRequest)(nil),   // 15: event.DeleteOldEventsRequest
+	(*BatchCreateEventsRequest)(nil), // 16: event.BatchCreateEventsRequest
+	(*BatchDeleteEventsRequest)(nil), // 17: event.BatchDeleteEventsRequest
+	(*InviteRequest)(nil),            // 18: event.InviteRequest
+	(*RespondRequest)(nil),           // 19: event.RespondRequest
+	(*FreeBusyRequest)(nil),          // 20: event.FreeBusyRequest
+	(*Interval)(nil),                 // 21: event.Interval
+	(*UserBusy)(nil),                 // 22: event.UserBusy
+	(*Slot)(nil),                     // 23: event.Slot
+	(*FreeBusyResponse)(nil),         // 24: event.FreeBusyResponse
+	(*CalendarRequest)(nil),          // 25: event.CalendarRequest
+	(*ListCalendarsRequest)(nil),     // 26: event.ListCalendarsRequest
+	(*CalendarsResponse)(nil),        // 27: event.CalendarsResponse
+	(*ShareCalendarRequest)(nil),     // 28: event.ShareCalendarRequest
+	(*CalendarEventsRequest)(nil),    // 29: event.CalendarEventsRequest
+	(*TrashRequest)(nil),             // 30: event.TrashRequest
+	(*RestoreRequest)(nil),           // 31: event.RestoreRequest
+	(*AuditRecord)(nil),              // 32: event.AuditRecord
+	(*EventHistoryResponse)(nil),     // 33: event.EventHistoryResponse
+	(*BatchResult)(nil),              // 34: event.BatchResult
+	(*BatchResponse)(nil),            // 35: event.BatchResponse
+	(*Webhook)(nil),                  // 36: event.Webhook
+	(*WebhookRequest)(nil),           // 37: event.WebhookRequest
+	(*WebhooksResponse)(nil),         // 38: event.WebhooksResponse
+	(*WebhookIDRequest)(nil),         // 39: event.WebhookIDRequest
+	(*Delivery)(nil),                 // 40: event.Delivery
+	(*DeliveriesResponse)(nil),       // 41: event.DeliveriesResponse
+	(*Attachment)(nil),               // 42: event.Attachment
+	(*AttachmentsRequest)(nil),       // 43: event.AttachmentsRequest
+	(*AttachmentsResponse)(nil),      // 44: event.AttachmentsResponse
+	(*LinkRequest)(nil),              // 45: event.LinkRequest
+	(*AttachmentIDRequest)(nil),      // 46: event.AttachmentIDRequest
+	(*RecurringEventRequest)(nil),    // 47: event.RecurringEventRequest
+	(*RecurringEventResponse)(nil),   // 48: event.RecurringEventResponse
+	(*QuickAddRequest)(nil),          // 49: event.QuickAddRequest
+	(*QuickAddResponse)(nil),         // 50: event.QuickAddResponse
+	(*WorkingDaysRequest)(nil),       // 51: event.WorkingDaysRequest
+	(*WorkingDay)(nil),               // 52: event.WorkingDay
+	(*WorkingDaysResponse)(nil),      // 53: event.WorkingDaysResponse
+	(*timestamppb.Timestamp)(nil),    // 54: google.protobuf.Timestamp
 }
 var file_api_EventService_proto_depIdxs = []int32{
-	6,  // 0: event.Event.start_at:type_name -> google.protobuf.Timestamp
-	0,  // 1: event.EventRequest.event:type_name -> event.Event
-	6,  // 2: event.DeleteRequest.start:type_name -> google.protobuf.Timestamp
-	0,  // 3: event.EventsResponse.events:type_name -> event.Event
-	6,  // 4: event.DateRequest.date:type_name -> google.protobuf.Timestamp
-	2,  // 5: event.CalendarService.AddEvent:input_type -> event.EventRequest
-	2,  // 6: event.CalendarService.UpdateEvent:input_type -> event.EventRequest
-	3,  // 7: event.CalendarService.DeleteEvent:input_type -> event.DeleteRequest
-	1,  // 8: event.CalendarService.GetEvents:input_type -> event.Empty
-	5,  // 9: event.CalendarService.GetEventsByDay:input_type -> event.DateRequest
-	5,  // 10: event.CalendarService.GetEventsByWeek:input_type -> event.DateRequest
-	5,  // 11: event.CalendarService.GetEventsByMonth:input_type -> event.DateRequest
-	1,  // 12: event.CalendarService.AddEvent:output_type -> event.Empty
-	1,  // 13: event.CalendarService.UpdateEvent:output_type -> event.Empty
-	1,  // 14: event.CalendarService.DeleteEvent:output_type -> event.Empty
-	4,  // 15: event.CalendarService.GetEvents:output_type -> event.EventsResponse
-	4,  // 16: event.CalendarService.GetEventsByDay:output_type -> event.EventsResponse
-	4,  // 17: event.CalendarService.GetEventsByWeek:output_type -> event.EventsResponse
-	4,  // 18: event.CalendarService.GetEventsByMonth:output_type -> event.EventsResponse
-	12, // [12:19] is the sub-list for method output_type
-	5,  // [5:12] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+	54, // 0: event.Event.start_at:type_name -> google.protobuf.Timestamp
+	54, // 1: event.Event.created_at:type_name -> google.protobuf.Timestamp
+	3,  // 2: event.Event.attendees:type_name -> event.Attendee
+	54, // 3: event.Event.deleted_at:type_name -> google.protobuf.Timestamp
+	0,  // 4: event.Attendee.status:type_name -> event.RSVPStatus
+	54, // 5: event.Calendar.created_at:type_name -> google.protobuf.Timestamp
+	5,  // 6: event.Calendar.shares:type_name -> event.CalendarShare
+	1,  // 7: event.CalendarShare.permission:type_name -> event.Permission
+	2,  // 8: event.EventRequest.event:type_name -> event.Event
+	54, // 9: event.DeleteRequest.start:type_name -> google.protobuf.Timestamp
+	2,  // 10: event.EventsResponse.events:type_name -> event.Event
+	54, // 11: event.DateRequest.date:type_name -> google.protobuf.Timestamp
+	54, // 12: event.ListEventsRequest.from:type_name -> google.protobuf.Timestamp
+	54, // 13: event.ListEventsRequest.to:type_name -> google.protobuf.Timestamp
+	54, // 14: event.UpcomingEventsRequest.from:type_name -> google.protobuf.Timestamp
+	54, // 15: event.DeleteOldEventsRequest.before:type_name -> google.protobuf.Timestamp
+	2,  // 16: event.BatchCreateEventsRequest.events:type_name -> event.Event
+	0,  // 17: event.RespondRequest.status:type_name -> event.RSVPStatus
+	54, // 18: event.FreeBusyRequest.from:type_name -> google.protobuf.Timestamp
+	54, // 19: event.FreeBusyRequest.to:type_name -> google.protobuf.Timestamp
+	54, // 20: event.Interval.start:type_name -> google.protobuf.Timestamp
+	54, // 21: event.Interval.end:type_name -> google.protobuf.Timestamp
+	21, // 22: event.UserBusy.busy:type_name -> event.Interval
+	54, // 23: event.Slot.start:type_name -> google.protobuf.Timestamp
+	54, // 24: event.Slot.end:type_name -> google.protobuf.Timestamp
+	22, // 25: event.FreeBusyResponse.busy:type_name -> event.UserBusy
+	23, // 26: event.FreeBusyResponse.slots:type_name -> event.Slot
+	4,  // 27: event.CalendarRequest.calendar:type_name -> event.Calendar
+	4,  // 28: event.CalendarsResponse.calendars:type_name -> event.Calendar
+	1,  // 29: event.ShareCalendarRequest.permission:type_name -> event.Permission
+	54, // 30: event.CalendarEventsRequest.from:type_name -> google.protobuf.Timestamp
+	54, // 31: event.CalendarEventsRequest.to:type_name -> google.protobuf.Timestamp
+	2,  // 32: event.AuditRecord.before:type_name -> event.Event
+	2,  // 33: event.AuditRecord.after:type_name -> event.Event
+	54, // 34: event.AuditRecord.at:type_name -> google.protobuf.Timestamp
+	32, // 35: event.EventHistoryResponse.records:type_name -> event.AuditRecord
+	34, // 36: event.BatchResponse.results:type_name -> event.BatchResult
+	54, // 37: event.Webhook.disabled_at:type_name -> google.protobuf.Timestamp
+	54, // 38: event.Webhook.created_at:type_name -> google.protobuf.Timestamp
+	36, // 39: event.WebhookRequest.webhook:type_name -> event.Webhook
+	36, // 40: event.WebhooksResponse.webhooks:type_name -> event.Webhook
+	54, // 41: event.Delivery.next_attempt_at:type_name -> google.protobuf.Timestamp
+	54, // 42: event.Delivery.created_at:type_name -> google.protobuf.Timestamp
+	54, // 43: event.Delivery.delivered_at:type_name -> google.protobuf.Timestamp
+	40, // 44: event.DeliveriesResponse.deliveries:type_name -> event.Delivery
+	54, // 45: event.Attachment.created_at:type_name -> google.protobuf.Timestamp
+	42, // 46: event.AttachmentsResponse.attachments:type_name -> event.Attachment
+	2,  // 47: event.RecurringEventRequest.event:type_name -> event.Event
+	2,  // 48: event.RecurringEventResponse.events:type_name -> event.Event
+	54, // 49: event.RecurringEventResponse.skipped:type_name -> google.protobuf.Timestamp
+	2,  // 50: event.QuickAddResponse.event:type_name -> event.Event
+	52, // 51: event.WorkingDaysResponse.days:type_name -> event.WorkingDay
+	7,  // 52: event.CalendarService.AddEvent:input_type -> event.EventRequest
+	47, // 53: event.CalendarService.AddRecurringEvent:input_type -> event.RecurringEventRequest
+	49, // 54: event.CalendarService.QuickAdd:input_type -> event.QuickAddRequest
+	7,  // 55: event.CalendarService.UpdateEvent:input_type -> event.EventRequest
+	9,  // 56: event.CalendarService.DeleteEvent:input_type -> event.DeleteRequest
+	15, // 57: event.CalendarService.DeleteOldEvents:input_type -> event.DeleteOldEventsRequest
+	30, // 58: event.CalendarService.ListTrash:input_type -> event.TrashRequest
+	31, // 59: event.CalendarService.RestoreEvent:input_type -> event.RestoreRequest
+	12, // 60: event.CalendarService.GetEvent:input_type -> event.GetEventRequest
+	12, // 61: event.CalendarService.GetEventHistory:input_type -> event.GetEventRequest
+	6,  // 62: event.CalendarService.GetEvents:input_type -> event.Empty
+	13, // 63: event.CalendarService.ListEvents:input_type -> event.ListEventsRequest
+	14, // 64: event.CalendarService.GetUpcomingEvents:input_type -> event.UpcomingEventsRequest
+	11, // 65: event.CalendarService.GetEventsByDay:input_type -> event.DateRequest
+	11, // 66: event.CalendarService.GetEventsByWeek:input_type -> event.DateRequest
+	11, // 67: event.CalendarService.GetEventsByMonth:input_type -> event.DateRequest
+	18, // 68: event.CalendarService.InviteAttendees:input_type -> event.InviteRequest
+	19, // 69: event.CalendarService.RespondToInvite:input_type -> event.RespondRequest
+	20, // 70: event.CalendarService.GetFreeBusy:input_type -> event.FreeBusyRequest
+	25, // 71: event.CalendarService.CreateCalendar:input_type -> event.CalendarRequest
+	25, // 72: event.CalendarService.UpdateCalendar:input_type -> event.CalendarRequest
+	26, // 73: event.CalendarService.ListCalendars:input_type -> event.ListCalendarsRequest
+	28, // 74: event.CalendarService.ShareCalendar:input_type -> event.ShareCalendarRequest
+	29, // 75: event.CalendarService.ListCalendarEvents:input_type -> event.CalendarEventsRequest
+	51, // 76: event.CalendarService.GetWorkingDays:input_type -> event.WorkingDaysRequest
+	16, // 77: event.CalendarService.BatchCreateEvents:input_type -> event.BatchCreateEventsRequest
+	17, // 78: event.CalendarService.BatchDeleteEvents:input_type -> event.BatchDeleteEventsRequest
+	37, // 79: event.CalendarService.CreateWebhook:input_type -> event.WebhookRequest
+	6,  // 80: event.CalendarService.ListWebhooks:input_type -> event.Empty
+	39, // 81: event.CalendarService.DeleteWebhook:input_type -> event.WebhookIDRequest
+	39, // 82: event.CalendarService.EnableWebhook:input_type -> event.WebhookIDRequest
+	39, // 83: event.CalendarService.ListWebhookDeliveries:input_type -> event.WebhookIDRequest
+	43, // 84: event.CalendarService.ListAttachments:input_type -> event.AttachmentsRequest
+	45, // 85: event.CalendarService.AddLink:input_type -> event.LinkRequest
+	46, // 86: event.CalendarService.DeleteAttachment:input_type -> event.AttachmentIDRequest
+	8,  // 87: event.CalendarService.AddEvent:output_type -> event.AddEventResponse
+	48, // 88: event.CalendarService.AddRecurringEvent:output_type -> event.RecurringEventResponse
+	50, // 89: event.CalendarService.QuickAdd:output_type -> event.QuickAddResponse
+	6,  // 90: event.CalendarService.UpdateEvent:output_type -> event.Empty
+	6,  // 91: event.CalendarService.DeleteEvent:output_type -> event.Empty
+	6,  // 92: event.CalendarService.DeleteOldEvents:output_type -> event.Empty
+	10, // 93: event.CalendarService.ListTrash:output_type -> event.EventsResponse
+	2,  // 94: event.CalendarService.RestoreEvent:output_type -> event.Event
+	2,  // 95: event.CalendarService.GetEvent:output_type -> event.Event
+	33, // 96: event.CalendarService.GetEventHistory:output_type -> event.EventHistoryResponse
+	10, // 97: event.CalendarService.GetEvents:output_type -> event.EventsResponse
+	10, // 98: event.CalendarService.ListEvents:output_type -> event.EventsResponse
+	10, // 99: event.CalendarService.GetUpcomingEvents:output_type -> event.EventsResponse
+	10, // 100: event.CalendarService.GetEventsByDay:output_type -> event.EventsResponse
+	10, // 101: event.CalendarService.GetEventsByWeek:output_type -> event.EventsResponse
+	10, // 102: event.CalendarService.GetEventsByMonth:output_type -> event.EventsResponse
+	2,  // 103: event.CalendarService.InviteAttendees:output_type -> event.Event
+	2,  // 104: event.CalendarService.RespondToInvite:output_type -> event.Event
+	24, // 105: event.CalendarService.GetFreeBusy:output_type -> event.FreeBusyResponse
+	4,  // 106: event.CalendarService.CreateCalendar:output_type -> event.Calendar
+	4,  // 107: event.CalendarService.UpdateCalendar:output_type -> event.Calendar
+	27, // 108: event.CalendarService.ListCalendars:output_type -> event.CalendarsResponse
+	4,  // 109: event.CalendarService.ShareCalendar:output_type -> event.Calendar
+	10, // 110: event.CalendarService.ListCalendarEvents:output_type -> event.EventsResponse
+	53, // 111: event.CalendarService.GetWorkingDays:output_type -> event.WorkingDaysResponse
+	35, // 112: event.CalendarService.BatchCreateEvents:output_type -> event.BatchResponse
+	35, // 113: event.CalendarService.BatchDeleteEvents:output_type -> event.BatchResponse
+	36, // 114: event.CalendarService.CreateWebhook:output_type -> event.Webhook
+	38, // 115: event.CalendarService.ListWebhooks:output_type -> event.WebhooksResponse
+	6,  // 116: event.CalendarService.DeleteWebhook:output_type -> event.Empty
+	36, // 117: event.CalendarService.EnableWebhook:output_type -> event.Webhook
+	41, // 118: event.CalendarService.ListWebhookDeliveries:output_type -> event.DeliveriesResponse
+	44, // 119: event.CalendarService.ListAttachments:output_type -> event.AttachmentsResponse
+	42, // 120: event.CalendarService.AddLink:output_type -> event.Attachment
+	6,  // 121: event.CalendarService.DeleteAttachment:output_type -> event.Empty
+	87, // [87:122] is the sub-list for method output_type
+	52, // [52:87] is the sub-list for method input_type
+	52, // [52:52] is the sub-list for extension type_name
+	52, // [52:52] is the sub-list for extension extendee
+	0,  // [0:52] is the sub-list for field type_name
 }
 
 func init() { file_api_EventService_proto_init() }
@@ -416,13 +3759,14 @@ func file_api_EventService_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_EventService_proto_rawDesc), len(file_api_EventService_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   6,
+			NumEnums:      2,
+			NumMessages:   52,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_api_EventService_proto_goTypes,
 		DependencyIndexes: file_api_EventService_proto_depIdxs,
+		EnumInfos:         file_api_EventService_proto_enumTypes,
 		MessageInfos:      file_api_EventService_proto_msgTypes,
 	}.Build()
 	File_api_EventService_proto = out.File
@@ -19,26 +19,99 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	CalendarService_AddEvent_FullMethodName         = "/event.CalendarService/AddEvent"
-	CalendarService_UpdateEvent_FullMethodName      = "/event.CalendarService/UpdateEvent"
-	CalendarService_DeleteEvent_FullMethodName      = "/event.CalendarService/DeleteEvent"
-	CalendarService_GetEvents_FullMethodName        = "/event.CalendarService/GetEvents"
-	CalendarService_GetEventsByDay_FullMethodName   = "/event.CalendarService/GetEventsByDay"
-	CalendarService_GetEventsByWeek_FullMethodName  = "/event.CalendarService/GetEventsByWeek"
-	CalendarService_GetEventsByMonth_FullMethodName = "/event.CalendarService/GetEventsByMonth"
+	CalendarService_AddEvent_FullMethodName              = "/event.CalendarService/AddEvent"
+	CalendarService_AddRecurringEvent_FullMethodName     = "/event.CalendarService/AddRecurringEvent"
+	CalendarService_QuickAdd_FullMethodName              = "/event.CalendarService/QuickAdd"
+	CalendarService_UpdateEvent_FullMethodName           = "/event.CalendarService/UpdateEvent"
+	CalendarService_DeleteEvent_FullMethodName           = "/event.CalendarService/DeleteEvent"
+	CalendarService_DeleteOldEvents_FullMethodName       = "/event.CalendarService/DeleteOldEvents"
+	CalendarService_ListTrash_FullMethodName             = "/event.CalendarService/ListTrash"
+	CalendarService_RestoreEvent_FullMethodName          = "/event.CalendarService/RestoreEvent"
+	CalendarService_GetEvent_FullMethodName              = "/event.CalendarService/GetEvent"
+	CalendarService_GetEventHistory_FullMethodName       = "/event.CalendarService/GetEventHistory"
+	CalendarService_GetEvents_FullMethodName             = "/event.CalendarService/GetEvents"
+	CalendarService_ListEvents_FullMethodName            = "/event.CalendarService/ListEvents"
+	CalendarService_GetUpcomingEvents_FullMethodName     = "/event.CalendarService/GetUpcomingEvents"
+	CalendarService_GetEventsByDay_FullMethodName        = "/event.CalendarService/GetEventsByDay"
+	CalendarService_GetEventsByWeek_FullMethodName       = "/event.CalendarService/GetEventsByWeek"
+	CalendarService_GetEventsByMonth_FullMethodName      = "/event.CalendarService/GetEventsByMonth"
+	CalendarService_InviteAttendees_FullMethodName       = "/event.CalendarService/InviteAttendees"
+	CalendarService_RespondToInvite_FullMethodName       = "/event.CalendarService/RespondToInvite"
+	CalendarService_GetFreeBusy_FullMethodName           = "/event.CalendarService/GetFreeBusy"
+	CalendarService_CreateCalendar_FullMethodName        = "/event.CalendarService/CreateCalendar"
+	CalendarService_UpdateCalendar_FullMethodName        = "/event.CalendarService/UpdateCalendar"
+	CalendarService_ListCalendars_FullMethodName         = "/event.CalendarService/ListCalendars"
+	CalendarService_ShareCalendar_FullMethodName         = "/event.CalendarService/ShareCalendar"
+	CalendarService_ListCalendarEvents_FullMethodName    = "/event.CalendarService/ListCalendarEvents"
+	CalendarService_GetWorkingDays_FullMethodName        = "/event.CalendarService/GetWorkingDays"
+	CalendarService_BatchCreateEvents_FullMethodName     = "/event.CalendarService/BatchCreateEvents"
+	CalendarService_BatchDeleteEvents_FullMethodName     = "/event.CalendarService/BatchDeleteEvents"
+	CalendarService_CreateWebhook_FullMethodName         = "/event.CalendarService/CreateWebhook"
+	CalendarService_ListWebhooks_FullMethodName          = "/event.CalendarService/ListWebhooks"
+	CalendarService_DeleteWebhook_FullMethodName         = "/event.CalendarService/DeleteWebhook"
+	CalendarService_EnableWebhook_FullMethodName         = "/event.CalendarService/EnableWebhook"
+	CalendarService_ListWebhookDeliveries_FullMethodName = "/event.CalendarService/ListWebhookDeliveries"
+	CalendarService_ListAttachments_FullMethodName       = "/event.CalendarService/ListAttachments"
+	CalendarService_AddLink_FullMethodName               = "/event.CalendarService/AddLink"
+	CalendarService_DeleteAttachment_FullMethodName      = "/event.CalendarService/DeleteAttachment"
 )
 
 // CalendarServiceClient is the client API for CalendarService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// REST-маршруты HTTP API описаны аннотациями google.api.http и обслуживаются
+// grpc-gateway в том же процессе, что и gRPC.
 type CalendarServiceClient interface {
-	AddEvent(ctx context.Context, in *EventRequest, opts ...grpc.CallOption) (*Empty, error)
+	AddEvent(ctx context.Context, in *EventRequest, opts ...grpc.CallOption) (*AddEventResponse, error)
+	// Создаёт серию повторяющихся событий.
+	AddRecurringEvent(ctx context.Context, in *RecurringEventRequest, opts ...grpc.CallOption) (*RecurringEventResponse, error)
+	// Создаёт событие пользователя x-user-id из строки на естественном языке.
+	QuickAdd(ctx context.Context, in *QuickAddRequest, opts ...grpc.CallOption) (*QuickAddResponse, error)
 	UpdateEvent(ctx context.Context, in *EventRequest, opts ...grpc.CallOption) (*Empty, error)
 	DeleteEvent(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error)
+	// Переносит в корзину события пользователя из x-user-id, начавшиеся до before.
+	DeleteOldEvents(ctx context.Context, in *DeleteOldEventsRequest, opts ...grpc.CallOption) (*Empty, error)
+	// Удалённые события лежат в корзине, пока планировщик не очистит её
+	// по сроку scheduler.cleanupOlderThanDays.
+	ListTrash(ctx context.Context, in *TrashRequest, opts ...grpc.CallOption) (*EventsResponse, error)
+	RestoreEvent(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*Event, error)
+	GetEvent(ctx context.Context, in *GetEventRequest, opts ...grpc.CallOption) (*Event, error)
+	// Все события; в HTTP API вместо него GET /events (ListEvents без фильтров).
+	GetEventHistory(ctx context.Context, in *GetEventRequest, opts ...grpc.CallOption) (*EventHistoryResponse, error)
 	GetEvents(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*EventsResponse, error)
+	ListEvents(ctx context.Context, in *ListEventsRequest, opts ...grpc.CallOption) (*EventsResponse, error)
+	GetUpcomingEvents(ctx context.Context, in *UpcomingEventsRequest, opts ...grpc.CallOption) (*EventsResponse, error)
 	GetEventsByDay(ctx context.Context, in *DateRequest, opts ...grpc.CallOption) (*EventsResponse, error)
 	GetEventsByWeek(ctx context.Context, in *DateRequest, opts ...grpc.CallOption) (*EventsResponse, error)
 	GetEventsByMonth(ctx context.Context, in *DateRequest, opts ...grpc.CallOption) (*EventsResponse, error)
+	InviteAttendees(ctx context.Context, in *InviteRequest, opts ...grpc.CallOption) (*Event, error)
+	RespondToInvite(ctx context.Context, in *RespondRequest, opts ...grpc.CallOption) (*Event, error)
+	GetFreeBusy(ctx context.Context, in *FreeBusyRequest, opts ...grpc.CallOption) (*FreeBusyResponse, error)
+	CreateCalendar(ctx context.Context, in *CalendarRequest, opts ...grpc.CallOption) (*Calendar, error)
+	// Меняет календарь владельца x-user-id; shares и is_default игнорируются.
+	UpdateCalendar(ctx context.Context, in *CalendarRequest, opts ...grpc.CallOption) (*Calendar, error)
+	ListCalendars(ctx context.Context, in *ListCalendarsRequest, opts ...grpc.CallOption) (*CalendarsResponse, error)
+	ShareCalendar(ctx context.Context, in *ShareCalendarRequest, opts ...grpc.CallOption) (*Calendar, error)
+	ListCalendarEvents(ctx context.Context, in *CalendarEventsRequest, opts ...grpc.CallOption) (*EventsResponse, error)
+	GetWorkingDays(ctx context.Context, in *WorkingDaysRequest, opts ...grpc.CallOption) (*WorkingDaysResponse, error)
+	BatchCreateEvents(ctx context.Context, in *BatchCreateEventsRequest, opts ...grpc.CallOption) (*BatchResponse, error)
+	// Удалить можно своё событие или событие календаря с правом записи,
+	// иначе в результате по этому ID - ошибка доступа.
+	BatchDeleteEvents(ctx context.Context, in *BatchDeleteEventsRequest, opts ...grpc.CallOption) (*BatchResponse, error)
+	CreateWebhook(ctx context.Context, in *WebhookRequest, opts ...grpc.CallOption) (*Webhook, error)
+	ListWebhooks(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*WebhooksResponse, error)
+	DeleteWebhook(ctx context.Context, in *WebhookIDRequest, opts ...grpc.CallOption) (*Empty, error)
+	// Включает подписку, выключенную после неудачных доставок.
+	EnableWebhook(ctx context.Context, in *WebhookIDRequest, opts ...grpc.CallOption) (*Webhook, error)
+	// Последние доставки подписки, новые первыми.
+	ListWebhookDeliveries(ctx context.Context, in *WebhookIDRequest, opts ...grpc.CallOption) (*DeliveriesResponse, error)
+	// Вложения события видны тем, кто видит событие, менять их может владелец
+	// и пользователи с правом записи в календарь события.
+	ListAttachments(ctx context.Context, in *AttachmentsRequest, opts ...grpc.CallOption) (*AttachmentsResponse, error)
+	AddLink(ctx context.Context, in *LinkRequest, opts ...grpc.CallOption) (*Attachment, error)
+	// Удаляет вложение вместе с содержимым файла.
+	DeleteAttachment(ctx context.Context, in *AttachmentIDRequest, opts ...grpc.CallOption) (*Empty, error)
 }
 
 type calendarServiceClient struct {
@@ -49,9 +122,9 @@ func NewCalendarServiceClient(cc grpc.ClientConnInterface) CalendarServiceClient
 	return &calendarServiceClient{cc}
 }
 
-func (c *calendarServiceClient) AddEvent(ctx context.Context, in *EventRequest, opts ...grpc.CallOption) (*Empty, error) {
+func (c *calendarServiceClient) AddEvent(ctx context.Context, in *EventRequest, opts ...grpc.CallOption) (*AddEventResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(Empty)
+	out := new(AddEventResponse)
 	err := c.cc.Invoke(ctx, CalendarService_AddEvent_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
@@ -59,6 +132,26 @@ func (c *calendarServiceClient) AddEvent(ctx context.Context, in *EventRequest,
 	return out, nil
 }
 
+func (c *calendarServiceClient) AddRecurringEvent(ctx context.Context, in *RecurringEventRequest, opts ...grpc.CallOption) (*RecurringEventResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecurringEventResponse)
+	err := c.cc.Invoke(ctx, CalendarService_AddRecurringEvent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) QuickAdd(ctx context.Context, in *QuickAddRequest, opts ...grpc.CallOption) (*QuickAddResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QuickAddResponse)
+	err := c.cc.Invoke(ctx, CalendarService_QuickAdd_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *calendarServiceClient) UpdateEvent(ctx context.Context, in *EventRequest, opts ...grpc.CallOption) (*Empty, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(Empty)
@@ -79,6 +172,56 @@ func (c *calendarServiceClient) DeleteEvent(ctx context.Context, in *DeleteReque
 	return out, nil
 }
 
+func (c *calendarServiceClient) DeleteOldEvents(ctx context.Context, in *DeleteOldEventsRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, CalendarService_DeleteOldEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) ListTrash(ctx context.Context, in *TrashRequest, opts ...grpc.CallOption) (*EventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EventsResponse)
+	err := c.cc.Invoke(ctx, CalendarService_ListTrash_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) RestoreEvent(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*Event, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Event)
+	err := c.cc.Invoke(ctx, CalendarService_RestoreEvent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) GetEvent(ctx context.Context, in *GetEventRequest, opts ...grpc.CallOption) (*Event, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Event)
+	err := c.cc.Invoke(ctx, CalendarService_GetEvent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) GetEventHistory(ctx context.Context, in *GetEventRequest, opts ...grpc.CallOption) (*EventHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EventHistoryResponse)
+	err := c.cc.Invoke(ctx, CalendarService_GetEventHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *calendarServiceClient) GetEvents(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*EventsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(EventsResponse)
@@ -89,6 +232,26 @@ func (c *calendarServiceClient) GetEvents(ctx context.Context, in *Empty, opts .
 	return out, nil
 }
 
+func (c *calendarServiceClient) ListEvents(ctx context.Context, in *ListEventsRequest, opts ...grpc.CallOption) (*EventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EventsResponse)
+	err := c.cc.Invoke(ctx, CalendarService_ListEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) GetUpcomingEvents(ctx context.Context, in *UpcomingEventsRequest, opts ...grpc.CallOption) (*EventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EventsResponse)
+	err := c.cc.Invoke(ctx, CalendarService_GetUpcomingEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *calendarServiceClient) GetEventsByDay(ctx context.Context, in *DateRequest, opts ...grpc.CallOption) (*EventsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(EventsResponse)
@@ -119,17 +282,252 @@ func (c *calendarServiceClient) GetEventsByMonth(ctx context.Context, in *DateRe
 	return out, nil
 }
 
+func (c *calendarServiceClient) InviteAttendees(ctx context.Context, in *InviteRequest, opts ...grpc.CallOption) (*Event, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Event)
+	err := c.cc.Invoke(ctx, CalendarService_InviteAttendees_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) RespondToInvite(ctx context.Context, in *RespondRequest, opts ...grpc.CallOption) (*Event, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Event)
+	err := c.cc.Invoke(ctx, CalendarService_RespondToInvite_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) GetFreeBusy(ctx context.Context, in *FreeBusyRequest, opts ...grpc.CallOption) (*FreeBusyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FreeBusyResponse)
+	err := c.cc.Invoke(ctx, CalendarService_GetFreeBusy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) CreateCalendar(ctx context.Context, in *CalendarRequest, opts ...grpc.CallOption) (*Calendar, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Calendar)
+	err := c.cc.Invoke(ctx, CalendarService_CreateCalendar_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) UpdateCalendar(ctx context.Context, in *CalendarRequest, opts ...grpc.CallOption) (*Calendar, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Calendar)
+	err := c.cc.Invoke(ctx, CalendarService_UpdateCalendar_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) ListCalendars(ctx context.Context, in *ListCalendarsRequest, opts ...grpc.CallOption) (*CalendarsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CalendarsResponse)
+	err := c.cc.Invoke(ctx, CalendarService_ListCalendars_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) ShareCalendar(ctx context.Context, in *ShareCalendarRequest, opts ...grpc.CallOption) (*Calendar, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Calendar)
+	err := c.cc.Invoke(ctx, CalendarService_ShareCalendar_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) ListCalendarEvents(ctx context.Context, in *CalendarEventsRequest, opts ...grpc.CallOption) (*EventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EventsResponse)
+	err := c.cc.Invoke(ctx, CalendarService_ListCalendarEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) GetWorkingDays(ctx context.Context, in *WorkingDaysRequest, opts ...grpc.CallOption) (*WorkingDaysResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WorkingDaysResponse)
+	err := c.cc.Invoke(ctx, CalendarService_GetWorkingDays_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) BatchCreateEvents(ctx context.Context, in *BatchCreateEventsRequest, opts ...grpc.CallOption) (*BatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchResponse)
+	err := c.cc.Invoke(ctx, CalendarService_BatchCreateEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) BatchDeleteEvents(ctx context.Context, in *BatchDeleteEventsRequest, opts ...grpc.CallOption) (*BatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchResponse)
+	err := c.cc.Invoke(ctx, CalendarService_BatchDeleteEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) CreateWebhook(ctx context.Context, in *WebhookRequest, opts ...grpc.CallOption) (*Webhook, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Webhook)
+	err := c.cc.Invoke(ctx, CalendarService_CreateWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) ListWebhooks(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*WebhooksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WebhooksResponse)
+	err := c.cc.Invoke(ctx, CalendarService_ListWebhooks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) DeleteWebhook(ctx context.Context, in *WebhookIDRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, CalendarService_DeleteWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) EnableWebhook(ctx context.Context, in *WebhookIDRequest, opts ...grpc.CallOption) (*Webhook, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Webhook)
+	err := c.cc.Invoke(ctx, CalendarService_EnableWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) ListWebhookDeliveries(ctx context.Context, in *WebhookIDRequest, opts ...grpc.CallOption) (*DeliveriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeliveriesResponse)
+	err := c.cc.Invoke(ctx, CalendarService_ListWebhookDeliveries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) ListAttachments(ctx context.Context, in *AttachmentsRequest, opts ...grpc.CallOption) (*AttachmentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AttachmentsResponse)
+	err := c.cc.Invoke(ctx, CalendarService_ListAttachments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) AddLink(ctx context.Context, in *LinkRequest, opts ...grpc.CallOption) (*Attachment, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Attachment)
+	err := c.cc.Invoke(ctx, CalendarService_AddLink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarServiceClient) DeleteAttachment(ctx context.Context, in *AttachmentIDRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, CalendarService_DeleteAttachment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CalendarServiceServer is the server API for CalendarService service.
 // All implementations must embed UnimplementedCalendarServiceServer
 // for forward compatibility.
+//
+// REST-маршруты HTTP API описаны аннотациями google.api.http и обслуживаются
+// grpc-gateway в том же процессе, что и gRPC.
 type CalendarServiceServer interface {
-	AddEvent(context.Context, *EventRequest) (*Empty, error)
+	AddEvent(context.Context, *EventRequest) (*AddEventResponse, error)
+	// Создаёт серию повторяющихся событий.
+	AddRecurringEvent(context.Context, *RecurringEventRequest) (*RecurringEventResponse, error)
+	// Создаёт событие пользователя x-user-id из строки на естественном языке.
+	QuickAdd(context.Context, *QuickAddRequest) (*QuickAddResponse, error)
 	UpdateEvent(context.Context, *EventRequest) (*Empty, error)
 	DeleteEvent(context.Context, *DeleteRequest) (*Empty, error)
+	// Переносит в корзину события пользователя из x-user-id, начавшиеся до before.
+	DeleteOldEvents(context.Context, *DeleteOldEventsRequest) (*Empty, error)
+	// Удалённые события лежат в корзине, пока планировщик не очистит её
+	// по сроку scheduler.cleanupOlderThanDays.
+	ListTrash(context.Context, *TrashRequest) (*EventsResponse, error)
+	RestoreEvent(context.Context, *RestoreRequest) (*Event, error)
+	GetEvent(context.Context, *GetEventRequest) (*Event, error)
+	// Все события; в HTTP API вместо него GET /events (ListEvents без фильтров).
+	GetEventHistory(context.Context, *GetEventRequest) (*EventHistoryResponse, error)
 	GetEvents(context.Context, *Empty) (*EventsResponse, error)
+	ListEvents(context.Context, *ListEventsRequest) (*EventsResponse, error)
+	GetUpcomingEvents(context.Context, *UpcomingEventsRequest) (*EventsResponse, error)
 	GetEventsByDay(context.Context, *DateRequest) (*EventsResponse, error)
 	GetEventsByWeek(context.Context, *DateRequest) (*EventsResponse, error)
 	GetEventsByMonth(context.Context, *DateRequest) (*EventsResponse, error)
+	InviteAttendees(context.Context, *InviteRequest) (*Event, error)
+	RespondToInvite(context.Context, *RespondRequest) (*Event, error)
+	GetFreeBusy(context.Context, *FreeBusyRequest) (*FreeBusyResponse, error)
+	CreateCalendar(context.Context, *CalendarRequest) (*Calendar, error)
+	// Меняет календарь владельца x-user-id; shares и is_default игнорируются.
+	UpdateCalendar(context.Context, *CalendarRequest) (*Calendar, error)
+	ListCalendars(context.Context, *ListCalendarsRequest) (*CalendarsResponse, error)
+	ShareCalendar(context.Context, *ShareCalendarRequest) (*Calendar, error)
+	ListCalendarEvents(context.Context, *CalendarEventsRequest) (*EventsResponse, error)
+	GetWorkingDays(context.Context, *WorkingDaysRequest) (*WorkingDaysResponse, error)
+	BatchCreateEvents(context.Context, *BatchCreateEventsRequest) (*BatchResponse, error)
+	// Удалить можно своё событие или событие календаря с правом записи,
+	// иначе в результате по этому ID - ошибка доступа.
+	BatchDeleteEvents(context.Context, *BatchDeleteEventsRequest) (*BatchResponse, error)
+	CreateWebhook(context.Context, *WebhookRequest) (*Webhook, error)
+	ListWebhooks(context.Context, *Empty) (*WebhooksResponse, error)
+	DeleteWebhook(context.Context, *WebhookIDRequest) (*Empty, error)
+	// Включает подписку, выключенную после неудачных доставок.
+	EnableWebhook(context.Context, *WebhookIDRequest) (*Webhook, error)
+	// Последние доставки подписки, новые первыми.
+	ListWebhookDeliveries(context.Context, *WebhookIDRequest) (*DeliveriesResponse, error)
+	// Вложения события видны тем, кто видит событие, менять их может владелец
+	// и пользователи с правом записи в календарь события.
+	ListAttachments(context.Context, *AttachmentsRequest) (*AttachmentsResponse, error)
+	AddLink(context.Context, *LinkRequest) (*Attachment, error)
+	// Удаляет вложение вместе с содержимым файла.
+	DeleteAttachment(context.Context, *AttachmentIDRequest) (*Empty, error)
 	mustEmbedUnimplementedCalendarServiceServer()
 }
 
@@ -140,18 +538,45 @@ type CalendarServiceServer interface {
 // pointer dereference when methods are called.
 type UnimplementedCalendarServiceServer struct{}
 
-func (UnimplementedCalendarServiceServer) AddEvent(context.Context, *EventRequest) (*Empty, error) {
+func (UnimplementedCalendarServiceServer) AddEvent(context.Context, *EventRequest) (*AddEventResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AddEvent not implemented")
 }
+func (UnimplementedCalendarServiceServer) AddRecurringEvent(context.Context, *RecurringEventRequest) (*RecurringEventResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddRecurringEvent not implemented")
+}
+func (UnimplementedCalendarServiceServer) QuickAdd(context.Context, *QuickAddRequest) (*QuickAddResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QuickAdd not implemented")
+}
 func (UnimplementedCalendarServiceServer) UpdateEvent(context.Context, *EventRequest) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateEvent not implemented")
 }
 func (UnimplementedCalendarServiceServer) DeleteEvent(context.Context, *DeleteRequest) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteEvent not implemented")
 }
+func (UnimplementedCalendarServiceServer) DeleteOldEvents(context.Context, *DeleteOldEventsRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteOldEvents not implemented")
+}
+func (UnimplementedCalendarServiceServer) ListTrash(context.Context, *TrashRequest) (*EventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTrash not implemented")
+}
+func (UnimplementedCalendarServiceServer) RestoreEvent(context.Context, *RestoreRequest) (*Event, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreEvent not implemented")
+}
+func (UnimplementedCalendarServiceServer) GetEvent(context.Context, *GetEventRequest) (*Event, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEvent not implemented")
+}
+func (UnimplementedCalendarServiceServer) GetEventHistory(context.Context, *GetEventRequest) (*EventHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEventHistory not implemented")
+}
 func (UnimplementedCalendarServiceServer) GetEvents(context.Context, *Empty) (*EventsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetEvents not implemented")
 }
+func (UnimplementedCalendarServiceServer) ListEvents(context.Context, *ListEventsRequest) (*EventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEvents not implemented")
+}
+func (UnimplementedCalendarServiceServer) GetUpcomingEvents(context.Context, *UpcomingEventsRequest) (*EventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUpcomingEvents not implemented")
+}
 func (UnimplementedCalendarServiceServer) GetEventsByDay(context.Context, *DateRequest) (*EventsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetEventsByDay not implemented")
 }
@@ -161,6 +586,63 @@ func (UnimplementedCalendarServiceServer) GetEventsByWeek(context.Context, *Date
 func (UnimplementedCalendarServiceServer) GetEventsByMonth(context.Context, *DateRequest) (*EventsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetEventsByMonth not implemented")
 }
+func (UnimplementedCalendarServiceServer) InviteAttendees(context.Context, *InviteRequest) (*Event, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InviteAttendees not implemented")
+}
+func (UnimplementedCalendarServiceServer) RespondToInvite(context.Context, *RespondRequest) (*Event, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RespondToInvite not implemented")
+}
+func (UnimplementedCalendarServiceServer) GetFreeBusy(context.Context, *FreeBusyRequest) (*FreeBusyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFreeBusy not implemented")
+}
+func (UnimplementedCalendarServiceServer) CreateCalendar(context.Context, *CalendarRequest) (*Calendar, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCalendar not implemented")
+}
+func (UnimplementedCalendarServiceServer) UpdateCalendar(context.Context, *CalendarRequest) (*Calendar, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCalendar not implemented")
+}
+func (UnimplementedCalendarServiceServer) ListCalendars(context.Context, *ListCalendarsRequest) (*CalendarsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCalendars not implemented")
+}
+func (UnimplementedCalendarServiceServer) ShareCalendar(context.Context, *ShareCalendarRequest) (*Calendar, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ShareCalendar not implemented")
+}
+func (UnimplementedCalendarServiceServer) ListCalendarEvents(context.Context, *CalendarEventsRequest) (*EventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCalendarEvents not implemented")
+}
+func (UnimplementedCalendarServiceServer) GetWorkingDays(context.Context, *WorkingDaysRequest) (*WorkingDaysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWorkingDays not implemented")
+}
+func (UnimplementedCalendarServiceServer) BatchCreateEvents(context.Context, *BatchCreateEventsRequest) (*BatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchCreateEvents not implemented")
+}
+func (UnimplementedCalendarServiceServer) BatchDeleteEvents(context.Context, *BatchDeleteEventsRequest) (*BatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchDeleteEvents not implemented")
+}
+func (UnimplementedCalendarServiceServer) CreateWebhook(context.Context, *WebhookRequest) (*Webhook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWebhook not implemented")
+}
+func (UnimplementedCalendarServiceServer) ListWebhooks(context.Context, *Empty) (*WebhooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhooks not implemented")
+}
+func (UnimplementedCalendarServiceServer) DeleteWebhook(context.Context, *WebhookIDRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWebhook not implemented")
+}
+func (UnimplementedCalendarServiceServer) EnableWebhook(context.Context, *WebhookIDRequest) (*Webhook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnableWebhook not implemented")
+}
+func (UnimplementedCalendarServiceServer) ListWebhookDeliveries(context.Context, *WebhookIDRequest) (*DeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhookDeliveries not implemented")
+}
+func (UnimplementedCalendarServiceServer) ListAttachments(context.Context, *AttachmentsRequest) (*AttachmentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAttachments not implemented")
+}
+func (UnimplementedCalendarServiceServer) AddLink(context.Context, *LinkRequest) (*Attachment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddLink not implemented")
+}
+func (UnimplementedCalendarServiceServer) DeleteAttachment(context.Context, *AttachmentIDRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAttachment not implemented")
+}
 func (UnimplementedCalendarServiceServer) mustEmbedUnimplementedCalendarServiceServer() {}
 func (UnimplementedCalendarServiceServer) testEmbeddedByValue()                         {}
 
@@ -200,62 +682,224 @@ func _CalendarService_AddEvent_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
-func _CalendarService_UpdateEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(EventRequest)
+func _CalendarService_AddRecurringEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecurringEventRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(CalendarServiceServer).UpdateEvent(ctx, in)
+		return srv.(CalendarServiceServer).AddRecurringEvent(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: CalendarService_UpdateEvent_FullMethodName,
+		FullMethod: CalendarService_AddRecurringEvent_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CalendarServiceServer).UpdateEvent(ctx, req.(*EventRequest))
+		return srv.(CalendarServiceServer).AddRecurringEvent(ctx, req.(*RecurringEventRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _CalendarService_DeleteEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteRequest)
+func _CalendarService_QuickAdd_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuickAddRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(CalendarServiceServer).DeleteEvent(ctx, in)
+		return srv.(CalendarServiceServer).QuickAdd(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: CalendarService_DeleteEvent_FullMethodName,
+		FullMethod: CalendarService_QuickAdd_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CalendarServiceServer).DeleteEvent(ctx, req.(*DeleteRequest))
+		return srv.(CalendarServiceServer).QuickAdd(ctx, req.(*QuickAddRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _CalendarService_GetEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+func _CalendarService_UpdateEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EventRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(CalendarServiceServer).GetEvents(ctx, in)
+		return srv.(CalendarServiceServer).UpdateEvent(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: CalendarService_GetEvents_FullMethodName,
+		FullMethod: CalendarService_UpdateEvent_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CalendarServiceServer).GetEvents(ctx, req.(*Empty))
+		return srv.(CalendarServiceServer).UpdateEvent(ctx, req.(*EventRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _CalendarService_GetEventsByDay_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DateRequest)
+func _CalendarService_DeleteEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).DeleteEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_DeleteEvent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).DeleteEvent(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_DeleteOldEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteOldEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).DeleteOldEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_DeleteOldEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).DeleteOldEvents(ctx, req.(*DeleteOldEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_ListTrash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TrashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).ListTrash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_ListTrash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).ListTrash(ctx, req.(*TrashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_RestoreEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).RestoreEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_RestoreEvent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).RestoreEvent(ctx, req.(*RestoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_GetEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).GetEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_GetEvent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).GetEvent(ctx, req.(*GetEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_GetEventHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).GetEventHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_GetEventHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).GetEventHistory(ctx, req.(*GetEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_GetEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).GetEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_GetEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).GetEvents(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_ListEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).ListEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_ListEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).ListEvents(ctx, req.(*ListEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_GetUpcomingEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpcomingEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).GetUpcomingEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_GetUpcomingEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).GetUpcomingEvents(ctx, req.(*UpcomingEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_GetEventsByDay_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DateRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -308,6 +952,348 @@ func _CalendarService_GetEventsByMonth_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CalendarService_InviteAttendees_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InviteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).InviteAttendees(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_InviteAttendees_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).InviteAttendees(ctx, req.(*InviteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_RespondToInvite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RespondRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).RespondToInvite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_RespondToInvite_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).RespondToInvite(ctx, req.(*RespondRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_GetFreeBusy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FreeBusyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).GetFreeBusy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_GetFreeBusy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).GetFreeBusy(ctx, req.(*FreeBusyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_CreateCalendar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CalendarRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).CreateCalendar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_CreateCalendar_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).CreateCalendar(ctx, req.(*CalendarRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_UpdateCalendar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CalendarRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).UpdateCalendar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_UpdateCalendar_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).UpdateCalendar(ctx, req.(*CalendarRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_ListCalendars_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCalendarsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).ListCalendars(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_ListCalendars_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).ListCalendars(ctx, req.(*ListCalendarsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_ShareCalendar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShareCalendarRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).ShareCalendar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_ShareCalendar_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).ShareCalendar(ctx, req.(*ShareCalendarRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_ListCalendarEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CalendarEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).ListCalendarEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_ListCalendarEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).ListCalendarEvents(ctx, req.(*CalendarEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_GetWorkingDays_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkingDaysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).GetWorkingDays(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_GetWorkingDays_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).GetWorkingDays(ctx, req.(*WorkingDaysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_BatchCreateEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchCreateEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).BatchCreateEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_BatchCreateEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).BatchCreateEvents(ctx, req.(*BatchCreateEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_BatchDeleteEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchDeleteEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).BatchDeleteEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_BatchDeleteEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).BatchDeleteEvents(ctx, req.(*BatchDeleteEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_CreateWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).CreateWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_CreateWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).CreateWebhook(ctx, req.(*WebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_ListWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).ListWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_ListWebhooks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).ListWebhooks(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_DeleteWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WebhookIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).DeleteWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_DeleteWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).DeleteWebhook(ctx, req.(*WebhookIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_EnableWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WebhookIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).EnableWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_EnableWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).EnableWebhook(ctx, req.(*WebhookIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_ListWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WebhookIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).ListWebhookDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_ListWebhookDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).ListWebhookDeliveries(ctx, req.(*WebhookIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_ListAttachments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AttachmentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).ListAttachments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_ListAttachments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).ListAttachments(ctx, req.(*AttachmentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_AddLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).AddLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_AddLink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).AddLink(ctx, req.(*LinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalendarService_DeleteAttachment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AttachmentIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServiceServer).DeleteAttachment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalendarService_DeleteAttachment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServiceServer).DeleteAttachment(ctx, req.(*AttachmentIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CalendarService_ServiceDesc is the grpc.ServiceDesc for CalendarService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -319,6 +1305,14 @@ var CalendarService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "AddEvent",
 			Handler:    _CalendarService_AddEvent_Handler,
 		},
+		{
+			MethodName: "AddRecurringEvent",
+			Handler:    _CalendarService_AddRecurringEvent_Handler,
+		},
+		{
+			MethodName: "QuickAdd",
+			Handler:    _CalendarService_QuickAdd_Handler,
+		},
 		{
 			MethodName: "UpdateEvent",
 			Handler:    _CalendarService_UpdateEvent_Handler,
@@ -327,10 +1321,38 @@ var CalendarService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteEvent",
 			Handler:    _CalendarService_DeleteEvent_Handler,
 		},
+		{
+			MethodName: "DeleteOldEvents",
+			Handler:    _CalendarService_DeleteOldEvents_Handler,
+		},
+		{
+			MethodName: "ListTrash",
+			Handler:    _CalendarService_ListTrash_Handler,
+		},
+		{
+			MethodName: "RestoreEvent",
+			Handler:    _CalendarService_RestoreEvent_Handler,
+		},
+		{
+			MethodName: "GetEvent",
+			Handler:    _CalendarService_GetEvent_Handler,
+		},
+		{
+			MethodName: "GetEventHistory",
+			Handler:    _CalendarService_GetEventHistory_Handler,
+		},
 		{
 			MethodName: "GetEvents",
 			Handler:    _CalendarService_GetEvents_Handler,
 		},
+		{
+			MethodName: "ListEvents",
+			Handler:    _CalendarService_ListEvents_Handler,
+		},
+		{
+			MethodName: "GetUpcomingEvents",
+			Handler:    _CalendarService_GetUpcomingEvents_Handler,
+		},
 		{
 			MethodName: "GetEventsByDay",
 			Handler:    _CalendarService_GetEventsByDay_Handler,
@@ -343,6 +1365,82 @@ var CalendarService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetEventsByMonth",
 			Handler:    _CalendarService_GetEventsByMonth_Handler,
 		},
+		{
+			MethodName: "InviteAttendees",
+			Handler:    _CalendarService_InviteAttendees_Handler,
+		},
+		{
+			MethodName: "RespondToInvite",
+			Handler:    _CalendarService_RespondToInvite_Handler,
+		},
+		{
+			MethodName: "GetFreeBusy",
+			Handler:    _CalendarService_GetFreeBusy_Handler,
+		},
+		{
+			MethodName: "CreateCalendar",
+			Handler:    _CalendarService_CreateCalendar_Handler,
+		},
+		{
+			MethodName: "UpdateCalendar",
+			Handler:    _CalendarService_UpdateCalendar_Handler,
+		},
+		{
+			MethodName: "ListCalendars",
+			Handler:    _CalendarService_ListCalendars_Handler,
+		},
+		{
+			MethodName: "ShareCalendar",
+			Handler:    _CalendarService_ShareCalendar_Handler,
+		},
+		{
+			MethodName: "ListCalendarEvents",
+			Handler:    _CalendarService_ListCalendarEvents_Handler,
+		},
+		{
+			MethodName: "GetWorkingDays",
+			Handler:    _CalendarService_GetWorkingDays_Handler,
+		},
+		{
+			MethodName: "BatchCreateEvents",
+			Handler:    _CalendarService_BatchCreateEvents_Handler,
+		},
+		{
+			MethodName: "BatchDeleteEvents",
+			Handler:    _CalendarService_BatchDeleteEvents_Handler,
+		},
+		{
+			MethodName: "CreateWebhook",
+			Handler:    _CalendarService_CreateWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhooks",
+			Handler:    _CalendarService_ListWebhooks_Handler,
+		},
+		{
+			MethodName: "DeleteWebhook",
+			Handler:    _CalendarService_DeleteWebhook_Handler,
+		},
+		{
+			MethodName: "EnableWebhook",
+			Handler:    _CalendarService_EnableWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhookDeliveries",
+			Handler:    _CalendarService_ListWebhookDeliveries_Handler,
+		},
+		{
+			MethodName: "ListAttachments",
+			Handler:    _CalendarService_ListAttachments_Handler,
+		},
+		{
+			MethodName: "AddLink",
+			Handler:    _CalendarService_AddLink_Handler,
+		},
+		{
+			MethodName: "DeleteAttachment",
+			Handler:    _CalendarService_DeleteAttachment_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/EventService.proto",
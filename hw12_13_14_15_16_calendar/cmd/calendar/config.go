@@ -0,0 +1,23 @@
+package main
+
+import (
+	"time"
+
+	"mycalendar/internal/config"
+	"mycalendar/internal/webhook"
+)
+
+// webhookOptions переводит настройки webhooks из конфига в параметры рассылки.
+func webhookOptions(c config.WebhooksConfig) webhook.Options {
+	return webhook.Options{
+		Interval:     time.Duration(c.IntervalSeconds) * time.Second,
+		Timeout:      time.Duration(c.TimeoutSeconds) * time.Second,
+		MaxAttempts:  c.MaxAttempts,
+		Backoff:      time.Duration(c.BackoffSeconds) * time.Second,
+		MaxBackoff:   time.Duration(c.MaxBackoffSeconds) * time.Second,
+		DisableAfter: c.DisableAfter,
+		Workers:      c.Workers,
+		Retention:    time.Duration(c.RetentionDays) * 24 * time.Hour,
+		AllowPrivate: c.AllowPrivateNetworks,
+	}
+}
@@ -2,30 +2,47 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
 	"flag"
 	"fmt"
 	"log"
+	"log/slog"
 	"net"
+	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"mycalendar/api/calendarpb"
 	"mycalendar/internal/app"
+	"mycalendar/internal/blob"
+	"mycalendar/internal/certs"
 	"mycalendar/internal/config"
+	"mycalendar/internal/holidays"
+	"mycalendar/internal/lifecycle"
 	"mycalendar/internal/logger"
+	"mycalendar/internal/ratelimit"
 	grpcserver "mycalendar/internal/server/grpc"
 	internalhttp "mycalendar/internal/server/http"
 	"mycalendar/internal/storage"
+	cachestorage "mycalendar/internal/storage/cache"
 	memorystorage "mycalendar/internal/storage/memory"
 	sqlstorage "mycalendar/internal/storage/sql"
+	"mycalendar/internal/tenant"
+	"mycalendar/internal/webhook"
 )
 
-var configFile string
+var (
+	configFile string
+	overrides  = config.Overrides{}
+)
 
 func init() {
-	flag.StringVar(&configFile, "config", "configs/config.toml", "Path to configuration file")
+	flag.StringVar(&configFile, "config", "configs/config.toml", "Path to configuration file (toml or yaml)")
+	overrides.RegisterFlags(flag.CommandLine)
 }
 
 func main() {
@@ -34,41 +51,58 @@ func main() {
 		printVersion()
 		return
 	}
+	if flag.Arg(0) == "config" {
+		if err := config.PrintCommand(os.Stdout, flag.Args()[1:], configFile, overrides); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 	if err := mainImpl(); err != nil {
 		log.Fatal(err)
 	}
 }
 
 func mainImpl() error {
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	conf, err := config.Read(configFile)
+	conf, err := config.Load(configFile, overrides)
 	if err != nil {
 		return fmt.Errorf("cannot read config: %w", err)
 	}
+	if err := conf.Validate(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
 
 	////////////////////////
-	mylogger, err := logger.New(conf.Logger.Level, conf.Logger.Path)
+	mylogger, err := logger.New(logger.Options{
+		Level:      conf.Logger.Level,
+		Format:     conf.Logger.Format,
+		Path:       conf.Logger.Path,
+		MaxSizeMB:  conf.Logger.MaxSizeMB,
+		MaxAgeDays: conf.Logger.MaxAgeDays,
+		MaxBackups: conf.Logger.MaxBackups,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to initialize logger: %w", err)
 	}
+	defer mylogger.Close()
+	slog.SetDefault(mylogger.Logger)
 
-	mylogger.Debug("this is debug")
-	mylogger.Info("running on port")
-	mylogger.Error("something went wrong")
+	group := lifecycle.New(mylogger.Component("lifecycle"), time.Duration(conf.Shutdown.TimeoutSeconds)*time.Second)
 	////////////////////////
 
-	var store storage.EventsStorage
+	var store storage.Storage
 
 	switch conf.Storage.Type {
 	case "memory":
 		store = memorystorage.New()
 	case "sql":
-		sqlStore := sqlstorage.New()
+		sqlStore := sqlstorage.New(sqlstorage.OptionsFromConfig(conf.PSQL))
 		if err := sqlStore.Connect(ctx, conf.PSQL.DSN); err != nil {
 			return fmt.Errorf("DB connect failed: %w", err)
 		}
+		group.OnClose("storage", sqlStore.Close)
 		if err := sqlStore.Migrate(ctx, conf.PSQL.Migration); err != nil {
 			return fmt.Errorf("cannot migrate: %w", err)
 		}
@@ -78,8 +112,17 @@ func mainImpl() error {
 		return fmt.Errorf("unknown storage type: %s", conf.Storage.Type)
 	}
 
+	if conf.Cache.Size > 0 {
+		cached := cachestorage.New(store, conf.Cache.Size, time.Duration(conf.Cache.TTLSeconds)*time.Second)
+		group.Add("cache-stats", func(ctx context.Context) error {
+			cached.ReportStats(ctx, mylogger.Component("cache"), time.Minute)
+			return nil
+		}, nil)
+		store = cached
+	}
+
 	////////////////////////
-	calendar, err := app.New(mylogger, store)
+	calendar, err := app.New(mylogger.Component("app"), store)
 	if err != nil {
 		return fmt.Errorf("cannot create app: %w", err)
 	}
@@ -87,68 +130,145 @@ func mainImpl() error {
 	if conf.Storage.Type == "memory" {
 		then, _ := time.Parse("2006-01-02", "2025-07-21")
 		then1, _ := time.Parse("2006-01-02", "2025-12-21")
-		err = calendar.CreateEvent(ctx, "007", "test event1", "test event", "1h", 15, then1)
+		_, err = calendar.CreateEvent(ctx, "007", "test event1", "test event", "1h", 15, then1)
 		if err != nil {
-			mylogger.Printf("failed to create event: %v", err)
+			mylogger.Error("failed to create event", "err", err)
 		}
-		err = calendar.CreateEvent(ctx, "006", "test event2", "test event", "1h", 15, then)
+		_, err = calendar.CreateEvent(ctx, "006", "test event2", "test event", "1h", 15, then)
 		if err != nil {
-			mylogger.Printf("failed to create event: %v", err)
+			mylogger.Error("failed to create event", "err", err)
 		}
-		err = calendar.CreateEvent(ctx, "006", "test event3", "test event", "1h", 15, then1)
+		_, err = calendar.CreateEvent(ctx, "006", "test event3", "test event", "1h", 15, then1)
 		if err != nil {
-			mylogger.Printf("failed to create event: %v", err)
+			mylogger.Error("failed to create event", "err", err)
 		}
 	}
 
+	if conf.Webhooks.Enabled {
+		calendar.SetWebhooks(webhook.New(store), webhook.Guard{AllowPrivate: conf.Webhooks.AllowPrivateNetworks})
+		dispatcher := webhook.NewDispatcher(store, webhookOptions(conf.Webhooks), mylogger.Component("webhook"))
+		group.Add("webhooks", dispatcher.Run, nil)
+	}
+
+	if conf.Attachments.Dir != "" {
+		blobs, err := blob.NewFS(conf.Attachments.Dir)
+		if err != nil {
+			return fmt.Errorf("attachments: %w", err)
+		}
+		calendar.SetAttachments(blobs, app.AttachmentLimits{
+			MaxBytes: conf.Attachments.MaxBytes,
+			Types:    conf.Attachments.Types,
+		})
+	}
+
+	holidayCalendars, err := holidays.Load(conf.Holidays.Countries, conf.Holidays.Files)
+	if err != nil {
+		return fmt.Errorf("holidays: %w", err)
+	}
+	calendar.SetHolidays(holidayCalendars)
+
 	if err := calendar.Run(ctx); err != nil {
 		return fmt.Errorf("cannot run app: %w", err)
 	}
 
 	////////////////////////
-	srv := internalhttp.NewServer(mylogger, calendar, conf.HTTP.Host, conf.HTTP.Port)
+	httpTLS, err := setupTLS(group, conf.HTTP.TLS, conf.HTTP.Host, mylogger.Component("tls"))
+	if err != nil {
+		return fmt.Errorf("HTTP TLS: %w", err)
+	}
+	grpcTLS, err := setupTLS(group, conf.GRPC.TLS, conf.GRPC.Host, mylogger.Component("tls"))
+	if err != nil {
+		return fmt.Errorf("gRPC TLS: %w", err)
+	}
 
-	grpcServer := grpc.NewServer()
-	calendarpb.RegisterCalendarServiceServer(grpcServer, grpcserver.NewServer(calendar))
+	api := grpcserver.NewServer(calendar)
+	gateway, err := grpcserver.NewGateway(ctx, api)
+	if err != nil {
+		return fmt.Errorf("cannot create HTTP gateway: %w", err)
+	}
 
-	// Create context with cancel on SIGINT/SIGTERM
-	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
-	defer stop()
+	// Один лимитер на оба API, чтобы клиент не получал двойную квоту.
+	var limiter *ratelimit.Limiter
+	if conf.Limits.RPS > 0 {
+		limiter = ratelimit.New(conf.Limits.RPS, conf.Limits.Burst)
+	}
+	tenants := tenant.NewSet(conf.Tenants.Names, conf.Tenants.Principals)
+	srv := internalhttp.NewServer(mylogger.Component("http"), gateway, conf.HTTP.Host, conf.HTTP.Port,
+		internalhttp.Options{
+			Limiter:        limiter,
+			MaxBodyBytes:   conf.Limits.MaxBodyBytes,
+			TLS:            httpTLS,
+			Tenants:        tenants,
+			Bulk:           calendar,
+			MaxImportBytes: conf.Limits.MaxImportBytes,
+			Attachments:    calendar,
+		})
 
-	// Start gRPC server in a goroutine
-	go func() {
-		grpcAddr := net.JoinHostPort(conf.GRPC.Host, conf.GRPC.Port)
-		lis, err := net.Listen("tcp", grpcAddr)
-		if err != nil {
-			mylogger.Printf("failed to listen for gRPC: %v", err)
-			stop()
-			return
-		}
-		mylogger.Printf("gRPC server listening on %s", grpcAddr)
+	interceptors := []grpc.UnaryServerInterceptor{
+		grpcserver.RequestIDInterceptor(),
+		grpcserver.LoggingInterceptor(mylogger.Component("grpc")),
+		grpcserver.IdentityInterceptor(),
+		grpcserver.TenantInterceptor(tenants),
+	}
+	if limiter != nil {
+		interceptors = append(interceptors, grpcserver.RateLimitInterceptor(limiter))
+	}
+	grpcOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(interceptors...),
+		grpc.MaxRecvMsgSize(int(conf.Limits.MaxBodyBytes)),
+	}
+	if grpcTLS != nil {
+		grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(grpcTLS)))
+	}
+	grpcServer := grpc.NewServer(grpcOpts...)
+	calendarpb.RegisterCalendarServiceServer(grpcServer, api)
 
-		if err := grpcServer.Serve(lis); err != nil {
-			mylogger.Printf("gRPC server error: %v", err)
-			stop()
-		}
-	}()
+	grpcAddr := net.JoinHostPort(conf.GRPC.Host, conf.GRPC.Port)
+	group.Add("grpc", func(ctx context.Context) error {
+		mylogger.Info("gRPC server listening", "addr", grpcAddr)
+		return grpcserver.Serve(ctx, grpcServer, grpcAddr)
+	}, func(ctx context.Context) error {
+		return grpcserver.GracefulStop(ctx, grpcServer)
+	})
+	group.Add("http", srv.Start, srv.Stop)
 
-	// Start HTTP server in a goroutine
-	go func() {
-		if err := srv.Start(ctx); err != nil {
-			mylogger.Printf("HTTP server error: %v", err)
-			stop()
-		}
-	}()
+	// По SIGHUP перечитываем конфиг, на лету меняется только уровень логов.
+	reloader := config.NewReloader(configFile, overrides, conf, mylogger.Component("config"))
+	reloader.OnChange("logger.level", func(c *config.Config) {
+		_ = mylogger.SetLevel(c.Logger.Level)
+	})
+	group.Add("config", func(ctx context.Context) error {
+		reloader.Run(ctx, time.Duration(conf.Reload.WatchSeconds)*time.Second)
+		return nil
+	}, nil)
 
-	// Wait for signal
-	<-ctx.Done()
-	mylogger.Printf("Shutdown signal received")
+	return group.Run(ctx)
+}
 
-	// Gracefully stop servers
-	grpcServer.GracefulStop()
-	if err := srv.Stop(context.Background()); err != nil {
-		mylogger.Printf("error shutting down HTTP server: %v", err)
+// setupTLS возвращает TLS конфиг слушателя или nil, если TLS не настроен.
+// Сертификаты из файлов перечитываются при изменении, пока работает group.
+func setupTLS(group *lifecycle.Group, c config.TLSConfig, host string, log *slog.Logger) (*tls.Config, error) {
+	opts := certs.Options{
+		CertFile:     c.CertFile,
+		KeyFile:      c.KeyFile,
+		ClientCAFile: c.ClientCAFile,
+		SelfSigned:   c.SelfSigned,
+		Hosts:        []string{"localhost", "127.0.0.1", host},
 	}
-
-	return nil
+	if !opts.Enabled() {
+		return nil, nil
+	}
+	m, err := certs.New(opts, log)
+	if err != nil {
+		return nil, err
+	}
+	if c.SelfSigned {
+		log.Warn("using self-signed certificate, do not use in production",
+			"sha256", fmt.Sprintf("%x", sha256.Sum256(m.Leaf().Raw)))
+	}
+	group.Add("tls", func(ctx context.Context) error {
+		m.Run(ctx, time.Duration(c.WatchSeconds)*time.Second)
+		return nil
+	}, nil)
+	return m.TLSConfig(), nil
 }
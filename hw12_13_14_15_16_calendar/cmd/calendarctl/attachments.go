@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+	pb "mycalendar/api/calendarpb"
+)
+
+// uploaded - ответ POST /events/{id}/attachments.
+type uploaded struct {
+	ID          int64     `json:"id"`
+	EventID     int64     `json:"eventId"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"contentType"`
+	Size        int64     `json:"size"`
+	CreatedBy   string    `json:"createdBy"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+func listAttachments(ctx context.Context, c *client, args []string) error {
+	id, err := requiredID("files", "Event ID (required)", args)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.call(ctx)
+	defer cancel()
+
+	resp, err := c.api.ListAttachments(ctx, &pb.AttachmentsRequest{EventId: id})
+	if err != nil {
+		return err
+	}
+	return c.printer.attachments(resp.Attachments)
+}
+
+// attachFile загружает файл через HTTP API потоком, как import.
+func attachFile(ctx context.Context, c *client, args []string) error {
+	fs := flag.NewFlagSet("attach", flag.ContinueOnError)
+	eventID := fs.Int64("id", 0, "Event ID (required)")
+	name := fs.String("name", "", "Attachment name, default the file name")
+	contentType := fs.String("type", "", "MIME type, default by file extension")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *eventID == 0 || fs.NArg() != 1 {
+		return errors.New("usage: attach -id <event> [-name name] [-type mime/type] <file>")
+	}
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if *name == "" {
+		*name = filepath.Base(f.Name())
+	}
+	if *contentType == "" {
+		*contentType = mime.TypeByExtension(filepath.Ext(f.Name()))
+	}
+	if *contentType == "" {
+		*contentType = "application/octet-stream"
+	}
+
+	path := "/events/" + strconv.FormatInt(*eventID, 10) + "/attachments"
+	resp, err := c.httpDo(ctx, http.MethodPost, path, url.Values{"name": {*name}}, f, *contentType)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var a uploaded
+	if err := json.NewDecoder(resp.Body).Decode(&a); err != nil {
+		return fmt.Errorf("attach: %s", resp.Status)
+	}
+	return c.printer.attachments([]*pb.Attachment{{
+		Id:          a.ID,
+		EventId:     a.EventID,
+		Name:        a.Name,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		CreatedBy:   a.CreatedBy,
+		CreatedAt:   timestamppb.New(a.CreatedAt),
+	}})
+}
+
+func attachLink(ctx context.Context, c *client, args []string) error {
+	req := &pb.LinkRequest{}
+	fs := flag.NewFlagSet("link", flag.ContinueOnError)
+	fs.Int64Var(&req.EventId, "id", 0, "Event ID (required)")
+	fs.StringVar(&req.Url, "url", "", "Link, http or https (required)")
+	fs.StringVar(&req.Name, "name", "", "Link name, default the URL")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if req.EventId == 0 || req.Url == "" {
+		return errors.New("-id and -url are required")
+	}
+	ctx, cancel := c.call(ctx)
+	defer cancel()
+
+	a, err := c.api.AddLink(ctx, req)
+	if err != nil {
+		return err
+	}
+	return c.printer.attachments([]*pb.Attachment{a})
+}
+
+func downloadAttachment(ctx context.Context, c *client, args []string) error {
+	fs := flag.NewFlagSet("download", flag.ContinueOnError)
+	id := fs.Int64("id", 0, "Attachment ID (required)")
+	out := fs.String("out", "", "Output file, - for stdout, default the attachment name in the current directory")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *id == 0 {
+		return errors.New("-id is required")
+	}
+	resp, err := c.httpDo(ctx, http.MethodGet, "/attachments/"+strconv.FormatInt(*id, 10)+"/content", nil, nil, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	name := *out
+	if name == "" {
+		_, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition"))
+		// имя приходит с сервера, поэтому от него остаётся только последний элемент
+		name = filepath.Base(params["filename"])
+		if err != nil || name == "." || name == ".." || name == string(filepath.Separator) {
+			name = "attachment-" + strconv.FormatInt(*id, 10)
+		}
+	}
+	w := c.printer.out()
+	if name != "-" {
+		f, err := os.Create(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("download: %w", err)
+	}
+	return nil
+}
+
+func deleteAttachment(ctx context.Context, c *client, args []string) error {
+	id, err := requiredID("detach", "Attachment ID (required)", args)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.call(ctx)
+	defer cancel()
+
+	_, err = c.api.DeleteAttachment(ctx, &pb.AttachmentIDRequest{Id: id})
+	return err
+}
+
+func requiredID(name, usage string, args []string) (int64, error) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	id := fs.Int64("id", 0, usage)
+	if err := fs.Parse(args); err != nil {
+		return 0, err
+	}
+	if *id == 0 {
+		return 0, errors.New("-id is required")
+	}
+	return *id, nil
+}
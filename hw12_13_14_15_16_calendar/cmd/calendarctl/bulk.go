@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"mycalendar/internal/bulk"
+	"mycalendar/internal/identity"
+	"mycalendar/internal/tenant"
+)
+
+// importReport - ответ POST /events/import.
+type importReport struct {
+	Imported int  `json:"imported"`
+	Failed   int  `json:"failed"`
+	DryRun   bool `json:"dryRun"`
+	Errors   []struct {
+		Line  int    `json:"line"`
+		Error string `json:"error"`
+	} `json:"errors,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// Импорт и экспорт идут через HTTP API потоком, поэтому таймаут из конфига
+// к ним не применяется.
+
+func importEvents(ctx context.Context, c *client, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "Check the file and report errors without saving")
+	formatName := fs.String("format", "", "csv or ndjson, default by file extension")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: import [-dry-run] [-format csv|ndjson] <file|->")
+	}
+	name := fs.Arg(0)
+	format, err := fileFormat(*formatName, name)
+	if err != nil {
+		return err
+	}
+
+	var body io.Reader = os.Stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		body = f
+	}
+
+	q := url.Values{"format": {string(format)}}
+	if *dryRun {
+		q.Set("dry_run", "true")
+	}
+	resp, err := c.httpDo(ctx, http.MethodPost, "/events/import", q, body, format.ContentType())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var report importReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return fmt.Errorf("import: %s", resp.Status)
+	}
+	if err := c.printer.importReport(report); err != nil {
+		return err
+	}
+	if report.Error != "" {
+		return fmt.Errorf("import interrupted: %s", report.Error)
+	}
+	return nil
+}
+
+func exportEvents(ctx context.Context, c *client, args []string) error {
+	from, to := &timeFlag{}, &timeFlag{}
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	formatName := fs.String("format", "", "csv or ndjson, default by -out extension or ndjson")
+	out := fs.String("out", "-", "Output file, - for stdout")
+	fs.Var(from, "from", "Events starting at or after this time")
+	fs.Var(to, "to", "Events starting before this time")
+	calendarID := fs.Int64("calendar", 0, "Only this calendar")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	format, err := fileFormat(*formatName, *out)
+	if err != nil {
+		return err
+	}
+
+	q := url.Values{"format": {string(format)}}
+	if !from.t.IsZero() {
+		q.Set("from", from.t.Format(time.RFC3339))
+	}
+	if !to.t.IsZero() {
+		q.Set("to", to.t.Format(time.RFC3339))
+	}
+	if *calendarID != 0 {
+		q.Set("calendar_id", strconv.FormatInt(*calendarID, 10))
+	}
+	resp, err := c.httpDo(ctx, http.MethodGet, "/events/export", q, nil, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	w := c.printer.out()
+	if *out != "-" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+	return nil
+}
+
+// fileFormat - формат из флага, иначе по расширению; для "-" по умолчанию ndjson.
+func fileFormat(flagValue, name string) (bulk.Format, error) {
+	if flagValue != "" {
+		return bulk.ParseFormat(flagValue)
+	}
+	if name == "-" {
+		return bulk.NDJSON, nil
+	}
+	return bulk.FormatOf(name)
+}
+
+// httpDo выполняет запрос к HTTP API от пользователя из конфига. Ответ с ошибкой
+// без тела отчёта возвращается как ошибка.
+func (c *client) httpDo(ctx context.Context, method, path string, q url.Values, body io.Reader, contentType string) (*http.Response, error) {
+	hc, base, err := c.cfg.httpClient()
+	if err != nil {
+		return nil, fmt.Errorf("TLS: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, base+path+"?"+q.Encode(), body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.cfg.UserID != "" {
+		req.Header.Set(identity.UserIDHeader, c.cfg.UserID)
+	}
+	if c.cfg.Tenant != "" {
+		req.Header.Set(tenant.Header, c.cfg.Tenant)
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK && !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return resp, nil
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"slices"
+
+	pb "mycalendar/api/calendarpb"
+)
+
+func listCalendars(ctx context.Context, c *client, args []string) error {
+	fs := flag.NewFlagSet("calendars", flag.ContinueOnError)
+	user := fs.String("user", "", "Whose calendars, default is the user from config")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ctx, cancel := c.call(ctx)
+	defer cancel()
+
+	resp, err := c.api.ListCalendars(ctx, &pb.ListCalendarsRequest{UserId: *user})
+	if err != nil {
+		return err
+	}
+	return c.printer.calendars(resp.Calendars)
+}
+
+func createCalendar(ctx context.Context, c *client, args []string) error {
+	cal := &pb.Calendar{}
+	fs := flag.NewFlagSet("mkcal", flag.ContinueOnError)
+	fs.StringVar(&cal.Name, "name", "", "Calendar name (required)")
+	fs.StringVar(&cal.Color, "color", "", "Color, #rrggbb")
+	fs.StringVar(&cal.TimeZone, "tz", "", "IANA time zone (default UTC)")
+	fs.StringVar(&cal.HolidayCountry, "holidays", "", "Country of public holidays, e.g. RU or US")
+	fs.BoolVar(&cal.DeferReminders, "defer-reminders", false, "Do not remind on non-working days about events after them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if cal.Name == "" {
+		return errors.New("-name is required")
+	}
+	ctx, cancel := c.call(ctx)
+	defer cancel()
+
+	created, err := c.api.CreateCalendar(ctx, &pb.CalendarRequest{Calendar: cal})
+	if err != nil {
+		return err
+	}
+	return c.printer.calendars([]*pb.Calendar{created})
+}
+
+// updateCalendar меняет только заданные флагами поля календаря.
+func updateCalendar(ctx context.Context, c *client, args []string) error {
+	var set pb.Calendar
+	fs := flag.NewFlagSet("setcal", flag.ContinueOnError)
+	id := fs.Int64("id", 0, "Calendar ID (required)")
+	fs.StringVar(&set.Name, "name", "", "Calendar name")
+	fs.StringVar(&set.Color, "color", "", "Color, #rrggbb")
+	fs.StringVar(&set.TimeZone, "tz", "", "IANA time zone")
+	fs.StringVar(&set.HolidayCountry, "holidays", "", "Country of public holidays, empty - weekends only")
+	fs.BoolVar(&set.DeferReminders, "defer-reminders", false, "Do not remind on non-working days about events after them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *id == 0 {
+		return errors.New("-id is required")
+	}
+	ctx, cancel := c.call(ctx)
+	defer cancel()
+
+	resp, err := c.api.ListCalendars(ctx, &pb.ListCalendarsRequest{})
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(resp.Calendars, func(cal *pb.Calendar) bool { return cal.Id == *id })
+	if i < 0 {
+		return fmt.Errorf("calendar %d not found", *id)
+	}
+	cal := resp.Calendars[i]
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "name":
+			cal.Name = set.Name
+		case "color":
+			cal.Color = set.Color
+		case "tz":
+			cal.TimeZone = set.TimeZone
+		case "holidays":
+			cal.HolidayCountry = set.HolidayCountry
+		case "defer-reminders":
+			cal.DeferReminders = set.DeferReminders
+		}
+	})
+
+	updated, err := c.api.UpdateCalendar(ctx, &pb.CalendarRequest{Calendar: cal})
+	if err != nil {
+		return err
+	}
+	return c.printer.calendars([]*pb.Calendar{updated})
+}
+
+// workingDays показывает рабочие дни и праздники: workdays -calendar 2 2026-01-01 2026-01-31.
+func workingDays(ctx context.Context, c *client, args []string) error {
+	fs := flag.NewFlagSet("workdays", flag.ContinueOnError)
+	calendarID := fs.Int64("calendar", 0, "Take the country and time zone from this calendar")
+	country := fs.String("country", "", "Country of public holidays, e.g. RU or US")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return errors.New("usage: workdays [-calendar id] [-country code] <from> <to>, dates as YYYY-MM-DD")
+	}
+	ctx, cancel := c.call(ctx)
+	defer cancel()
+
+	resp, err := c.api.GetWorkingDays(ctx, &pb.WorkingDaysRequest{
+		From:       fs.Arg(0),
+		To:         fs.Arg(1),
+		Country:    *country,
+		CalendarId: *calendarID,
+	})
+	if err != nil {
+		return err
+	}
+	return c.printer.workingDays(resp)
+}
+
+var permissions = map[string]pb.Permission{
+	"free-busy": pb.Permission_PERMISSION_FREE_BUSY,
+	"read":      pb.Permission_PERMISSION_READ,
+	"write":     pb.Permission_PERMISSION_WRITE,
+	"none":      pb.Permission_PERMISSION_UNSPECIFIED,
+}
+
+func shareCalendar(ctx context.Context, c *client, args []string) error {
+	fs := flag.NewFlagSet("share", flag.ContinueOnError)
+	id := fs.Int64("id", 0, "Calendar ID (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	perm, ok := permissions[fs.Arg(1)]
+	if *id == 0 || fs.NArg() != 2 || !ok {
+		return errors.New("usage: share -id <calendar> <user> free-busy|read|write|none")
+	}
+	ctx, cancel := c.call(ctx)
+	defer cancel()
+
+	cal, err := c.api.ShareCalendar(ctx, &pb.ShareCalendarRequest{CalendarId: *id, UserId: fs.Arg(0), Permission: perm})
+	if err != nil {
+		return err
+	}
+	return c.printer.calendars([]*pb.Calendar{cal})
+}
+
+func calendarEvents(ctx context.Context, c *client, args []string) error {
+	fs := flag.NewFlagSet("calendar", flag.ContinueOnError)
+	id := fs.Int64("id", 0, "Calendar ID (required)")
+	var from, to timeFlag
+	fs.Var(&from, "from", "Events starting at or after this time")
+	fs.Var(&to, "to", "Events starting before this time")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *id == 0 {
+		return errors.New("-id is required")
+	}
+	ctx, cancel := c.call(ctx)
+	defer cancel()
+
+	resp, err := c.api.ListCalendarEvents(ctx, &pb.CalendarEventsRequest{CalendarId: *id, From: from.proto(), To: to.proto()})
+	if err != nil {
+		return err
+	}
+	events := resp.GetEvents()
+	sortEvents(events)
+	return c.printer.events(events)
+}
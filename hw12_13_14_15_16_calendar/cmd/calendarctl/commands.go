@@ -0,0 +1,524 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	pb "mycalendar/api/calendarpb"
+	"mycalendar/internal/storage"
+)
+
+type client struct {
+	api     pb.CalendarServiceClient
+	cfg     clientConfig
+	printer printer
+}
+
+type command func(ctx context.Context, c *client, args []string) error
+
+var commands = map[string]command{
+	"add":        addEvent,
+	"quick":      quickAdd,
+	"update":     updateEvent,
+	"delete":     deleteEvent,
+	"get":        getEvent,
+	"list":       listEvents,
+	"trash":      listTrash,
+	"restore":    restoreEvent,
+	"history":    eventHistory,
+	"invite":     inviteAttendees,
+	"rsvp":       respondToInvite,
+	"freebusy":   freeBusy,
+	"calendars":  listCalendars,
+	"mkcal":      createCalendar,
+	"setcal":     updateCalendar,
+	"workdays":   workingDays,
+	"share":      shareCalendar,
+	"calendar":   calendarEvents,
+	"import":     importEvents,
+	"export":     exportEvents,
+	"webhooks":   listWebhooks,
+	"mkhook":     createWebhook,
+	"rmhook":     deleteWebhook,
+	"enablehook": enableWebhook,
+	"deliveries": webhookDeliveries,
+	"files":      listAttachments,
+	"attach":     attachFile,
+	"link":       attachLink,
+	"download":   downloadAttachment,
+	"detach":     deleteAttachment,
+	"day":        rangeCommand((*client).day),
+	"week":       rangeCommand((*client).week),
+	"month":      rangeCommand((*client).month),
+}
+
+// Форматы времени, которые принимают флаги -start, -from, -to и -date.
+var timeLayouts = []string{time.RFC3339, "2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02"}
+
+// timeFlag - флаг со временем в одном из timeLayouts, локальная зона по умолчанию.
+type timeFlag struct {
+	t time.Time
+}
+
+func (f *timeFlag) String() string {
+	if f.t.IsZero() {
+		return ""
+	}
+	return f.t.Format(time.RFC3339)
+}
+
+func (f *timeFlag) Set(s string) error {
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			f.t = t
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported time %q, use RFC3339 or \"2006-01-02 15:04\"", s)
+}
+
+func (f *timeFlag) proto() *timestamppb.Timestamp {
+	if f.t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(f.t)
+}
+
+// int32Flag разбирает значение флага в *p, Sscan проверяет переполнение.
+func int32Flag(p *int32) func(string) error {
+	return func(s string) error {
+		_, err := fmt.Sscan(s, p)
+		return err
+	}
+}
+
+// call ограничивает один вызов API таймаутом из конфига.
+func (c *client) call(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, c.cfg.timeout())
+}
+
+// parseEvent разбирает общие флаги add и update.
+func parseEvent(name string, c *client, args []string) (*pb.Event, error) {
+	return parseEventFlags(flag.NewFlagSet(name, flag.ContinueOnError), c, args)
+}
+
+// parseEventFlags добавляет в fs флаги события и разбирает args.
+func parseEventFlags(fs *flag.FlagSet, c *client, args []string) (*pb.Event, error) {
+	e := &pb.Event{}
+	start := &timeFlag{}
+	fs.StringVar(&e.UserId, "user", c.cfg.UserID, "Owner of the event")
+	fs.StringVar(&e.Title, "title", "", "Title")
+	fs.StringVar(&e.Description, "desc", "", "Description")
+	fs.Var(start, "start", "Start time, RFC3339 or \"2006-01-02 15:04\" (required)")
+	fs.StringVar(&e.Duration, "duration", "1h", "Duration, e.g. 30m or 1h30m")
+	notice := fs.Uint("notice", 0, "Notify this many days before the event")
+	fs.Int64Var(&e.CalendarId, "calendar", 0, "Calendar ID, default is the owner's default calendar (add only)")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	e.NoticeBefore = int32(*notice) //nolint:gosec // дни, значения малы
+	if start.t.IsZero() {
+		return nil, errors.New("-start is required")
+	}
+	if e.UserId == "" {
+		return nil, errors.New("-user is required (or set userId in config)")
+	}
+	if _, err := storage.ParseDuration(e.Duration); err != nil {
+		return nil, fmt.Errorf("-duration: %w", err)
+	}
+	e.StartAt = start.proto()
+	return e, nil
+}
+
+func addEvent(ctx context.Context, c *client, args []string) error {
+	series := &pb.RecurringEventRequest{}
+	fs := flag.NewFlagSet("add", flag.ContinueOnError)
+	fs.StringVar(&series.Frequency, "repeat", "", "Create a series: daily, weekly or monthly")
+	fs.Func("interval", "Repeat every N days, weeks or months (with -repeat)", int32Flag(&series.Interval))
+	fs.Func("count", "Number of occurrences, including the first and skipped ones (with -repeat)", int32Flag(&series.Count))
+	fs.StringVar(&series.Until, "until", "", "Last day of the series, YYYY-MM-DD (with -repeat)")
+	fs.BoolVar(&series.SkipHolidays, "skip-holidays", false, "Skip occurrences on weekends and holidays of the calendar (with -repeat)")
+	e, err := parseEventFlags(fs, c, args)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.call(ctx)
+	defer cancel()
+
+	if series.Frequency != "" {
+		series.Event = e
+		resp, err := c.api.AddRecurringEvent(ctx, series)
+		if err != nil {
+			return err
+		}
+		for _, t := range resp.Skipped {
+			fmt.Fprintln(os.Stderr, "calendarctl: skipped non-working day:", t.AsTime().Local().Format("2006-01-02 15:04"))
+		}
+		return c.printer.events(resp.Events)
+	}
+
+	resp, err := c.api.AddEvent(ctx, &pb.EventRequest{Event: e})
+	if err != nil {
+		return err
+	}
+	e.Id = resp.Id
+	return c.printer.events([]*pb.Event{e})
+}
+
+// quickAdd создаёт событие из строки: quick standup tomorrow 10:00 for 15m.
+func quickAdd(ctx context.Context, c *client, args []string) error {
+	req := &pb.QuickAddRequest{}
+	fs := flag.NewFlagSet("quick", flag.ContinueOnError)
+	fs.BoolVar(&req.Preview, "preview", false, "Only show how the text is understood, do not create the event")
+	fs.Int64Var(&req.CalendarId, "calendar", 0, "Calendar ID, default the own default calendar")
+	fs.StringVar(&req.TimeZone, "tz", "", "IANA time zone for relative dates, default the zone of the calendar")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	req.Text = strings.Join(fs.Args(), " ")
+	if req.Text == "" {
+		return errors.New("usage: quick [-preview] [-calendar id] [-tz zone] <text>")
+	}
+	ctx, cancel := c.call(ctx)
+	defer cancel()
+
+	resp, err := c.api.QuickAdd(ctx, req)
+	if err != nil {
+		return err
+	}
+	for _, w := range resp.Warnings {
+		fmt.Fprintln(os.Stderr, "calendarctl: warning:", w)
+	}
+	return c.printer.events([]*pb.Event{resp.Event})
+}
+
+func updateEvent(ctx context.Context, c *client, args []string) error {
+	e, err := parseEvent("update", c, args)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.call(ctx)
+	defer cancel()
+
+	_, err = c.api.UpdateEvent(ctx, &pb.EventRequest{Event: e})
+	return err
+}
+
+func deleteEvent(ctx context.Context, c *client, args []string) error {
+	fs := flag.NewFlagSet("delete", flag.ContinueOnError)
+	id := fs.Int64("id", 0, "Event ID")
+	user := fs.String("user", c.cfg.UserID, "Owner of the event, with -start")
+	start := &timeFlag{}
+	fs.Var(start, "start", "Start time of the event, with -user")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ctx, cancel := c.call(ctx)
+	defer cancel()
+
+	switch {
+	case *id != 0:
+		resp, err := c.api.BatchDeleteEvents(ctx, &pb.BatchDeleteEventsRequest{Ids: []int64{*id}})
+		if err != nil {
+			return err
+		}
+		if len(resp.Results) != 1 {
+			return fmt.Errorf("unexpected response: %d results for one event", len(resp.Results))
+		}
+		if r := resp.Results[0]; r.Error != "" {
+			return errors.New(r.Error)
+		}
+		return nil
+	case *user != "" && !start.t.IsZero():
+		_, err := c.api.DeleteEvent(ctx, &pb.DeleteRequest{UserId: *user, Start: start.proto()})
+		return err
+	default:
+		return errors.New("either -id or -user with -start is required")
+	}
+}
+
+func getEvent(ctx context.Context, c *client, args []string) error {
+	fs := flag.NewFlagSet("get", flag.ContinueOnError)
+	id := fs.Int64("id", 0, "Event ID (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *id == 0 {
+		return errors.New("-id is required")
+	}
+
+	ctx, cancel := c.call(ctx)
+	defer cancel()
+
+	e, err := c.api.GetEvent(ctx, &pb.GetEventRequest{Id: *id})
+	if err != nil {
+		return err
+	}
+	return c.printer.events([]*pb.Event{e})
+}
+
+func listEvents(ctx context.Context, c *client, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	user := fs.String("user", "", "Only events of this user")
+	from, to := &timeFlag{}, &timeFlag{}
+	fs.Var(from, "from", "Events starting at or after this time")
+	fs.Var(to, "to", "Events starting before this time")
+	watch := fs.Duration("watch", 0, "Poll the server with this interval and reprint on changes")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	req := &pb.ListEventsRequest{UserId: *user, From: from.proto(), To: to.proto()}
+	fetch := func(ctx context.Context) ([]*pb.Event, error) {
+		resp, err := c.api.ListEvents(ctx, req)
+		return resp.GetEvents(), err
+	}
+	return c.show(ctx, fetch, *watch)
+}
+
+func listTrash(ctx context.Context, c *client, args []string) error {
+	fs := flag.NewFlagSet("trash", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ctx, cancel := c.call(ctx)
+	defer cancel()
+
+	resp, err := c.api.ListTrash(ctx, &pb.TrashRequest{})
+	if err != nil {
+		return err
+	}
+	return c.printer.events(resp.GetEvents())
+}
+
+func restoreEvent(ctx context.Context, c *client, args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	id := fs.Int64("id", 0, "Event ID (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *id == 0 {
+		return errors.New("-id is required")
+	}
+	ctx, cancel := c.call(ctx)
+	defer cancel()
+
+	e, err := c.api.RestoreEvent(ctx, &pb.RestoreRequest{Id: *id})
+	if err != nil {
+		return err
+	}
+	return c.printer.events([]*pb.Event{e})
+}
+
+func eventHistory(ctx context.Context, c *client, args []string) error {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	id := fs.Int64("id", 0, "Event ID (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *id == 0 {
+		return errors.New("-id is required")
+	}
+	ctx, cancel := c.call(ctx)
+	defer cancel()
+
+	resp, err := c.api.GetEventHistory(ctx, &pb.GetEventRequest{Id: *id})
+	if err != nil {
+		return err
+	}
+	return c.printer.history(resp.GetRecords())
+}
+
+func inviteAttendees(ctx context.Context, c *client, args []string) error {
+	fs := flag.NewFlagSet("invite", flag.ContinueOnError)
+	id := fs.Int64("id", 0, "Event ID (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *id == 0 || fs.NArg() == 0 {
+		return errors.New("usage: invite -id <event> <user>...")
+	}
+
+	ctx, cancel := c.call(ctx)
+	defer cancel()
+
+	e, err := c.api.InviteAttendees(ctx, &pb.InviteRequest{EventId: *id, UserIds: fs.Args()})
+	if err != nil {
+		return err
+	}
+	return c.printer.events([]*pb.Event{e})
+}
+
+var rsvpStatuses = map[string]pb.RSVPStatus{
+	"accepted":     pb.RSVPStatus_RSVP_STATUS_ACCEPTED,
+	"declined":     pb.RSVPStatus_RSVP_STATUS_DECLINED,
+	"tentative":    pb.RSVPStatus_RSVP_STATUS_TENTATIVE,
+	"needs-action": pb.RSVPStatus_RSVP_STATUS_NEEDS_ACTION,
+}
+
+func respondToInvite(ctx context.Context, c *client, args []string) error {
+	fs := flag.NewFlagSet("rsvp", flag.ContinueOnError)
+	id := fs.Int64("id", 0, "Event ID (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	st, ok := rsvpStatuses[fs.Arg(0)]
+	if *id == 0 || !ok {
+		return errors.New("usage: rsvp -id <event> accepted|declined|tentative|needs-action")
+	}
+
+	ctx, cancel := c.call(ctx)
+	defer cancel()
+
+	e, err := c.api.RespondToInvite(ctx, &pb.RespondRequest{EventId: *id, Status: st})
+	if err != nil {
+		return err
+	}
+	return c.printer.events([]*pb.Event{e})
+}
+
+func freeBusy(ctx context.Context, c *client, args []string) error {
+	fs := flag.NewFlagSet("freebusy", flag.ContinueOnError)
+	var from, to timeFlag
+	fs.Var(&from, "from", "Window start (default now)")
+	fs.Var(&to, "to", "Window end (default -from plus 7 days)")
+	dur := fs.String("duration", "1h", "Meeting duration")
+	workStart := fs.String("work-start", "", "Working day start, HH:MM (server default 09:00)")
+	workEnd := fs.String("work-end", "", "Working day end, HH:MM (server default 18:00)")
+	tz := fs.String("tz", "", "IANA time zone of working hours (default UTC)")
+	weekends := fs.Bool("weekends", false, "Suggest slots on Saturday and Sunday")
+	limit := fs.Int("limit", 0, "Number of slots (server default 10)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("usage: freebusy [flags] <user>...")
+	}
+	if from.t.IsZero() {
+		from.t = time.Now().Truncate(time.Minute)
+	}
+	if to.t.IsZero() {
+		to.t = from.t.AddDate(0, 0, 7)
+	}
+
+	ctx, cancel := c.call(ctx)
+	defer cancel()
+
+	fb, err := c.api.GetFreeBusy(ctx, &pb.FreeBusyRequest{
+		UserIds:         fs.Args(),
+		From:            from.proto(),
+		To:              to.proto(),
+		Duration:        *dur,
+		WorkDayStart:    *workStart,
+		WorkDayEnd:      *workEnd,
+		TimeZone:        *tz,
+		IncludeWeekends: *weekends,
+		Limit:           int32(*limit), //nolint:gosec // лимит задаёт пользователь
+	})
+	if err != nil {
+		return err
+	}
+	return c.printer.freeBusy(fb)
+}
+
+// rangeCommand - день, неделя или месяц вокруг -date.
+func rangeCommand(method func(*client, context.Context, *pb.DateRequest) (*pb.EventsResponse, error)) command {
+	return func(ctx context.Context, c *client, args []string) error {
+		fs := flag.NewFlagSet("range", flag.ContinueOnError)
+		date := &timeFlag{t: time.Now()}
+		fs.Var(date, "date", "Any moment inside the period (default now)")
+		user := fs.String("user", "", "Only events the user owns or attends")
+		watch := fs.Duration("watch", 0, "Poll the server with this interval and reprint on changes")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+
+		req := &pb.DateRequest{Date: date.proto(), UserId: *user}
+		fetch := func(ctx context.Context) ([]*pb.Event, error) {
+			resp, err := method(c, ctx, req)
+			return resp.GetEvents(), err
+		}
+		return c.show(ctx, fetch, *watch)
+	}
+}
+
+func (c *client) day(ctx context.Context, req *pb.DateRequest) (*pb.EventsResponse, error) {
+	return c.api.GetEventsByDay(ctx, req)
+}
+
+func (c *client) week(ctx context.Context, req *pb.DateRequest) (*pb.EventsResponse, error) {
+	return c.api.GetEventsByWeek(ctx, req)
+}
+
+func (c *client) month(ctx context.Context, req *pb.DateRequest) (*pb.EventsResponse, error) {
+	return c.api.GetEventsByMonth(ctx, req)
+}
+
+// show печатает события один раз или, с watch, перепечатывает их при каждом
+// изменении. Потоковых методов в CalendarService нет, поэтому watch опрашивает сервер.
+func (c *client) show(ctx context.Context, fetch func(context.Context) ([]*pb.Event, error), watch time.Duration) error {
+	var last []*pb.Event
+	for first := true; ; first = false {
+		callCtx, cancel := c.call(ctx)
+		events, err := fetch(callCtx)
+		cancel()
+		if err != nil {
+			if watch > 0 && ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		sortEvents(events)
+
+		if first || !sameEvents(last, events) {
+			if watch > 0 && !first {
+				fmt.Fprintf(c.printer.out(), "\n--- %s\n", time.Now().Format(time.DateTime))
+			}
+			if err := c.printer.events(events); err != nil {
+				return err
+			}
+			last = events
+		}
+		if watch <= 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(watch):
+		}
+	}
+}
+
+// sortEvents упорядочивает события по началу и ID: сервер не гарантирует порядок.
+func sortEvents(events []*pb.Event) {
+	slices.SortFunc(events, func(a, b *pb.Event) int {
+		if c := a.StartAt.AsTime().Compare(b.StartAt.AsTime()); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Id, b.Id)
+	})
+}
+
+func sameEvents(a, b []*pb.Event) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !proto.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	pb "mycalendar/api/calendarpb"
+)
+
+func TestMain(m *testing.M) {
+	// вывод печатает время в локальной зоне
+	time.Local = time.UTC
+	os.Exit(m.Run())
+}
+
+// fakeAPI отвечает на вызовы, которые нужны тестам; остальные методы паникуют.
+type fakeAPI struct {
+	pb.CalendarServiceClient
+
+	events  []*pb.Event
+	results []*pb.BatchResult
+	added   *pb.Event
+	series  *pb.RecurringEventRequest
+	invited *pb.InviteRequest
+}
+
+func (f *fakeAPI) AddRecurringEvent(_ context.Context, in *pb.RecurringEventRequest, _ ...grpc.CallOption) (*pb.RecurringEventResponse, error) {
+	f.series = in
+	first := proto.Clone(in.Event).(*pb.Event)
+	first.Id = 42
+	return &pb.RecurringEventResponse{Events: []*pb.Event{first}}, nil
+}
+
+func (f *fakeAPI) AddEvent(_ context.Context, in *pb.EventRequest, _ ...grpc.CallOption) (*pb.AddEventResponse, error) {
+	f.added = in.Event
+	return &pb.AddEventResponse{Id: 42}, nil
+}
+
+func (f *fakeAPI) BatchDeleteEvents(context.Context, *pb.BatchDeleteEventsRequest, ...grpc.CallOption) (*pb.BatchResponse, error) {
+	return &pb.BatchResponse{Results: f.results}, nil
+}
+
+func (f *fakeAPI) ListEvents(context.Context, *pb.ListEventsRequest, ...grpc.CallOption) (*pb.EventsResponse, error) {
+	return &pb.EventsResponse{Events: f.events}, nil
+}
+
+func (f *fakeAPI) InviteAttendees(_ context.Context, in *pb.InviteRequest, _ ...grpc.CallOption) (*pb.Event, error) {
+	f.invited = in
+	return &pb.Event{Id: in.EventId}, nil
+}
+
+func newTestClient(api *fakeAPI) (*client, *bytes.Buffer) {
+	var buf bytes.Buffer
+	return &client{api: api, cfg: clientConfig{UserID: "alice"}, printer: tablePrinter{&buf}}, &buf
+}
+
+func TestTimeFlag(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+		err  bool
+	}{
+		{in: "2025-06-10T10:00:00+03:00", want: time.Date(2025, 6, 10, 7, 0, 0, 0, time.UTC)},
+		{in: "2025-06-10 10:00", want: time.Date(2025, 6, 10, 10, 0, 0, 0, time.UTC)},
+		{in: "2025-06-10T10:00", want: time.Date(2025, 6, 10, 10, 0, 0, 0, time.UTC)},
+		{in: "2025-06-10", want: time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC)},
+		{in: "10:00", err: true},
+		{in: "tomorrow", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			var f timeFlag
+			err := f.Set(tt.in)
+			if tt.err {
+				require.ErrorContains(t, err, "unsupported time")
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, tt.want.Equal(f.t), f.t)
+		})
+	}
+}
+
+func TestParseEvent(t *testing.T) {
+	start := timestamppb.New(time.Date(2025, 6, 10, 10, 0, 0, 0, time.UTC))
+	tests := []struct {
+		name string
+		args []string
+		want *pb.Event
+		err  string
+	}{
+		{
+			name: "defaults",
+			args: []string{"-title", "Standup", "-start", "2025-06-10 10:00"},
+			want: &pb.Event{UserId: "alice", Title: "Standup", StartAt: start, Duration: "1h"},
+		},
+		{
+			name: "all flags",
+			args: []string{
+				"-user", "bob", "-title", "Review", "-desc", "Q2", "-start", "2025-06-10 10:00",
+				"-duration", "1h30m", "-notice", "2", "-calendar", "7",
+			},
+			want: &pb.Event{
+				UserId: "bob", Title: "Review", Description: "Q2", StartAt: start,
+				Duration: "1h30m", NoticeBefore: 2, CalendarId: 7,
+			},
+		},
+		{name: "no start", args: []string{"-title", "Standup"}, err: "-start is required"},
+		{name: "empty user", args: []string{"-user", "", "-start", "2025-06-10"}, err: "-user is required"},
+		{name: "bad duration", args: []string{"-start", "2025-06-10", "-duration", "soon"}, err: "-duration"},
+		{name: "unknown flag", args: []string{"-start", "2025-06-10", "-color", "red"}, err: "flag provided but not defined"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newTestClient(&fakeAPI{})
+			e, err := parseEvent("add", c, tt.args)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want.String(), e.String())
+		})
+	}
+}
+
+func TestCommandArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  command
+		args []string
+		err  string
+	}{
+		{name: "delete without id", cmd: deleteEvent, err: "either -id or -user with -start is required"},
+		{name: "get without id", cmd: getEvent, err: "-id is required"},
+		{name: "restore without id", cmd: restoreEvent, err: "-id is required"},
+		{name: "invite without users", cmd: inviteAttendees, args: []string{"-id", "1"}, err: "usage: invite"},
+		{name: "rsvp unknown status", cmd: respondToInvite, args: []string{"-id", "1", "maybe"}, err: "usage: rsvp"},
+		{name: "freebusy without users", cmd: freeBusy, err: "usage: freebusy"},
+		{name: "quick without text", cmd: quickAdd, args: []string{"-preview"}, err: "usage: quick"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newTestClient(&fakeAPI{})
+			err := tt.cmd(context.Background(), c, tt.args)
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestAddEvent(t *testing.T) {
+	api := &fakeAPI{}
+	c, out := newTestClient(api)
+	err := addEvent(context.Background(), c, []string{"-title", "Standup", "-start", "2025-06-10 10:00", "-duration", "15m"})
+	require.NoError(t, err)
+	require.Equal(t, "Standup", api.added.Title)
+	require.Equal(t, "alice", api.added.UserId)
+	require.Equal(t, ""+
+		"ID  CALENDAR  USER   START             DURATION  NOTICE  TITLE    ATTENDEES\n"+
+		"42  0         alice  2025-06-10 10:00  15m       0d      Standup  \n", out.String())
+}
+
+func TestAddEvent_Repeat(t *testing.T) {
+	api := &fakeAPI{}
+	c, out := newTestClient(api)
+	err := addEvent(context.Background(), c, []string{
+		"-title", "Standup", "-start", "2025-06-10 10:00", "-repeat", "weekly", "-interval", "2", "-count", "5", "-skip-holidays",
+	})
+	require.NoError(t, err)
+	require.Nil(t, api.added)
+	require.Equal(t, "weekly", api.series.Frequency)
+	require.EqualValues(t, 2, api.series.Interval)
+	require.EqualValues(t, 5, api.series.Count)
+	require.True(t, api.series.SkipHolidays)
+	require.Equal(t, "Standup", api.series.Event.Title)
+	require.Contains(t, out.String(), "42  0         alice  2025-06-10 10:00")
+
+	err = addEvent(context.Background(), c, []string{"-title", "Standup", "-start", "2025-06-10 10:00", "-count", "99999999999"})
+	require.Error(t, err)
+}
+
+func TestInviteAttendees(t *testing.T) {
+	api := &fakeAPI{}
+	c, _ := newTestClient(api)
+	require.NoError(t, inviteAttendees(context.Background(), c, []string{"-id", "5", "bob", "carol"}))
+	require.Equal(t, int64(5), api.invited.EventId)
+	require.Equal(t, []string{"bob", "carol"}, api.invited.UserIds)
+}
+
+func TestDeleteEventByID(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []*pb.BatchResult
+		err     string
+	}{
+		{name: "deleted", results: []*pb.BatchResult{{Id: 1}}},
+		{name: "failed", results: []*pb.BatchResult{{Id: 1, Error: "event not found"}}, err: "event not found"},
+		{name: "empty response", err: "unexpected response: 0 results"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newTestClient(&fakeAPI{results: tt.results})
+			err := deleteEvent(context.Background(), c, []string{"-id", "1"})
+			if tt.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestListEventsSorted(t *testing.T) {
+	at := func(h int) *timestamppb.Timestamp {
+		return timestamppb.New(time.Date(2025, 6, 10, h, 0, 0, 0, time.UTC))
+	}
+	api := &fakeAPI{events: []*pb.Event{
+		{Id: 3, UserId: "alice", Title: "Lunch", StartAt: at(13), Duration: "1h"},
+		{Id: 2, UserId: "alice", Title: "Standup", StartAt: at(10), Duration: "15m"},
+		{Id: 1, UserId: "bob", Title: "Sync", StartAt: at(10), Duration: "30m"},
+	}}
+	c, out := newTestClient(api)
+	require.NoError(t, listEvents(context.Background(), c, nil))
+	require.Equal(t, ""+
+		"ID  CALENDAR  USER   START             DURATION  NOTICE  TITLE    ATTENDEES\n"+
+		"1   0         bob    2025-06-10 10:00  30m       0d      Sync     \n"+
+		"2   0         alice  2025-06-10 10:00  15m       0d      Standup  \n"+
+		"3   0         alice  2025-06-10 13:00  1h        0d      Lunch    \n", out.String())
+}
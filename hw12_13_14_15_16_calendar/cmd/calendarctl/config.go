@@ -0,0 +1,133 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"gopkg.in/yaml.v3"
+)
+
+// clientConfig - настройки подключения к CalendarService.
+type clientConfig struct {
+	Address        string    `yaml:"address"`
+	HTTPAddress    string    `yaml:"httpAddress"` // HTTP API для import, export и файлов вложений
+	UserID         string    `yaml:"userId"`      // передаётся серверу в x-user-id
+	Tenant         string    `yaml:"tenant"`      // в x-tenant-id, пусто - тенант по умолчанию
+	TimeoutSeconds int       `yaml:"timeoutSeconds"`
+	TLS            clientTLS `yaml:"tls"`
+}
+
+type clientTLS struct {
+	Enabled    bool   `yaml:"enabled"`
+	CAFile     string `yaml:"caFile"`   // CA сервера, пусто - системные корневые сертификаты
+	CertFile   string `yaml:"certFile"` // сертификат клиента для mTLS
+	KeyFile    string `yaml:"keyFile"`
+	ServerName string `yaml:"serverName"` // если имя в сертификате не совпадает с адресом
+	Insecure   bool   `yaml:"insecure"`   // не проверять сертификат сервера, только для разработки
+}
+
+func defaultClientConfig() clientConfig {
+	return clientConfig{
+		Address:        "localhost:50051",
+		HTTPAddress:    "localhost:8080",
+		TimeoutSeconds: 10,
+	}
+}
+
+// defaultConfigPath - ~/.config/calendarctl.yaml.
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "calendarctl.yaml")
+}
+
+// loadClientConfig читает файл поверх значений по умолчанию. Отсутствие файла
+// по умолчанию не ошибка, явно указанного - ошибка.
+func loadClientConfig(path string, explicit bool) (clientConfig, error) {
+	cfg := defaultClientConfig()
+	if path == "" {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return cfg, err
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func (c clientConfig) timeout() time.Duration {
+	return time.Duration(c.TimeoutSeconds) * time.Second
+}
+
+// credentials собирает транспорт по настройкам TLS.
+func (c clientConfig) credentials() (credentials.TransportCredentials, error) {
+	cfg, err := c.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		return insecure.NewCredentials(), nil
+	}
+	return credentials.NewTLS(cfg), nil
+}
+
+// httpClient - клиент HTTP API с теми же настройками TLS, что и у gRPC.
+func (c clientConfig) httpClient() (*http.Client, string, error) {
+	cfg, err := c.tlsConfig()
+	if err != nil {
+		return nil, "", err
+	}
+	if cfg == nil {
+		return http.DefaultClient, "http://" + c.HTTPAddress, nil
+	}
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: cfg}}, "https://" + c.HTTPAddress, nil
+}
+
+// tlsConfig возвращает nil, если TLS не настроен.
+func (c clientConfig) tlsConfig() (*tls.Config, error) {
+	t := c.TLS
+	if !t.Enabled && t.CAFile == "" && t.CertFile == "" && !t.Insecure {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: t.Insecure, //nolint:gosec // включается явно для самоподписанных сертификатов
+	}
+	if t.CAFile != "" {
+		data, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("%s: no certificates found", t.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+	if t.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
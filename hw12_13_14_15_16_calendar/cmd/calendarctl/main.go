@@ -0,0 +1,151 @@
+// calendarctl - клиент командной строки для gRPC API календаря.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	pb "mycalendar/api/calendarpb"
+	"mycalendar/internal/identity"
+	"mycalendar/internal/tenant"
+)
+
+const usage = `Usage: calendarctl [flags] <command> [command flags]
+
+Commands:
+  add        create an event, or a series with -repeat daily|weekly|monthly
+  quick      create an event from text: quick [-preview] standup tomorrow 10:00 for 15m
+  update     update the event of a user starting at -start
+  delete     move an event to the trash by -id or by -user and -start
+  get        show one event by -id
+  list       list events, optionally filtered by -user, -from, -to
+  trash      list deleted events
+  restore    restore a deleted event by -id
+  history    changes of an event by -id: who, when and what changed
+  invite     invite users to the event: invite -id <event> <user>...
+  rsvp       answer an invitation: rsvp -id <event> accepted|declined|tentative
+  freebusy   busy time and suggested meeting slots: freebusy [flags] <user>...
+  calendars  list own and shared calendars
+  mkcal      create a calendar: mkcal -name <name> [-color #rrggbb] [-tz <zone>] [-holidays RU]
+  setcal     change a calendar by -id: name, color, zone, holidays, reminder deferral
+  workdays   working days and holidays: workdays [-calendar id] [-country RU] <from> <to>
+  share      share a calendar: share -id <calendar> <user> free-busy|read|write|none
+  calendar   events of a calendar by -id, optionally -from and -to
+  day        events of the day containing -date
+  week       events of the week containing -date
+  month      events of the month containing -date
+  import     load events from a CSV or JSON Lines file: import [-dry-run] <file|->
+  export     save own events as CSV or JSON Lines: export [-out file] [-from] [-to]
+  webhooks   list own webhook subscriptions
+  mkhook     subscribe a URL to event changes: mkhook -url <url> [-events event.created,...]
+  rmhook     delete a webhook by -id
+  enablehook re-enable a webhook disabled after failed deliveries, by -id
+  deliveries recent deliveries of a webhook by -id
+  files      files and links attached to an event by -id
+  attach     upload a file to an event: attach -id <event> [-name] [-type] <file>
+  link       attach a link to an event: link -id <event> -url <url> [-name]
+  download   save an attached file by -id: download -id <attachment> [-out file|-]
+  detach     delete an attachment by -id
+  version    print build information
+
+Run "calendarctl <command> -h" for command flags.
+
+Flags:
+`
+
+var (
+	configFile string
+	address    string
+	userID     string
+	tenantID   string
+	output     string
+)
+
+func init() {
+	flag.StringVar(&configFile, "config", "", "Path to client config (default ~/.config/calendarctl.yaml)")
+	flag.StringVar(&address, "addr", "", "CalendarService address, overrides config")
+	flag.StringVar(&userID, "user", "", "User ID sent to the server, overrides config")
+	flag.StringVar(&tenantID, "tenant", "", "Tenant ID sent to the server, overrides config")
+	flag.StringVar(&output, "o", "table", "Output format: table, json or ics")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+}
+
+func main() {
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if flag.Arg(0) == "version" {
+		printVersion()
+		return
+	}
+	if err := run(flag.Arg(0), flag.Args()[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "calendarctl:", err)
+		os.Exit(1)
+	}
+}
+
+func run(command string, args []string) error {
+	cmd, ok := commands[command]
+	if !ok {
+		return fmt.Errorf("unknown command %q, see calendarctl -h", command)
+	}
+	p, err := newPrinter(output, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	path, explicit := configFile, configFile != ""
+	if !explicit {
+		path = defaultConfigPath()
+	}
+	cfg, err := loadClientConfig(path, explicit)
+	if err != nil {
+		return fmt.Errorf("cannot read config: %w", err)
+	}
+	if address != "" {
+		cfg.Address = address
+	}
+	if userID != "" {
+		cfg.UserID = userID
+	}
+	if tenantID != "" {
+		cfg.Tenant = tenantID
+	}
+
+	creds, err := cfg.credentials()
+	if err != nil {
+		return fmt.Errorf("TLS: %w", err)
+	}
+	conn, err := grpc.NewClient(cfg.Address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if cfg.UserID != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, identity.UserIDHeader, cfg.UserID)
+	}
+	if cfg.Tenant != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, tenant.Header, cfg.Tenant)
+	}
+
+	c := &client{
+		api:     pb.NewCalendarServiceClient(conn),
+		cfg:     cfg,
+		printer: p,
+	}
+	return cmd(ctx, c, args)
+}
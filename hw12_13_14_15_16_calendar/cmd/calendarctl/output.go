@@ -0,0 +1,405 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	pb "mycalendar/api/calendarpb"
+	"mycalendar/internal/ics"
+	"mycalendar/internal/storage"
+)
+
+type printer interface {
+	events(events []*pb.Event) error
+	freeBusy(fb *pb.FreeBusyResponse) error
+	calendars(calendars []*pb.Calendar) error
+	workingDays(wd *pb.WorkingDaysResponse) error
+	history(records []*pb.AuditRecord) error
+	importReport(r importReport) error
+	webhooks(hooks []*pb.Webhook) error
+	deliveries(ds []*pb.Delivery) error
+	attachments(as []*pb.Attachment) error
+	out() io.Writer
+}
+
+func newPrinter(format string, w io.Writer) (printer, error) {
+	switch format {
+	case "table":
+		return tablePrinter{w}, nil
+	case "json":
+		return jsonPrinter{w}, nil
+	case "ics":
+		return icsPrinter{w}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q, use table, json or ics", format)
+	}
+}
+
+type tablePrinter struct{ w io.Writer }
+
+func (p tablePrinter) out() io.Writer { return p.w }
+
+func (p tablePrinter) events(events []*pb.Event) error {
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tCALENDAR\tUSER\tSTART\tDURATION\tNOTICE\tTITLE\tATTENDEES")
+	for _, e := range events {
+		fmt.Fprintf(tw, "%d\t%d\t%s\t%s\t%s\t%dd\t%s\t%s\n",
+			e.Id, e.CalendarId, e.UserId, e.StartAt.AsTime().Local().Format("2006-01-02 15:04"),
+			e.Duration, e.NoticeBefore, e.Title, attendeesColumn(e.Attendees))
+	}
+	return tw.Flush()
+}
+
+func (p tablePrinter) freeBusy(fb *pb.FreeBusyResponse) error {
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "USER\tBUSY")
+	for _, u := range fb.Busy {
+		parts := make([]string, 0, len(u.Busy))
+		for _, iv := range u.Busy {
+			parts = append(parts, formatRange(iv.Start.AsTime(), iv.End.AsTime()))
+		}
+		fmt.Fprintf(tw, "%s\t%s\n", u.UserId, strings.Join(parts, ", "))
+	}
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "#\tSLOT\tAVAILABLE\tBUSY")
+	for i, s := range fb.Slots {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", i+1, formatRange(s.Start.AsTime(), s.End.AsTime()),
+			strings.Join(s.Available, ", "), strings.Join(s.Busy, ", "))
+	}
+	return tw.Flush()
+}
+
+func (p tablePrinter) calendars(calendars []*pb.Calendar) error {
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tOWNER\tNAME\tCOLOR\tTIME ZONE\tHOLIDAYS\tSHARED WITH")
+	for _, c := range calendars {
+		name := c.Name
+		if c.IsDefault {
+			name += " (default)"
+		}
+		shares := make([]string, 0, len(c.Shares))
+		for _, sh := range c.Shares {
+			shares = append(shares, sh.UserId+":"+permissionName(sh.Permission))
+		}
+		holidays := c.HolidayCountry
+		if c.DeferReminders {
+			holidays = strings.TrimSpace(holidays + " (defer reminders)")
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			c.Id, c.OwnerId, name, c.Color, c.TimeZone, holidays, strings.Join(shares, ", "))
+	}
+	return tw.Flush()
+}
+
+func (p tablePrinter) workingDays(wd *pb.WorkingDaysResponse) error {
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "DATE\tDAY\tWORKING\tHOLIDAY")
+	for _, d := range wd.Days {
+		day := ""
+		if t, err := time.Parse(time.DateOnly, d.Date); err == nil {
+			day = t.Weekday().String()[:3]
+		}
+		working := "no"
+		if d.Working {
+			working = "yes"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", d.Date, day, working, d.Holiday)
+	}
+	country := wd.Country
+	if country == "" {
+		country = "weekends only"
+	}
+	fmt.Fprintf(tw, "\n%d of %d days are working (%s)\n", wd.WorkingCount, len(wd.Days), country)
+	return tw.Flush()
+}
+
+func (p tablePrinter) history(records []*pb.AuditRecord) error {
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "AT\tACTOR\tACTION\tREQUEST\tCHANGED")
+	for _, r := range records {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			r.At.AsTime().Local().Format("2006-01-02 15:04:05"), r.Actor, r.Action, r.RequestId,
+			strings.Join(changedFields(r.Before, r.After), ", "))
+	}
+	return tw.Flush()
+}
+
+func (p tablePrinter) importReport(r importReport) error {
+	verb := "imported"
+	if r.DryRun {
+		verb = "would import"
+	}
+	fmt.Fprintf(p.w, "%s %d, failed %d\n", verb, r.Imported, r.Failed)
+	if len(r.Errors) == 0 {
+		return nil
+	}
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "LINE\tERROR")
+	for _, e := range r.Errors {
+		fmt.Fprintf(tw, "%d\t%s\n", e.Line, e.Error)
+	}
+	if r.Failed > len(r.Errors) {
+		fmt.Fprintf(tw, "...\t%d more\n", r.Failed-len(r.Errors))
+	}
+	return tw.Flush()
+}
+
+func (p tablePrinter) webhooks(hooks []*pb.Webhook) error {
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tURL\tEVENTS\tSTATE\tFAILURES")
+	for _, h := range hooks {
+		events := strings.Join(h.Events, ", ")
+		if events == "" {
+			events = "all"
+		}
+		state := "active"
+		if !h.Active {
+			state = "disabled"
+			if h.DisabledAt != nil {
+				state += " " + h.DisabledAt.AsTime().Local().Format("2006-01-02 15:04")
+			}
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%d\n", h.Id, h.Url, events, state, h.Failures)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	// секрет приходит только при создании
+	for _, h := range hooks {
+		if h.Secret != "" {
+			fmt.Fprintf(p.w, "\nsigning secret of webhook %d, it is not shown again:\n%s\n", h.Id, h.Secret)
+		}
+	}
+	return nil
+}
+
+func (p tablePrinter) deliveries(ds []*pb.Delivery) error {
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tCREATED\tEVENT\tSTATE\tATTEMPTS\tSTATUS\tNEXT\tERROR")
+	for _, d := range ds {
+		next := ""
+		if d.State == string(storage.DeliveryPending) && d.NextAttemptAt != nil {
+			next = d.NextAttemptAt.AsTime().Local().Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%d\t%d\t%s\t%s\n",
+			d.Id, d.CreatedAt.AsTime().Local().Format("2006-01-02 15:04:05"), d.Event, d.State,
+			d.Attempts, d.LastStatus, next, d.LastError)
+	}
+	return tw.Flush()
+}
+
+func (p tablePrinter) attachments(as []*pb.Attachment) error {
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tTYPE\tSIZE\tADDED BY\tADDED")
+	for _, a := range as {
+		kind, size := a.ContentType, strconv.FormatInt(a.Size, 10)
+		if a.Url != "" {
+			kind, size = "link "+a.Url, ""
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n",
+			a.Id, a.Name, kind, size, a.CreatedBy, a.CreatedAt.AsTime().Local().Format("2006-01-02 15:04"))
+	}
+	return tw.Flush()
+}
+
+// changedFields - поля, которые различаются у снимков до и после изменения.
+func changedFields(before, after *pb.Event) []string {
+	if before == nil || after == nil {
+		return nil
+	}
+	var fields []string
+	check := func(name string, changed bool) {
+		if changed {
+			fields = append(fields, name)
+		}
+	}
+	check("title", before.Title != after.Title)
+	check("description", before.Description != after.Description)
+	check("start", !before.StartAt.AsTime().Equal(after.StartAt.AsTime()))
+	check("duration", before.Duration != after.Duration)
+	check("notice", before.NoticeBefore != after.NoticeBefore)
+	check("calendar", before.CalendarId != after.CalendarId)
+	check("attendees", attendeesColumn(before.Attendees) != attendeesColumn(after.Attendees))
+	return fields
+}
+
+// permissionName - "free-busy", "read" или "write".
+func permissionName(p pb.Permission) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimPrefix(p.String(), "PERMISSION_")), "_", "-")
+}
+
+// formatRange - "2025-06-10 10:00-11:00" в локальной зоне, дата конца - только если другая.
+func formatRange(start, end time.Time) string {
+	start, end = start.Local(), end.Local()
+	layout := "15:04"
+	if start.YearDay() != end.YearDay() || start.Year() != end.Year() {
+		layout = "2006-01-02 15:04"
+	}
+	return start.Format("2006-01-02 15:04") + "-" + end.Format(layout)
+}
+
+// attendeesColumn - "alice:accepted, bob:needs-action".
+func attendeesColumn(attendees []*pb.Attendee) string {
+	parts := make([]string, 0, len(attendees))
+	for _, a := range attendees {
+		st := strings.ToLower(strings.TrimPrefix(a.Status.String(), "RSVP_STATUS_"))
+		parts = append(parts, a.UserId+":"+strings.ReplaceAll(st, "_", "-"))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// jsonPrinter печатает события так же, как их отдаёт HTTP API.
+type jsonPrinter struct{ w io.Writer }
+
+func (p jsonPrinter) out() io.Writer { return p.w }
+
+func (p jsonPrinter) events(events []*pb.Event) error {
+	data, err := protojson.MarshalOptions{Multiline: true, EmitUnpopulated: true}.
+		Marshal(&pb.EventsResponse{Events: events})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(p.w, string(data))
+	return err
+}
+
+func (p jsonPrinter) freeBusy(fb *pb.FreeBusyResponse) error {
+	data, err := protojson.MarshalOptions{Multiline: true, EmitUnpopulated: true}.Marshal(fb)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(p.w, string(data))
+	return err
+}
+
+func (p jsonPrinter) calendars(calendars []*pb.Calendar) error {
+	data, err := protojson.MarshalOptions{Multiline: true, EmitUnpopulated: true}.
+		Marshal(&pb.CalendarsResponse{Calendars: calendars})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(p.w, string(data))
+	return err
+}
+
+func (p jsonPrinter) workingDays(wd *pb.WorkingDaysResponse) error {
+	data, err := protojson.MarshalOptions{Multiline: true, EmitUnpopulated: true}.Marshal(wd)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(p.w, string(data))
+	return err
+}
+
+func (p jsonPrinter) history(records []*pb.AuditRecord) error {
+	data, err := protojson.MarshalOptions{Multiline: true, EmitUnpopulated: true}.
+		Marshal(&pb.EventHistoryResponse{Records: records})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(p.w, string(data))
+	return err
+}
+
+func (p jsonPrinter) importReport(r importReport) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(p.w, string(data))
+	return err
+}
+
+func (p jsonPrinter) webhooks(hooks []*pb.Webhook) error {
+	data, err := protojson.MarshalOptions{Multiline: true, EmitUnpopulated: true}.
+		Marshal(&pb.WebhooksResponse{Webhooks: hooks})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(p.w, string(data))
+	return err
+}
+
+func (p jsonPrinter) deliveries(ds []*pb.Delivery) error {
+	data, err := protojson.MarshalOptions{Multiline: true, EmitUnpopulated: true}.
+		Marshal(&pb.DeliveriesResponse{Deliveries: ds})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(p.w, string(data))
+	return err
+}
+
+func (p jsonPrinter) attachments(as []*pb.Attachment) error {
+	data, err := protojson.MarshalOptions{Multiline: true, EmitUnpopulated: true}.
+		Marshal(&pb.AttachmentsResponse{Attachments: as})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(p.w, string(data))
+	return err
+}
+
+type icsPrinter struct{ w io.Writer }
+
+func (p icsPrinter) out() io.Writer { return p.w }
+
+func (p icsPrinter) events(events []*pb.Event) error {
+	res := make([]ics.Event, 0, len(events))
+	for _, e := range events {
+		d, _ := storage.ParseDuration(e.Duration)
+		var created time.Time
+		if e.CreatedAt != nil {
+			created = e.CreatedAt.AsTime()
+		}
+		res = append(res, ics.Event{
+			UID:         strconv.FormatInt(e.Id, 10) + "@mycalendar",
+			Summary:     e.Title,
+			Description: e.Description,
+			Start:       e.StartAt.AsTime(),
+			Duration:    d,
+			AlarmDays:   e.NoticeBefore,
+			Created:     created,
+		})
+	}
+	return ics.Encode(p.w, res)
+}
+
+func (p icsPrinter) freeBusy(*pb.FreeBusyResponse) error {
+	return errors.New("ics output is not supported for freebusy, use table or json")
+}
+
+func (p icsPrinter) calendars([]*pb.Calendar) error {
+	return errors.New("ics output is not supported for calendars, use table or json")
+}
+
+func (p icsPrinter) workingDays(*pb.WorkingDaysResponse) error {
+	return errors.New("ics output is not supported for workdays, use table or json")
+}
+
+func (p icsPrinter) history([]*pb.AuditRecord) error {
+	return errors.New("ics output is not supported for history, use table or json")
+}
+
+func (p icsPrinter) importReport(importReport) error {
+	return errors.New("ics output is not supported for import, use table or json")
+}
+
+func (p icsPrinter) webhooks([]*pb.Webhook) error {
+	return errors.New("ics output is not supported for webhooks, use table or json")
+}
+
+func (p icsPrinter) deliveries([]*pb.Delivery) error {
+	return errors.New("ics output is not supported for deliveries, use table or json")
+}
+
+func (p icsPrinter) attachments([]*pb.Attachment) error {
+	return errors.New("ics output is not supported for attachments, use table or json")
+}
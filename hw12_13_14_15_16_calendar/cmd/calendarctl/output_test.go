@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	pb "mycalendar/api/calendarpb"
+)
+
+func TestNewPrinter(t *testing.T) {
+	for _, format := range []string{"table", "json", "ics"} {
+		_, err := newPrinter(format, &bytes.Buffer{})
+		require.NoError(t, err, format)
+	}
+	_, err := newPrinter("xml", &bytes.Buffer{})
+	require.ErrorContains(t, err, `unknown output format "xml"`)
+}
+
+func TestPrintEvents(t *testing.T) {
+	events := []*pb.Event{{
+		Id:         7,
+		CalendarId: 2,
+		UserId:     "alice",
+		Title:      "Review",
+		StartAt:    timestamppb.New(time.Date(2025, 6, 10, 10, 0, 0, 0, time.UTC)),
+		Duration:   "1h",
+		Attendees: []*pb.Attendee{
+			{UserId: "bob", Status: pb.RSVPStatus_RSVP_STATUS_ACCEPTED},
+			{UserId: "carol", Status: pb.RSVPStatus_RSVP_STATUS_NEEDS_ACTION},
+		},
+	}}
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{format: "table", want: []string{
+			"ID  CALENDAR  USER   START             DURATION  NOTICE  TITLE   ATTENDEES\n",
+			"7   2         alice  2025-06-10 10:00  1h        0d      Review  bob:accepted, carol:needs-action\n",
+		}},
+		{format: "json", want: []string{`"id": "7"`, `"title": "Review"`, `"userId": "bob"`}},
+		{format: "ics", want: []string{"BEGIN:VEVENT", "UID:7@mycalendar", "SUMMARY:Review", "DTSTART:20250610T100000Z"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			p, err := newPrinter(tt.format, &buf)
+			require.NoError(t, err)
+			require.NoError(t, p.events(events))
+			for _, want := range tt.want {
+				require.Contains(t, buf.String(), want)
+			}
+		})
+	}
+}
+
+func TestPrintCalendars(t *testing.T) {
+	calendars := []*pb.Calendar{{
+		Id: 1, OwnerId: "alice", Name: "Work", IsDefault: true, TimeZone: "Europe/Moscow",
+		HolidayCountry: "RU", DeferReminders: true,
+		Shares: []*pb.CalendarShare{{UserId: "bob", Permission: pb.Permission_PERMISSION_FREE_BUSY}},
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, tablePrinter{&buf}.calendars(calendars))
+	require.Equal(t, ""+
+		"ID  OWNER  NAME            COLOR  TIME ZONE      HOLIDAYS              SHARED WITH\n"+
+		"1   alice  Work (default)         Europe/Moscow  RU (defer reminders)  bob:free-busy\n", buf.String())
+
+	require.ErrorContains(t, icsPrinter{&buf}.calendars(calendars), "ics output is not supported for calendars")
+}
+
+func TestFormatRange(t *testing.T) {
+	at := func(day, hour int) time.Time { return time.Date(2025, 6, day, hour, 0, 0, 0, time.UTC) }
+	require.Equal(t, "2025-06-10 10:00-11:00", formatRange(at(10, 10), at(10, 11)))
+	require.Equal(t, "2025-06-10 22:00-2025-06-11 01:00", formatRange(at(10, 22), at(11, 1)))
+}
+
+func TestChangedFields(t *testing.T) {
+	before := &pb.Event{Title: "Review", Duration: "1h", StartAt: timestamppb.New(time.Unix(0, 0))}
+	after := &pb.Event{Title: "Review Q2", Duration: "1h", StartAt: timestamppb.New(time.Unix(3600, 0)),
+		Attendees: []*pb.Attendee{{UserId: "bob"}}}
+	require.Equal(t, []string{"title", "start", "attendees"}, changedFields(before, after))
+	require.Nil(t, changedFields(nil, after))
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"strings"
+
+	pb "mycalendar/api/calendarpb"
+)
+
+func listWebhooks(ctx context.Context, c *client, args []string) error {
+	fs := flag.NewFlagSet("webhooks", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ctx, cancel := c.call(ctx)
+	defer cancel()
+
+	resp, err := c.api.ListWebhooks(ctx, &pb.Empty{})
+	if err != nil {
+		return err
+	}
+	return c.printer.webhooks(resp.Webhooks)
+}
+
+func createWebhook(ctx context.Context, c *client, args []string) error {
+	hook := &pb.Webhook{}
+	fs := flag.NewFlagSet("mkhook", flag.ContinueOnError)
+	fs.StringVar(&hook.Url, "url", "", "Receiver URL, http or https (required)")
+	events := fs.String("events", "", "Comma-separated event.created, event.updated, event.deleted, event.reminder; default all")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if hook.Url == "" {
+		return errors.New("-url is required")
+	}
+	if *events != "" {
+		for _, e := range strings.Split(*events, ",") {
+			hook.Events = append(hook.Events, strings.TrimSpace(e))
+		}
+	}
+	ctx, cancel := c.call(ctx)
+	defer cancel()
+
+	created, err := c.api.CreateWebhook(ctx, &pb.WebhookRequest{Webhook: hook})
+	if err != nil {
+		return err
+	}
+	return c.printer.webhooks([]*pb.Webhook{created})
+}
+
+func deleteWebhook(ctx context.Context, c *client, args []string) error {
+	id, err := webhookID("rmhook", args)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.call(ctx)
+	defer cancel()
+
+	_, err = c.api.DeleteWebhook(ctx, &pb.WebhookIDRequest{Id: id})
+	return err
+}
+
+func enableWebhook(ctx context.Context, c *client, args []string) error {
+	id, err := webhookID("enablehook", args)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.call(ctx)
+	defer cancel()
+
+	hook, err := c.api.EnableWebhook(ctx, &pb.WebhookIDRequest{Id: id})
+	if err != nil {
+		return err
+	}
+	return c.printer.webhooks([]*pb.Webhook{hook})
+}
+
+func webhookDeliveries(ctx context.Context, c *client, args []string) error {
+	id, err := webhookID("deliveries", args)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.call(ctx)
+	defer cancel()
+
+	resp, err := c.api.ListWebhookDeliveries(ctx, &pb.WebhookIDRequest{Id: id})
+	if err != nil {
+		return err
+	}
+	return c.printer.deliveries(resp.Deliveries)
+}
+
+func webhookID(name string, args []string) (int64, error) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	id := fs.Int64("id", 0, "Webhook ID (required)")
+	if err := fs.Parse(args); err != nil {
+		return 0, err
+	}
+	if *id == 0 {
+		return 0, errors.New("-id is required")
+	}
+	return *id, nil
+}
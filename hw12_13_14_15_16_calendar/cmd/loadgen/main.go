@@ -0,0 +1,354 @@
+// Loadgen нагружает HTTP и gRPC API календаря смесью чтений и записей и печатает
+// перцентили задержек и ошибки по операциям. Для прогона на машине разработчика
+// достаточно календаря с хранилищем в памяти: make loadtest.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	pb "mycalendar/api/calendarpb"
+)
+
+const usage = `Usage: loadgen [flags]
+       loadgen version
+
+Creates, reads, lists, updates and deletes events of -users synthetic users
+through the calendar HTTP and gRPC APIs for -duration and prints latency
+percentiles of all requests and of failed requests per protocol and
+operation. Run it against a calendar without
+a rate limit, for example: calendar -config configs/loadtest.yaml
+
+With delete in -mix a few get and update requests find their event already
+deleted by another worker and are reported as NotFound.
+
+Flags:
+`
+
+var (
+	grpcAddr = flag.String("grpc", "localhost:50051", "gRPC address of the calendar")
+	httpAddr = flag.String("http", "localhost:8080", "HTTP address of the calendar")
+	protos   = flag.String("proto", "grpc,http", "APIs to load: grpc, http or both, comma separated")
+	mixFlag  = flag.String("mix", "create=20,get=40,list=30,update=10", "Operation weights: create, get, list, update, delete")
+	duration = flag.Duration("duration", 30*time.Second, "How long to run")
+	workers  = flag.Int("workers", 16, "Concurrent requests")
+	rate     = flag.Float64("rate", 0, "Requests per second over all workers, 0 - as fast as possible")
+	users    = flag.Int("users", 100, "Synthetic users")
+	seed     = flag.Int("seed", 200, "Events created before the run, not measured")
+	timeout  = flag.Duration("timeout", 5*time.Second, "Timeout of one request")
+	tenantID = flag.String("tenant", "", "Tenant ID sent to the server")
+	jsonOut  = flag.Bool("json", false, "Print the report as JSON")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.Arg(0) == "version" {
+		printVersion()
+		return
+	}
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run() error {
+	mix, err := parseMix(*mixFlag)
+	if err != nil {
+		return err
+	}
+	switch {
+	case *workers <= 0:
+		return errors.New("-workers must be positive")
+	case *users <= 0:
+		return errors.New("-users must be positive")
+	case *rate < 0:
+		return errors.New("-rate must not be negative")
+	case *rate > maxRate:
+		return fmt.Errorf("-rate must not exceed %g", maxRate)
+	}
+
+	targets := make(map[string]target)
+	var names []string
+	for _, p := range strings.Split(*protos, ",") {
+		p = strings.TrimSpace(p)
+		if _, ok := targets[p]; ok {
+			continue
+		}
+		switch p {
+		case "grpc":
+			conn, err := grpc.NewClient(*grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+			targets[p] = grpcTarget{api: pb.NewCalendarServiceClient(conn), tenant: *tenantID}
+		case "http":
+			transport := http.DefaultTransport.(*http.Transport).Clone()
+			transport.MaxIdleConnsPerHost = *workers
+			targets[p] = httpTarget{client: &http.Client{Transport: transport}, base: "http://" + *httpAddr, tenant: *tenantID}
+		default:
+			return fmt.Errorf("unknown protocol %q in -proto, use grpc and http", p)
+		}
+		names = append(names, p)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	g := &generator{
+		targets: targets,
+		protos:  names,
+		mix:     mix,
+		users:   *users,
+		prefix:  "load-" + strconv.FormatInt(time.Now().Unix(), 36) + "-",
+		timeout: *timeout,
+		start:   time.Now().Truncate(time.Hour).AddDate(1, 0, 0),
+		stats:   newStats(),
+	}
+	if err := g.seed(ctx, *seed); err != nil {
+		return fmt.Errorf("seed: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, *duration)
+	defer cancel()
+	started := time.Now()
+	g.run(ctx, *workers, *rate)
+	report := g.stats.report(time.Since(started))
+
+	if *jsonOut {
+		return report.writeJSON(os.Stdout)
+	}
+	return report.writeTable(os.Stdout)
+}
+
+// maxRate - наибольший -rate: при большем интервал тикера меньше наносекунды.
+const maxRate = 1e9
+
+var operations = []string{"create", "get", "list", "update", "delete"}
+
+type weighted struct {
+	op     string
+	weight int
+}
+
+// parseMix разбирает веса операций вида "create=20,get=80".
+func parseMix(s string) ([]weighted, error) {
+	var res []weighted
+	total := 0
+	for _, part := range strings.Split(s, ",") {
+		op, w, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok || !slices.Contains(operations, op) {
+			return nil, fmt.Errorf("invalid -mix entry %q, use op=weight with op one of %s", part, strings.Join(operations, ", "))
+		}
+		n, err := strconv.Atoi(w)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid weight in -mix entry %q", part)
+		}
+		total += n
+		res = append(res, weighted{op, n})
+	}
+	if total == 0 {
+		return nil, errors.New("-mix: at least one weight must be positive")
+	}
+	return res, nil
+}
+
+// generator выбирает операции по весам и держит созданные события для чтений и изменений.
+type generator struct {
+	targets map[string]target
+	protos  []string
+	mix     []weighted
+	users   int
+	prefix  string // у каждого прогона свои пользователи, чтобы время событий не пересекалось
+	timeout time.Duration
+	start   time.Time // начало первого события, у каждого следующего - на час позже
+	seq     atomic.Int64
+	stats   *stats
+
+	mu     sync.Mutex
+	events []created
+}
+
+type created struct {
+	id    int64
+	user  string
+	start time.Time
+}
+
+// maxKept - сколько созданных событий хранить для чтений, дальше заменяются случайные.
+const maxKept = 100_000
+
+func (g *generator) seed(ctx context.Context, n int) error {
+	for range n {
+		if err := g.create(ctx, g.targets[g.protos[0]]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// run запускает workers и ждёт конца ctx. С rate запросы идут по тикам общего
+// таймера; если сервер не успевает, тики пропускаются, а не копятся.
+func (g *generator) run(ctx context.Context, workers int, rate float64) {
+	var ticks <-chan time.Time
+	if rate > 0 {
+		t := time.NewTicker(time.Duration(float64(time.Second) / rate))
+		defer t.Stop()
+		ticks = t.C
+	}
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := w; ; i++ {
+				if ticks != nil {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticks:
+					}
+				}
+				if ctx.Err() != nil {
+					return
+				}
+				proto := g.protos[i%len(g.protos)]
+				g.do(ctx, proto, g.pick())
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func (g *generator) pick() string {
+	total := 0
+	for _, w := range g.mix {
+		total += w.weight
+	}
+	n := rand.IntN(total)
+	for _, w := range g.mix {
+		if n < w.weight {
+			return w.op
+		}
+		n -= w.weight
+	}
+	return g.mix[len(g.mix)-1].op
+}
+
+// do выполняет операцию и записывает её в статистику. Пока нет созданных
+// событий, чтения и изменения заменяются созданием.
+func (g *generator) do(ctx context.Context, proto, op string) {
+	t := g.targets[proto]
+	var e created
+	if op == "get" || op == "update" || op == "delete" {
+		var ok bool
+		if e, ok = g.random(op == "delete"); !ok {
+			op = "create"
+		}
+	}
+	started := time.Now()
+	var err error
+	switch op {
+	case "create":
+		err = g.create(ctx, t)
+	case "get":
+		err = g.call(ctx, func(ctx context.Context) error { return t.get(ctx, e.user, e.id) })
+	case "list":
+		user := g.user(rand.IntN(g.users))
+		from := g.start.Add(time.Duration(rand.IntN(int(g.seq.Load())+1)) * time.Hour)
+		err = g.call(ctx, func(ctx context.Context) error { return t.list(ctx, user, from, from.AddDate(0, 0, 7)) })
+	case "update":
+		err = g.call(ctx, func(ctx context.Context) error {
+			return t.update(ctx, e.user, newEvent(e.user, e.start, "updated"))
+		})
+	case "delete":
+		err = g.call(ctx, func(ctx context.Context) error { return t.remove(ctx, e.user, e.start) })
+	}
+	// запрос, прерванный концом прогона, не считается
+	if ctx.Err() != nil {
+		return
+	}
+	g.stats.add(proto, op, time.Since(started), err)
+}
+
+func (g *generator) call(ctx context.Context, fn func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, g.timeout)
+	defer cancel()
+	return fn(ctx)
+}
+
+// create создаёт событие со своим часом начала, чтобы время никогда не было занято.
+func (g *generator) create(ctx context.Context, t target) error {
+	n := g.seq.Add(1)
+	user := g.user(int(n))
+	start := g.start.Add(time.Duration(n) * time.Hour)
+	var id int64
+	err := g.call(ctx, func(ctx context.Context) error {
+		var err error
+		id, err = t.create(ctx, user, newEvent(user, start, "load "+strconv.FormatInt(n, 10)))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	c := created{id: id, user: user, start: start}
+	if len(g.events) < maxKept {
+		g.events = append(g.events, c)
+	} else {
+		g.events[rand.IntN(len(g.events))] = c
+	}
+	return nil
+}
+
+func newEvent(user string, start time.Time, title string) *pb.Event {
+	return &pb.Event{
+		UserId:       user,
+		Title:        title,
+		StartAt:      timestamppb.New(start),
+		Duration:     "30m",
+		NoticeBefore: 1,
+	}
+}
+
+func (g *generator) user(n int) string {
+	return g.prefix + strconv.Itoa(n%g.users)
+}
+
+// random возвращает случайное созданное событие, take - убирая его из списка.
+func (g *generator) random(take bool) (created, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.events) == 0 {
+		return created{}, false
+	}
+	i := rand.IntN(len(g.events))
+	c := g.events[i]
+	if take {
+		g.events[i] = g.events[len(g.events)-1]
+		g.events = g.events[:len(g.events)-1]
+	}
+	return c, true
+}
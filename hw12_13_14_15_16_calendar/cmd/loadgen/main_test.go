@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMix(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []weighted
+		err  string
+	}{
+		{in: "create=20,get=80", want: []weighted{{"create", 20}, {"get", 80}}},
+		{in: " list=1 , delete=0", want: []weighted{{"list", 1}, {"delete", 0}}},
+		{in: "create=0,get=0", err: "at least one weight must be positive"},
+		{in: "create", err: "invalid -mix entry"},
+		{in: "drop=10", err: "invalid -mix entry"},
+		{in: "get=-1", err: "invalid weight"},
+		{in: "get=many", err: "invalid weight"},
+		{in: "", err: "invalid -mix entry"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseMix(tt.in)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRun_RateLimit(t *testing.T) {
+	old := *rate
+	t.Cleanup(func() { *rate = old })
+	*rate = 2e9
+	require.ErrorContains(t, run(), "-rate must not exceed")
+}
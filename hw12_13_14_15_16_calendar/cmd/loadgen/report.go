@@ -0,0 +1,177 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// stats собирает длительности и ошибки запросов по протоколу и операции.
+type stats struct {
+	mu   sync.Mutex
+	keys map[key]*series
+}
+
+type key struct {
+	proto string
+	op    string
+}
+
+type series struct {
+	latencies    []time.Duration // все запросы
+	errLatencies []time.Duration // запросы с ошибкой
+	errors       map[string]int  // по errorKind
+	lastError    string          // пример для отчёта
+}
+
+func newStats() *stats {
+	return &stats{keys: make(map[key]*series)}
+}
+
+func (s *stats) add(proto, op string, d time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k := key{proto, op}
+	sr, ok := s.keys[k]
+	if !ok {
+		sr = &series{errors: make(map[string]int)}
+		s.keys[k] = sr
+	}
+	sr.latencies = append(sr.latencies, d)
+	if err != nil {
+		sr.errLatencies = append(sr.errLatencies, d)
+		sr.errors[errorKind(err)]++
+		sr.lastError = err.Error()
+	}
+}
+
+// Row - строка отчёта. Задержки в миллисекундах: P50-Max по всем запросам,
+// Err* - только по запросам с ошибкой, быстрые отказы не прячут медленные.
+type Row struct {
+	Proto     string         `json:"proto"`
+	Op        string         `json:"op"`
+	Requests  int            `json:"requests"`
+	Errors    int            `json:"errors"`
+	RPS       float64        `json:"rps"`
+	P50       float64        `json:"p50Ms"`
+	P90       float64        `json:"p90Ms"`
+	P99       float64        `json:"p99Ms"`
+	Max       float64        `json:"maxMs"`
+	ErrP50    float64        `json:"errP50Ms"`
+	ErrP99    float64        `json:"errP99Ms"`
+	ErrorKind map[string]int `json:"errorKinds,omitempty"`
+	LastError string         `json:"lastError,omitempty"`
+}
+
+// Report - итог прогона: строки по протоколам и операциям и общая строка "all".
+type Report struct {
+	Elapsed float64 `json:"elapsedSeconds"`
+	Rows    []Row   `json:"rows"`
+}
+
+func (s *stats) report(elapsed time.Duration) Report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := Report{Elapsed: elapsed.Seconds()}
+	all := &series{errors: make(map[string]int)}
+	keys := slices.SortedFunc(maps.Keys(s.keys), func(a, b key) int {
+		if c := cmp.Compare(a.proto, b.proto); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.op, b.op)
+	})
+	for _, k := range keys {
+		sr := s.keys[k]
+		res.Rows = append(res.Rows, sr.row(k.proto, k.op, elapsed))
+		all.latencies = append(all.latencies, sr.latencies...)
+		all.errLatencies = append(all.errLatencies, sr.errLatencies...)
+		for kind, n := range sr.errors {
+			all.errors[kind] += n
+		}
+	}
+	res.Rows = append(res.Rows, all.row("all", "all", elapsed))
+	return res
+}
+
+func (sr *series) row(proto, op string, elapsed time.Duration) Row {
+	slices.Sort(sr.latencies)
+	slices.Sort(sr.errLatencies)
+	r := Row{
+		Proto:     proto,
+		Op:        op,
+		Requests:  len(sr.latencies),
+		Errors:    len(sr.errLatencies),
+		P50:       ms(percentile(sr.latencies, 0.50)),
+		P90:       ms(percentile(sr.latencies, 0.90)),
+		P99:       ms(percentile(sr.latencies, 0.99)),
+		Max:       ms(percentile(sr.latencies, 1)),
+		ErrP50:    ms(percentile(sr.errLatencies, 0.50)),
+		ErrP99:    ms(percentile(sr.errLatencies, 0.99)),
+		LastError: sr.lastError,
+	}
+	if r.Errors > 0 {
+		r.ErrorKind = sr.errors
+	}
+	if elapsed > 0 {
+		r.RPS = float64(r.Requests) / elapsed.Seconds()
+	}
+	return r
+}
+
+// percentile возвращает p-й перцентиль отсортированных длительностей
+// методом ближайшего ранга: наименьшее значение, не меньше которого
+// доля p всех значений.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(0, min(i, len(sorted)-1))]
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func (r Report) writeTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "PROTO\tOP\tREQUESTS\tERRORS\tRPS\tP50 ms\tP90 ms\tP99 ms\tMAX ms\tERR P50 ms\tERR P99 ms\t")
+	for _, row := range r.Rows {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%.1f\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t\n",
+			row.Proto, row.Op, row.Requests, row.Errors, row.RPS, row.P50, row.P90, row.P99, row.Max, row.ErrP50, row.ErrP99)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	first := true
+	for _, row := range r.Rows {
+		if row.Proto == "all" || len(row.ErrorKind) == 0 {
+			continue
+		}
+		if first {
+			fmt.Fprintln(w, "\nErrors:")
+			first = false
+		}
+		for _, kind := range slices.Sorted(maps.Keys(row.ErrorKind)) {
+			fmt.Fprintf(w, "  %s %s: %s x%d\n", row.Proto, row.Op, kind, row.ErrorKind[kind])
+		}
+		fmt.Fprintf(w, "    last: %s\n", row.LastError)
+	}
+	fmt.Fprintf(w, "\nelapsed %.1fs\n", r.Elapsed)
+	return nil
+}
+
+func (r Report) writeJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
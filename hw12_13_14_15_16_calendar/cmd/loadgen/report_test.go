@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPercentile(t *testing.T) {
+	ten := make([]time.Duration, 10)
+	for i := range ten {
+		ten[i] = time.Duration(i+1) * time.Millisecond
+	}
+	tests := []struct {
+		name   string
+		sorted []time.Duration
+		p      float64
+		want   time.Duration
+	}{
+		{name: "empty", sorted: nil, p: 0.5, want: 0},
+		{name: "one", sorted: ten[:1], p: 0.99, want: time.Millisecond},
+		{name: "p0", sorted: ten, p: 0, want: time.Millisecond},
+		{name: "p50 of 10", sorted: ten, p: 0.5, want: 5 * time.Millisecond},
+		{name: "p90 of 10", sorted: ten, p: 0.9, want: 9 * time.Millisecond},
+		{name: "p99 of 10", sorted: ten, p: 0.99, want: 10 * time.Millisecond},
+		{name: "max", sorted: ten, p: 1, want: 10 * time.Millisecond},
+		// ранг округляется вверх: 0.5*3 = 1.5 -> второе значение
+		{name: "p50 of 3", sorted: ten[:3], p: 0.5, want: 2 * time.Millisecond},
+		{name: "p25 of 4", sorted: ten[:4], p: 0.25, want: time.Millisecond},
+		{name: "p26 of 4", sorted: ten[:4], p: 0.26, want: 2 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, percentile(tt.sorted, tt.p))
+		})
+	}
+}
+
+func TestStats_ErrorLatency(t *testing.T) {
+	s := newStats()
+	s.add("grpc", "get", 2*time.Millisecond, nil)
+	s.add("grpc", "get", 4*time.Millisecond, nil)
+	s.add("grpc", "get", 100*time.Millisecond, errors.New("boom"))
+
+	r := s.report(time.Second)
+	require.Len(t, r.Rows, 2)
+	row := r.Rows[0]
+	require.Equal(t, 3, row.Requests)
+	require.Equal(t, 1, row.Errors)
+	require.InDelta(t, 3.0, row.RPS, 1e-9)
+	// медленная ошибка попадает в общие перцентили
+	require.InDelta(t, 4.0, row.P50, 1e-9)
+	require.InDelta(t, 100.0, row.Max, 1e-9)
+	require.InDelta(t, 100.0, row.ErrP50, 1e-9)
+	require.Equal(t, map[string]int{"transport": 1}, row.ErrorKind)
+	require.Equal(t, "boom", row.LastError)
+
+	all := r.Rows[1]
+	require.Equal(t, "all", all.Proto)
+	require.Equal(t, 3, all.Requests)
+	require.InDelta(t, 100.0, all.ErrP99, 1e-9)
+}
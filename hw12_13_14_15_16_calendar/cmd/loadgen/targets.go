@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	pb "mycalendar/api/calendarpb"
+	"mycalendar/internal/identity"
+	"mycalendar/internal/tenant"
+)
+
+// target - один из API календаря. Запросы идут от имени user.
+type target interface {
+	create(ctx context.Context, user string, e *pb.Event) (int64, error)
+	get(ctx context.Context, user string, id int64) error
+	list(ctx context.Context, user string, from, to time.Time) error
+	update(ctx context.Context, user string, e *pb.Event) error
+	remove(ctx context.Context, user string, start time.Time) error
+}
+
+type grpcTarget struct {
+	api    pb.CalendarServiceClient
+	tenant string
+}
+
+func (t grpcTarget) with(ctx context.Context, user string) context.Context {
+	ctx = metadata.AppendToOutgoingContext(ctx, identity.UserIDHeader, user)
+	if t.tenant != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, tenant.Header, t.tenant)
+	}
+	return ctx
+}
+
+func (t grpcTarget) create(ctx context.Context, user string, e *pb.Event) (int64, error) {
+	resp, err := t.api.AddEvent(t.with(ctx, user), &pb.EventRequest{Event: e})
+	if err != nil {
+		return 0, err
+	}
+	return resp.Id, nil
+}
+
+func (t grpcTarget) get(ctx context.Context, user string, id int64) error {
+	_, err := t.api.GetEvent(t.with(ctx, user), &pb.GetEventRequest{Id: id})
+	return err
+}
+
+func (t grpcTarget) list(ctx context.Context, user string, from, to time.Time) error {
+	_, err := t.api.ListEvents(t.with(ctx, user), &pb.ListEventsRequest{
+		UserId: user,
+		From:   timestamppb.New(from),
+		To:     timestamppb.New(to),
+	})
+	return err
+}
+
+func (t grpcTarget) update(ctx context.Context, user string, e *pb.Event) error {
+	_, err := t.api.UpdateEvent(t.with(ctx, user), &pb.EventRequest{Event: e})
+	return err
+}
+
+func (t grpcTarget) remove(ctx context.Context, user string, start time.Time) error {
+	_, err := t.api.DeleteEvent(t.with(ctx, user), &pb.DeleteRequest{UserId: user, Start: timestamppb.New(start)})
+	return err
+}
+
+// httpTarget ходит в REST API grpc-gateway.
+type httpTarget struct {
+	client *http.Client
+	base   string // http://host:port
+	tenant string
+}
+
+// httpError - ответ с кодом 4xx или 5xx.
+type httpError struct {
+	code int
+	body string
+}
+
+func (e httpError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.code, e.body)
+}
+
+// do отправляет запрос и разбирает ответ в out, если он не nil.
+func (t httpTarget) do(ctx context.Context, user, method, path string, body proto.Message, out proto.Message) error {
+	var rd io.Reader
+	if body != nil {
+		data, err := protojson.Marshal(body)
+		if err != nil {
+			return err
+		}
+		rd = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, t.base+path, rd)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(identity.UserIDHeader, user)
+	if t.tenant != "" {
+		req.Header.Set(tenant.Header, t.tenant)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return httpError{code: resp.StatusCode, body: string(bytes.TrimSpace(data))}
+	}
+	if out == nil {
+		return nil
+	}
+	return protojson.Unmarshal(data, out)
+}
+
+func (t httpTarget) create(ctx context.Context, user string, e *pb.Event) (int64, error) {
+	var resp pb.AddEventResponse
+	if err := t.do(ctx, user, http.MethodPost, "/events", e, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Id, nil
+}
+
+func (t httpTarget) get(ctx context.Context, user string, id int64) error {
+	return t.do(ctx, user, http.MethodGet, "/events/"+strconv.FormatInt(id, 10), nil, nil)
+}
+
+func (t httpTarget) list(ctx context.Context, user string, from, to time.Time) error {
+	q := url.Values{
+		"user_id": {user},
+		"from":    {from.Format(time.RFC3339)},
+		"to":      {to.Format(time.RFC3339)},
+	}
+	return t.do(ctx, user, http.MethodGet, "/events?"+q.Encode(), nil, nil)
+}
+
+func (t httpTarget) update(ctx context.Context, user string, e *pb.Event) error {
+	return t.do(ctx, user, http.MethodPut, "/events", e, nil)
+}
+
+func (t httpTarget) remove(ctx context.Context, user string, start time.Time) error {
+	q := url.Values{"user_id": {user}, "start": {start.Format(time.RFC3339)}}
+	return t.do(ctx, user, http.MethodDelete, "/events?"+q.Encode(), nil, nil)
+}
+
+// errorKind - класс ошибки для отчёта: код gRPC, HTTP статус или сбой соединения.
+func errorKind(err error) string {
+	var he httpError
+	switch {
+	case errors.As(err, &he):
+		return "HTTP " + strconv.Itoa(he.code)
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	}
+	if st, ok := status.FromError(err); ok {
+		return st.Code().String()
+	}
+	return "transport"
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"mycalendar/internal/archive"
+	"mycalendar/internal/blob"
+	"mycalendar/internal/config"
+	sqlstorage "mycalendar/internal/storage/sql"
+	"mycalendar/internal/tenant"
+)
+
+// importArchive реализует подкоманду "import [-tenant id] <file>...": возвращает
+// события из архивов в базу как действующие события тенанта, вместе с вложениями.
+// Файлы вложений возвращаются из scheduler.archiveDir в attachments.dir.
+func importArchive(ctx context.Context) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	tenantID := fs.String("tenant", tenant.Default, "Tenant the archived events belong to")
+	_ = fs.Parse(flag.Args()[1:])
+	files := fs.Args()
+	if len(files) == 0 {
+		return errors.New("usage: import [-tenant id] <archive.jsonl.gz>...")
+	}
+	if !tenant.Valid(*tenantID) {
+		return fmt.Errorf("%w: %q", tenant.ErrInvalid, *tenantID)
+	}
+	ctx = tenant.With(ctx, *tenantID)
+	conf, err := config.Load(configFile, overrides)
+	if err != nil {
+		return fmt.Errorf("cannot read config: %w", err)
+	}
+
+	store := sqlstorage.New(sqlstorage.OptionsFromConfig(conf.PSQL))
+	if err := store.Connect(ctx, conf.PSQL.DSN); err != nil {
+		return fmt.Errorf("DB connect failed: %w", err)
+	}
+	defer store.Close()
+
+	// Содержимое файлов вложений возвращается из каталога архива в каталог вложений.
+	var blobs archive.Files
+	if conf.Scheduler.ArchiveDir != "" && conf.Attachments.Dir != "" {
+		arch, err := archive.New(conf.Scheduler.ArchiveDir)
+		if err != nil {
+			return err
+		}
+		if blobs.Store, err = blob.NewFS(conf.Attachments.Dir); err != nil {
+			return fmt.Errorf("attachments: %w", err)
+		}
+		blobs.Archive = arch.Files()
+	}
+
+	for _, file := range files {
+		events, attachments, err := archive.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		res, err := archive.Import(ctx, store, events, attachments, blobs)
+		fmt.Printf("%s: imported %d, skipped %d (time already taken), attachment files skipped %d\n",
+			file, res.Imported, res.Skipped, res.FilesSkipped)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -5,22 +5,33 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"mycalendar/internal/archive"
+	"mycalendar/internal/blob"
 	"mycalendar/internal/config"
+	"mycalendar/internal/holidays"
+	"mycalendar/internal/lifecycle"
+	"mycalendar/internal/logger"
 	"mycalendar/internal/mq"
 	"mycalendar/internal/scheduler"
 	"mycalendar/internal/storage"
 	sqlstorage "mycalendar/internal/storage/sql"
+	"mycalendar/internal/webhook"
 )
 
-var configFile string
+var (
+	configFile string
+	overrides  = config.Overrides{}
+)
 
 func init() {
-	flag.StringVar(&configFile, "config", "configs/config.toml", "Path to configuration file")
+	flag.StringVar(&configFile, "config", "configs/config.toml", "Path to configuration file (toml or yaml)")
+	overrides.RegisterFlags(flag.CommandLine)
 }
 
 func main() {
@@ -29,6 +40,18 @@ func main() {
 		printVersion()
 		return
 	}
+	if flag.Arg(0) == "config" {
+		if err := config.PrintCommand(os.Stdout, flag.Args()[1:], configFile, overrides); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if flag.Arg(0) == "import" {
+		if err := importArchive(context.Background()); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 	if err := mainImpl(); err != nil {
 		log.Fatal(err)
 	}
@@ -38,38 +61,121 @@ func mainImpl() error {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
-	conf, err := config.Read(configFile)
+	conf, err := config.Load(configFile, overrides)
 	if err != nil {
 		return fmt.Errorf("cannot read config: %w", err)
 	}
+	if err := conf.Validate(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	mylogger, err := logger.New(logger.Options{
+		Level:      conf.Logger.Level,
+		Format:     conf.Logger.Format,
+		Path:       conf.Logger.Path,
+		MaxSizeMB:  conf.Logger.MaxSizeMB,
+		MaxAgeDays: conf.Logger.MaxAgeDays,
+		MaxBackups: conf.Logger.MaxBackups,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer mylogger.Close()
+	slog.SetDefault(mylogger.Logger)
+
+	// Ресурсы закрываются в обратном порядке: сначала хранилище, потом очередь.
+	group := lifecycle.New(mylogger.Component("lifecycle"), time.Duration(conf.Shutdown.TimeoutSeconds)*time.Second)
+
+	var arch *archive.Archive
+	if conf.Scheduler.ArchiveDir != "" {
+		if arch, err = archive.New(conf.Scheduler.ArchiveDir); err != nil {
+			return err
+		}
+	}
 
 	rmq, err := mq.NewRabbitMQ(conf.Queue.URL)
-	fmt.Println(conf.Queue.URL)
 	if err != nil {
 		return fmt.Errorf("MQ error: %w", err)
 	}
-	defer rmq.Close()
+	group.OnClose("mq", rmq.Close)
 
 	var store storage.EventsStorage
 
-	sqlStore := sqlstorage.New()
+	sqlStore := sqlstorage.New(sqlstorage.OptionsFromConfig(conf.PSQL))
 	if err := sqlStore.Connect(ctx, conf.PSQL.DSN); err != nil {
+		_ = rmq.Close()
 		return fmt.Errorf("DB connect failed: %w", err)
 	}
+	group.OnClose("storage", sqlStore.Close)
 	store = sqlStore
 
-	s := scheduler.NewScheduler(store, rmq, conf.Queue.Name, conf.Scheduler.CleanupOlderThanDays)
-
-	ticker := time.NewTicker(time.Duration(conf.Scheduler.IntervalSeconds) * time.Second)
-	defer ticker.Stop()
+	s := scheduler.NewScheduler(store, rmq, conf.Queue.Name, conf.Scheduler.CleanupOlderThanDays,
+		mylogger.Component("scheduler"))
+	s.SetTenants(conf.Tenants.Names, conf.Tenants.RemindersPerRun)
+	if arch != nil {
+		s.SetArchiver(arch)
+	}
+	if conf.Webhooks.Enabled {
+		s.SetWebhooks(webhook.New(sqlStore))
+	}
+	// Ссылки архивируются и без каталога вложений, файлы - только с ним.
+	var blobs blob.Store
+	if conf.Attachments.Dir != "" {
+		fs, err := blob.NewFS(conf.Attachments.Dir)
+		if err != nil {
+			return fmt.Errorf("attachments: %w", err)
+		}
+		blobs = fs
+		if arch != nil {
+			arch.SetBlobs(fs)
+		}
+	}
+	s.SetAttachments(sqlStore, blobs)
+	holidayCalendars, err := holidays.Load(conf.Holidays.Countries, conf.Holidays.Files)
+	if err != nil {
+		return fmt.Errorf("holidays: %w", err)
+	}
+	s.SetHolidays(sqlStore, holidayCalendars)
 
-	for {
+	// Безопасные изменения применяем на лету, остальные требуют перезапуска.
+	intervalCh := make(chan time.Duration, 1)
+	reloader := config.NewReloader(configFile, overrides, conf, mylogger.Component("config"))
+	reloader.OnChange("logger.level", func(c *config.Config) {
+		_ = mylogger.SetLevel(c.Logger.Level)
+	})
+	reloader.OnChange("scheduler.intervalSeconds", func(c *config.Config) {
+		// пока идёт проход, интервал из прошлой перезагрузки ещё может лежать в канале
 		select {
-		case <-ticker.C:
-			s.Run(ctx)
-		case <-ctx.Done():
-			log.Println("scheduler: context cancelled, exiting")
-			return nil
+		case <-intervalCh:
+		default:
 		}
-	}
+		intervalCh <- time.Duration(c.Scheduler.IntervalSeconds) * time.Second
+	})
+	reloader.OnChange("scheduler.cleanupOlderThanDays", func(c *config.Config) {
+		s.SetRetention(c.Scheduler.CleanupOlderThanDays)
+	})
+	group.Add("config", func(ctx context.Context) error {
+		reloader.Run(ctx, time.Duration(conf.Reload.WatchSeconds)*time.Second)
+		return nil
+	}, nil)
+
+	// Текущий проход планировщика доводится до конца: ctx отменяется только между проходами.
+	group.Add("scheduler", func(ctx context.Context) error {
+		ticker := time.NewTicker(time.Duration(conf.Scheduler.IntervalSeconds) * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.Run(context.WithoutCancel(ctx))
+			case d := <-intervalCh:
+				ticker.Reset(d)
+			case <-ctx.Done():
+				mylogger.Info("context cancelled, exiting")
+				return nil
+			}
+		}
+	}, nil)
+
+	return group.Run(ctx)
 }
@@ -5,18 +5,29 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
 	"os/signal"
+	"sync/atomic"
 	"syscall"
+	"time"
 
 	"mycalendar/internal/config"
+	"mycalendar/internal/lifecycle"
+	"mycalendar/internal/logger"
 	"mycalendar/internal/mq"
+	"mycalendar/internal/ratelimit"
+	"mycalendar/internal/tenant"
 )
 
-var configFile string
+var (
+	configFile string
+	overrides  = config.Overrides{}
+)
 
 func init() {
-	flag.StringVar(&configFile, "config", "configs/config.toml", "Path to configuration file")
+	flag.StringVar(&configFile, "config", "configs/config.toml", "Path to configuration file (toml or yaml)")
+	overrides.RegisterFlags(flag.CommandLine)
 }
 
 func main() {
@@ -25,6 +36,12 @@ func main() {
 		printVersion()
 		return
 	}
+	if flag.Arg(0) == "config" {
+		if err := config.PrintCommand(os.Stdout, flag.Args()[1:], configFile, overrides); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 	if err := mainImpl(); err != nil {
 		log.Fatal(err)
 	}
@@ -34,31 +51,102 @@ func mainImpl() error {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
-	conf, err := config.Read(configFile)
+	conf, err := config.Load(configFile, overrides)
 	if err != nil {
 		return fmt.Errorf("cannot read config: %w", err)
 	}
+	if err := conf.Validate(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	mylogger, err := logger.New(logger.Options{
+		Level:      senderLevel(conf),
+		Format:     conf.Logger.Format,
+		Path:       conf.Logger.Path,
+		MaxSizeMB:  conf.Logger.MaxSizeMB,
+		MaxAgeDays: conf.Logger.MaxAgeDays,
+		MaxBackups: conf.Logger.MaxBackups,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer mylogger.Close()
+	slog.SetDefault(mylogger.Logger)
+	senderLog := mylogger.Component("sender")
+
+	group := lifecycle.New(mylogger.Component("lifecycle"), time.Duration(conf.Shutdown.TimeoutSeconds)*time.Second)
 
 	rmq, err := mq.NewRabbitMQ(conf.Queue.URL)
 	if err != nil {
 		return fmt.Errorf("can't connect to MQ: %w", err)
 	}
-	defer rmq.Close()
+	group.OnClose("mq", rmq.Close)
 
-	handler := func(msg []byte) {
-		fmt.Printf("📧 Уведомление: %s\n", string(msg))
+	var channel atomic.Value
+	channel.Store(conf.Sender.Channel)
+
+	reloader := config.NewReloader(configFile, overrides, conf, mylogger.Component("config"))
+	setLevel := func(c *config.Config) {
+		_ = mylogger.SetLevel(senderLevel(c))
+	}
+	reloader.OnChange("logger.level", setLevel)
+	reloader.OnChange("sender.logLevel", setLevel)
+	reloader.OnChange("sender.channel", func(c *config.Config) {
+		channel.Store(c.Sender.Channel)
+	})
+	group.Add("config", func(ctx context.Context) error {
+		reloader.Run(ctx, time.Duration(conf.Reload.WatchSeconds)*time.Second)
+		return nil
+	}, nil)
+
+	// Лимит у каждого тенанта свой: его очередь обрабатывается отдельно
+	// и ждёт токена, не задерживая очереди других тенантов.
+	var limiter *ratelimit.Limiter
+	if conf.Tenants.SendRPS > 0 {
+		limiter = ratelimit.New(conf.Tenants.SendRPS, max(1, int(conf.Tenants.SendRPS)))
+	}
+	handler := func(t string) func([]byte) error {
+		return func(msg []byte) error {
+			for limiter != nil {
+				ok, wait := limiter.Allow(t)
+				if ok {
+					break
+				}
+				time.Sleep(wait)
+			}
+			if channel.Load() == "stdout" {
+				if _, err := fmt.Printf("📧 Уведомление [%s]: %s\n", t, string(msg)); err != nil {
+					senderLog.Error("cannot send notification", "tenant", t, "error", err)
+					return err
+				}
+				return nil
+			}
+			senderLog.Info("📧 Уведомление", "tenant", t, "notification", string(msg))
+			return nil
+		}
 	}
 
-	// Start consuming in the background
-	go func() {
-		if err := rmq.Consume(conf.Queue.Name, handler); err != nil {
-			log.Printf("consume failed: %v", err)
-			cancel() // cancel context to exit main
+	// Сообщения обрабатываются, пока не закрыто соединение с очередью.
+	// Очередь подтверждается после отправки, поэтому уведомления, не отправленные
+	// до остановки, достанутся следующему запуску.
+	group.Add("consumer", func(ctx context.Context) error {
+		for _, t := range conf.Tenants.Names {
+			if err := rmq.Consume(tenant.Queue(conf.Queue.Name, t), conf.Sender.Prefetch, handler(t)); err != nil {
+				return fmt.Errorf("consume failed for tenant %s: %w", t, err)
+			}
 		}
-	}()
+		<-ctx.Done()
+		senderLog.Info("shutting down gracefully")
+		return nil
+	}, nil)
+
+	return group.Run(ctx)
+}
 
-	// Block until context is done (e.g., signal received)
-	<-ctx.Done()
-	log.Println("shutting down gracefully")
-	return nil
+// senderLevel - уровень логов рассыльщика: sender.logLevel, если задан, иначе logger.level.
+func senderLevel(c *config.Config) string {
+	if c.Sender.LogLevel != "" {
+		return c.Sender.LogLevel
+	}
+	return c.Logger.Level
 }
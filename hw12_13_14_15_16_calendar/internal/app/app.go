@@ -2,34 +2,49 @@ package app
 
 import (
 	"context"
-	"log/slog"
-	"os"
+	"fmt"
+	"slices"
 	"time"
 
-	"github.com/lmittmann/tint"
+	"mycalendar/internal/blob"
+	"mycalendar/internal/holidays"
+	"mycalendar/internal/identity"
 	"mycalendar/internal/storage"
 )
 
 type App struct {
-	events storage.EventsStorage
-	logger Logger
+	events       storage.Storage
+	logger       Logger
+	webhooks     WebhookNotifier // nil - без уведомлений подписчикам
+	webhookGuard WebhookGuard    // nil - адреса подписок не проверяются
+
+	blobs        blob.Store // nil - только вложения-ссылки
+	attachLimits AttachmentLimits
+
+	holidays *holidays.Registry
 }
 
 type Logger interface {
-	Printf(format string, v ...any)
-	Info(msg string)
-	Error(msg string)
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Error(msg string, args ...any)
 }
 
-func New(logger Logger, events storage.EventsStorage) (*App, error) {
+func New(logger Logger, events storage.Storage) (*App, error) {
 	return &App{
-		events: events,
-		logger: logger,
+		events:   events,
+		logger:   logger,
+		holidays: holidays.NewRegistry(),
 	}, nil
 }
 
-func (a *App) CreateEvent(ctx context.Context, uID, title, desc, dur string, noticeB int32, startAt time.Time) error {
-	now := time.Now()
+// BatchResult - результат одной операции в пакетном запросе.
+type BatchResult struct {
+	ID  int64
+	Err error
+}
+
+func (a *App) CreateEvent(ctx context.Context, uID, title, desc, dur string, noticeB int32, startAt time.Time) (int64, error) {
 	e := storage.Event{
 		UserID:        uID,
 		Title:         title,
@@ -37,12 +52,82 @@ func (a *App) CreateEvent(ctx context.Context, uID, title, desc, dur string, not
 		StartDateTime: startAt,
 		Duration:      dur,
 		NoticeBefore:  noticeB,
-		CreatedAt:     now,
 	}
-	return a.events.AddEvent(ctx, e)
+	return a.AddEvent(ctx, e)
 }
 
+// AddEvent создаёт событие от имени пользователя из x-user-id: e.UserID можно
+// не указывать, чужой отклоняется. В календарь e.CalendarID у него должно быть
+// право записи; без календаря событие попадает в календарь по умолчанию.
+// Без x-user-id владельцем считается e.UserID, но писать можно только в его
+// календарь по умолчанию. Участники из e.Attendees приглашаются в той же транзакции.
+func (a *App) AddEvent(ctx context.Context, e storage.Event) (int64, error) {
+	if err := a.checkNewEvent(ctx, &e); err != nil {
+		return 0, err
+	}
+	e.CreatedAt = time.Now()
+	attendees := attendeeIDs(e.Attendees)
+	e.Attendees = nil
+	after, err := a.change(ctx, storage.AuditCreate, func(ctx context.Context, tx storage.Storage) (*storage.Event, *storage.Event, error) {
+		id, err := tx.AddEvent(ctx, e)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(attendees) > 0 {
+			if err := tx.InviteAttendees(ctx, id, attendees); err != nil {
+				return nil, nil, err
+			}
+		}
+		created, err := tx.GetEvent(ctx, id)
+		return nil, &created, err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return after.EventID, nil
+}
+
+// checkNewEvent проверяет права на создание события e и подставляет владельца
+// из x-user-id, если он не указан.
+func (a *App) checkNewEvent(ctx context.Context, e *storage.Event) error {
+	actor := identity.UserIDFromContext(ctx)
+	if e.UserID == "" {
+		e.UserID = actor
+	}
+	if actor != "" && e.UserID != actor {
+		return fmt.Errorf("%w: %s cannot create events of %s", ErrForbidden, actor, e.UserID)
+	}
+	if e.CalendarID != 0 {
+		c, err := a.events.GetCalendar(ctx, e.CalendarID)
+		if err != nil {
+			return err
+		}
+		if actor == "" || !c.Access(actor).Allows(storage.PermWrite) {
+			return fmt.Errorf("%w: %q cannot write to calendar %d", ErrForbidden, actor, c.ID)
+		}
+	}
+	return nil
+}
+
+func attendeeIDs(attendees []storage.Attendee) []string {
+	if len(attendees) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(attendees))
+	for _, at := range attendees {
+		ids = append(ids, at.UserID)
+	}
+	return ids
+}
+
+// UpdateEvent меняет событие пользователя uID, начинающееся в startAt. Менять
+// может пользователь из x-user-id, если он владелец или может писать в календарь
+// события; без uID ищется его собственное событие.
 func (a *App) UpdateEvent(ctx context.Context, uID, title, desc, dur string, noticeB int32, startAt time.Time) error {
+	actor := identity.UserIDFromContext(ctx)
+	if uID == "" {
+		uID = actor
+	}
 	now := time.Now()
 	e := storage.Event{
 		UserID:        uID,
@@ -53,47 +138,276 @@ func (a *App) UpdateEvent(ctx context.Context, uID, title, desc, dur string, not
 		NoticeBefore:  noticeB,
 		CreatedAt:     now,
 	}
-	return a.events.UpdateEvent(ctx, e)
+	_, err := a.change(ctx, storage.AuditUpdate, func(ctx context.Context, tx storage.Storage) (*storage.Event, *storage.Event, error) {
+		before, err := findEvent(ctx, tx, uID, startAt)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := canEdit(ctx, tx, actor, *before); err != nil {
+			return nil, nil, err
+		}
+		if err := tx.UpdateEvent(ctx, e); err != nil {
+			return nil, nil, err
+		}
+		after, err := tx.GetEvent(ctx, before.EventID)
+		return before, &after, err
+	})
+	return err
 }
 
+// DeleteEvent переносит в корзину событие пользователя userID, начинающееся в
+// start. Права - как у UpdateEvent.
 func (a *App) DeleteEvent(ctx context.Context, userID string, start time.Time) error {
-	return a.events.DeleteEvent(ctx, userID, start)
+	actor := identity.UserIDFromContext(ctx)
+	if userID == "" {
+		userID = actor
+	}
+	_, err := a.change(ctx, storage.AuditDelete, func(ctx context.Context, tx storage.Storage) (*storage.Event, *storage.Event, error) {
+		before, err := findEvent(ctx, tx, userID, start)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := canEdit(ctx, tx, actor, *before); err != nil {
+			return nil, nil, err
+		}
+		return before, nil, tx.DeleteEvent(ctx, userID, start)
+	})
+	return err
+}
+
+// BatchCreateEvents создаёт события по одному, ошибка одного события не мешает остальным.
+func (a *App) BatchCreateEvents(ctx context.Context, events []storage.Event) []BatchResult {
+	results := make([]BatchResult, 0, len(events))
+	for _, e := range events {
+		id, err := a.AddEvent(ctx, e)
+		results = append(results, BatchResult{ID: id, Err: err})
+	}
+	return results
+}
+
+// BatchDeleteEvents удаляет события по ID, ошибка одного события не мешает остальным.
+// Права на каждое событие - как у UpdateEvent.
+func (a *App) BatchDeleteEvents(ctx context.Context, ids []int64) []BatchResult {
+	results := make([]BatchResult, 0, len(ids))
+	for _, id := range ids {
+		results = append(results, BatchResult{ID: id, Err: a.deleteEventByID(ctx, id)})
+	}
+	return results
+}
+
+func (a *App) deleteEventByID(ctx context.Context, id int64) error {
+	_, err := a.change(ctx, storage.AuditDelete, func(ctx context.Context, tx storage.Storage) (*storage.Event, *storage.Event, error) {
+		before, err := tx.GetEvent(ctx, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := canEdit(ctx, tx, identity.UserIDFromContext(ctx), before); err != nil {
+			return nil, nil, err
+		}
+		return &before, nil, tx.DeleteEventByID(ctx, id)
+	})
+	return err
 }
 
+// DeleteOldEvents переносит в корзину события пользователя из x-user-id,
+// начавшиеся до before, и пишет каждое в журнал в той же транзакции. События,
+// куда его только пригласили, не трогаются.
 func (a *App) DeleteOldEvents(ctx context.Context, before time.Time) error {
-	return a.events.DeleteOldEvents(ctx, before)
+	actor := identity.UserIDFromContext(ctx)
+	if actor == "" {
+		return fmt.Errorf("%w: user is required", ErrForbidden)
+	}
+	var old []storage.Event
+	err := a.events.InTx(ctx, func(ctx context.Context, tx storage.Storage) error {
+		events, err := tx.ListEvents(ctx, storage.EventFilter{UserID: actor, To: before})
+		if err != nil {
+			return err
+		}
+		old = old[:0]
+		for _, e := range events {
+			if e.UserID != actor {
+				continue
+			}
+			if err := tx.DeleteEventByID(ctx, e.EventID); err != nil {
+				return err
+			}
+			if err := a.record(ctx, tx, storage.AuditDelete, &e, nil); err != nil {
+				return err
+			}
+			old = append(old, e)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, e := range old {
+		a.notify(ctx, storage.AuditDelete, &e, nil)
+	}
+	return nil
 }
 
+// GetEvent возвращает событие пользователю из x-user-id, если он его видит
+// (см. visibleEvents).
+func (a *App) GetEvent(ctx context.Context, id int64) (storage.Event, error) {
+	viewer := identity.UserIDFromContext(ctx)
+	e, err := a.events.GetEvent(ctx, id)
+	if err != nil {
+		return storage.Event{}, err
+	}
+	visible, err := a.visibleEvents(ctx, viewer, []storage.Event{e})
+	if err != nil {
+		return storage.Event{}, err
+	}
+	if len(visible) == 0 {
+		return storage.Event{}, fmt.Errorf("%w: %s has no access to event %d", ErrForbidden, viewer, id)
+	}
+	return visible[0], nil
+}
+
+// GetEvents и остальные списки возвращают только события, которые видит
+// пользователь из x-user-id (см. visibleEvents).
 func (a *App) GetEvents(ctx context.Context) ([]storage.Event, error) {
-	return a.events.GetEvents(ctx)
+	return a.visible(ctx, func() ([]storage.Event, error) { return a.events.GetEvents(ctx) })
+}
+
+// ListEvents - f.UserID только сужает выборку, чужие события видны по правам на их календари.
+func (a *App) ListEvents(ctx context.Context, f storage.EventFilter) ([]storage.Event, error) {
+	return a.visible(ctx, func() ([]storage.Event, error) { return a.events.ListEvents(ctx, f) })
 }
 
 func (a *App) GetUpcomingEvents(ctx context.Context, from time.Time) ([]storage.Event, error) {
-	return a.events.GetUpcomingEvents(ctx, from)
+	return a.visible(ctx, func() ([]storage.Event, error) { return a.events.GetUpcomingEvents(ctx, from) })
 }
 
-func (a *App) GetEventsByDay(ctx context.Context, date time.Time) ([]storage.Event, error) {
-	return a.events.GetEventsByDay(ctx, date)
+// GetEventsByDay возвращает события дня; с userID - только те, где пользователь
+// владелец или участник.
+func (a *App) GetEventsByDay(ctx context.Context, date time.Time, userID string) ([]storage.Event, error) {
+	if userID == "" {
+		return a.visible(ctx, func() ([]storage.Event, error) { return a.events.GetEventsByDay(ctx, date) })
+	}
+	from := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	return a.ListEvents(ctx, storage.EventFilter{UserID: userID, From: from, To: from.AddDate(0, 0, 1)})
 }
 
-func (a *App) GetEventsByWeek(ctx context.Context, date time.Time) ([]storage.Event, error) {
-	return a.events.GetEventsByWeek(ctx, date)
+// GetEventsByWeek - то же для ISO недели (с понедельника).
+func (a *App) GetEventsByWeek(ctx context.Context, date time.Time, userID string) ([]storage.Event, error) {
+	if userID == "" {
+		return a.visible(ctx, func() ([]storage.Event, error) { return a.events.GetEventsByWeek(ctx, date) })
+	}
+	offset := (int(date.Weekday()) + 6) % 7 // дней с понедельника
+	from := time.Date(date.Year(), date.Month(), date.Day()-offset, 0, 0, 0, 0, date.Location())
+	return a.ListEvents(ctx, storage.EventFilter{UserID: userID, From: from, To: from.AddDate(0, 0, 7)})
+}
+
+// GetEventsByMonth - то же для календарного месяца.
+func (a *App) GetEventsByMonth(ctx context.Context, date time.Time, userID string) ([]storage.Event, error) {
+	if userID == "" {
+		return a.visible(ctx, func() ([]storage.Event, error) { return a.events.GetEventsByMonth(ctx, date) })
+	}
+	from := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+	return a.ListEvents(ctx, storage.EventFilter{UserID: userID, From: from, To: from.AddDate(0, 1, 0)})
+}
+
+// visible читает события через list и оставляет видимые пользователю из x-user-id.
+func (a *App) visible(ctx context.Context, list func() ([]storage.Event, error)) ([]storage.Event, error) {
+	viewer := identity.UserIDFromContext(ctx)
+	if viewer == "" {
+		return nil, fmt.Errorf("%w: user is required", ErrForbidden)
+	}
+	events, err := list()
+	if err != nil {
+		return nil, err
+	}
+	return a.visibleEvents(ctx, viewer, events)
 }
 
-func (a *App) GetEventsByMonth(ctx context.Context, date time.Time) ([]storage.Event, error) {
-	return a.events.GetEventsByMonth(ctx, date)
+// InviteAttendees приглашает пользователей и возвращает событие с обновлённым
+// списком участников. Приглашать может пользователь из x-user-id, если он
+// владелец события или может писать в его календарь.
+func (a *App) InviteAttendees(ctx context.Context, eventID int64, userIDs []string) (storage.Event, error) {
+	actor := identity.UserIDFromContext(ctx)
+	after, err := a.change(ctx, storage.AuditInvite, func(ctx context.Context, tx storage.Storage) (*storage.Event, *storage.Event, error) {
+		return transition(ctx, tx, eventID, func(before storage.Event) error {
+			if err := canEdit(ctx, tx, actor, before); err != nil {
+				return err
+			}
+			return tx.InviteAttendees(ctx, eventID, userIDs)
+		})
+	})
+	if err != nil {
+		return storage.Event{}, err
+	}
+	return *after, nil
+}
+
+// RespondToInvite записывает ответ участника и возвращает событие.
+func (a *App) RespondToInvite(ctx context.Context, eventID int64, userID string, status storage.RSVPStatus) (storage.Event, error) {
+	after, err := a.change(ctx, storage.AuditRSVP, func(ctx context.Context, tx storage.Storage) (*storage.Event, *storage.Event, error) {
+		return transition(ctx, tx, eventID, func(storage.Event) error {
+			return tx.SetAttendeeStatus(ctx, eventID, userID, status)
+		})
+	})
+	if err != nil {
+		return storage.Event{}, err
+	}
+	return *after, nil
+}
+
+// transition читает событие до и после изменения update, update получает событие до.
+func transition(
+	ctx context.Context, tx storage.Storage, eventID int64, update func(before storage.Event) error,
+) (*storage.Event, *storage.Event, error) {
+	before, err := tx.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := update(before); err != nil {
+		return nil, nil, err
+	}
+	after, err := tx.GetEvent(ctx, eventID)
+	return &before, &after, err
+}
+
+// ListTrash возвращает удалённые события пользователя.
+func (a *App) ListTrash(ctx context.Context, userID string) ([]storage.Event, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("%w: user is required", ErrForbidden)
+	}
+	return a.events.ListTrash(ctx, userID)
+}
+
+// RestoreEvent возвращает событие из корзины. Восстановить можно только своё
+// событие, чужое выглядит как отсутствующее.
+func (a *App) RestoreEvent(ctx context.Context, userID string, id int64) (storage.Event, error) {
+	after, err := a.change(ctx, storage.AuditRestore, func(ctx context.Context, tx storage.Storage) (*storage.Event, *storage.Event, error) {
+		trash, err := tx.ListTrash(ctx, userID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if userID == "" || !slices.ContainsFunc(trash, func(e storage.Event) bool { return e.EventID == id }) {
+			return nil, nil, storage.ErrNotFound
+		}
+		if err := tx.RestoreEvent(ctx, id); err != nil {
+			return nil, nil, err
+		}
+		restored, err := tx.GetEvent(ctx, id)
+		return nil, &restored, err
+	})
+	if err != nil {
+		return storage.Event{}, err
+	}
+	return *after, nil
 }
 
 func (a *App) Run(ctx context.Context) error {
-	slog.SetDefault(slog.New(tint.NewHandler(os.Stdout, nil)))
 	events, err := a.events.GetEvents(ctx)
 	if err != nil {
 		return err
 	}
-	a.logger.Printf("events:")
-	// log.Println("events:")
+	a.logger.Info("events loaded", "count", len(events))
 	for _, b := range events {
-		a.logger.Printf("\t %+v", b)
+		a.logger.Debug("event", "event", b)
 	}
 
 	return nil
@@ -0,0 +1,278 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"mycalendar/internal/blob"
+	"mycalendar/internal/storage"
+	"mycalendar/internal/tenant"
+)
+
+var (
+	ErrInvalidAttachment  = errors.New("invalid attachment")
+	ErrAttachmentTooLarge = errors.New("attachment is too large")
+	ErrAttachmentsOff     = errors.New("file attachments are disabled")
+)
+
+const maxAttachmentName = 255
+
+// AttachmentLimits - ограничения на загружаемые файлы.
+type AttachmentLimits struct {
+	MaxBytes int64    // 0 - без ограничения
+	Types    []string // допустимые MIME типы, "image/*" - любой image; пустой - любые
+}
+
+// allows проверяет MIME тип без параметров (charset и т.п.).
+func (l AttachmentLimits) allows(contentType string) bool {
+	if len(l.Types) == 0 {
+		return true
+	}
+	major, _, _ := strings.Cut(contentType, "/")
+	for _, t := range l.Types {
+		if t == contentType || t == major+"/*" {
+			return true
+		}
+	}
+	return false
+}
+
+// SetAttachments включает вложения-файлы с содержимым в blobs. Без них можно
+// прикреплять только ссылки. Вызывается до Run.
+func (a *App) SetAttachments(blobs blob.Store, limits AttachmentLimits) {
+	a.blobs = blobs
+	a.attachLimits = limits
+}
+
+// AddAttachment сохраняет файл из r и прикрепляет его к событию. Прикреплять
+// может тот, кто может менять событие: владелец или пользователь с правом записи
+// в календарь события.
+func (a *App) AddAttachment(ctx context.Context, userID string, eventID int64, name, contentType string, r io.Reader) (storage.Attachment, error) {
+	if a.blobs == nil {
+		return storage.Attachment{}, ErrAttachmentsOff
+	}
+	// от пути к файлу на машине клиента остаётся только имя
+	name, err := checkName(path.Base(strings.ReplaceAll(name, "\\", "/")))
+	if err != nil {
+		return storage.Attachment{}, err
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return storage.Attachment{}, fmt.Errorf("%w: content type %q", ErrInvalidAttachment, contentType)
+	}
+	if !a.attachLimits.allows(mediaType) {
+		return storage.Attachment{}, fmt.Errorf("%w: content type %s is not allowed", ErrInvalidAttachment, mediaType)
+	}
+	if _, err := a.editableEvent(ctx, userID, eventID); err != nil {
+		return storage.Attachment{}, err
+	}
+
+	key, err := blobKey(ctx, eventID)
+	if err != nil {
+		return storage.Attachment{}, err
+	}
+	body := r
+	if a.attachLimits.MaxBytes > 0 {
+		body = &limitReader{r: r, left: a.attachLimits.MaxBytes}
+	}
+	size, err := a.blobs.Put(ctx, key, body)
+	if err != nil {
+		return storage.Attachment{}, err
+	}
+
+	att := storage.Attachment{
+		EventID:     eventID,
+		Name:        name,
+		ContentType: contentType,
+		Size:        size,
+		BlobKey:     key,
+		CreatedBy:   userID,
+	}
+	if att.ID, err = a.events.AddAttachment(ctx, att); err != nil {
+		// событие могли удалить, пока шла загрузка
+		a.deleteBlob(ctx, key)
+		return storage.Attachment{}, err
+	}
+	return a.events.GetAttachment(ctx, att.ID)
+}
+
+// AddLink прикрепляет к событию ссылку, права - как у AddAttachment.
+func (a *App) AddLink(ctx context.Context, userID string, eventID int64, name, link string) (storage.Attachment, error) {
+	u, err := url.Parse(link)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return storage.Attachment{}, fmt.Errorf("%w: url must be absolute http or https", ErrInvalidAttachment)
+	}
+	if name == "" {
+		name = link
+	}
+	if name, err = checkName(name); err != nil {
+		return storage.Attachment{}, err
+	}
+	if _, err := a.editableEvent(ctx, userID, eventID); err != nil {
+		return storage.Attachment{}, err
+	}
+	id, err := a.events.AddAttachment(ctx, storage.Attachment{EventID: eventID, Name: name, URL: link, CreatedBy: userID})
+	if err != nil {
+		return storage.Attachment{}, err
+	}
+	return a.events.GetAttachment(ctx, id)
+}
+
+// ListAttachments возвращает вложения события тому, кто видит событие.
+func (a *App) ListAttachments(ctx context.Context, userID string, eventID int64) ([]storage.Attachment, error) {
+	if _, err := a.visibleEvent(ctx, userID, eventID); err != nil {
+		return nil, err
+	}
+	return a.events.ListAttachments(ctx, eventID)
+}
+
+// OpenAttachment возвращает вложение-файл и его содержимое, закрыть его должен вызывающий.
+func (a *App) OpenAttachment(ctx context.Context, userID string, id int64) (storage.Attachment, io.ReadCloser, error) {
+	att, err := a.events.GetAttachment(ctx, id)
+	if err != nil {
+		return storage.Attachment{}, nil, err
+	}
+	if _, err := a.visibleEvent(ctx, userID, att.EventID); err != nil {
+		return storage.Attachment{}, nil, err
+	}
+	if att.IsLink() {
+		return storage.Attachment{}, nil, fmt.Errorf("%w: attachment %d is a link to %s", ErrInvalidAttachment, id, att.URL)
+	}
+	if a.blobs == nil {
+		return storage.Attachment{}, nil, ErrAttachmentsOff
+	}
+	r, err := a.blobs.Open(ctx, att.BlobKey)
+	if err != nil {
+		return storage.Attachment{}, nil, err
+	}
+	return att, r, nil
+}
+
+// DeleteAttachment открепляет вложение и удаляет содержимое файла.
+func (a *App) DeleteAttachment(ctx context.Context, userID string, id int64) error {
+	att, err := a.events.GetAttachment(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, err := a.editableEvent(ctx, userID, att.EventID); err != nil {
+		return err
+	}
+	if err := a.events.DeleteAttachment(ctx, id); err != nil {
+		return err
+	}
+	if !att.IsLink() {
+		a.deleteBlob(ctx, att.BlobKey)
+	}
+	return nil
+}
+
+// deleteBlob - запись о вложении уже удалена, поэтому ошибка только логируется:
+// осиротевший файл никому не виден.
+func (a *App) deleteBlob(ctx context.Context, key string) {
+	if a.blobs == nil {
+		return
+	}
+	if err := a.blobs.Delete(ctx, key); err != nil {
+		a.logger.Error("cannot delete attachment content", "key", key, "err", err)
+	}
+}
+
+// visibleEvent - событие, которое видит userID: он владелец, участник или может
+// читать календарь события.
+func (a *App) visibleEvent(ctx context.Context, userID string, eventID int64) (storage.Event, error) {
+	e, err := a.events.GetEvent(ctx, eventID)
+	if err != nil {
+		return storage.Event{}, err
+	}
+	if e.Involves(userID) {
+		return e, nil
+	}
+	if err := calendarAllows(ctx, a.events, userID, e, storage.PermRead); err != nil {
+		return storage.Event{}, err
+	}
+	return e, nil
+}
+
+// editableEvent - событие, которое может менять userID: он владелец или может
+// писать в календарь события.
+func (a *App) editableEvent(ctx context.Context, userID string, eventID int64) (storage.Event, error) {
+	e, err := a.events.GetEvent(ctx, eventID)
+	if err != nil {
+		return storage.Event{}, err
+	}
+	if err := canEdit(ctx, a.events, userID, e); err != nil {
+		return storage.Event{}, err
+	}
+	return e, nil
+}
+
+// canEdit проверяет, что userID может менять событие e: он владелец или может
+// писать в календарь события. st - хранилище или транзакция изменения.
+func canEdit(ctx context.Context, st storage.Storage, userID string, e storage.Event) error {
+	if userID != "" && e.UserID == userID {
+		return nil
+	}
+	return calendarAllows(ctx, st, userID, e, storage.PermWrite)
+}
+
+func calendarAllows(ctx context.Context, st storage.Storage, userID string, e storage.Event, perm storage.Permission) error {
+	if e.CalendarID != 0 && userID != "" {
+		c, err := st.GetCalendar(ctx, e.CalendarID)
+		if err != nil && !errors.Is(err, storage.ErrCalendarNotFound) {
+			return err
+		}
+		if err == nil && c.Access(userID).Allows(perm) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s has no %s access to event %d", ErrForbidden, userID, perm, e.EventID)
+}
+
+// checkName убирает пробелы по краям и проверяет, что имя непустое и не слишком длинное.
+func checkName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		return "", fmt.Errorf("%w: name is required", ErrInvalidAttachment)
+	}
+	if len(name) > maxAttachmentName {
+		return "", fmt.Errorf("%w: name is longer than %d bytes", ErrInvalidAttachment, maxAttachmentName)
+	}
+	return name, nil
+}
+
+// blobKey - <тенант>/<событие>/<случайный ID>: имя файла в ключ не попадает,
+// поэтому одинаковые имена не конфликтуют.
+func blobKey(ctx context.Context, eventID int64) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return tenant.FromContext(ctx) + "/" + strconv.FormatInt(eventID, 10) + "/" + hex.EncodeToString(b), nil
+}
+
+// limitReader возвращает ErrAttachmentTooLarge, как только прочитано больше left байт.
+type limitReader struct {
+	r    io.Reader
+	left int64
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > l.left+1 {
+		p = p[:l.left+1]
+	}
+	n, err := l.r.Read(p)
+	l.left -= int64(n)
+	if l.left < 0 {
+		return 0, ErrAttachmentTooLarge
+	}
+	return n, err
+}
@@ -0,0 +1,103 @@
+package app_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"mycalendar/internal/app"
+	"mycalendar/internal/blob"
+	"mycalendar/internal/identity"
+	"mycalendar/internal/storage"
+	memorystorage "mycalendar/internal/storage/memory"
+)
+
+func TestApp_Attachments(t *testing.T) {
+	ctx := context.Background()
+	a, err := app.New(slog.New(slog.DiscardHandler), memorystorage.New())
+	require.NoError(t, err)
+
+	start := time.Date(2025, 6, 10, 9, 0, 0, 0, time.UTC)
+	id, err := a.AddEvent(ctx, storage.Event{UserID: "alice", Title: "Planning", StartDateTime: start, Duration: "1h"})
+	require.NoError(t, err)
+
+	// без blob хранилища можно только ссылки
+	_, err = a.AddAttachment(ctx, "alice", id, "agenda.txt", "text/plain", strings.NewReader("agenda"))
+	require.ErrorIs(t, err, app.ErrAttachmentsOff)
+	link, err := a.AddLink(ctx, "alice", id, "", "https://docs.example.com/agenda")
+	require.NoError(t, err)
+	require.Equal(t, "https://docs.example.com/agenda", link.Name)
+	_, err = a.AddLink(ctx, "alice", id, "", "javascript:alert(1)")
+	require.ErrorIs(t, err, app.ErrInvalidAttachment)
+
+	dir := t.TempDir()
+	blobs, err := blob.NewFS(dir)
+	require.NoError(t, err)
+	a.SetAttachments(blobs, app.AttachmentLimits{MaxBytes: 10, Types: []string{"text/plain", "image/*"}})
+
+	_, err = a.AddAttachment(ctx, "alice", id, "big.txt", "text/plain", strings.NewReader(strings.Repeat("x", 11)))
+	require.ErrorIs(t, err, app.ErrAttachmentTooLarge)
+	_, err = a.AddAttachment(ctx, "alice", id, "run.sh", "application/x-sh", strings.NewReader("rm"))
+	require.ErrorIs(t, err, app.ErrInvalidAttachment)
+	_, err = a.AddAttachment(ctx, "bob", id, "agenda.txt", "text/plain", strings.NewReader("agenda"))
+	require.ErrorIs(t, err, app.ErrForbidden)
+
+	file, err := a.AddAttachment(ctx, "alice", id, "../../notes/agenda.txt", "text/plain; charset=utf-8", strings.NewReader("agenda"))
+	require.NoError(t, err)
+	require.Equal(t, "agenda.txt", file.Name)
+	require.EqualValues(t, 6, file.Size)
+	_, err = a.AddAttachment(ctx, "alice", id, "photo.png", "image/png", strings.NewReader("png"))
+	require.NoError(t, err)
+
+	list, err := a.ListAttachments(ctx, "alice", id)
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+	_, err = a.ListAttachments(ctx, "bob", id)
+	require.ErrorIs(t, err, app.ErrForbidden)
+
+	// участник видит и скачивает вложения, но не удаляет
+	_, err = a.InviteAttendees(identity.WithUserID(ctx, "alice"), id, []string{"bob"})
+	require.NoError(t, err)
+	got, r, err := a.OpenAttachment(ctx, "bob", file.ID)
+	require.NoError(t, err)
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	require.Equal(t, "agenda", string(data))
+	require.Equal(t, "text/plain; charset=utf-8", got.ContentType)
+	require.ErrorIs(t, a.DeleteAttachment(ctx, "bob", file.ID), app.ErrForbidden)
+
+	_, _, err = a.OpenAttachment(ctx, "alice", link.ID)
+	require.ErrorIs(t, err, app.ErrInvalidAttachment)
+
+	require.NoError(t, a.DeleteAttachment(ctx, "alice", file.ID))
+	_, _, err = a.OpenAttachment(ctx, "alice", file.ID)
+	require.ErrorIs(t, err, storage.ErrAttachmentNotFound)
+
+	// на диске остался только png, неудачные загрузки ничего не оставили
+	var files []string
+	require.NoError(t, walk(dir, &files))
+	require.Len(t, files, 1)
+}
+
+func walk(dir string, files *[]string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			if err := walk(dir+"/"+e.Name(), files); err != nil {
+				return err
+			}
+			continue
+		}
+		*files = append(*files, e.Name())
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"mycalendar/internal/identity"
+	"mycalendar/internal/logger"
+	"mycalendar/internal/storage"
+)
+
+// EventHistory возвращает журнал изменений события, от создания до последней
+// правки. Журнал видит тот, кто видит событие; журнал удалённого события - только
+// его владелец.
+func (a *App) EventHistory(ctx context.Context, userID string, eventID int64) ([]storage.AuditRecord, error) {
+	_, err := a.visibleEvent(ctx, userID, eventID)
+	deleted := errors.Is(err, storage.ErrNotFound)
+	if err != nil && !deleted {
+		return nil, err
+	}
+	records, err := a.events.EventHistory(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if deleted && !ownedBy(records, userID) {
+		return nil, storage.ErrNotFound
+	}
+	return records, nil
+}
+
+// ownedBy проверяет по журналу, что событие принадлежало userID.
+func ownedBy(records []storage.AuditRecord, userID string) bool {
+	for _, r := range records {
+		if (r.Before != nil && r.Before.UserID == userID) || (r.After != nil && r.After.UserID == userID) {
+			return true
+		}
+	}
+	return false
+}
+
+// change выполняет fn в транзакции и пишет в ту же транзакцию запись журнала о
+// переходе before -> after: без записи в журнале изменение не сохраняется.
+// Подписчики узнают об изменении только после фиксации.
+func (a *App) change(
+	ctx context.Context,
+	action storage.AuditAction,
+	fn func(ctx context.Context, tx storage.Storage) (before, after *storage.Event, err error),
+) (*storage.Event, error) {
+	var before, after *storage.Event
+	err := a.events.InTx(ctx, func(ctx context.Context, tx storage.Storage) error {
+		var err error
+		if before, after, err = fn(ctx, tx); err != nil {
+			return err
+		}
+		return a.record(ctx, tx, action, before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+	a.notify(ctx, action, before, after)
+	return after, nil
+}
+
+// record пишет запись журнала через tx.
+func (a *App) record(ctx context.Context, tx storage.Storage, action storage.AuditAction, before, after *storage.Event) error {
+	r := storage.AuditRecord{
+		Actor:     identity.UserIDFromContext(ctx),
+		Action:    action,
+		Before:    before,
+		After:     after,
+		RequestID: logger.RequestIDFromContext(ctx),
+		At:        time.Now(),
+	}
+	if after != nil {
+		r.EventID = after.EventID
+	} else if before != nil {
+		r.EventID = before.EventID
+	}
+	if err := tx.AppendAudit(ctx, r); err != nil {
+		return fmt.Errorf("cannot write audit record of event %d: %w", r.EventID, err)
+	}
+	return nil
+}
+
+// findEvent ищет событие владельца userID, начинающееся в start.
+func findEvent(ctx context.Context, tx storage.Storage, userID string, start time.Time) (*storage.Event, error) {
+	events, err := tx.ListEvents(ctx, storage.EventFilter{
+		UserID: userID,
+		From:   start,
+		To:     start.Add(time.Microsecond), // точность timestamptz в postgres
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range events {
+		if e.UserID == userID && e.StartDateTime.Equal(start) {
+			return &e, nil
+		}
+	}
+	return nil, storage.ErrNotFound
+}
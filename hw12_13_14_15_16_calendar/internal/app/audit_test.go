@@ -0,0 +1,103 @@
+package app_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"mycalendar/internal/app"
+	"mycalendar/internal/identity"
+	"mycalendar/internal/logger"
+	"mycalendar/internal/storage"
+	memorystorage "mycalendar/internal/storage/memory"
+)
+
+func TestApp_EventHistory(t *testing.T) {
+	ctx := identity.WithUserID(context.Background(), "alice")
+	ctx = logger.WithRequestID(ctx, "req-1")
+	a, err := app.New(slog.New(slog.DiscardHandler), memorystorage.New())
+	require.NoError(t, err)
+
+	start := time.Date(2025, 6, 10, 9, 0, 0, 0, time.UTC)
+	id, err := a.AddEvent(ctx, storage.Event{UserID: "alice", Title: "Sync", StartDateTime: start, Duration: "1h"})
+	require.NoError(t, err)
+	_, err = a.InviteAttendees(ctx, id, []string{"bob"})
+	require.NoError(t, err)
+	_, err = a.RespondToInvite(identity.WithUserID(ctx, "bob"), id, "bob", storage.RSVPAccepted)
+	require.NoError(t, err)
+	results := a.BatchDeleteEvents(ctx, []int64{id})
+	require.NoError(t, results[0].Err)
+	_, err = a.RestoreEvent(identity.WithUserID(ctx, "bob"), "bob", id)
+	require.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = a.RestoreEvent(ctx, "alice", id)
+	require.NoError(t, err)
+
+	// неудачные изменения в журнал не попадают
+	require.ErrorIs(t, a.UpdateEvent(ctx, "alice", "x", "", "1h", 0, start.Add(time.Hour)), storage.ErrNotFound)
+	require.ErrorIs(t, a.DeleteEvent(ctx, "alice", start.Add(time.Hour)), storage.ErrNotFound)
+
+	_, err = a.EventHistory(ctx, "mallory", id)
+	require.ErrorIs(t, err, app.ErrForbidden)
+	history, err := a.EventHistory(ctx, "bob", id) // участник видит журнал
+	require.NoError(t, err)
+	actions := make([]storage.AuditAction, 0, len(history))
+	for _, r := range history {
+		require.Equal(t, id, r.EventID)
+		require.Equal(t, "req-1", r.RequestID)
+		require.False(t, r.At.IsZero())
+		actions = append(actions, r.Action)
+	}
+	require.Equal(t, []storage.AuditAction{
+		storage.AuditCreate, storage.AuditInvite, storage.AuditRSVP, storage.AuditDelete, storage.AuditRestore,
+	}, actions)
+
+	rsvp := history[2]
+	require.Equal(t, "bob", rsvp.Actor)
+	require.Equal(t, storage.RSVPNeedsAction, rsvp.Before.Attendees[0].Status)
+	require.Equal(t, storage.RSVPAccepted, rsvp.After.Attendees[0].Status)
+	require.Equal(t, "Sync", history[3].Before.Title)
+	require.Nil(t, history[3].After)
+}
+
+func TestApp_DeleteOldEventsAudit(t *testing.T) {
+	ctx := identity.WithUserID(context.Background(), "alice")
+	a, err := app.New(slog.New(slog.DiscardHandler), memorystorage.New())
+	require.NoError(t, err)
+
+	old, err := a.CreateEvent(ctx, "alice", "Old", "", "1h", 0, time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	fresh, err := a.CreateEvent(ctx, "alice", "Fresh", "", "1h", 0, time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	bob := identity.WithUserID(context.Background(), "bob")
+	bobs, err := a.CreateEvent(bob, "bob", "Bob's old", "", "1h", 0, time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	_, err = a.InviteAttendees(bob, bobs, []string{"alice"})
+	require.NoError(t, err)
+	_, err = a.RespondToInvite(ctx, bobs, "alice", storage.RSVPAccepted)
+	require.NoError(t, err)
+
+	cutoff := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	require.ErrorIs(t, a.DeleteOldEvents(context.Background(), cutoff), app.ErrForbidden)
+	require.NoError(t, a.DeleteOldEvents(ctx, cutoff))
+
+	// журнал удалённого события видит только владелец
+	history, err := a.EventHistory(ctx, "alice", old)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, storage.AuditDelete, history[1].Action)
+	require.Equal(t, "alice", history[1].Actor)
+
+	// событие bob, где alice только участник, не удаляется
+	history, err = a.EventHistory(ctx, "alice", bobs)
+	require.NoError(t, err)
+	require.Equal(t, storage.AuditRSVP, history[len(history)-1].Action)
+
+	_, err = a.EventHistory(ctx, "bob", old)
+	require.ErrorIs(t, err, storage.ErrNotFound)
+
+	history, err = a.EventHistory(ctx, "alice", fresh)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+}
@@ -0,0 +1,152 @@
+package app_test
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"mycalendar/internal/app"
+	"mycalendar/internal/identity"
+	"mycalendar/internal/storage"
+	memorystorage "mycalendar/internal/storage/memory"
+)
+
+const (
+	benchUsers   = 100
+	benchPerUser = 100
+)
+
+var benchStart = time.Date(2032, 1, 5, 9, 0, 0, 0, time.UTC)
+
+// benchApp создаёт приложение в памяти с benchPerUser событиями у каждого
+// из benchUsers пользователей, по событию в час.
+func benchApp(b *testing.B) (*app.App, []int64) {
+	b.Helper()
+	ctx := context.Background()
+	a, err := app.New(slog.New(slog.DiscardHandler), memorystorage.New())
+	if err != nil {
+		b.Fatal(err)
+	}
+	ids := make([]int64, 0, benchUsers*benchPerUser)
+	for u := range benchUsers {
+		for i := range benchPerUser {
+			id, err := a.CreateEvent(ctx, benchUser(u), "event", "", "30m", 1, benchStart.Add(time.Duration(i)*time.Hour))
+			if err != nil {
+				b.Fatal(err)
+			}
+			ids = append(ids, id)
+		}
+	}
+	return a, ids
+}
+
+func benchUser(i int) string {
+	return fmt.Sprintf("user-%d", i%benchUsers)
+}
+
+// benchOwner - контекст владельца i-го события из benchApp: события идут по
+// пользователям подряд, а читать их может только владелец.
+func benchOwner(i int) context.Context {
+	return identity.WithUserID(context.Background(), benchUser(i/benchPerUser))
+}
+
+func BenchmarkApp_CreateEvent(b *testing.B) {
+	a, _ := benchApp(b)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		start := benchStart.Add(time.Duration(benchPerUser+i) * time.Hour)
+		if _, err := a.CreateEvent(ctx, benchUser(i), "event", "", "30m", 1, start); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkApp_GetEvent(b *testing.B) {
+	a, ids := benchApp(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		k := i % len(ids)
+		if _, err := a.GetEvent(benchOwner(k), ids[k]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkApp_ListEvents(b *testing.B) {
+	a, _ := benchApp(b)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		f := storage.EventFilter{UserID: benchUser(i), From: benchStart, To: benchStart.AddDate(0, 0, 1)}
+		if _, err := a.ListEvents(identity.WithUserID(ctx, f.UserID), f); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkApp_FreeBusy(b *testing.B) {
+	a, _ := benchApp(b)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		_, err := a.FreeBusy(ctx, app.FreeBusyQuery{
+			UserIDs:  []string{benchUser(i), benchUser(i + 1), benchUser(i + 2)},
+			From:     benchStart,
+			To:       benchStart.AddDate(0, 0, 7),
+			Duration: time.Hour,
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkApp_Mixed - параллельные чтения и записи в заданной пропорции:
+// показывает, как общая блокировка хранилища в памяти держит запись.
+func BenchmarkApp_Mixed(b *testing.B) {
+	for _, writes := range []int{0, 10, 50} {
+		b.Run(fmt.Sprintf("writes=%d%%", writes), func(b *testing.B) {
+			a, ids := benchApp(b)
+			ctx := context.Background()
+			var seq atomic.Int64
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					n := int(seq.Add(1))
+					if n%100 < writes {
+						start := benchStart.Add(time.Duration(benchPerUser+n) * time.Hour)
+						if _, err := a.CreateEvent(ctx, benchUser(n), "event", "", "30m", 1, start); err != nil {
+							b.Error(err)
+							return
+						}
+						continue
+					}
+					k := n % len(ids)
+					if _, err := a.GetEvent(benchOwner(k), ids[k]); err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkParseQuickAdd(b *testing.B) {
+	now := time.Date(2025, 6, 11, 14, 30, 0, 0, time.UTC)
+	b.ReportAllocs()
+	for range b.N {
+		if _, err := app.ParseQuickAdd("Созвон с командой завтра в 10 утра на полчаса, напомнить за 10 минут", now); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package app
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"mycalendar/internal/bulk"
+	"mycalendar/internal/storage"
+)
+
+const (
+	importBatch  = 500  // событий в одном COPY
+	maxRowErrors = 1000 // больше ошибок в отчёт не попадает, только в Failed
+)
+
+// ImportReport - итог массовой загрузки.
+type ImportReport struct {
+	Imported int
+	Failed   int
+	DryRun   bool
+	Errors   []*bulk.RowError // первые maxRowErrors ошибок
+}
+
+// sort упорядочивает ошибки по строкам: ошибки разбора находятся сразу,
+// а занятое время - только при записи пакета.
+func (r *ImportReport) sort() {
+	slices.SortStableFunc(r.Errors, func(a, b *bulk.RowError) int { return cmp.Compare(a.Line, b.Line) })
+}
+
+func (r *ImportReport) fail(err *bulk.RowError) {
+	r.Failed++
+	if len(r.Errors) < maxRowErrors {
+		r.Errors = append(r.Errors, err)
+	}
+}
+
+var (
+	// errDryRun откатывает транзакцию пробной загрузки.
+	errDryRun = errors.New("dry run")
+	// errImportRetry - транзакцию загрузки нужно повторить после конфликта, но
+	// прочитанные строки уже не перечитать.
+	errImportRetry = errors.New("import transaction conflicted and cannot be retried, repeat the import")
+)
+
+// ImportEvents загружает события пользователя userID одной транзакцией, читая dec
+// потоком: проверенные строки добавляются пакетами по importBatch вместе с
+// приглашениями и записями журнала, в памяти лежит только текущий пакет. Строки
+// с ошибками и занятым временем попадают в отчёт и не мешают остальным. Если
+// загрузку пришлось прервать, не сохраняется ничего и возвращается ошибка; отчёт
+// тогда описывает строки, прочитанные до неё. С dryRun всё выполняется и
+// откатывается. Транзакция, откатившаяся из-за конфликта, не повторяется.
+func (a *App) ImportEvents(ctx context.Context, userID string, dec bulk.Decoder, dryRun bool) (report ImportReport, err error) {
+	report.DryRun = dryRun
+	defer report.sort()
+	var (
+		created  []int64
+		attempts int
+	)
+	err = a.events.InTx(ctx, func(ctx context.Context, tx storage.Storage) error {
+		if attempts++; attempts > 1 {
+			return errImportRetry
+		}
+		im := &importer{app: a, tx: tx, userID: userID, now: time.Now(), report: &report, access: make(map[int64]error)}
+		for {
+			row, err := dec.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			var rowErr *bulk.RowError
+			if errors.As(err, &rowErr) {
+				report.fail(rowErr)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if err := im.add(ctx, row); err != nil {
+				return err
+			}
+		}
+		if err := im.flush(ctx); err != nil {
+			return err
+		}
+		created = im.created
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if errors.Is(err, errDryRun) {
+		return report, nil
+	}
+	if err != nil {
+		report.Imported = 0
+		return report, err
+	}
+	for _, id := range created {
+		e, err := a.events.GetEvent(ctx, id)
+		if err != nil {
+			a.logger.Error("cannot notify about imported event", "event_id", id, "err", err)
+			continue
+		}
+		a.notify(ctx, storage.AuditCreate, nil, &e)
+	}
+	return report, nil
+}
+
+// importer копит проверенные строки загрузки в пакет и записывает его в tx.
+type importer struct {
+	app     *App
+	tx      storage.Storage
+	userID  string
+	now     time.Time
+	report  *ImportReport
+	access  map[int64]error // проверенные календари
+	lines   []int           // номера строк событий пакета
+	batch   []storage.Event
+	created []int64 // ID загруженных событий для уведомлений, только если они включены
+}
+
+// add проверяет строку и добавляет её событие в пакет, полный пакет записывается.
+func (im *importer) add(ctx context.Context, row bulk.Row) error {
+	e := row.Record.Event()
+	e.UserID = im.userID
+	e.CreatedAt = im.now
+	if e.CalendarID != 0 {
+		if _, ok := im.access[e.CalendarID]; !ok {
+			im.access[e.CalendarID] = canWrite(ctx, im.tx, im.userID, e.CalendarID)
+		}
+		if err := im.access[e.CalendarID]; err != nil {
+			im.report.fail(&bulk.RowError{Line: row.Line, Err: err})
+			return nil
+		}
+	}
+	im.lines = append(im.lines, row.Line)
+	im.batch = append(im.batch, e)
+	if len(im.batch) < importBatch {
+		return nil
+	}
+	return im.flush(ctx)
+}
+
+// flush записывает пакет; события на занятое время попадают в отчёт.
+func (im *importer) flush(ctx context.Context) error {
+	if len(im.batch) == 0 {
+		return nil
+	}
+	ids, err := im.tx.ImportEvents(ctx, im.batch)
+	if err != nil {
+		return err
+	}
+	for i, id := range ids {
+		if id == 0 {
+			im.report.fail(&bulk.RowError{Line: im.lines[i], Err: storage.ErrDateBusy})
+			continue
+		}
+		if _, err := im.app.imported(ctx, im.tx, id, im.batch[i]); err != nil {
+			return err
+		}
+		im.report.Imported++
+		if im.app.webhooks != nil {
+			im.created = append(im.created, id)
+		}
+	}
+	im.lines, im.batch = im.lines[:0], im.batch[:0]
+	return nil
+}
+
+// imported приглашает участников загруженного события и пишет его в журнал в
+// транзакции загрузки.
+func (a *App) imported(ctx context.Context, tx storage.Storage, id int64, e storage.Event) (storage.Event, error) {
+	e.EventID = id
+	if len(e.Attendees) > 0 {
+		if err := tx.InviteAttendees(ctx, id, attendeeIDs(e.Attendees)); err != nil {
+			return storage.Event{}, fmt.Errorf("cannot invite attendees of event %d: %w", id, err)
+		}
+		var err error
+		if e, err = tx.GetEvent(ctx, id); err != nil {
+			return storage.Event{}, err
+		}
+	}
+	if err := a.record(ctx, tx, storage.AuditCreate, nil, &e); err != nil {
+		return storage.Event{}, err
+	}
+	return e, nil
+}
+
+func canWrite(ctx context.Context, st storage.Storage, userID string, calendarID int64) error {
+	c, err := st.GetCalendar(ctx, calendarID)
+	if err != nil {
+		return err
+	}
+	if !c.Access(userID).Allows(storage.PermWrite) {
+		return fmt.Errorf("%w: %s cannot write to calendar %d", ErrForbidden, userID, c.ID)
+	}
+	return nil
+}
+
+// ExportEvents передаёт fn события владельца f.UserID по мере чтения из хранилища.
+func (a *App) ExportEvents(ctx context.Context, f storage.EventFilter, fn func(storage.Event) error) error {
+	return a.events.ExportEvents(ctx, f, fn)
+}
@@ -0,0 +1,207 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"mycalendar/internal/app"
+	"mycalendar/internal/bulk"
+	"mycalendar/internal/identity"
+	"mycalendar/internal/storage"
+	memorystorage "mycalendar/internal/storage/memory"
+)
+
+func TestApp_ImportEvents(t *testing.T) {
+	ctx := context.Background()
+	store := memorystorage.New()
+	a, err := app.New(slog.New(slog.DiscardHandler), store)
+	require.NoError(t, err)
+
+	team, err := a.CreateCalendar(ctx, storage.Calendar{OwnerID: "bob", Name: "Team"})
+	require.NoError(t, err)
+	_, err = a.AddEvent(ctx, storage.Event{UserID: "alice", StartDateTime: time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)})
+	require.NoError(t, err)
+
+	in := "start,title,calendar_id,attendees\n" +
+		"2030-01-01T09:00:00Z,busy,,\n" + // время уже занято
+		"2030-01-01T10:00:00Z,ok,,carol\n" +
+		"2030-01-01T10:00:00Z,twice,,\n" + // занято строкой выше
+		"bad,broken,,\n" +
+		fmt.Sprintf("2030-01-01T11:00:00Z,foreign,%d,\n", team.ID)
+
+	report, err := a.ImportEvents(ctx, "alice", bulk.NewDecoder(strings.NewReader(in), bulk.CSV), true)
+	require.NoError(t, err)
+	require.True(t, report.DryRun)
+	require.Equal(t, 1, report.Imported)
+	require.Equal(t, 4, report.Failed)
+	lines := make([]int, 0, len(report.Errors))
+	for _, e := range report.Errors {
+		lines = append(lines, e.Line)
+	}
+	require.Equal(t, []int{2, 4, 5, 6}, lines)
+	require.ErrorIs(t, report.Errors[0], storage.ErrDateBusy)
+	require.ErrorIs(t, report.Errors[1], storage.ErrDateBusy)
+	require.ErrorIs(t, report.Errors[3], app.ErrForbidden)
+
+	// dry run ничего не сохраняет
+	events, err := a.ListEvents(identity.WithUserID(ctx, "alice"), storage.EventFilter{UserID: "alice"})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	report, err = a.ImportEvents(ctx, "alice", bulk.NewDecoder(strings.NewReader(in), bulk.CSV), false)
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Imported)
+	events, err = a.ListEvents(identity.WithUserID(ctx, "alice"), storage.EventFilter{UserID: "alice", From: time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC)})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "ok", events[0].Title)
+	require.Equal(t, []storage.Attendee{{UserID: "carol", Status: storage.RSVPNeedsAction}}, events[0].Attendees)
+
+	history, err := a.EventHistory(ctx, "alice", events[0].EventID)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, storage.AuditCreate, history[0].Action)
+}
+
+func TestApp_ImportExportBatches(t *testing.T) {
+	ctx := context.Background()
+	a, err := app.New(slog.New(slog.DiscardHandler), memorystorage.New())
+	require.NoError(t, err)
+
+	// больше одного пакета
+	const n = 1234
+	var in strings.Builder
+	start := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range n {
+		fmt.Fprintf(&in, `{"title":"e%d","start":%q,"duration":"30m"}`+"\n", i, start.Add(time.Duration(i)*time.Hour).Format(time.RFC3339))
+	}
+	report, err := a.ImportEvents(ctx, "alice", bulk.NewDecoder(strings.NewReader(in.String()), bulk.NDJSON), false)
+	require.NoError(t, err)
+	require.Equal(t, n, report.Imported)
+	require.Zero(t, report.Failed)
+
+	var exported []storage.Event
+	err = a.ExportEvents(ctx, storage.EventFilter{UserID: "alice", To: start.Add(10 * time.Hour)}, func(e storage.Event) error {
+		exported = append(exported, e)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, exported, 10)
+	for i, e := range exported {
+		require.Equal(t, fmt.Sprintf("e%d", i), e.Title)
+	}
+
+	// чужие события не выгружаются
+	err = a.ExportEvents(ctx, storage.EventFilter{UserID: "bob"}, func(storage.Event) error {
+		t.Fatal("unexpected event")
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+// failingDecoder отдаёт строки inner, а после них - ошибку чтения.
+type failingDecoder struct {
+	inner bulk.Decoder
+	err   error
+}
+
+func (d failingDecoder) Next() (bulk.Row, error) {
+	row, err := d.inner.Next()
+	if errors.Is(err, io.EOF) {
+		return bulk.Row{}, d.err
+	}
+	return row, err
+}
+
+func TestApp_ImportAllOrNothing(t *testing.T) {
+	ctx := context.Background()
+	a, err := app.New(slog.New(slog.DiscardHandler), memorystorage.New())
+	require.NoError(t, err)
+
+	// обрыв чтения после первой строки: не сохраняется ничего
+	in := "title,start,duration\nfirst,2030-01-01T09:00:00Z,1h\n"
+	broken := errors.New("connection reset")
+	report, err := a.ImportEvents(ctx, "alice", failingDecoder{bulk.NewDecoder(strings.NewReader(in), bulk.CSV), broken}, false)
+	require.ErrorIs(t, err, broken)
+	require.Zero(t, report.Imported)
+	events, err := a.ListEvents(identity.WithUserID(ctx, "alice"), storage.EventFilter{UserID: "alice"})
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	// пробная загрузка видит занятое время и в разных пакетах
+	var dup strings.Builder
+	dup.WriteString("title,start,duration\n")
+	for i := range 501 {
+		fmt.Fprintf(&dup, "e%d,2030-01-01T%02d:00:00Z,1h\n", i, i%20)
+	}
+	report, err = a.ImportEvents(ctx, "alice", bulk.NewDecoder(strings.NewReader(dup.String()), bulk.CSV), true)
+	require.NoError(t, err)
+	require.Equal(t, 20, report.Imported)
+	require.Equal(t, 481, report.Failed)
+	events, err = a.ListEvents(identity.WithUserID(ctx, "alice"), storage.EventFilter{UserID: "alice"})
+	require.NoError(t, err)
+	require.Empty(t, events)
+}
+
+// countingStorage считает события, записанные ImportEvents, в том числе в транзакции.
+type countingStorage struct {
+	storage.Storage
+	imported *int
+	maxBatch *int
+}
+
+func (s countingStorage) InTx(ctx context.Context, fn func(ctx context.Context, tx storage.Storage) error) error {
+	return s.Storage.InTx(ctx, func(ctx context.Context, tx storage.Storage) error {
+		return fn(ctx, countingStorage{tx, s.imported, s.maxBatch})
+	})
+}
+
+func (s countingStorage) ImportEvents(ctx context.Context, events []storage.Event) ([]int64, error) {
+	*s.imported += len(events)
+	*s.maxBatch = max(*s.maxBatch, len(events))
+	return s.Storage.ImportEvents(ctx, events)
+}
+
+// watchingDecoder запоминает, сколько событий было записано к каждой строке.
+type watchingDecoder struct {
+	inner    bulk.Decoder
+	imported *int
+	seen     []int
+}
+
+func (d *watchingDecoder) Next() (bulk.Row, error) {
+	d.seen = append(d.seen, *d.imported)
+	return d.inner.Next()
+}
+
+func TestApp_ImportStreams(t *testing.T) {
+	ctx := context.Background()
+	var imported, maxBatch int
+	a, err := app.New(slog.New(slog.DiscardHandler), countingStorage{memorystorage.New(), &imported, &maxBatch})
+	require.NoError(t, err)
+
+	const n = 1234
+	var in strings.Builder
+	start := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range n {
+		fmt.Fprintf(&in, `{"title":"e%d","start":%q,"duration":"30m"}`+"\n", i, start.Add(time.Duration(i)*time.Hour).Format(time.RFC3339))
+	}
+	dec := &watchingDecoder{inner: bulk.NewDecoder(strings.NewReader(in.String()), bulk.NDJSON), imported: &imported}
+	report, err := a.ImportEvents(ctx, "alice", dec, false)
+	require.NoError(t, err)
+	require.Equal(t, n, report.Imported)
+	require.Equal(t, n, imported)
+
+	// пакеты по 500 пишутся по мере чтения, а не после разбора всего файла
+	require.Equal(t, 500, maxBatch)
+	require.Zero(t, dec.seen[499])
+	require.Equal(t, 500, dec.seen[500])
+	require.Equal(t, 1000, dec.seen[1000])
+}
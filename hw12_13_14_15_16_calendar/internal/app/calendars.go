@@ -0,0 +1,198 @@
+package app
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"mycalendar/internal/storage"
+)
+
+var (
+	ErrForbidden       = errors.New("permission denied")
+	ErrInvalidCalendar = errors.New("invalid calendar")
+)
+
+var colorRe = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// CreateCalendar создаёт дополнительный календарь пользователя c.OwnerID.
+func (a *App) CreateCalendar(ctx context.Context, c storage.Calendar) (storage.Calendar, error) {
+	if c.OwnerID == "" {
+		return storage.Calendar{}, fmt.Errorf("%w: owner is required", ErrInvalidCalendar)
+	}
+	if err := a.checkCalendar(&c); err != nil {
+		return storage.Calendar{}, err
+	}
+	c.IsDefault = false // календарь по умолчанию создаёт только хранилище
+
+	id, err := a.events.CreateCalendar(ctx, c)
+	if err != nil {
+		return storage.Calendar{}, err
+	}
+	return a.events.GetCalendar(ctx, id)
+}
+
+// UpdateCalendar меняет название, цвет, зону и настройки рабочих дней календаря c.ID.
+// Менять календарь может только владелец.
+func (a *App) UpdateCalendar(ctx context.Context, actor string, c storage.Calendar) (storage.Calendar, error) {
+	cur, err := a.events.GetCalendar(ctx, c.ID)
+	if err != nil {
+		return storage.Calendar{}, err
+	}
+	if cur.OwnerID != actor {
+		return storage.Calendar{}, fmt.Errorf("%w: only the owner can change calendar %d", ErrForbidden, c.ID)
+	}
+	if err := a.checkCalendar(&c); err != nil {
+		return storage.Calendar{}, err
+	}
+	if err := a.events.UpdateCalendar(ctx, c); err != nil {
+		return storage.Calendar{}, err
+	}
+	return a.events.GetCalendar(ctx, c.ID)
+}
+
+// checkCalendar проверяет изменяемые поля календаря и заполняет значения по умолчанию.
+func (a *App) checkCalendar(c *storage.Calendar) error {
+	switch {
+	case c.Name == "":
+		return fmt.Errorf("%w: name is required", ErrInvalidCalendar)
+	case c.Color != "" && !colorRe.MatchString(c.Color):
+		return fmt.Errorf("%w: color must be #rrggbb", ErrInvalidCalendar)
+	}
+	if c.TimeZone == "" {
+		c.TimeZone = "UTC"
+	}
+	if _, err := time.LoadLocation(c.TimeZone); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidCalendar, err)
+	}
+	c.HolidayCountry = strings.ToUpper(c.HolidayCountry)
+	if _, err := a.holidays.Get(c.HolidayCountry); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidCalendar, err)
+	}
+	return nil
+}
+
+// ListCalendars возвращает календари пользователя, начиная с календаря по умолчанию,
+// и расшаренные ему. В чужих календарях видны только его собственные права.
+func (a *App) ListCalendars(ctx context.Context, userID string) ([]storage.Calendar, error) {
+	if _, err := a.events.DefaultCalendar(ctx, userID); err != nil {
+		return nil, err
+	}
+	calendars, err := a.events.ListCalendars(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i, c := range calendars {
+		if c.OwnerID != userID {
+			calendars[i].Shares = []storage.Share{{UserID: userID, Permission: c.Access(userID)}}
+		}
+	}
+	slices.SortStableFunc(calendars, func(x, y storage.Calendar) int {
+		return cmp.Compare(ownDefault(y, userID), ownDefault(x, userID))
+	})
+	return calendars, nil
+}
+
+func ownDefault(c storage.Calendar, userID string) int {
+	if c.IsDefault && c.OwnerID == userID {
+		return 1
+	}
+	return 0
+}
+
+// ShareCalendar выдаёт userID права perm на календарь, пустые права отзывают доступ.
+// Менять права может только владелец.
+func (a *App) ShareCalendar(ctx context.Context, actor string, calendarID int64, userID string, perm storage.Permission) (storage.Calendar, error) {
+	c, err := a.events.GetCalendar(ctx, calendarID)
+	if err != nil {
+		return storage.Calendar{}, err
+	}
+	if c.OwnerID != actor {
+		return storage.Calendar{}, fmt.Errorf("%w: only the owner can share calendar %d", ErrForbidden, c.ID)
+	}
+	if userID == "" || userID == c.OwnerID {
+		return storage.Calendar{}, fmt.Errorf("%w: cannot share with %q", ErrInvalidCalendar, userID)
+	}
+
+	if perm == "" {
+		err = a.events.UnshareCalendar(ctx, calendarID, userID)
+	} else {
+		err = a.events.ShareCalendar(ctx, calendarID, userID, perm)
+	}
+	if err != nil {
+		return storage.Calendar{}, err
+	}
+	return a.events.GetCalendar(ctx, calendarID)
+}
+
+// ListCalendarEvents возвращает события календаря, которые видит viewer. С правами
+// free-busy у событий остаются только календарь, время начала и длительность.
+func (a *App) ListCalendarEvents(ctx context.Context, viewer string, calendarID int64, from, to time.Time) ([]storage.Event, error) {
+	c, err := a.events.GetCalendar(ctx, calendarID)
+	if err != nil {
+		return nil, err
+	}
+	perm := c.Access(viewer)
+	if !perm.Allows(storage.PermFreeBusy) {
+		return nil, fmt.Errorf("%w: %s has no access to calendar %d", ErrForbidden, viewer, c.ID)
+	}
+
+	events, err := a.events.ListEvents(ctx, storage.EventFilter{CalendarID: calendarID, From: from, To: to})
+	if err != nil {
+		return nil, err
+	}
+	if perm.Allows(storage.PermRead) {
+		return events, nil
+	}
+	for i, e := range events {
+		events[i] = freeBusyOnly(e)
+	}
+	return events, nil
+}
+
+// visibleEvents оставляет из events те, что видит viewer: где он владелец или
+// участник и из календарей, которые он может читать. Из календарей с правом
+// free-busy остаются только календарь, время начала и длительность.
+func (a *App) visibleEvents(ctx context.Context, viewer string, events []storage.Event) ([]storage.Event, error) {
+	if viewer == "" {
+		return nil, fmt.Errorf("%w: user is required", ErrForbidden)
+	}
+	perms := make(map[int64]storage.Permission) // по календарям
+	res := make([]storage.Event, 0, len(events))
+	for _, e := range events {
+		if e.Involves(viewer) {
+			res = append(res, e)
+			continue
+		}
+		if e.CalendarID == 0 {
+			continue
+		}
+		perm, ok := perms[e.CalendarID]
+		if !ok {
+			c, err := a.events.GetCalendar(ctx, e.CalendarID)
+			if err != nil && !errors.Is(err, storage.ErrCalendarNotFound) {
+				return nil, err
+			}
+			if err == nil {
+				perm = c.Access(viewer)
+			}
+			perms[e.CalendarID] = perm
+		}
+		switch {
+		case perm.Allows(storage.PermRead):
+			res = append(res, e)
+		case perm.Allows(storage.PermFreeBusy):
+			res = append(res, freeBusyOnly(e))
+		}
+	}
+	return res, nil
+}
+
+func freeBusyOnly(e storage.Event) storage.Event {
+	return storage.Event{CalendarID: e.CalendarID, StartDateTime: e.StartDateTime, Duration: e.Duration}
+}
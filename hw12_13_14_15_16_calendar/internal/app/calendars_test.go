@@ -0,0 +1,233 @@
+package app_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"mycalendar/internal/app"
+	"mycalendar/internal/identity"
+	"mycalendar/internal/storage"
+	memorystorage "mycalendar/internal/storage/memory"
+)
+
+func TestApp_CalendarSharing(t *testing.T) {
+	ctx := context.Background()
+	a, err := app.New(slog.New(slog.DiscardHandler), memorystorage.New())
+	require.NoError(t, err)
+
+	_, err = a.CreateCalendar(ctx, storage.Calendar{OwnerID: "alice", Name: "Team", Color: "red"})
+	require.ErrorIs(t, err, app.ErrInvalidCalendar)
+	_, err = a.CreateCalendar(ctx, storage.Calendar{OwnerID: "alice", Name: "Team", TimeZone: "Mars/Olympus"})
+	require.ErrorIs(t, err, app.ErrInvalidCalendar)
+
+	team, err := a.CreateCalendar(ctx, storage.Calendar{OwnerID: "alice", Name: "Team", Color: "#3366ff"})
+	require.NoError(t, err)
+	require.Equal(t, "UTC", team.TimeZone)
+
+	alice := identity.WithUserID(ctx, "alice")
+	bob := identity.WithUserID(ctx, "bob")
+	start := time.Date(2025, 6, 10, 9, 0, 0, 0, time.UTC)
+	_, err = a.AddEvent(alice, storage.Event{Title: "Retro", StartDateTime: start, Duration: "1h", CalendarID: team.ID})
+	require.NoError(t, err)
+
+	// права проверяются у пользователя из x-user-id, а не из тела запроса
+	_, err = a.AddEvent(bob, storage.Event{UserID: "alice", StartDateTime: start.Add(time.Hour), CalendarID: team.ID})
+	require.ErrorIs(t, err, app.ErrForbidden)
+	_, err = a.AddEvent(ctx, storage.Event{UserID: "alice", StartDateTime: start.Add(time.Hour), CalendarID: team.ID})
+	require.ErrorIs(t, err, app.ErrForbidden)
+
+	// без прав нельзя ни писать, ни читать, ни раздавать доступ
+	_, err = a.AddEvent(bob, storage.Event{StartDateTime: start, CalendarID: team.ID})
+	require.ErrorIs(t, err, app.ErrForbidden)
+	_, err = a.ListCalendarEvents(ctx, "bob", team.ID, time.Time{}, time.Time{})
+	require.ErrorIs(t, err, app.ErrForbidden)
+	_, err = a.ShareCalendar(ctx, "bob", team.ID, "bob", storage.PermWrite)
+	require.ErrorIs(t, err, app.ErrForbidden)
+
+	// free-busy видит только время
+	_, err = a.ShareCalendar(ctx, "alice", team.ID, "bob", storage.PermFreeBusy)
+	require.NoError(t, err)
+	evs, err := a.ListCalendarEvents(ctx, "bob", team.ID, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, evs, 1)
+	require.Empty(t, evs[0].Title)
+	require.Empty(t, evs[0].UserID)
+	require.True(t, start.Equal(evs[0].StartDateTime))
+
+	// read видит событие целиком, write может добавлять
+	_, err = a.ShareCalendar(ctx, "alice", team.ID, "bob", storage.PermRead)
+	require.NoError(t, err)
+	evs, err = a.ListCalendarEvents(ctx, "bob", team.ID, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, "Retro", evs[0].Title)
+	_, err = a.AddEvent(bob, storage.Event{StartDateTime: start, CalendarID: team.ID})
+	require.ErrorIs(t, err, app.ErrForbidden)
+
+	c, err := a.ShareCalendar(ctx, "alice", team.ID, "bob", storage.PermWrite)
+	require.NoError(t, err)
+	require.Equal(t, []storage.Share{{UserID: "bob", Permission: storage.PermWrite}}, c.Shares)
+	_, err = a.AddEvent(bob, storage.Event{StartDateTime: start, CalendarID: team.ID})
+	require.NoError(t, err)
+
+	// у bob свой календарь по умолчанию первым, в чужом - только его права
+	calendars, err := a.ListCalendars(ctx, "bob")
+	require.NoError(t, err)
+	require.Len(t, calendars, 2)
+	require.True(t, calendars[0].IsDefault)
+	require.Equal(t, "bob", calendars[0].OwnerID)
+	require.Equal(t, team.ID, calendars[1].ID)
+
+	// пустые права отзывают доступ
+	_, err = a.ShareCalendar(ctx, "alice", team.ID, "bob", "")
+	require.NoError(t, err)
+	_, err = a.ListCalendarEvents(ctx, "bob", team.ID, time.Time{}, time.Time{})
+	require.ErrorIs(t, err, app.ErrForbidden)
+}
+
+func TestApp_EditEventAccess(t *testing.T) {
+	ctx := context.Background()
+	a, err := app.New(slog.New(slog.DiscardHandler), memorystorage.New())
+	require.NoError(t, err)
+	alice := identity.WithUserID(ctx, "alice")
+	bob := identity.WithUserID(ctx, "bob")
+
+	team, err := a.CreateCalendar(ctx, storage.Calendar{OwnerID: "alice", Name: "Team"})
+	require.NoError(t, err)
+	start := time.Date(2025, 6, 10, 9, 0, 0, 0, time.UTC)
+	_, err = a.AddEvent(alice, storage.Event{Title: "Retro", StartDateTime: start, Duration: "1h", CalendarID: team.ID})
+	require.NoError(t, err)
+
+	// владельца из тела запроса мало: менять может только сам владелец или writer календаря
+	for _, caller := range []context.Context{ctx, bob} {
+		require.ErrorIs(t, a.UpdateEvent(caller, "alice", "Hacked", "", "1h", 0, start), app.ErrForbidden)
+		require.ErrorIs(t, a.DeleteEvent(caller, "alice", start), app.ErrForbidden)
+	}
+	_, err = a.ShareCalendar(ctx, "alice", team.ID, "bob", storage.PermRead)
+	require.NoError(t, err)
+	require.ErrorIs(t, a.UpdateEvent(bob, "alice", "Hacked", "", "1h", 0, start), app.ErrForbidden)
+
+	_, err = a.ShareCalendar(ctx, "alice", team.ID, "bob", storage.PermWrite)
+	require.NoError(t, err)
+	require.NoError(t, a.UpdateEvent(bob, "alice", "Retro v2", "", "1h", 0, start))
+	evs, err := a.ListCalendarEvents(ctx, "alice", team.ID, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, "Retro v2", evs[0].Title)
+	require.Equal(t, "alice", evs[0].UserID)
+	require.NoError(t, a.DeleteEvent(bob, "alice", start))
+
+	// без владельца в запросе ищется событие самого пользователя
+	_, err = a.AddEvent(alice, storage.Event{Title: "Own", StartDateTime: start, Duration: "1h"})
+	require.NoError(t, err)
+	require.ErrorIs(t, a.DeleteEvent(bob, "", start), storage.ErrNotFound)
+	require.NoError(t, a.UpdateEvent(alice, "", "Own v2", "", "1h", 0, start))
+	require.NoError(t, a.DeleteEvent(alice, "", start))
+}
+
+func TestApp_EventVisibility(t *testing.T) {
+	ctx := context.Background()
+	a, err := app.New(slog.New(slog.DiscardHandler), memorystorage.New())
+	require.NoError(t, err)
+	alice := identity.WithUserID(ctx, "alice")
+	bob := identity.WithUserID(ctx, "bob")
+	carol := identity.WithUserID(ctx, "carol")
+
+	team, err := a.CreateCalendar(ctx, storage.Calendar{OwnerID: "alice", Name: "Team"})
+	require.NoError(t, err)
+	start := time.Date(2025, 6, 10, 9, 0, 0, 0, time.UTC)
+	retro, err := a.AddEvent(alice, storage.Event{Title: "Retro", StartDateTime: start, Duration: "1h", CalendarID: team.ID})
+	require.NoError(t, err)
+	dentist, err := a.AddEvent(alice, storage.Event{Title: "Dentist", StartDateTime: start.Add(3 * time.Hour), Duration: "1h"})
+	require.NoError(t, err)
+	_, err = a.InviteAttendees(alice, dentist, []string{"carol"})
+	require.NoError(t, err)
+
+	// чужие события не видны ни по ID, ни в списках, ни по user_id владельца
+	_, err = a.GetEvent(bob, retro)
+	require.ErrorIs(t, err, app.ErrForbidden)
+	_, err = a.GetEvents(ctx)
+	require.ErrorIs(t, err, app.ErrForbidden)
+	lists := map[string]func(ctx context.Context) ([]storage.Event, error){
+		"all": a.GetEvents,
+		"list": func(ctx context.Context) ([]storage.Event, error) {
+			return a.ListEvents(ctx, storage.EventFilter{UserID: "alice"})
+		},
+		"upcoming": func(ctx context.Context) ([]storage.Event, error) {
+			return a.GetUpcomingEvents(ctx, start.Add(-time.Hour))
+		},
+		"day": func(ctx context.Context) ([]storage.Event, error) {
+			return a.GetEventsByDay(ctx, start, "")
+		},
+		"week": func(ctx context.Context) ([]storage.Event, error) {
+			return a.GetEventsByWeek(ctx, start, "alice")
+		},
+		"month": func(ctx context.Context) ([]storage.Event, error) {
+			return a.GetEventsByMonth(ctx, start, "")
+		},
+	}
+	for name, list := range lists {
+		evs, err := list(bob)
+		require.NoError(t, err, name)
+		require.Empty(t, evs, name)
+		evs, err = list(alice)
+		require.NoError(t, err, name)
+		require.Len(t, evs, 2, name)
+	}
+
+	// участник видит событие целиком, хотя календарь ему не расшарен
+	e, err := a.GetEvent(carol, dentist)
+	require.NoError(t, err)
+	require.Equal(t, "Dentist", e.Title)
+
+	// free-busy видит в календаре только время, личный календарь alice - не видит
+	_, err = a.ShareCalendar(ctx, "alice", team.ID, "bob", storage.PermFreeBusy)
+	require.NoError(t, err)
+	e, err = a.GetEvent(bob, retro)
+	require.NoError(t, err)
+	require.Equal(t, storage.Event{CalendarID: team.ID, StartDateTime: e.StartDateTime, Duration: "1h"}, e)
+	require.True(t, start.Equal(e.StartDateTime))
+	for name, list := range lists {
+		evs, err := list(bob)
+		require.NoError(t, err, name)
+		require.Len(t, evs, 1, name)
+		require.Empty(t, evs[0].Title, name)
+	}
+
+	_, err = a.ShareCalendar(ctx, "alice", team.ID, "bob", storage.PermRead)
+	require.NoError(t, err)
+	e, err = a.GetEvent(bob, retro)
+	require.NoError(t, err)
+	require.Equal(t, "Retro", e.Title)
+	require.Equal(t, "alice", e.UserID)
+}
+
+func TestApp_InviteAccess(t *testing.T) {
+	ctx := context.Background()
+	a, err := app.New(slog.New(slog.DiscardHandler), memorystorage.New())
+	require.NoError(t, err)
+	alice := identity.WithUserID(ctx, "alice")
+	bob := identity.WithUserID(ctx, "bob")
+
+	team, err := a.CreateCalendar(ctx, storage.Calendar{OwnerID: "alice", Name: "Team"})
+	require.NoError(t, err)
+	start := time.Date(2025, 6, 10, 9, 0, 0, 0, time.UTC)
+	id, err := a.AddEvent(alice, storage.Event{Title: "Retro", StartDateTime: start, CalendarID: team.ID})
+	require.NoError(t, err)
+
+	for _, caller := range []context.Context{ctx, bob, identity.WithUserID(ctx, "carol")} {
+		_, err = a.InviteAttendees(caller, id, []string{"mallory"})
+		require.ErrorIs(t, err, app.ErrForbidden)
+	}
+	// участник сам приглашать не может, пишущий в календарь - может
+	_, err = a.InviteAttendees(alice, id, []string{"carol"})
+	require.NoError(t, err)
+	_, err = a.InviteAttendees(identity.WithUserID(ctx, "carol"), id, []string{"mallory"})
+	require.ErrorIs(t, err, app.ErrForbidden)
+	_, err = a.ShareCalendar(ctx, "alice", team.ID, "bob", storage.PermWrite)
+	require.NoError(t, err)
+	e, err := a.InviteAttendees(bob, id, []string{"dave"})
+	require.NoError(t, err)
+	require.Len(t, e.Attendees, 2)
+}
@@ -0,0 +1,219 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+const (
+	DefaultWorkDayStart = 9 * time.Hour
+	DefaultWorkDayEnd   = 18 * time.Hour
+
+	defaultSlotStep  = 15 * time.Minute
+	defaultSlotLimit = 10
+
+	maxFreeBusyWindow = 31 * 24 * time.Hour
+	maxFreeBusyUsers  = 50
+)
+
+var ErrInvalidQuery = errors.New("invalid free/busy query")
+
+// FreeBusyQuery - параметры поиска времени для встречи. Нулевые шаг и лимит
+// заменяются значениями по умолчанию, рабочий день - если не задан ни один из концов.
+type FreeBusyQuery struct {
+	UserIDs  []string
+	From     time.Time
+	To       time.Time
+	Duration time.Duration // длительность встречи
+
+	WorkDayStart    time.Duration // смещение от полуночи, по умолчанию 9:00
+	WorkDayEnd      time.Duration // по умолчанию 18:00
+	Location        *time.Location
+	IncludeWeekends bool
+	Step            time.Duration // шаг начала слотов, по умолчанию 15 минут
+	Limit           int           // сколько слотов вернуть, по умолчанию 10
+}
+
+// Interval - занятый промежуток [Start, End).
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Slot - вариант времени встречи и кто в это время свободен.
+type Slot struct {
+	Start     time.Time
+	End       time.Time
+	Available []string
+	Busy      []string
+}
+
+// FreeBusy - занятость каждого пользователя и слоты в порядке убывания ранга.
+type FreeBusy struct {
+	Busy  map[string][]Interval
+	Slots []Slot
+}
+
+// FreeBusy собирает занятость пользователей в окне [From, To) одним запросом к хранилищу
+// и подбирает слоты в рабочее время. Выше ранжируются слоты, где свободно больше
+// участников, при равенстве - более ранние. Слоты в ответе не пересекаются.
+func (a *App) FreeBusy(ctx context.Context, q FreeBusyQuery) (FreeBusy, error) {
+	if err := q.normalize(); err != nil {
+		return FreeBusy{}, err
+	}
+
+	events, err := a.events.GetBusyEvents(ctx, q.UserIDs, q.From, q.To)
+	if err != nil {
+		return FreeBusy{}, err
+	}
+
+	busy := make(map[string][]Interval, len(q.UserIDs))
+	for _, u := range q.UserIDs {
+		busy[u] = []Interval{}
+	}
+	for _, e := range events {
+		iv := Interval{Start: maxTime(e.StartDateTime, q.From), End: minTime(e.End(), q.To)}
+		if !iv.End.After(iv.Start) {
+			continue // событие без длительности время не занимает
+		}
+		for _, u := range q.UserIDs {
+			if e.Involves(u) {
+				busy[u] = append(busy[u], iv)
+			}
+		}
+	}
+	for u := range busy {
+		busy[u] = mergeIntervals(busy[u])
+	}
+
+	return FreeBusy{Busy: busy, Slots: rankSlots(q, busy)}, nil
+}
+
+func (q *FreeBusyQuery) normalize() error {
+	if q.WorkDayStart == 0 && q.WorkDayEnd == 0 {
+		q.WorkDayStart, q.WorkDayEnd = DefaultWorkDayStart, DefaultWorkDayEnd
+	}
+	if q.Location == nil {
+		q.Location = time.UTC
+	}
+	if q.Step <= 0 {
+		q.Step = defaultSlotStep
+	}
+	if q.Limit <= 0 {
+		q.Limit = defaultSlotLimit
+	}
+
+	users := make([]string, 0, len(q.UserIDs))
+	for _, u := range q.UserIDs {
+		if u != "" && !slices.Contains(users, u) {
+			users = append(users, u)
+		}
+	}
+	q.UserIDs = users
+
+	switch {
+	case len(q.UserIDs) == 0:
+		return fmt.Errorf("%w: no users", ErrInvalidQuery)
+	case len(q.UserIDs) > maxFreeBusyUsers:
+		return fmt.Errorf("%w: more than %d users", ErrInvalidQuery, maxFreeBusyUsers)
+	case q.From.IsZero() || !q.To.After(q.From):
+		return fmt.Errorf("%w: empty time window", ErrInvalidQuery)
+	case q.To.Sub(q.From) > maxFreeBusyWindow:
+		return fmt.Errorf("%w: window longer than %s", ErrInvalidQuery, maxFreeBusyWindow)
+	case q.Duration <= 0:
+		return fmt.Errorf("%w: duration must be positive", ErrInvalidQuery)
+	case q.WorkDayStart < 0 || q.WorkDayEnd > 24*time.Hour || q.WorkDayStart >= q.WorkDayEnd:
+		return fmt.Errorf("%w: bad working hours", ErrInvalidQuery)
+	case q.Duration > q.WorkDayEnd-q.WorkDayStart:
+		return fmt.Errorf("%w: duration exceeds working day", ErrInvalidQuery)
+	}
+	return nil
+}
+
+// rankSlots перебирает начала слотов с шагом Step внутри рабочих часов каждого дня.
+func rankSlots(q FreeBusyQuery, busy map[string][]Interval) []Slot {
+	var candidates []Slot
+	from := q.From.In(q.Location)
+	for day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, q.Location); day.Before(q.To); day = day.AddDate(0, 0, 1) {
+		if !q.IncludeWeekends && (day.Weekday() == time.Saturday || day.Weekday() == time.Sunday) {
+			continue
+		}
+		workStart, workEnd := day.Add(q.WorkDayStart), minTime(day.Add(q.WorkDayEnd), q.To)
+		start := workStart
+		if start.Before(q.From) {
+			start = start.Add((q.From.Sub(start) + q.Step - 1) / q.Step * q.Step)
+		}
+		for ; !start.Add(q.Duration).After(workEnd); start = start.Add(q.Step) {
+			slot := Slot{Start: start, End: start.Add(q.Duration)}
+			for _, u := range q.UserIDs {
+				if isFree(busy[u], slot.Start, slot.End) {
+					slot.Available = append(slot.Available, u)
+				} else {
+					slot.Busy = append(slot.Busy, u)
+				}
+			}
+			if len(slot.Available) > 0 {
+				candidates = append(candidates, slot)
+			}
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return len(candidates[i].Available) > len(candidates[j].Available)
+	})
+
+	var slots []Slot
+	for _, c := range candidates {
+		if len(slots) == q.Limit {
+			break
+		}
+		overlaps := slices.ContainsFunc(slots, func(s Slot) bool {
+			return c.Start.Before(s.End) && s.Start.Before(c.End)
+		})
+		if !overlaps {
+			slots = append(slots, c)
+		}
+	}
+	return slots
+}
+
+// isFree - в отсортированных непересекающихся интервалах нет пересечения с [start, end).
+func isFree(busy []Interval, start, end time.Time) bool {
+	i := sort.Search(len(busy), func(i int) bool { return busy[i].End.After(start) })
+	return i == len(busy) || !busy[i].Start.Before(end)
+}
+
+func mergeIntervals(ivs []Interval) []Interval {
+	if len(ivs) == 0 {
+		return ivs
+	}
+	sort.Slice(ivs, func(i, j int) bool { return ivs[i].Start.Before(ivs[j].Start) })
+	res := []Interval{ivs[0]}
+	for _, iv := range ivs[1:] {
+		last := &res[len(res)-1]
+		if iv.Start.After(last.End) {
+			res = append(res, iv)
+			continue
+		}
+		last.End = maxTime(last.End, iv.End)
+	}
+	return res
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
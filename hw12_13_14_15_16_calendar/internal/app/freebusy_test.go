@@ -0,0 +1,92 @@
+package app_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"mycalendar/internal/app"
+	"mycalendar/internal/storage"
+	memorystorage "mycalendar/internal/storage/memory"
+)
+
+func TestApp_FreeBusy(t *testing.T) {
+	ctx := context.Background()
+	mem := memorystorage.New()
+	a, err := app.New(slog.New(slog.DiscardHandler), mem)
+	require.NoError(t, err)
+
+	monday := time.Date(2025, 5, 12, 0, 0, 0, 0, time.UTC)
+	at := func(h, m int) time.Time { return monday.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute) }
+
+	_, err = mem.AddEvent(ctx, storage.Event{UserID: "alice", StartDateTime: at(9, 0), Duration: "1h30m"})
+	require.NoError(t, err)
+	_, err = mem.AddEvent(ctx, storage.Event{UserID: "alice", StartDateTime: at(10, 0), Duration: "1h"}) // пересекается с первым
+	require.NoError(t, err)
+	id, err := mem.AddEvent(ctx, storage.Event{UserID: "carol", StartDateTime: at(12, 0), Duration: "1h"})
+	require.NoError(t, err)
+	require.NoError(t, mem.InviteAttendees(ctx, id, []string{"bob"}))
+
+	fb, err := a.FreeBusy(ctx, app.FreeBusyQuery{
+		UserIDs:      []string{"alice", "bob"},
+		From:         monday,
+		To:           monday.AddDate(0, 0, 1),
+		Duration:     time.Hour,
+		WorkDayStart: 9 * time.Hour,
+		WorkDayEnd:   14 * time.Hour,
+		Step:         30 * time.Minute,
+		Limit:        3,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []app.Interval{{Start: at(9, 0), End: at(11, 0)}}, fb.Busy["alice"])
+	require.Equal(t, []app.Interval{{Start: at(12, 0), End: at(13, 0)}}, fb.Busy["bob"])
+
+	require.Len(t, fb.Slots, 3)
+	// оба свободны только 11:00-12:00 и 13:00-14:00
+	require.Equal(t, at(11, 0), fb.Slots[0].Start)
+	require.Equal(t, []string{"alice", "bob"}, fb.Slots[0].Available)
+	require.Equal(t, at(13, 0), fb.Slots[1].Start)
+	// дальше - слоты, где свободен только bob
+	require.Equal(t, at(9, 0), fb.Slots[2].Start)
+	require.Equal(t, []string{"alice"}, fb.Slots[2].Busy)
+}
+
+func TestApp_FreeBusy_Weekends(t *testing.T) {
+	a, err := app.New(slog.New(slog.DiscardHandler), memorystorage.New())
+	require.NoError(t, err)
+
+	saturday := time.Date(2025, 5, 17, 0, 0, 0, 0, time.UTC)
+	q := app.FreeBusyQuery{
+		UserIDs:  []string{"alice"},
+		From:     saturday,
+		To:       saturday.AddDate(0, 0, 2),
+		Duration: time.Hour,
+	}
+	fb, err := a.FreeBusy(context.Background(), q)
+	require.NoError(t, err)
+	require.Empty(t, fb.Slots)
+
+	q.IncludeWeekends = true
+	fb, err = a.FreeBusy(context.Background(), q)
+	require.NoError(t, err)
+	require.Len(t, fb.Slots, 10)
+	require.Equal(t, saturday.Add(9*time.Hour), fb.Slots[0].Start)
+}
+
+func TestApp_FreeBusy_Invalid(t *testing.T) {
+	a, err := app.New(slog.New(slog.DiscardHandler), memorystorage.New())
+	require.NoError(t, err)
+	now := time.Now()
+
+	for name, q := range map[string]app.FreeBusyQuery{
+		"no users":    {From: now, To: now.Add(time.Hour), Duration: time.Hour},
+		"empty range": {UserIDs: []string{"u"}, From: now, To: now, Duration: time.Hour},
+		"no duration": {UserIDs: []string{"u"}, From: now, To: now.Add(time.Hour)},
+		"too long":    {UserIDs: []string{"u"}, From: now, To: now.AddDate(0, 2, 0), Duration: time.Hour},
+	} {
+		_, err := a.FreeBusy(context.Background(), q)
+		require.ErrorIs(t, err, app.ErrInvalidQuery, name)
+	}
+}
@@ -0,0 +1,711 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"mycalendar/internal/storage"
+)
+
+const (
+	// DefaultQuickDuration - длительность события, если в строке её нет.
+	DefaultQuickDuration = time.Hour
+
+	maxQuickAddText = 500          // символов
+	maxQuickSpan    = 366 * oneDay // длительности и напоминания длиннее не считаются таковыми
+	oneDay          = 24 * time.Hour
+)
+
+var ErrInvalidQuickAdd = errors.New("invalid quick add text")
+
+// QuickEvent - разобранная строка быстрого добавления.
+type QuickEvent struct {
+	Title    string
+	Start    time.Time
+	Duration time.Duration
+	Reminder time.Duration // 0 - без напоминания
+}
+
+// QuickAddResult - событие из строки быстрого добавления. В режиме предпросмотра
+// оно не сохраняется и EventID нулевой.
+type QuickAddResult struct {
+	Event    storage.Event
+	Warnings []string
+}
+
+// QuickAdd разбирает строку вида "standup tomorrow 10:00 for 15m remind 10m before"
+// и создаёт событие пользователя в календаре calendarID (0 - по умолчанию).
+// Относительные даты считаются в зоне loc, без неё - в зоне календаря.
+// С preview событие только разбирается и ничего не создаётся, в том числе
+// календарь по умолчанию; права на календарь проверяются так же.
+func (a *App) QuickAdd(ctx context.Context, userID, text string, calendarID int64, loc *time.Location, preview bool) (QuickAddResult, error) {
+	var (
+		c   storage.Calendar
+		err error
+	)
+	switch {
+	case calendarID != 0:
+		c, err = a.events.GetCalendar(ctx, calendarID)
+	case loc != nil:
+	case preview:
+		c, err = a.findDefaultCalendar(ctx, userID)
+	default:
+		c, err = a.events.DefaultCalendar(ctx, userID)
+	}
+	if err != nil {
+		return QuickAddResult{}, err
+	}
+	if calendarID != 0 && !c.Access(userID).Allows(storage.PermWrite) {
+		return QuickAddResult{}, fmt.Errorf("%w: %s cannot write to calendar %d", ErrForbidden, userID, c.ID)
+	}
+	if loc == nil {
+		if loc, err = time.LoadLocation(c.TimeZone); err != nil {
+			return QuickAddResult{}, err
+		}
+	}
+
+	q, err := ParseQuickAdd(text, time.Now().In(loc))
+	if err != nil {
+		return QuickAddResult{}, err
+	}
+	res := QuickAddResult{Event: storage.Event{
+		UserID:        userID,
+		Title:         q.Title,
+		StartDateTime: q.Start,
+		Duration:      formatDuration(q.Duration),
+		CalendarID:    calendarID,
+	}}
+	if q.Reminder > 0 {
+		// напоминания хранятся в днях (notice_before), меньшие сроки округляются вверх
+		days := (q.Reminder + oneDay - 1) / oneDay
+		res.Event.NoticeBefore = int32(days)
+		if q.Reminder%oneDay != 0 {
+			res.Warnings = append(res.Warnings, fmt.Sprintf(
+				"reminder %s before rounded up to %d day(s): reminders are set in whole days",
+				formatDuration(q.Reminder), days))
+		}
+	}
+	if preview {
+		return res, nil
+	}
+
+	id, err := a.AddEvent(ctx, res.Event)
+	if err != nil {
+		return QuickAddResult{}, err
+	}
+	if res.Event, err = a.events.GetEvent(ctx, id); err != nil {
+		return QuickAddResult{}, err
+	}
+	return res, nil
+}
+
+// findDefaultCalendar находит календарь пользователя по умолчанию, не создавая
+// его. Пока календаря нет, зона - UTC, как у календаря, который создаст AddEvent.
+func (a *App) findDefaultCalendar(ctx context.Context, userID string) (storage.Calendar, error) {
+	calendars, err := a.events.ListCalendars(ctx, userID)
+	if err != nil {
+		return storage.Calendar{}, err
+	}
+	for _, c := range calendars {
+		if c.IsDefault && c.OwnerID == userID {
+			return c, nil
+		}
+	}
+	return storage.Calendar{OwnerID: userID, TimeZone: "UTC"}, nil
+}
+
+// ParseQuickAdd разбирает строку быстрого добавления на английском или русском.
+// Относительные даты отсчитываются от now в его зоне. Понимает:
+//   - даты: today, tomorrow, friday, next monday, 2025-06-10, 10.06, june 10,
+//     сегодня, завтра, послезавтра, в пятницу, 10 июня;
+//   - время: 10:00, at 10, 3pm, 10:00-11:30 (конец раньше начала - на следующий
+//     день, совпадающий с началом - ошибка), noon, в 10, 7 вечера, полдень;
+//   - относительное начало: in 2 hours, через 30 минут;
+//   - длительность: for 15m, for an hour, на 15 минут, на полчаса;
+//   - напоминание: remind 10m before, remind me 1 day before, напомнить за час.
+//
+// Остальные слова становятся названием, кроме похожих на дату несуществующего
+// дня ("2026-02-30", "31.04"): это ошибка. Без даты событие ставится на ближайшее
+// указанное время, без времени - на начало рабочего дня, без длительности -
+// на DefaultQuickDuration.
+func ParseQuickAdd(text string, now time.Time) (QuickEvent, error) {
+	if utf8.RuneCountInString(text) > maxQuickAddText {
+		return QuickEvent{}, fmt.Errorf("%w: longer than %d characters", ErrInvalidQuickAdd, maxQuickAddText)
+	}
+	p := quickParser{now: now, words: strings.Fields(text), seen: make(map[string]bool)}
+	p.tokens = make([]string, len(p.words))
+	for i, w := range p.words {
+		p.tokens[i] = strings.Trim(strings.ToLower(w), ",;!?.")
+	}
+
+	matchers := []func(i int) (int, error){p.reminder, p.duration, p.relative, p.date, p.clock}
+	for i := 0; i < len(p.tokens); {
+		n := 0
+		for _, m := range matchers {
+			var err error
+			if n, err = m(i); err != nil {
+				return QuickEvent{}, err
+			}
+			if n > 0 {
+				break
+			}
+		}
+		if n == 0 {
+			p.title = append(p.title, p.words[i])
+			n = 1
+		}
+		i += n
+	}
+	return p.result()
+}
+
+type quickParser struct {
+	now    time.Time
+	words  []string // исходные слова, из них собирается название
+	tokens []string // те же слова в нижнем регистре без знаков препинания по краям
+	seen   map[string]bool
+	title  []string
+
+	day      time.Time     // полночь указанного дня, нулевой - день не указан
+	clockAt  time.Duration // время от полуночи, если seen["time"]
+	start    time.Time     // точное начало ("через 2 часа")
+	length   time.Duration // если seen["duration"]
+	remindAt time.Duration
+}
+
+func (p *quickParser) tok(i int) string {
+	if i < len(p.tokens) {
+		return p.tokens[i]
+	}
+	return ""
+}
+
+// once отмечает, что часть события задана, повтор - ошибка.
+func (p *quickParser) once(part string) error {
+	if p.seen[part] {
+		return fmt.Errorf("%w: %s is given twice", ErrInvalidQuickAdd, part)
+	}
+	p.seen[part] = true
+	return nil
+}
+
+// reminder - "remind [me] 10m [before]", "reminder 1 day before", "напомнить [мне] за 10 минут".
+func (p *quickParser) reminder(i int) (int, error) {
+	n := 1
+	switch p.tok(i) {
+	case "remind", "reminder":
+		if p.tok(i+n) == "me" {
+			n++
+		}
+	case "напомнить", "напомни", "напоминание":
+		if p.tok(i+n) == "мне" {
+			n++
+		}
+		if p.tok(i+n) != "за" {
+			return 0, nil
+		}
+		n++
+	default:
+		return 0, nil
+	}
+	d, m := p.span(i + n)
+	if m == 0 {
+		return 0, nil
+	}
+	n += m
+	switch p.tok(i + n) {
+	case "before", "earlier", "ahead", "до", "заранее":
+		n++
+	}
+	if err := p.once("reminder"); err != nil {
+		return 0, err
+	}
+	p.remindAt = d
+	return n, nil
+}
+
+// duration - "for 15m", "for an hour", "на 15 минут", "на полчаса".
+func (p *quickParser) duration(i int) (int, error) {
+	switch p.tok(i) {
+	case "for", "lasting", "на":
+	default:
+		return 0, nil
+	}
+	d, m := p.span(i + 1)
+	if m == 0 {
+		return 0, nil
+	}
+	if err := p.once("duration"); err != nil {
+		return 0, err
+	}
+	p.length = d
+	return 1 + m, nil
+}
+
+// relative - "in 2 hours", "через 3 дня". Целые дни задают только день.
+func (p *quickParser) relative(i int) (int, error) {
+	switch p.tok(i) {
+	case "in", "через":
+	default:
+		return 0, nil
+	}
+	d, m := p.span(i + 1)
+	if m == 0 {
+		return 0, nil
+	}
+	if err := p.once("date"); err != nil {
+		return 0, err
+	}
+	if d%oneDay == 0 {
+		p.day = midnight(p.now).AddDate(0, 0, int(d/oneDay))
+		return 1 + m, nil
+	}
+	if err := p.once("time"); err != nil {
+		return 0, err
+	}
+	p.start = p.now.Add(d).Truncate(time.Minute)
+	return 1 + m, nil
+}
+
+// date - день с необязательным предлогом: "tomorrow", "on friday", "next monday",
+// "в пятницу", "в следующий вторник", "на завтра", "10 июня", "2025-06-10".
+func (p *quickParser) date(i int) (int, error) {
+	n := 0
+	switch p.tok(i) {
+	case "on", "for", "в", "во", "на":
+		n++
+	}
+	next := false
+	switch p.tok(i + n) {
+	case "next", "следующий", "следующую", "следующее", "следующая":
+		next = true
+		n++
+	}
+	d, m, err := p.dayAt(i+n, next)
+	if err != nil {
+		return 0, err
+	}
+	if m == 0 {
+		return 0, nil
+	}
+	if err := p.once("date"); err != nil {
+		return 0, err
+	}
+	p.day = d
+	return n + m, nil
+}
+
+// dayAt разбирает день. Слово, похожее на дату, но несуществующего дня
+// ("2026-02-30", "31.04", "30 февраля") - ошибка, а не часть названия.
+func (p *quickParser) dayAt(i int, next bool) (time.Time, int, error) {
+	today := midnight(p.now)
+	t := p.tok(i)
+	if wd, ok := weekdays[t]; ok {
+		diff := (int(wd) - int(today.Weekday()) + 7) % 7
+		if next && diff == 0 {
+			diff = 7
+		}
+		return today.AddDate(0, 0, diff), 1, nil
+	}
+	if next {
+		return time.Time{}, 0, nil
+	}
+
+	switch t {
+	case "today", "сегодня":
+		return today, 1, nil
+	case "tomorrow", "завтра":
+		return today.AddDate(0, 0, 1), 1, nil
+	case "послезавтра":
+		return today.AddDate(0, 0, 2), 1, nil
+	case "day":
+		if p.tok(i+1) == "after" && p.tok(i+2) == "tomorrow" {
+			return today.AddDate(0, 0, 2), 3, nil
+		}
+		return time.Time{}, 0, nil
+	}
+	if d, err := time.ParseInLocation("2006-01-02", t, p.now.Location()); err == nil {
+		return d, 1, nil
+	} else if isISODate(t) {
+		return time.Time{}, 0, noSuchDate(t)
+	}
+	// 10.06 и 10.06.2025
+	if parts := strings.Split(t, "."); (len(parts) == 2 || len(parts) == 3) && len(parts[1]) == 2 {
+		dd, err1 := strconv.Atoi(parts[0])
+		mm, err2 := strconv.Atoi(parts[1])
+		year := 0
+		var err3 error
+		if len(parts) == 3 {
+			year, err3 = strconv.Atoi(parts[2])
+			if len(parts[2]) != 4 {
+				err3 = strconv.ErrSyntax
+			}
+		}
+		if err1 == nil && err2 == nil && err3 == nil && mm >= 1 && mm <= 12 {
+			if d, ok := p.dayOfMonth(dd, time.Month(mm), year); ok {
+				return d, 1, nil
+			}
+			if dd >= 1 && dd <= 31 {
+				return time.Time{}, 0, noSuchDate(t)
+			}
+		}
+		return time.Time{}, 0, nil
+	}
+	// 10 июня [2026], june 10[th] [2026]
+	dd, month, n := 0, time.Month(0), 0
+	if v, err := strconv.Atoi(trimOrdinal(t)); err == nil {
+		if m, ok := months[p.tok(i+1)]; ok {
+			dd, month, n = v, m, 2
+		}
+	} else if m, ok := months[t]; ok {
+		if v, err := strconv.Atoi(trimOrdinal(p.tok(i + 1))); err == nil {
+			dd, month, n = v, m, 2
+		}
+	}
+	if n == 0 {
+		return time.Time{}, 0, nil
+	}
+	year := 0
+	if y, err := strconv.Atoi(p.tok(i + n)); err == nil && len(p.tok(i+n)) == 4 {
+		year = y
+		n++
+	}
+	d, ok := p.dayOfMonth(dd, month, year)
+	if !ok && dd >= 1 && dd <= 31 {
+		return time.Time{}, 0, noSuchDate(strings.Join(p.words[i:i+n], " "))
+	}
+	if !ok {
+		return time.Time{}, 0, nil
+	}
+	return d, n, nil
+}
+
+// isISODate - слово вида 2026-02-30, даже если такого дня нет.
+func isISODate(s string) bool {
+	if len(s) != len("2006-01-02") {
+		return false
+	}
+	for i, r := range s {
+		if i == 4 || i == 7 {
+			if r != '-' {
+				return false
+			}
+		} else if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func noSuchDate(s string) error {
+	return fmt.Errorf("%w: no such date %s", ErrInvalidQuickAdd, s)
+}
+
+// dayOfMonth - полночь дня; без года - ближайший такой день, начиная с сегодняшнего.
+func (p *quickParser) dayOfMonth(dd int, month time.Month, year int) (time.Time, bool) {
+	today := midnight(p.now)
+	y := year
+	if y == 0 {
+		y = today.Year()
+	}
+	d := time.Date(y, month, dd, 0, 0, 0, 0, today.Location())
+	if d.Day() != dd || d.Month() != month {
+		return time.Time{}, false
+	}
+	if year == 0 && d.Before(today) {
+		d = d.AddDate(1, 0, 0)
+	}
+	return d, true
+}
+
+// clock - время с необязательным предлогом: "10:00", "at 10", "3pm", "3 pm",
+// "10:00-11:30", "noon", "в 10", "в 7 вечера", "полдень". Голое число без
+// предлога и без am/pm временем не считается.
+func (p *quickParser) clock(i int) (int, error) {
+	n := 0
+	switch p.tok(i) {
+	case "at", "@", "в":
+		n++
+	}
+	withPrep := n > 0
+	t := p.tok(i + n)
+
+	var (
+		c, end time.Duration
+		hasEnd bool
+		ok     bool
+	)
+	switch t {
+	case "noon", "полдень":
+		c, ok = 12*time.Hour, true
+	case "midnight", "полночь":
+		c, ok = 0, true
+	default:
+		if from, to, isRange := strings.Cut(t, "-"); isRange {
+			c, ok = parseClock12(from, withPrep)
+			if ok {
+				end, hasEnd = parseClock12(to, withPrep)
+				ok = hasEnd
+			}
+			break
+		}
+		// "3 pm", "7 вечера"
+		if mer := meridiem(p.tok(i + n + 1)); mer != "" {
+			if h, err := strconv.Atoi(t); err == nil {
+				c, ok = applyMeridiem(time.Duration(h)*time.Hour, mer)
+				n++
+				break
+			}
+		}
+		c, ok = parseClock12(t, withPrep)
+	}
+	if !ok {
+		return 0, nil
+	}
+	if err := p.once("time"); err != nil {
+		return 0, err
+	}
+	p.clockAt = c
+	if hasEnd {
+		if err := p.once("duration"); err != nil {
+			return 0, err
+		}
+		if end == c {
+			return 0, fmt.Errorf("%w: empty time range %s", ErrInvalidQuickAdd, t)
+		}
+		if end < c {
+			end += oneDay
+		}
+		p.length = end - c
+	}
+	return n + 1, nil
+}
+
+// parseClock12 разбирает "10:30", "10", "3pm", "3:30pm". Голое число - только с bare.
+func parseClock12(s string, bare bool) (time.Duration, bool) {
+	mer := ""
+	for _, suffix := range []string{"am", "pm", "a.m", "p.m"} {
+		if strings.HasSuffix(s, suffix) {
+			s, mer = strings.TrimSuffix(s, suffix), meridiem(suffix)
+			break
+		}
+	}
+	hh, mm, hasMinutes := strings.Cut(s, ":")
+	if !hasMinutes && !bare && mer == "" {
+		return 0, false
+	}
+	h, err := strconv.Atoi(hh)
+	if err != nil || len(hh) > 2 || h < 0 || h > 23 {
+		return 0, false
+	}
+	m := 0
+	if hasMinutes {
+		if m, err = strconv.Atoi(mm); err != nil || len(mm) != 2 || m > 59 {
+			return 0, false
+		}
+	}
+	c := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute
+	if mer != "" {
+		return applyMeridiem(c, mer)
+	}
+	return c, true
+}
+
+// meridiem приводит обозначение половины суток к am, pm, утра, дня, вечера или ночи.
+func meridiem(s string) string {
+	switch s {
+	case "am", "a.m":
+		return "am"
+	case "pm", "p.m":
+		return "pm"
+	case "утра", "дня", "вечера", "ночи":
+		return s
+	}
+	return ""
+}
+
+func applyMeridiem(c time.Duration, mer string) (time.Duration, bool) {
+	h := int(c / time.Hour)
+	if h < 1 || h > 12 {
+		return 0, false
+	}
+	switch {
+	case h == 12 && (mer == "am" || mer == "ночи"):
+		c -= 12 * time.Hour
+	case h == 12:
+	case mer == "pm" || mer == "вечера" || mer == "дня" && h <= 6 || mer == "ночи" && h >= 9:
+		c += 12 * time.Hour
+	}
+	return c, true
+}
+
+// span разбирает промежуток: "15m", "1h30m", "15 минут", "1.5 hours", "an hour", "час", "полчаса".
+func (p *quickParser) span(i int) (time.Duration, int) {
+	t := p.tok(i)
+	var (
+		d time.Duration
+		n int
+	)
+	switch {
+	case t == "полчаса":
+		d, n = 30*time.Minute, 1
+	case t == "half" && p.tok(i+1) == "an" && p.tok(i+2) == "hour":
+		d, n = 30*time.Minute, 3
+	case t == "a" || t == "an" || t == "one":
+		if u, ok := spanUnits[p.tok(i+1)]; ok {
+			d, n = u, 2
+		}
+	case oneUnit[t] != 0:
+		d, n = oneUnit[t], 1
+	case isNumber(t):
+		if u, ok := spanUnits[p.tok(i+1)]; ok {
+			d, n = scale(t, u), 2
+		}
+	default:
+		// слитно: "15m", "15min", "2д"; составное "1h30m" - в формате длительности события
+		num := strings.TrimRightFunc(t, func(r rune) bool { return r < '0' || r > '9' })
+		if u, ok := spanUnits[t[len(num):]]; ok && isNumber(num) {
+			d, n = scale(num, u), 1
+		} else if v, err := storage.ParseDuration(t); err == nil && isNumber(t[:1]) {
+			d, n = v, 1
+		}
+	}
+	if n == 0 || d <= 0 || d > maxQuickSpan {
+		return 0, 0
+	}
+	return d, n
+}
+
+func isNumber(s string) bool {
+	if s == "" || strings.Count(s, ".") > 1 || s[0] == '.' {
+		return false
+	}
+	for _, r := range s {
+		if (r < '0' || r > '9') && r != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+// scale умножает единицу на число; слишком большое значение даёт 0.
+func scale(num string, unit time.Duration) time.Duration {
+	v, err := strconv.ParseFloat(num, 64)
+	if err != nil || v*float64(unit) > float64(maxQuickSpan) {
+		return 0
+	}
+	return time.Duration(v * float64(unit)).Round(time.Minute)
+}
+
+func trimOrdinal(s string) string {
+	for _, suffix := range []string{"st", "nd", "rd", "th"} {
+		if t, ok := strings.CutSuffix(s, suffix); ok {
+			return t
+		}
+	}
+	return s
+}
+
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func (p *quickParser) result() (QuickEvent, error) {
+	q := QuickEvent{
+		Title:    strings.Trim(strings.Join(p.title, " "), " ,;:-"),
+		Duration: DefaultQuickDuration,
+		Reminder: p.remindAt,
+	}
+	if q.Title == "" {
+		return QuickEvent{}, fmt.Errorf("%w: no title", ErrInvalidQuickAdd)
+	}
+	if p.seen["duration"] {
+		q.Duration = p.length
+	}
+
+	// время переводится через time.Date, чтобы переход на летнее время не сдвигал часы
+	at := func(d time.Time, c time.Duration) time.Time {
+		return time.Date(d.Year(), d.Month(), d.Day(), int(c/time.Hour), int(c%time.Hour/time.Minute), 0, 0, d.Location())
+	}
+	switch {
+	case !p.start.IsZero():
+		q.Start = p.start
+	case p.seen["time"] && p.day.IsZero():
+		// без дня - ближайшее такое время
+		q.Start = at(p.now, p.clockAt)
+		if q.Start.Before(p.now) {
+			q.Start = at(midnight(p.now).AddDate(0, 0, 1), p.clockAt)
+		}
+	case p.seen["time"]:
+		q.Start = at(p.day, p.clockAt)
+	case !p.day.IsZero():
+		q.Start = at(p.day, DefaultWorkDayStart)
+	default:
+		return QuickEvent{}, fmt.Errorf("%w: no date or time", ErrInvalidQuickAdd)
+	}
+	return q, nil
+}
+
+// formatDuration записывает длительность в формате событий: "15m", "1h30m", "2d".
+func formatDuration(d time.Duration) string {
+	var s string
+	if days := d / oneDay; days > 0 {
+		s = strconv.FormatInt(int64(days), 10) + "d"
+		d -= days * oneDay
+	}
+	if d == 0 && s != "" {
+		return s
+	}
+	r := d.String() // "1h30m0s"
+	if strings.HasSuffix(r, "m0s") {
+		r = strings.TrimSuffix(r, "0s")
+	}
+	if strings.HasSuffix(r, "h0m") {
+		r = strings.TrimSuffix(r, "0m")
+	}
+	return s + r
+}
+
+var weekdays = map[string]time.Weekday{
+	"monday": time.Monday, "mon": time.Monday, "понедельник": time.Monday, "пн": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday, "вторник": time.Tuesday, "вт": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday, "среда": time.Wednesday, "среду": time.Wednesday, "ср": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thurs": time.Thursday, "четверг": time.Thursday, "чт": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday, "пятница": time.Friday, "пятницу": time.Friday, "пт": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday, "суббота": time.Saturday, "субботу": time.Saturday, "сб": time.Saturday,
+	"sunday": time.Sunday, "sun": time.Sunday, "воскресенье": time.Sunday, "вс": time.Sunday,
+}
+
+var months = map[string]time.Month{
+	"january": time.January, "jan": time.January, "января": time.January,
+	"february": time.February, "feb": time.February, "февраля": time.February,
+	"march": time.March, "mar": time.March, "марта": time.March,
+	"april": time.April, "apr": time.April, "апреля": time.April,
+	"may": time.May, "мая": time.May,
+	"june": time.June, "jun": time.June, "июня": time.June,
+	"july": time.July, "jul": time.July, "июля": time.July,
+	"august": time.August, "aug": time.August, "августа": time.August,
+	"september": time.September, "sep": time.September, "sept": time.September, "сентября": time.September,
+	"october": time.October, "oct": time.October, "октября": time.October,
+	"november": time.November, "nov": time.November, "ноября": time.November,
+	"december": time.December, "dec": time.December, "декабря": time.December,
+}
+
+var spanUnits = map[string]time.Duration{
+	"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+	"мин": time.Minute, "минута": time.Minute, "минуту": time.Minute, "минуты": time.Minute, "минут": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hrs": time.Hour, "hour": time.Hour, "hours": time.Hour,
+	"ч": time.Hour, "час": time.Hour, "часа": time.Hour, "часов": time.Hour,
+	"d": oneDay, "day": oneDay, "days": oneDay, "д": oneDay, "день": oneDay, "дня": oneDay, "дней": oneDay,
+	"w": 7 * oneDay, "week": 7 * oneDay, "weeks": 7 * oneDay, "неделя": 7 * oneDay, "неделю": 7 * oneDay, "недели": 7 * oneDay, "недель": 7 * oneDay,
+}
+
+// oneUnit - единицы, которые по-русски означают одну штуку: "на час", "за день".
+var oneUnit = map[string]time.Duration{
+	"минуту": time.Minute, "час": time.Hour, "день": oneDay, "сутки": oneDay, "неделю": 7 * oneDay,
+}
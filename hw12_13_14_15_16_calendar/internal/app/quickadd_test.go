@@ -0,0 +1,156 @@
+package app_test
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"mycalendar/internal/app"
+	"mycalendar/internal/identity"
+	"mycalendar/internal/storage"
+	memorystorage "mycalendar/internal/storage/memory"
+)
+
+func TestParseQuickAdd(t *testing.T) {
+	msk := time.FixedZone("MSK", 3*60*60)
+	now := time.Date(2025, 6, 11, 14, 30, 0, 0, msk) // среда
+	at := func(month time.Month, d, h, m int) time.Time { return time.Date(2025, month, d, h, m, 0, 0, msk) }
+
+	tests := []struct {
+		text string
+		want app.QuickEvent
+	}{
+		{
+			text: "standup tomorrow 10:00 for 15m remind 10m before",
+			want: app.QuickEvent{Title: "standup", Start: at(6, 12, 10, 0), Duration: 15 * time.Minute, Reminder: 10 * time.Minute},
+		},
+		{
+			text: "Созвон с командой завтра в 10 утра на полчаса, напомнить за 10 минут",
+			want: app.QuickEvent{Title: "Созвон с командой", Start: at(6, 12, 10, 0), Duration: 30 * time.Minute, Reminder: 10 * time.Minute},
+		},
+		{
+			text: "Lunch with Bob on friday at 1pm for an hour",
+			want: app.QuickEvent{Title: "Lunch with Bob", Start: at(6, 13, 13, 0), Duration: time.Hour},
+		},
+		{
+			text: "review next wednesday 10:00-11:30",
+			want: app.QuickEvent{Title: "review", Start: at(6, 18, 10, 0), Duration: 90 * time.Minute},
+		},
+		{
+			// конец раньше начала - на следующий день
+			text: "night shift 22:00-06:00",
+			want: app.QuickEvent{Title: "night shift", Start: at(6, 11, 22, 0), Duration: 8 * time.Hour},
+		},
+		{
+			text: "ретро в пятницу в 7 вечера на 2 часа",
+			want: app.QuickEvent{Title: "ретро", Start: at(6, 13, 19, 0), Duration: 2 * time.Hour},
+		},
+		{
+			// время уже прошло - ближайшее завтра
+			text: "gym 9am",
+			want: app.QuickEvent{Title: "gym", Start: at(6, 12, 9, 0), Duration: app.DefaultQuickDuration},
+		},
+		{
+			text: "call mom in 2 hours",
+			want: app.QuickEvent{Title: "call mom", Start: at(6, 11, 16, 30), Duration: app.DefaultQuickDuration},
+		},
+		{
+			text: "отчёт через 3 дня, напомни за день",
+			want: app.QuickEvent{Title: "отчёт", Start: at(6, 14, 9, 0), Duration: app.DefaultQuickDuration, Reminder: 24 * time.Hour},
+		},
+		{
+			// прошедшая дата без года - в следующем году
+			text: "день рождения Маши 10 июня в полдень",
+			want: app.QuickEvent{Title: "день рождения Маши", Start: time.Date(2026, 6, 10, 12, 0, 0, 0, msk), Duration: time.Hour},
+		},
+		{
+			text: "Release 2025-07-01 at 18 for 1h30m remind me 2 days before",
+			want: app.QuickEvent{Title: "Release", Start: at(7, 1, 18, 0), Duration: 90 * time.Minute, Reminder: 48 * time.Hour},
+		},
+		{
+			text: "планёрка 20.06 в 9:15",
+			want: app.QuickEvent{Title: "планёрка", Start: at(6, 20, 9, 15), Duration: time.Hour},
+		},
+		{
+			// слова, похожие на команды, без значения остаются в названии
+			text: "meeting in room 5 at 16:00",
+			want: app.QuickEvent{Title: "meeting in room 5", Start: at(6, 11, 16, 0), Duration: time.Hour},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := app.ParseQuickAdd(tt.text, now)
+			require.NoError(t, err)
+			require.Equal(t, tt.want.Title, got.Title)
+			require.True(t, tt.want.Start.Equal(got.Start), "start %s, want %s", got.Start, tt.want.Start)
+			require.Equal(t, tt.want.Duration, got.Duration)
+			require.Equal(t, tt.want.Reminder, got.Reminder)
+		})
+	}
+
+	for _, text := range []string{
+		"tomorrow 10:00",                // нет названия
+		"standup",                       // нет ни даты, ни времени
+		"standup tomorrow friday 10:00", // два дня
+		"standup 10:00 for 15m for 30m",
+		"standup tomorrow 10:00-10:00", // пустой промежуток, а не сутки
+		strings.Repeat("a ", 300) + "tomorrow",
+	} {
+		_, err := app.ParseQuickAdd(text, now)
+		require.ErrorIs(t, err, app.ErrInvalidQuickAdd, text)
+	}
+
+	// похоже на дату, но такого дня нет: ошибка, а не часть названия
+	for _, text := range []string{
+		"standup 2026-02-30 10:00",
+		"standup 2026-13-01 10:00",
+		"standup 31.04 10:00",
+		"standup 29.02.2027 10:00",
+		"standup february 30 10:00",
+		"standup 31 июня в 10",
+	} {
+		_, err := app.ParseQuickAdd(text, now)
+		require.ErrorIs(t, err, app.ErrInvalidQuickAdd, text)
+		require.ErrorContains(t, err, "no such date", text)
+	}
+}
+
+func TestApp_QuickAdd(t *testing.T) {
+	ctx := context.Background()
+	mem := memorystorage.New()
+	a, err := app.New(slog.New(slog.DiscardHandler), mem)
+	require.NoError(t, err)
+
+	res, err := a.QuickAdd(ctx, "alice", "standup 2030-03-04 10:00 for 15m remind 10m before", 0, nil, true)
+	require.NoError(t, err)
+	require.Zero(t, res.Event.EventID)
+	require.Equal(t, "15m", res.Event.Duration)
+	require.EqualValues(t, 1, res.Event.NoticeBefore)
+	require.Len(t, res.Warnings, 1)
+	events, err := mem.GetEvents(ctx)
+	require.NoError(t, err)
+	require.Empty(t, events)
+	// предпросмотр не создаёт и календарь по умолчанию
+	calendars, err := mem.ListCalendars(ctx, "alice")
+	require.NoError(t, err)
+	require.Empty(t, calendars)
+
+	// зона берётся из календаря
+	c, err := a.CreateCalendar(ctx, storage.Calendar{OwnerID: "alice", Name: "Work", TimeZone: "Europe/Moscow"})
+	require.NoError(t, err)
+	res, err = a.QuickAdd(identity.WithUserID(ctx, "alice"), "alice", "standup 2030-03-04 10:00 for 1h30m", c.ID, nil, false)
+	require.NoError(t, err)
+	require.NotZero(t, res.Event.EventID)
+	require.Equal(t, c.ID, res.Event.CalendarID)
+	require.Equal(t, "1h30m", res.Event.Duration)
+	require.Equal(t, time.Date(2030, 3, 4, 7, 0, 0, 0, time.UTC), res.Event.StartDateTime.UTC())
+	require.Empty(t, res.Warnings)
+
+	_, err = a.QuickAdd(ctx, "bob", "sync 2030-03-04 12:00", c.ID, nil, true)
+	require.ErrorIs(t, err, app.ErrForbidden)
+	_, err = a.QuickAdd(ctx, "alice", "no date here", 0, time.UTC, false)
+	require.ErrorIs(t, err, app.ErrInvalidQuickAdd)
+}
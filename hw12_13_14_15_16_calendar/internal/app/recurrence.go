@@ -0,0 +1,187 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"mycalendar/internal/holidays"
+	"mycalendar/internal/storage"
+)
+
+// Frequency - период повторения серии событий.
+type Frequency string
+
+const (
+	Daily   Frequency = "daily"
+	Weekly  Frequency = "weekly"
+	Monthly Frequency = "monthly"
+
+	maxOccurrences = 366 // событий в одной серии
+)
+
+var ErrInvalidRecurrence = errors.New("invalid recurrence")
+
+// Recurrence - правило повторения. Повторы создаются отдельными событиями,
+// дальше каждое меняется и удаляется само по себе.
+type Recurrence struct {
+	Freq     Frequency
+	Interval int // каждые Interval периодов, 0 - каждый
+	// Нужно одно из двух: сколько повторов, считая первый и пропущенные, или
+	// по какой день включительно (в зоне календаря, время не учитывается).
+	Count int
+	Until time.Time
+	// SkipHolidays - не ставить повторы на выходные и праздники календаря
+	// события (см. Calendar.HolidayCountry), первый повтор тоже.
+	SkipHolidays bool
+}
+
+// RecurringResult - созданная серия.
+type RecurringResult struct {
+	Events  []storage.Event
+	Skipped []time.Time // начала повторов, пропущенных из-за нерабочих дней
+}
+
+// AddRecurringEvent создаёт серию событий по образцу e, первое - в
+// e.StartDateTime. Права те же, что у AddEvent. Повторы считаются в зоне
+// календаря события: время начала не сдвигается при переходе на летнее время,
+// а ежемесячные повторы пропускают месяцы без такого числа. Серия создаётся
+// целиком или не создаётся, например если одно из времён уже занято.
+func (a *App) AddRecurringEvent(ctx context.Context, e storage.Event, r Recurrence) (RecurringResult, error) {
+	if err := a.checkNewEvent(ctx, &e); err != nil {
+		return RecurringResult{}, err
+	}
+	var (
+		c   storage.Calendar
+		err error
+	)
+	if e.CalendarID != 0 {
+		c, err = a.events.GetCalendar(ctx, e.CalendarID)
+	} else {
+		c, err = a.findDefaultCalendar(ctx, e.UserID)
+	}
+	if err != nil {
+		return RecurringResult{}, err
+	}
+	loc, err := time.LoadLocation(c.TimeZone)
+	if err != nil {
+		return RecurringResult{}, err
+	}
+	starts, err := r.starts(e.StartDateTime.In(loc))
+	if err != nil {
+		return RecurringResult{}, err
+	}
+
+	var res RecurringResult
+	if r.SkipHolidays {
+		var hc *holidays.Calendar
+		if hc, err = a.holidays.Get(c.HolidayCountry); err != nil {
+			return RecurringResult{}, fmt.Errorf("%w: %w", ErrInvalidRecurrence, err)
+		}
+		working := starts[:0]
+		for _, start := range starts {
+			if hc.IsWorkingDay(start) {
+				working = append(working, start)
+			} else {
+				res.Skipped = append(res.Skipped, start)
+			}
+		}
+		starts = working
+	}
+	if len(starts) == 0 {
+		return RecurringResult{}, fmt.Errorf("%w: every occurrence falls on a non-working day", ErrInvalidRecurrence)
+	}
+
+	e.CreatedAt = time.Now()
+	attendees := attendeeIDs(e.Attendees)
+	e.Attendees = nil
+	err = a.events.InTx(ctx, func(ctx context.Context, tx storage.Storage) error {
+		res.Events = res.Events[:0]
+		for _, start := range starts {
+			o := e
+			o.StartDateTime = start
+			id, err := tx.AddEvent(ctx, o)
+			if err != nil {
+				return fmt.Errorf("occurrence at %s: %w", start.Format(time.RFC3339), err)
+			}
+			if len(attendees) > 0 {
+				if err := tx.InviteAttendees(ctx, id, attendees); err != nil {
+					return err
+				}
+			}
+			created, err := tx.GetEvent(ctx, id)
+			if err != nil {
+				return err
+			}
+			if err := a.record(ctx, tx, storage.AuditCreate, nil, &created); err != nil {
+				return err
+			}
+			res.Events = append(res.Events, created)
+		}
+		return nil
+	})
+	if err != nil {
+		return RecurringResult{}, err
+	}
+	for i := range res.Events {
+		a.notify(ctx, storage.AuditCreate, nil, &res.Events[i])
+	}
+	return res, nil
+}
+
+// starts - начала повторов серии, первое - first.
+func (r Recurrence) starts(first time.Time) ([]time.Time, error) {
+	switch r.Freq {
+	case Daily, Weekly, Monthly:
+	default:
+		return nil, fmt.Errorf("%w: frequency must be daily, weekly or monthly, got %q", ErrInvalidRecurrence, r.Freq)
+	}
+	interval := r.Interval
+	switch {
+	case interval < 0:
+		return nil, fmt.Errorf("%w: interval must not be negative", ErrInvalidRecurrence)
+	case interval == 0:
+		interval = 1
+	}
+	switch {
+	case r.Count < 0 || (r.Count == 0) == r.Until.IsZero():
+		return nil, fmt.Errorf("%w: exactly one of count and until is required", ErrInvalidRecurrence)
+	case r.Count > maxOccurrences:
+		return nil, fmt.Errorf("%w: more than %d occurrences", ErrInvalidRecurrence, maxOccurrences)
+	}
+	loc := first.Location()
+	var end time.Time // полночь после дня Until
+	if !r.Until.IsZero() {
+		end = time.Date(r.Until.Year(), r.Until.Month(), r.Until.Day()+1, 0, 0, 0, 0, loc)
+		if !first.Before(end) {
+			return nil, fmt.Errorf("%w: until is before the first occurrence", ErrInvalidRecurrence)
+		}
+	}
+
+	y, m, d := first.Date()
+	hh, mm, ss := first.Clock()
+	var res []time.Time
+	for k := 0; r.Count == 0 || len(res) < r.Count; k++ {
+		var t time.Time
+		switch r.Freq {
+		case Daily:
+			t = time.Date(y, m, d+k*interval, hh, mm, ss, first.Nanosecond(), loc)
+		case Weekly:
+			t = time.Date(y, m, d+7*k*interval, hh, mm, ss, first.Nanosecond(), loc)
+		case Monthly:
+			t = time.Date(y, m+time.Month(k*interval), d, hh, mm, ss, first.Nanosecond(), loc)
+			if t.Day() != d {
+				continue // в месяце нет такого числа, например 31-го
+			}
+		}
+		if !end.IsZero() && !t.Before(end) {
+			break
+		}
+		if len(res) == maxOccurrences {
+			return nil, fmt.Errorf("%w: more than %d occurrences", ErrInvalidRecurrence, maxOccurrences)
+		}
+		res = append(res, t)
+	}
+	return res, nil
+}
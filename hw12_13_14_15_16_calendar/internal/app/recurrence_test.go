@@ -0,0 +1,99 @@
+package app_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"mycalendar/internal/app"
+	"mycalendar/internal/holidays"
+	"mycalendar/internal/identity"
+	"mycalendar/internal/storage"
+	memorystorage "mycalendar/internal/storage/memory"
+)
+
+func TestApp_AddRecurringEvent(t *testing.T) {
+	ctx := identity.WithUserID(context.Background(), "alice")
+	mem := memorystorage.New()
+	a, err := app.New(slog.New(slog.DiscardHandler), mem)
+	require.NoError(t, err)
+	reg, err := holidays.Load([]string{"RU"}, nil)
+	require.NoError(t, err)
+	a.SetHolidays(reg)
+	c, err := a.CreateCalendar(ctx, storage.Calendar{OwnerID: "alice", Name: "Work", TimeZone: "Europe/Moscow", HolidayCountry: "RU"})
+	require.NoError(t, err)
+
+	msk, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	at := func(month time.Month, d, h int) time.Time { return time.Date(2025, month, d, h, 0, 0, 0, msk) }
+	starts := func(events []storage.Event) []time.Time {
+		res := make([]time.Time, 0, len(events))
+		for _, e := range events {
+			res = append(res, e.StartDateTime.In(msk))
+		}
+		return res
+	}
+	standup := storage.Event{Title: "Standup", Duration: "15m", CalendarID: c.ID, StartDateTime: at(6, 11, 10)}
+
+	// 12 июня - День России, 14 и 15 - выходные
+	res, err := a.AddRecurringEvent(ctx, standup, app.Recurrence{Freq: app.Daily, Count: 5, SkipHolidays: true})
+	require.NoError(t, err)
+	require.Equal(t, []time.Time{at(6, 11, 10), at(6, 13, 10)}, starts(res.Events))
+	require.Equal(t, []time.Time{at(6, 12, 10), at(6, 14, 10), at(6, 15, 10)}, res.Skipped)
+	require.Equal(t, "alice", res.Events[0].UserID)
+	require.Equal(t, c.ID, res.Events[1].CalendarID)
+
+	// без SkipHolidays повторы ставятся на любые дни
+	standup.StartDateTime = at(6, 11, 11)
+	res, err = a.AddRecurringEvent(ctx, standup, app.Recurrence{Freq: app.Daily, Count: 5})
+	require.NoError(t, err)
+	require.Len(t, res.Events, 5)
+	require.Empty(t, res.Skipped)
+
+	// по какой день включительно, раз в две недели
+	standup.StartDateTime = at(6, 2, 12)
+	res, err = a.AddRecurringEvent(ctx, standup, app.Recurrence{
+		Freq: app.Weekly, Interval: 2, Until: time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []time.Time{at(6, 2, 12), at(6, 16, 12), at(6, 30, 12)}, starts(res.Events))
+
+	// месяцы без 31-го числа пропускаются
+	standup.StartDateTime = at(1, 31, 9)
+	res, err = a.AddRecurringEvent(ctx, standup, app.Recurrence{Freq: app.Monthly, Count: 3})
+	require.NoError(t, err)
+	require.Equal(t, []time.Time{at(1, 31, 9), at(3, 31, 9), at(5, 31, 9)}, starts(res.Events))
+
+	// серия создаётся целиком или не создаётся: 13 июня в 10:00 уже занято
+	before, err := mem.GetEvents(ctx)
+	require.NoError(t, err)
+	standup.StartDateTime = at(6, 10, 10)
+	_, err = a.AddRecurringEvent(ctx, standup, app.Recurrence{Freq: app.Daily, Count: 4})
+	require.ErrorIs(t, err, storage.ErrDateBusy)
+	after, err := mem.GetEvents(ctx)
+	require.NoError(t, err)
+	require.Len(t, after, len(before))
+
+	for _, r := range []app.Recurrence{
+		{Freq: "yearly", Count: 2},
+		{Freq: app.Daily},
+		{Freq: app.Daily, Count: 2, Until: at(7, 1, 0)},
+		{Freq: app.Daily, Count: 1000},
+		{Freq: app.Daily, Interval: -1, Count: 2},
+		{Freq: app.Daily, Until: at(6, 1, 0)},
+		{Freq: app.Daily, Until: at(6, 1, 0).AddDate(5, 0, 0)},
+		// все повторы на выходных
+		{Freq: app.Weekly, Count: 3, SkipHolidays: true},
+	} {
+		standup.StartDateTime = at(6, 21, 15)
+		_, err := a.AddRecurringEvent(ctx, standup, r)
+		require.ErrorIs(t, err, app.ErrInvalidRecurrence, "%+v", r)
+	}
+
+	_, err = a.AddRecurringEvent(identity.WithUserID(context.Background(), "bob"), storage.Event{
+		Title: "Intrusion", CalendarID: c.ID, StartDateTime: at(6, 23, 10),
+	}, app.Recurrence{Freq: app.Daily, Count: 2})
+	require.ErrorIs(t, err, app.ErrForbidden)
+}
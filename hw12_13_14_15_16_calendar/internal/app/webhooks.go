@@ -0,0 +1,154 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+
+	"mycalendar/internal/storage"
+)
+
+var ErrInvalidWebhook = errors.New("invalid webhook")
+
+// maxDeliveries - сколько последних доставок показывает WebhookDeliveries.
+const maxDeliveries = 100
+
+// WebhookNotifier ставит в очередь уведомления подписчикам об изменении события.
+type WebhookNotifier interface {
+	Notify(ctx context.Context, t storage.WebhookEvent, e, previous *storage.Event) error
+}
+
+// WebhookGuard проверяет, что на адрес подписки можно отправлять уведомления.
+type WebhookGuard interface {
+	CheckURL(ctx context.Context, rawURL string) error
+}
+
+// SetWebhooks включает уведомления подписчиков; адреса новых подписок проверяет
+// guard. Вызывается до Run.
+func (a *App) SetWebhooks(n WebhookNotifier, guard WebhookGuard) {
+	a.webhooks = n
+	a.webhookGuard = guard
+}
+
+// webhookEvents - какое уведомление получают подписчики на каждое действие журнала.
+var webhookEvents = map[storage.AuditAction]storage.WebhookEvent{
+	storage.AuditCreate:  storage.WebhookEventCreated,
+	storage.AuditRestore: storage.WebhookEventCreated,
+	storage.AuditUpdate:  storage.WebhookEventUpdated,
+	storage.AuditInvite:  storage.WebhookEventUpdated,
+	storage.AuditRSVP:    storage.WebhookEventUpdated,
+	storage.AuditDelete:  storage.WebhookEventDeleted,
+}
+
+// notify вызывается после фиксации изменения, поэтому ошибки только логируются.
+func (a *App) notify(ctx context.Context, action storage.AuditAction, before, after *storage.Event) {
+	t, ok := webhookEvents[action]
+	if a.webhooks == nil || !ok {
+		return
+	}
+	e, previous := after, before
+	if action == storage.AuditDelete {
+		e, previous = before, nil
+	}
+	if err := a.webhooks.Notify(ctx, t, e, previous); err != nil {
+		a.logger.Error("cannot enqueue webhooks", "action", action, "err", err)
+	}
+}
+
+// CreateWebhook подписывает w.OwnerID на изменения его событий и событий, куда
+// его пригласили. Секрет подписи генерируется и возвращается только здесь.
+func (a *App) CreateWebhook(ctx context.Context, w storage.Webhook) (storage.Webhook, error) {
+	if w.OwnerID == "" {
+		return storage.Webhook{}, fmt.Errorf("%w: owner is required", ErrInvalidWebhook)
+	}
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return storage.Webhook{}, fmt.Errorf("%w: url must be absolute http or https", ErrInvalidWebhook)
+	}
+	if a.webhookGuard != nil {
+		if err := a.webhookGuard.CheckURL(ctx, w.URL); err != nil {
+			return storage.Webhook{}, fmt.Errorf("%w: %w", ErrInvalidWebhook, err)
+		}
+	}
+	for _, e := range w.Events {
+		if !e.Valid() {
+			return storage.Webhook{}, fmt.Errorf("%w: unknown event type %q", ErrInvalidWebhook, e)
+		}
+	}
+	slices.Sort(w.Events)
+	w.Events = slices.Compact(w.Events)
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return storage.Webhook{}, err
+	}
+	w.Secret = hex.EncodeToString(secret)
+	w.Active, w.Failures, w.DisabledAt = true, 0, time.Time{}
+
+	id, err := a.events.CreateWebhook(ctx, w)
+	if err != nil {
+		return storage.Webhook{}, err
+	}
+	created, err := a.events.GetWebhook(ctx, id)
+	if err != nil {
+		return storage.Webhook{}, err
+	}
+	return created, nil
+}
+
+// ListWebhooks возвращает подписки пользователя без секретов.
+func (a *App) ListWebhooks(ctx context.Context, userID string) ([]storage.Webhook, error) {
+	hooks, err := a.events.ListWebhooks(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range hooks {
+		hooks[i].Secret = ""
+	}
+	return hooks, nil
+}
+
+func (a *App) DeleteWebhook(ctx context.Context, userID string, id int64) error {
+	if _, err := a.ownWebhook(ctx, userID, id); err != nil {
+		return err
+	}
+	return a.events.DeleteWebhook(ctx, id)
+}
+
+// EnableWebhook включает подписку, выключенную после неудачных доставок. Доставки,
+// которые ещё не исчерпали попытки, отправятся снова.
+func (a *App) EnableWebhook(ctx context.Context, userID string, id int64) (storage.Webhook, error) {
+	if _, err := a.ownWebhook(ctx, userID, id); err != nil {
+		return storage.Webhook{}, err
+	}
+	if err := a.events.EnableWebhook(ctx, id); err != nil {
+		return storage.Webhook{}, err
+	}
+	w, err := a.events.GetWebhook(ctx, id)
+	w.Secret = ""
+	return w, err
+}
+
+// WebhookDeliveries возвращает последние доставки подписки, новые первыми.
+func (a *App) WebhookDeliveries(ctx context.Context, userID string, id int64) ([]storage.Delivery, error) {
+	if _, err := a.ownWebhook(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	return a.events.ListDeliveries(ctx, id, maxDeliveries)
+}
+
+func (a *App) ownWebhook(ctx context.Context, userID string, id int64) (storage.Webhook, error) {
+	w, err := a.events.GetWebhook(ctx, id)
+	if err != nil {
+		return storage.Webhook{}, err
+	}
+	if w.OwnerID != userID {
+		return storage.Webhook{}, fmt.Errorf("%w: webhook %d belongs to another user", ErrForbidden, id)
+	}
+	return w, nil
+}
@@ -0,0 +1,80 @@
+package app_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"mycalendar/internal/app"
+	"mycalendar/internal/identity"
+	"mycalendar/internal/storage"
+	memorystorage "mycalendar/internal/storage/memory"
+	"mycalendar/internal/webhook"
+)
+
+func TestApp_Webhooks(t *testing.T) {
+	ctx := context.Background()
+	store := memorystorage.New()
+	a, err := app.New(slog.New(slog.DiscardHandler), store)
+	require.NoError(t, err)
+	a.SetWebhooks(webhook.New(store), webhook.Guard{})
+
+	_, err = a.CreateWebhook(ctx, storage.Webhook{OwnerID: "alice", URL: "ftp://example.com"})
+	require.ErrorIs(t, err, app.ErrInvalidWebhook)
+	_, err = a.CreateWebhook(ctx, storage.Webhook{OwnerID: "alice", URL: "http://203.0.113.10", Events: []storage.WebhookEvent{"event.moved"}})
+	require.ErrorIs(t, err, app.ErrInvalidWebhook)
+	for _, internal := range []string{"http://127.0.0.1:8080/", "http://169.254.169.254/latest/meta-data", "https://[::1]/", "http://10.1.2.3/"} {
+		_, err = a.CreateWebhook(ctx, storage.Webhook{OwnerID: "alice", URL: internal})
+		require.ErrorIs(t, err, webhook.ErrBlockedAddress, internal)
+		require.ErrorIs(t, err, app.ErrInvalidWebhook, internal)
+	}
+
+	// 203.0.113.0/24 - документационные, но глобальные адреса
+	hook, err := a.CreateWebhook(ctx, storage.Webhook{OwnerID: "alice", URL: "http://203.0.113.10/hook"})
+	require.NoError(t, err)
+	require.True(t, hook.Active)
+	require.Len(t, hook.Secret, 64)
+	bobs, err := a.CreateWebhook(ctx, storage.Webhook{
+		OwnerID: "bob", URL: "https://203.0.113.11/bob",
+		Events: []storage.WebhookEvent{storage.WebhookEventDeleted},
+	})
+	require.NoError(t, err)
+
+	hooks, err := a.ListWebhooks(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, hooks, 1)
+	require.Empty(t, hooks[0].Secret)
+
+	alice := identity.WithUserID(ctx, "alice")
+	start := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+	id, err := a.AddEvent(alice, storage.Event{Title: "sync", StartDateTime: start, Duration: "1h"})
+	require.NoError(t, err)
+	_, err = a.InviteAttendees(alice, id, []string{"bob"})
+	require.NoError(t, err)
+	require.NoError(t, a.DeleteEvent(alice, "alice", start))
+
+	ds, err := a.WebhookDeliveries(ctx, "alice", hook.ID)
+	require.NoError(t, err)
+	var types []storage.WebhookEvent
+	for _, d := range ds {
+		types = append(types, d.Event)
+	}
+	require.Equal(t, []storage.WebhookEvent{
+		storage.WebhookEventDeleted, storage.WebhookEventUpdated, storage.WebhookEventCreated,
+	}, types)
+
+	// bob подписан только на удаление событий, куда его пригласили
+	ds, err = a.WebhookDeliveries(ctx, "bob", bobs.ID)
+	require.NoError(t, err)
+	require.Len(t, ds, 1)
+	require.Equal(t, storage.WebhookEventDeleted, ds[0].Event)
+
+	_, err = a.WebhookDeliveries(ctx, "bob", hook.ID)
+	require.ErrorIs(t, err, app.ErrForbidden)
+	require.ErrorIs(t, a.DeleteWebhook(ctx, "bob", hook.ID), app.ErrForbidden)
+	require.NoError(t, a.DeleteWebhook(ctx, "alice", hook.ID))
+	_, err = a.EnableWebhook(ctx, "alice", hook.ID)
+	require.ErrorIs(t, err, storage.ErrWebhookNotFound)
+}
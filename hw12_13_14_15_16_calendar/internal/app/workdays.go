@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"mycalendar/internal/holidays"
+	"mycalendar/internal/storage"
+)
+
+const maxWorkdaysRange = 366 // дней в одном запросе
+
+var ErrInvalidWorkdays = errors.New("invalid working days query")
+
+// WorkingDays - дни периода по праздничному календарю страны.
+type WorkingDays struct {
+	Country string // пусто - только выходные
+	Days    []holidays.Day
+}
+
+// SetHolidays задаёт праздничные календари стран. По умолчанию известны только
+// выходные. Вызывается до запуска серверов.
+func (a *App) SetHolidays(r *holidays.Registry) {
+	a.holidays = r
+}
+
+// WorkingDays возвращает дни с даты from по дату to включительно, время from и to
+// не учитывается. Страна и зона дней берутся из календаря calendarID, если он
+// задан и их не передали явно; зона по умолчанию - UTC. Смотреть настройки
+// чужого календаря можно с правом free-busy.
+func (a *App) WorkingDays(ctx context.Context, userID, country string, calendarID int64, loc *time.Location, from, to time.Time) (WorkingDays, error) {
+	if calendarID != 0 {
+		c, err := a.events.GetCalendar(ctx, calendarID)
+		if err != nil {
+			return WorkingDays{}, err
+		}
+		if !c.Access(userID).Allows(storage.PermFreeBusy) {
+			return WorkingDays{}, fmt.Errorf("%w: %s has no access to calendar %d", ErrForbidden, userID, c.ID)
+		}
+		if country == "" {
+			country = c.HolidayCountry
+		}
+		if loc == nil {
+			if loc, err = time.LoadLocation(c.TimeZone); err != nil {
+				return WorkingDays{}, err
+			}
+		}
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	hc, err := a.holidays.Get(country)
+	if err != nil {
+		return WorkingDays{}, fmt.Errorf("%w: %w", ErrInvalidWorkdays, err)
+	}
+
+	if from.IsZero() || to.IsZero() {
+		return WorkingDays{}, fmt.Errorf("%w: from and to are required", ErrInvalidWorkdays)
+	}
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, loc)
+	switch {
+	case to.Before(from):
+		return WorkingDays{}, fmt.Errorf("%w: to is before from", ErrInvalidWorkdays)
+	case to.Sub(from) >= maxWorkdaysRange*24*time.Hour:
+		return WorkingDays{}, fmt.Errorf("%w: more than %d days", ErrInvalidWorkdays, maxWorkdaysRange)
+	}
+	return WorkingDays{Country: hc.Country(), Days: hc.Days(from, to)}, nil
+}
@@ -0,0 +1,88 @@
+package app_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"mycalendar/internal/app"
+	"mycalendar/internal/holidays"
+	"mycalendar/internal/storage"
+	memorystorage "mycalendar/internal/storage/memory"
+)
+
+func TestApp_WorkingDays(t *testing.T) {
+	ctx := context.Background()
+	mem := memorystorage.New()
+	a, err := app.New(slog.New(slog.DiscardHandler), mem)
+	require.NoError(t, err)
+
+	// без загруженных стран праздничный календарь нельзя выбрать
+	_, err = a.CreateCalendar(ctx, storage.Calendar{OwnerID: "alice", Name: "Work", HolidayCountry: "RU"})
+	require.ErrorIs(t, err, app.ErrInvalidCalendar)
+
+	reg, err := holidays.Load([]string{"RU"}, nil)
+	require.NoError(t, err)
+	a.SetHolidays(reg)
+	c, err := a.CreateCalendar(ctx, storage.Calendar{OwnerID: "alice", Name: "Work", TimeZone: "Europe/Moscow", HolidayCountry: "ru"})
+	require.NoError(t, err)
+	require.Equal(t, "RU", c.HolidayCountry)
+
+	from := time.Date(2025, 6, 11, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
+	res, err := a.WorkingDays(ctx, "alice", "", c.ID, nil, from, to)
+	require.NoError(t, err)
+	require.Equal(t, "RU", res.Country)
+	require.Len(t, res.Days, 5)
+	require.Equal(t, "Europe/Moscow", res.Days[0].Date.Location().String())
+	require.True(t, res.Days[0].Working())
+	require.Equal(t, "День России", res.Days[1].Holiday)
+
+	// без календаря и страны - только выходные
+	res, err = a.WorkingDays(ctx, "bob", "", 0, nil, from, to)
+	require.NoError(t, err)
+	require.Empty(t, res.Country)
+	require.True(t, res.Days[1].Working())
+
+	_, err = a.WorkingDays(ctx, "bob", "", c.ID, nil, from, to)
+	require.ErrorIs(t, err, app.ErrForbidden)
+	_, err = a.WorkingDays(ctx, "alice", "DE", 0, nil, from, to)
+	require.ErrorIs(t, err, app.ErrInvalidWorkdays)
+	_, err = a.WorkingDays(ctx, "alice", "", 0, nil, to, from)
+	require.ErrorIs(t, err, app.ErrInvalidWorkdays)
+	_, err = a.WorkingDays(ctx, "alice", "", 0, nil, from, from.AddDate(1, 1, 0))
+	require.ErrorIs(t, err, app.ErrInvalidWorkdays)
+}
+
+func TestApp_UpdateCalendar(t *testing.T) {
+	ctx := context.Background()
+	a, err := app.New(slog.New(slog.DiscardHandler), memorystorage.New())
+	require.NoError(t, err)
+	reg, err := holidays.Load([]string{"US"}, nil)
+	require.NoError(t, err)
+	a.SetHolidays(reg)
+
+	c, err := a.CreateCalendar(ctx, storage.Calendar{OwnerID: "alice", Name: "Work"})
+	require.NoError(t, err)
+
+	c.Name = "Office"
+	c.HolidayCountry = "us"
+	c.DeferReminders = true
+	got, err := a.UpdateCalendar(ctx, "alice", c)
+	require.NoError(t, err)
+	require.Equal(t, "Office", got.Name)
+	require.Equal(t, "US", got.HolidayCountry)
+	require.True(t, got.DeferReminders)
+	require.Equal(t, "UTC", got.TimeZone)
+
+	_, err = a.UpdateCalendar(ctx, "bob", c)
+	require.ErrorIs(t, err, app.ErrForbidden)
+	c.HolidayCountry = "XX"
+	_, err = a.UpdateCalendar(ctx, "alice", c)
+	require.ErrorIs(t, err, app.ErrInvalidCalendar)
+	c.ID = 999
+	_, err = a.UpdateCalendar(ctx, "alice", c)
+	require.ErrorIs(t, err, storage.ErrCalendarNotFound)
+}
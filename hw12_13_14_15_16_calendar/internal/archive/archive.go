@@ -0,0 +1,238 @@
+// Package archive сохраняет окончательно удаляемые события в сжатые файлы JSON Lines,
+// по файлу на месяц начала события, и восстанавливает их обратно в хранилище.
+// События тенанта по умолчанию лежат в самом каталоге, остальных - в подкаталоге
+// с ID тенанта. Вложения событий записываются вместе с ними, а содержимое файлов
+// вложений - в подкаталог files под ключами blob хранилища.
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"mycalendar/internal/blob"
+	"mycalendar/internal/storage"
+	"mycalendar/internal/tenant"
+)
+
+var ErrVerify = errors.New("archive verification failed")
+
+// FilesDir - подкаталог архива с содержимым файлов вложений.
+const FilesDir = "files"
+
+// Archive - каталог с архивами. Каждый вызов Write дописывает в файл месяца
+// отдельный gzip-поток, gzip.Reader читает такие файлы целиком.
+type Archive struct {
+	dir   string
+	blobs blob.Store // откуда копировать содержимое файлов, nil - не копировать
+	files *blob.FS
+	mu    sync.Mutex
+}
+
+func New(dir string) (*Archive, error) {
+	files, err := blob.NewFS(filepath.Join(dir, FilesDir))
+	if err != nil {
+		return nil, fmt.Errorf("cannot create archive dir: %w", err)
+	}
+	return &Archive{dir: dir, files: files}, nil
+}
+
+// SetBlobs включает копирование содержимого файлов вложений из blobs в архив.
+// Без него в архив попадают только записи о вложениях. Вызывается до первого Write.
+func (a *Archive) SetBlobs(blobs blob.Store) {
+	a.blobs = blobs
+}
+
+// Files - содержимое файлов вложений, сохранённое в архиве, для Import.
+func (a *Archive) Files() blob.Store {
+	return a.files
+}
+
+// FileName - имя файла архива для событий месяца t, например events-2025-06.jsonl.gz.
+func FileName(t time.Time) string {
+	return "events-" + t.UTC().Format("2006-01") + ".jsonl.gz"
+}
+
+// Write дописывает события тенанта из ctx с их вложениями из attachments в файлы
+// их месяцев и перечитывает каждый файл, проверяя, что все события и вложения
+// на месте. При ошибке файл обрезается до прежнего размера, так что повреждённый
+// хвост не мешает следующим записям. Содержимое файлов вложений копируется до
+// записи событий.
+func (a *Archive) Write(ctx context.Context, events []storage.Event, attachments []storage.Attachment) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := a.copyFiles(ctx, attachments); err != nil {
+		return err
+	}
+	byEvent := make(map[int64][]storage.Attachment)
+	for _, att := range attachments {
+		byEvent[att.EventID] = append(byEvent[att.EventID], att)
+	}
+
+	dir := a.dir
+	if id := tenant.FromContext(ctx); id != tenant.Default {
+		dir = filepath.Join(a.dir, id)
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return fmt.Errorf("cannot create archive dir: %w", err)
+		}
+	}
+
+	byFile := make(map[string][]storage.Event)
+	var order []string
+	for _, e := range events {
+		name := FileName(e.StartDateTime)
+		if _, ok := byFile[name]; !ok {
+			order = append(order, name)
+		}
+		byFile[name] = append(byFile[name], e)
+	}
+	for _, name := range order {
+		if err := writeFile(filepath.Join(dir, name), byFile[name], byEvent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyFiles копирует содержимое файлов вложений в архив. Файл, которого уже нет
+// в хранилище, пропускается: в архиве останется только запись о нём.
+func (a *Archive) copyFiles(ctx context.Context, attachments []storage.Attachment) error {
+	if a.blobs == nil {
+		return nil
+	}
+	for _, att := range attachments {
+		if att.IsLink() {
+			continue
+		}
+		r, err := a.blobs.Open(ctx, att.BlobKey)
+		if errors.Is(err, blob.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("cannot read attachment %d: %w", att.ID, err)
+		}
+		_, err = a.files.Put(ctx, att.BlobKey, r)
+		r.Close()
+		if err != nil {
+			return fmt.Errorf("cannot archive attachment %d: %w", att.ID, err)
+		}
+	}
+	return nil
+}
+
+func writeFile(path string, events []storage.Event, attachments map[int64][]storage.Attachment) (err error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return fmt.Errorf("cannot open archive: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("cannot stat archive: %w", err)
+	}
+	size := info.Size()
+	defer func() {
+		if err != nil {
+			_ = os.Truncate(path, size)
+		}
+	}()
+
+	if err := encode(f, events, attachments); err != nil {
+		f.Close()
+		return fmt.Errorf("cannot write archive %s: %w", path, err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("cannot sync archive %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("cannot close archive %s: %w", path, err)
+	}
+	return verify(path, events, attachments)
+}
+
+func encode(w io.Writer, events []storage.Event, attachments map[int64][]storage.Attachment) error {
+	zw := gzip.NewWriter(w)
+	enc := json.NewEncoder(zw)
+	for _, e := range events {
+		if err := enc.Encode(toRecord(e, attachments[e.EventID])); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func verify(path string, events []storage.Event, attachments map[int64][]storage.Attachment) error {
+	archived, archivedAttachments, err := ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrVerify, err)
+	}
+	ids := make(map[int64]struct{}, len(archived))
+	for _, e := range archived {
+		ids[e.EventID] = struct{}{}
+	}
+	attachmentIDs := make(map[int64]struct{}, len(archivedAttachments))
+	for _, att := range archivedAttachments {
+		attachmentIDs[att.ID] = struct{}{}
+	}
+	for _, e := range events {
+		if _, ok := ids[e.EventID]; !ok {
+			return fmt.Errorf("%w: event %d is missing in %s", ErrVerify, e.EventID, path)
+		}
+		for _, att := range attachments[e.EventID] {
+			if _, ok := attachmentIDs[att.ID]; !ok {
+				return fmt.Errorf("%w: attachment %d is missing in %s", ErrVerify, att.ID, path)
+			}
+		}
+	}
+	return nil
+}
+
+// ReadFile читает все события и их вложения из файла архива.
+func ReadFile(path string) ([]storage.Event, []storage.Attachment, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot open archive: %w", err)
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// Read читает события из архива в формате gzip JSON Lines. EventID вложений -
+// ID событий в архиве.
+func Read(r io.Reader) ([]storage.Event, []storage.Attachment, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot read archive: %w", err)
+	}
+	defer zr.Close()
+
+	var events []storage.Event
+	var attachments []storage.Attachment
+	sc := bufio.NewScanner(zr)
+	sc.Buffer(make([]byte, 64*1024), 1<<20)
+	for line := 1; sc.Scan(); line++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var rec record
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			return nil, nil, fmt.Errorf("archive line %d: %w", line, err)
+		}
+		events = append(events, rec.event())
+		attachments = append(attachments, rec.attachments()...)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, nil, fmt.Errorf("cannot read archive: %w", err)
+	}
+	return events, attachments, nil
+}
@@ -0,0 +1,182 @@
+package archive_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"mycalendar/internal/archive"
+	"mycalendar/internal/blob"
+	"mycalendar/internal/storage"
+	memorystorage "mycalendar/internal/storage/memory"
+	"mycalendar/internal/tenant"
+)
+
+func TestArchive_WriteRotatesByMonth(t *testing.T) {
+	dir := t.TempDir()
+	a, err := archive.New(dir)
+	require.NoError(t, err)
+
+	june := time.Date(2025, 6, 10, 9, 0, 0, 0, time.UTC)
+	july := time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)
+	deleted := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+	require.NoError(t, a.Write(ctx, []storage.Event{
+		{EventID: 1, UserID: "u1", Title: "June", StartDateTime: june, Duration: "1h", DeletedAt: deleted},
+		{EventID: 2, UserID: "u1", Title: "July", StartDateTime: july, DeletedAt: deleted},
+	}, nil))
+	// следующий проход дописывает в тот же файл
+	require.NoError(t, a.Write(ctx, []storage.Event{
+		{
+			EventID: 3, UserID: "u2", Title: "June again", StartDateTime: june, DeletedAt: deleted,
+			Attendees: []storage.Attendee{{UserID: "u1", Status: storage.RSVPAccepted}},
+		},
+	}, nil))
+
+	events, _, err := archive.ReadFile(filepath.Join(dir, "events-2025-06.jsonl.gz"))
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, "June", events[0].Title)
+	require.Equal(t, "1h", events[0].Duration)
+	require.True(t, events[0].StartDateTime.Equal(june))
+	require.True(t, events[0].DeletedAt.Equal(deleted))
+	require.Equal(t, []storage.Attendee{{UserID: "u1", Status: storage.RSVPAccepted}}, events[1].Attendees)
+
+	events, _, err = archive.ReadFile(filepath.Join(dir, archive.FileName(july)))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	// события других тенантов - в их подкаталогах
+	require.NoError(t, a.Write(tenant.With(ctx, "acme"), []storage.Event{
+		{EventID: 4, UserID: "u1", Title: "Acme", StartDateTime: june, DeletedAt: deleted},
+	}, nil))
+	events, _, err = archive.ReadFile(filepath.Join(dir, "acme", archive.FileName(june)))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	events, _, err = archive.ReadFile(filepath.Join(dir, archive.FileName(june)))
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+}
+
+func TestArchive_CorruptedFile(t *testing.T) {
+	dir := t.TempDir()
+	a, err := archive.New(dir)
+	require.NoError(t, err)
+
+	start := time.Date(2025, 6, 10, 9, 0, 0, 0, time.UTC)
+	path := filepath.Join(dir, archive.FileName(start))
+	require.NoError(t, os.WriteFile(path, []byte("not gzip"), 0o600))
+
+	err = a.Write(context.Background(), []storage.Event{{EventID: 1, UserID: "u1", StartDateTime: start}}, nil)
+	require.ErrorIs(t, err, archive.ErrVerify)
+	// неудачная запись не оставляет хвоста
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "not gzip", string(data))
+}
+
+func TestImport(t *testing.T) {
+	ctx := context.Background()
+	mem := memorystorage.New()
+	start := time.Date(2025, 6, 10, 9, 0, 0, 0, time.UTC)
+
+	_, err := mem.AddEvent(ctx, storage.Event{UserID: "u2", Title: "Taken", StartDateTime: start})
+	require.NoError(t, err)
+
+	res, err := archive.Import(ctx, mem, []storage.Event{
+		{
+			EventID: 10, UserID: "u1", Title: "Sync", StartDateTime: start, CalendarID: 999, DeletedAt: time.Now(),
+			Attendees: []storage.Attendee{
+				{UserID: "u2", Status: storage.RSVPDeclined},
+				{UserID: "u3", Status: storage.RSVPNeedsAction},
+			},
+		},
+		{EventID: 11, UserID: "u2", Title: "Busy", StartDateTime: start},
+	}, nil, archive.Files{})
+	require.NoError(t, err)
+	require.Equal(t, archive.ImportResult{Imported: 1, Skipped: 1}, res)
+
+	events, err := mem.ListEvents(ctx, storage.EventFilter{UserID: "u1"})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "Sync", events[0].Title)
+	require.True(t, events[0].DeletedAt.IsZero())
+	require.NotEqual(t, int64(999), events[0].CalendarID)
+	require.Equal(t, []storage.Attendee{
+		{UserID: "u2", Status: storage.RSVPDeclined},
+		{UserID: "u3", Status: storage.RSVPNeedsAction},
+	}, events[0].Attendees)
+}
+
+func TestArchive_Attachments(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	a, err := archive.New(dir)
+	require.NoError(t, err)
+	src, err := blob.NewFS(t.TempDir())
+	require.NoError(t, err)
+	a.SetBlobs(src)
+
+	_, err = src.Put(ctx, "default/10/abc", strings.NewReader("agenda"))
+	require.NoError(t, err)
+	start := time.Date(2025, 6, 10, 9, 0, 0, 0, time.UTC)
+	attachments := []storage.Attachment{
+		{ID: 1, EventID: 10, Name: "agenda.txt", ContentType: "text/plain", Size: 6, BlobKey: "default/10/abc", CreatedBy: "u1"},
+		{ID: 2, EventID: 10, Name: "Doc", URL: "https://example.com/doc", CreatedBy: "u1"},
+		// содержимое уже потеряно, в архиве остаётся только запись
+		{ID: 3, EventID: 10, Name: "lost.txt", ContentType: "text/plain", BlobKey: "default/10/lost"},
+	}
+	require.NoError(t, a.Write(ctx, []storage.Event{
+		{EventID: 10, UserID: "u1", Title: "Sync", StartDateTime: start, DeletedAt: start},
+	}, attachments))
+	// после очистки корзины файла в хранилище вложений нет
+	require.NoError(t, src.Delete(ctx, "default/10/abc"))
+
+	events, archived, err := archive.ReadFile(filepath.Join(dir, archive.FileName(start)))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Len(t, archived, 3)
+	require.Equal(t, "agenda.txt", archived[0].Name)
+	require.Equal(t, int64(10), archived[0].EventID)
+	require.Equal(t, "https://example.com/doc", archived[1].URL)
+
+	mem := memorystorage.New()
+	dst, err := blob.NewFS(t.TempDir())
+	require.NoError(t, err)
+	res, err := archive.Import(ctx, mem, events, archived, archive.Files{Archive: a.Files(), Store: dst})
+	require.NoError(t, err)
+	require.Equal(t, archive.ImportResult{Imported: 1, FilesSkipped: 1}, res)
+
+	restored, err := mem.ListEvents(ctx, storage.EventFilter{UserID: "u1"})
+	require.NoError(t, err)
+	require.Len(t, restored, 1)
+	list, err := mem.ListAttachments(ctx, restored[0].EventID)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	require.Equal(t, "agenda.txt", list[0].Name)
+	require.NotEqual(t, "default/10/abc", list[0].BlobKey)
+	r, err := dst.Open(ctx, list[0].BlobKey)
+	require.NoError(t, err)
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, "agenda", string(data))
+	require.Equal(t, "https://example.com/doc", list[1].URL)
+
+	// без хранилища вложений возвращаются только ссылки
+	mem = memorystorage.New()
+	res, err = archive.Import(ctx, mem, events, archived, archive.Files{})
+	require.NoError(t, err)
+	require.Equal(t, archive.ImportResult{Imported: 1, FilesSkipped: 2}, res)
+	restored, err = mem.ListEvents(ctx, storage.EventFilter{UserID: "u1"})
+	require.NoError(t, err)
+	list, err = mem.ListAttachments(ctx, restored[0].EventID)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.True(t, list[0].IsLink())
+}
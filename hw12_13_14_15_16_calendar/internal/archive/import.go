@@ -0,0 +1,144 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"time"
+
+	"mycalendar/internal/blob"
+	"mycalendar/internal/storage"
+	"mycalendar/internal/tenant"
+)
+
+// ImportResult - итог восстановления архива.
+type ImportResult struct {
+	Imported int
+	Skipped  int // время события уже занято, например событие восстановлено раньше
+	// Файлы вложений, которые не восстановлены: их содержимого нет в архиве
+	// или не задано, куда его вернуть.
+	FilesSkipped int
+}
+
+// Files - откуда и куда возвращать содержимое файлов вложений.
+type Files struct {
+	Archive blob.Store // копии в архиве, см. Archive.Files
+	Store   blob.Store // хранилище вложений календаря
+}
+
+// Import возвращает события из архива в хранилище как действующие, с новыми ID,
+// вместе с их вложениями из attachments. Если календаря события больше нет, оно
+// попадает в календарь владельца по умолчанию. Ссылки восстанавливаются всегда,
+// файлы - если их содержимое удалось вернуть из files.Archive в files.Store.
+func Import(
+	ctx context.Context, s storage.Storage, events []storage.Event, attachments []storage.Attachment, files Files,
+) (ImportResult, error) {
+	byEvent := make(map[int64][]storage.Attachment)
+	for _, att := range attachments {
+		byEvent[att.EventID] = append(byEvent[att.EventID], att)
+	}
+	var res ImportResult
+	for _, e := range events {
+		archivedID := e.EventID
+		attendees := e.Attendees
+		e.EventID = 0
+		e.DeletedAt = time.Time{}
+		e.Attendees = nil
+
+		id, err := s.AddEvent(ctx, e)
+		if errors.Is(err, storage.ErrCalendarNotFound) {
+			e.CalendarID = 0
+			id, err = s.AddEvent(ctx, e)
+		}
+		if errors.Is(err, storage.ErrDateBusy) {
+			res.Skipped++
+			continue
+		}
+		if err != nil {
+			return res, fmt.Errorf("cannot import event of %s at %s: %w", e.UserID, e.StartDateTime, err)
+		}
+		if err := restoreAttendees(ctx, s, id, attendees); err != nil {
+			return res, err
+		}
+		skipped, err := restoreAttachments(ctx, s, files, id, byEvent[archivedID])
+		res.FilesSkipped += skipped
+		if err != nil {
+			return res, err
+		}
+		res.Imported++
+	}
+	return res, nil
+}
+
+// restoreAttachments прикрепляет вложения к восстановленному событию id и
+// возвращает, сколько файлов пропущено.
+func restoreAttachments(
+	ctx context.Context, s storage.AttachmentStorage, files Files, id int64, attachments []storage.Attachment,
+) (int, error) {
+	skipped := 0
+	for _, att := range attachments {
+		att.ID = 0
+		att.EventID = id
+		if !att.IsLink() {
+			key, err := files.restore(ctx, id, att.BlobKey)
+			if err != nil {
+				return skipped, fmt.Errorf("cannot restore attachment %q of event %d: %w", att.Name, id, err)
+			}
+			if key == "" {
+				skipped++
+				continue
+			}
+			att.BlobKey = key
+		}
+		if _, err := s.AddAttachment(ctx, att); err != nil {
+			return skipped, fmt.Errorf("cannot restore attachment %q of event %d: %w", att.Name, id, err)
+		}
+	}
+	return skipped, nil
+}
+
+// restore копирует содержимое файла из архива в хранилище вложений под ключом
+// нового события и возвращает этот ключ, пустой - если копировать нечего.
+func (f Files) restore(ctx context.Context, eventID int64, archivedKey string) (string, error) {
+	if f.Archive == nil || f.Store == nil {
+		return "", nil
+	}
+	r, err := f.Archive.Open(ctx, archivedKey)
+	if errors.Is(err, blob.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	// ключ устроен как у вложений, загруженных через API: тенант/событие/случайная часть
+	key := tenant.FromContext(ctx) + "/" + strconv.FormatInt(eventID, 10) + "/" + path.Base(archivedKey)
+	if _, err := f.Store.Put(ctx, key, r); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+func restoreAttendees(ctx context.Context, s storage.EventsStorage, id int64, attendees []storage.Attendee) error {
+	if len(attendees) == 0 {
+		return nil
+	}
+	userIDs := make([]string, 0, len(attendees))
+	for _, a := range attendees {
+		userIDs = append(userIDs, a.UserID)
+	}
+	if err := s.InviteAttendees(ctx, id, userIDs); err != nil {
+		return fmt.Errorf("cannot restore attendees of event %d: %w", id, err)
+	}
+	for _, a := range attendees {
+		if a.Status == storage.RSVPNeedsAction || !a.Status.Valid() {
+			continue
+		}
+		if err := s.SetAttendeeStatus(ctx, id, a.UserID, a.Status); err != nil {
+			return fmt.Errorf("cannot restore attendees of event %d: %w", id, err)
+		}
+	}
+	return nil
+}
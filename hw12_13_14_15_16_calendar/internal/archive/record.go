@@ -0,0 +1,110 @@
+package archive
+
+import (
+	"time"
+
+	"mycalendar/internal/storage"
+)
+
+// record - строка архива. Формат не зависит от storage.Event, чтобы старые
+// архивы читались и после изменений модели.
+type record struct {
+	ID           int64      `json:"id"`
+	UserID       string     `json:"user_id"`
+	CalendarID   int64      `json:"calendar_id,omitempty"`
+	Title        string     `json:"title"`
+	Description  string     `json:"description,omitempty"`
+	Start        time.Time  `json:"start"`
+	Duration     string     `json:"duration,omitempty"`
+	NoticeBefore int32      `json:"notice_before,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	DeletedAt    time.Time  `json:"deleted_at"`
+	Attendees    []attendee `json:"attendees,omitempty"`
+	// Attachments - вложения события. Содержимое файлов лежит отдельно,
+	// в каталоге files архива под тем же ключом (см. Archive.SetBlobs).
+	Attachments []attachment `json:"attachments,omitempty"`
+}
+
+type attendee struct {
+	UserID string `json:"user_id"`
+	Status string `json:"status"`
+}
+
+type attachment struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"content_type,omitempty"`
+	Size        int64     `json:"size,omitempty"`
+	BlobKey     string    `json:"blob_key,omitempty"`
+	URL         string    `json:"url,omitempty"`
+	CreatedBy   string    `json:"created_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func toRecord(e storage.Event, attachments []storage.Attachment) record {
+	r := record{
+		ID:           e.EventID,
+		UserID:       e.UserID,
+		CalendarID:   e.CalendarID,
+		Title:        e.Title,
+		Description:  e.Description,
+		Start:        e.StartDateTime,
+		Duration:     e.Duration,
+		NoticeBefore: e.NoticeBefore,
+		CreatedAt:    e.CreatedAt,
+		DeletedAt:    e.DeletedAt,
+	}
+	for _, a := range e.Attendees {
+		r.Attendees = append(r.Attendees, attendee{UserID: a.UserID, Status: string(a.Status)})
+	}
+	for _, a := range attachments {
+		r.Attachments = append(r.Attachments, attachment{
+			ID:          a.ID,
+			Name:        a.Name,
+			ContentType: a.ContentType,
+			Size:        a.Size,
+			BlobKey:     a.BlobKey,
+			URL:         a.URL,
+			CreatedBy:   a.CreatedBy,
+			CreatedAt:   a.CreatedAt,
+		})
+	}
+	return r
+}
+
+func (r record) event() storage.Event {
+	e := storage.Event{
+		EventID:       r.ID,
+		UserID:        r.UserID,
+		CalendarID:    r.CalendarID,
+		Title:         r.Title,
+		Description:   r.Description,
+		StartDateTime: r.Start,
+		Duration:      r.Duration,
+		NoticeBefore:  r.NoticeBefore,
+		CreatedAt:     r.CreatedAt,
+		DeletedAt:     r.DeletedAt,
+	}
+	for _, a := range r.Attendees {
+		e.Attendees = append(e.Attendees, storage.Attendee{UserID: a.UserID, Status: storage.RSVPStatus(a.Status)})
+	}
+	return e
+}
+
+func (r record) attachments() []storage.Attachment {
+	res := make([]storage.Attachment, 0, len(r.Attachments))
+	for _, a := range r.Attachments {
+		res = append(res, storage.Attachment{
+			ID:          a.ID,
+			EventID:     r.ID,
+			Name:        a.Name,
+			ContentType: a.ContentType,
+			Size:        a.Size,
+			BlobKey:     a.BlobKey,
+			URL:         a.URL,
+			CreatedBy:   a.CreatedBy,
+			CreatedAt:   a.CreatedAt,
+		})
+	}
+	return res
+}
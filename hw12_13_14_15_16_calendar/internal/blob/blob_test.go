@@ -0,0 +1,73 @@
+package blob_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"mycalendar/internal/blob"
+)
+
+func TestFS(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s, err := blob.NewFS(dir)
+	require.NoError(t, err)
+
+	n, err := s.Put(ctx, "acme/1/agenda", strings.NewReader("agenda"))
+	require.NoError(t, err)
+	require.EqualValues(t, 6, n)
+
+	r, err := s.Open(ctx, "acme/1/agenda")
+	require.NoError(t, err)
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	require.Equal(t, "agenda", string(data))
+
+	require.NoError(t, s.Delete(ctx, "acme/1/agenda"))
+	_, err = s.Open(ctx, "acme/1/agenda")
+	require.ErrorIs(t, err, blob.ErrNotFound)
+	require.NoError(t, s.Delete(ctx, "acme/1/agenda"))
+
+	// опустевшие каталоги удалены, корневой остался
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestFS_FailedPut(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s, err := blob.NewFS(dir)
+	require.NoError(t, err)
+
+	broken := io.MultiReader(strings.NewReader("part"), errReader{})
+	_, err = s.Put(ctx, "1/file", broken)
+	require.Error(t, err)
+	_, err = s.Open(ctx, "1/file")
+	require.ErrorIs(t, err, blob.ErrNotFound)
+
+	// временный файл не остался
+	entries, err := os.ReadDir(filepath.Join(dir, "1"))
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestFS_InvalidKey(t *testing.T) {
+	s, err := blob.NewFS(t.TempDir())
+	require.NoError(t, err)
+	for _, key := range []string{"", "/etc/passwd", "../x", "a/../../x", "a//b", `a\b`, "a/.tmp-1"} {
+		_, err := s.Put(context.Background(), key, strings.NewReader("x"))
+		require.ErrorIs(t, err, blob.ErrInvalidKey, key)
+	}
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) { return 0, errors.New("connection reset") }
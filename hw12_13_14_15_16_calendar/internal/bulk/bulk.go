@@ -0,0 +1,115 @@
+// Package bulk - форматы массовой загрузки и выгрузки событий: CSV и JSON Lines.
+package bulk
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"mycalendar/internal/storage"
+)
+
+type Format string
+
+const (
+	CSV    Format = "csv"
+	NDJSON Format = "ndjson"
+)
+
+// ParseFormat понимает имя формата и MIME тип, пустая строка - ошибка.
+func ParseFormat(s string) (Format, error) {
+	s, _, _ = strings.Cut(strings.ToLower(strings.TrimSpace(s)), ";")
+	switch strings.TrimSpace(s) {
+	case "csv", "text/csv":
+		return CSV, nil
+	case "ndjson", "jsonl", "application/x-ndjson", "application/jsonl":
+		return NDJSON, nil
+	}
+	return "", fmt.Errorf("unsupported format %q, use csv or ndjson", s)
+}
+
+// FormatOf определяет формат по расширению файла.
+func FormatOf(name string) (Format, error) {
+	return ParseFormat(strings.TrimPrefix(filepath.Ext(name), "."))
+}
+
+func (f Format) ContentType() string {
+	if f == CSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/x-ndjson"
+}
+
+// Record - строка файла. Владелец не хранится: при загрузке события получает
+// тот, кто загружает.
+type Record struct {
+	Title        string    `json:"title"`
+	Description  string    `json:"description,omitempty"`
+	Start        time.Time `json:"start"`
+	Duration     string    `json:"duration,omitempty"`
+	NoticeBefore int32     `json:"notice_before,omitempty"`
+	CalendarID   int64     `json:"calendar_id,omitempty"`
+	Attendees    []string  `json:"attendees,omitempty"`
+}
+
+func FromEvent(e storage.Event) Record {
+	r := Record{
+		Title:        e.Title,
+		Description:  e.Description,
+		Start:        e.StartDateTime,
+		Duration:     e.Duration,
+		NoticeBefore: e.NoticeBefore,
+		CalendarID:   e.CalendarID,
+	}
+	for _, a := range e.Attendees {
+		r.Attendees = append(r.Attendees, a.UserID)
+	}
+	return r
+}
+
+// Event - событие без владельца, участники - с needs-action.
+func (r Record) Event() storage.Event {
+	e := storage.Event{
+		Title:         r.Title,
+		Description:   r.Description,
+		StartDateTime: r.Start,
+		Duration:      r.Duration,
+		NoticeBefore:  r.NoticeBefore,
+		CalendarID:    r.CalendarID,
+	}
+	for _, id := range r.Attendees {
+		e.Attendees = append(e.Attendees, storage.Attendee{UserID: id, Status: storage.RSVPNeedsAction})
+	}
+	return e
+}
+
+// Validate проверяет то, без чего событие нельзя сохранить.
+func (r Record) Validate() error {
+	if r.Start.IsZero() {
+		return fmt.Errorf("start is required")
+	}
+	if r.Duration != "" {
+		if _, err := storage.ParseDuration(r.Duration); err != nil {
+			return fmt.Errorf("duration: %w", err)
+		}
+	}
+	if r.NoticeBefore < 0 {
+		return fmt.Errorf("notice_before must not be negative")
+	}
+	return nil
+}
+
+// RowError - ошибка одной строки, после неё чтение продолжается.
+type RowError struct {
+	Line int // номер строки файла, с 1
+	Err  error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
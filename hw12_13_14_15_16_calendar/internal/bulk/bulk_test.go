@@ -0,0 +1,132 @@
+package bulk_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"mycalendar/internal/bulk"
+	"mycalendar/internal/storage"
+)
+
+// readAll возвращает прочитанные строки и номера строк с ошибками.
+func readAll(t *testing.T, dec bulk.Decoder) ([]bulk.Row, []int) {
+	t.Helper()
+	var (
+		rows []bulk.Row
+		bad  []int
+	)
+	for {
+		row, err := dec.Next()
+		if errors.Is(err, io.EOF) {
+			return rows, bad
+		}
+		var rowErr *bulk.RowError
+		if errors.As(err, &rowErr) {
+			bad = append(bad, rowErr.Line)
+			continue
+		}
+		require.NoError(t, err)
+		rows = append(rows, row)
+	}
+}
+
+func TestDecodeCSV(t *testing.T) {
+	in := "\ufeffstart,Title,attendees\n" +
+		"2030-01-02T09:00:00Z,Standup, bob ; carol\n" +
+		"\n" +
+		"yesterday,Bad,\n" +
+		"2030-01-02T10:00:00Z,\"multi\nline\",\n" +
+		"2030-01-02T11:00:00Z,too,many,fields\n" +
+		",no start,\n"
+	rows, bad := readAll(t, bulk.NewDecoder(strings.NewReader(in), bulk.CSV))
+
+	require.Equal(t, []int{4, 7, 8}, bad)
+	require.Len(t, rows, 2)
+	require.Equal(t, 2, rows[0].Line)
+	require.Equal(t, "Standup", rows[0].Record.Title)
+	require.Equal(t, []string{"bob", "carol"}, rows[0].Record.Attendees)
+	require.Equal(t, 5, rows[1].Line)
+	require.Equal(t, "multi\nline", rows[1].Record.Title)
+
+	_, err := bulk.NewDecoder(strings.NewReader("start,color\n"), bulk.CSV).Next()
+	require.ErrorContains(t, err, `unknown column "color"`)
+	_, err = bulk.NewDecoder(strings.NewReader("title\nx\n"), bulk.CSV).Next()
+	require.ErrorContains(t, err, "start is required")
+}
+
+func TestDecodeNDJSON(t *testing.T) {
+	in := `{"title":"a","start":"2030-01-02T09:00:00Z","duration":"1d2h"}
+
+{"title":"b","start":"2030-01-02T09:00:00Z","duration":"soon"}
+{"title":"c","begin":"2030-01-02T09:00:00Z"}
+not json
+{"title":"d","start":"2030-01-02T10:00:00Z","notice_before":1}
+`
+	rows, bad := readAll(t, bulk.NewDecoder(strings.NewReader(in), bulk.NDJSON))
+	require.Equal(t, []int{3, 4, 5}, bad)
+	require.Len(t, rows, 2)
+	require.Equal(t, 1, rows[0].Line)
+	require.Equal(t, 6, rows[1].Line)
+	require.EqualValues(t, 1, rows[1].Record.NoticeBefore)
+}
+
+func TestRoundTrip(t *testing.T) {
+	events := []storage.Event{
+		{
+			Title: "Standup, daily", Description: `says "hi"`, StartDateTime: time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC),
+			Duration: "15m", NoticeBefore: 1, CalendarID: 7,
+			Attendees: []storage.Attendee{{UserID: "bob", Status: storage.RSVPAccepted}},
+		},
+		{Title: "Review", StartDateTime: time.Date(2030, 1, 3, 9, 0, 0, 0, time.UTC), Duration: "1h"},
+	}
+	for _, f := range []bulk.Format{bulk.CSV, bulk.NDJSON} {
+		t.Run(string(f), func(t *testing.T) {
+			var buf bytes.Buffer
+			enc := bulk.NewEncoder(&buf, f)
+			for _, e := range events {
+				require.NoError(t, enc.Encode(e))
+			}
+			require.NoError(t, enc.Flush())
+
+			rows, bad := readAll(t, bulk.NewDecoder(&buf, f))
+			require.Empty(t, bad)
+			require.Len(t, rows, len(events))
+			for i, row := range rows {
+				want := bulk.FromEvent(events[i])
+				require.Equal(t, want.Title, row.Record.Title)
+				require.Equal(t, want.Description, row.Record.Description)
+				require.True(t, want.Start.Equal(row.Record.Start))
+				require.Equal(t, want.Duration, row.Record.Duration)
+				require.Equal(t, want.NoticeBefore, row.Record.NoticeBefore)
+				require.Equal(t, want.CalendarID, row.Record.CalendarID)
+				require.Equal(t, want.Attendees, row.Record.Attendees)
+			}
+		})
+	}
+
+	// пустая выгрузка CSV - только заголовок
+	var buf bytes.Buffer
+	require.NoError(t, bulk.NewEncoder(&buf, bulk.CSV).Flush())
+	require.Equal(t, "title,description,start,duration,notice_before,calendar_id,attendees\n", buf.String())
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]bulk.Format{
+		"csv": bulk.CSV, "text/csv; charset=utf-8": bulk.CSV, "NDJSON": bulk.NDJSON, "application/x-ndjson": bulk.NDJSON,
+	} {
+		f, err := bulk.ParseFormat(in)
+		require.NoError(t, err, in)
+		require.Equal(t, want, f, in)
+	}
+	_, err := bulk.ParseFormat("xml")
+	require.Error(t, err)
+
+	f, err := bulk.FormatOf("/tmp/events.jsonl")
+	require.NoError(t, err)
+	require.Equal(t, bulk.NDJSON, f)
+}
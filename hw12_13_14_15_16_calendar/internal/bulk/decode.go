@@ -0,0 +1,174 @@
+package bulk
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxLine - самая длинная строка JSON Lines.
+const maxLine = 1 << 20
+
+// Row - прочитанное событие и номер его строки.
+type Row struct {
+	Line   int
+	Record Record
+}
+
+// Decoder читает файл построчно. Next возвращает *RowError для строки, которую
+// не удалось разобрать, и io.EOF в конце файла; остальные ошибки - ошибки чтения.
+type Decoder interface {
+	Next() (Row, error)
+}
+
+func NewDecoder(r io.Reader, f Format) Decoder {
+	if f == CSV {
+		return &csvDecoder{r: csv.NewReader(r)}
+	}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), maxLine)
+	return &ndjsonDecoder{sc: sc}
+}
+
+type ndjsonDecoder struct {
+	sc   *bufio.Scanner
+	line int
+}
+
+func (d *ndjsonDecoder) Next() (Row, error) {
+	for d.sc.Scan() {
+		d.line++
+		data := bytes.TrimSpace(d.sc.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		var rec Record
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&rec); err != nil {
+			return Row{}, &RowError{Line: d.line, Err: err}
+		}
+		if err := rec.Validate(); err != nil {
+			return Row{}, &RowError{Line: d.line, Err: err}
+		}
+		return Row{Line: d.line, Record: rec}, nil
+	}
+	if err := d.sc.Err(); err != nil {
+		return Row{}, err
+	}
+	return Row{}, io.EOF
+}
+
+// CSV колонки, первая строка файла - заголовок. Обязательна только start,
+// участники перечисляются через ";".
+var csvColumns = []string{"title", "description", "start", "duration", "notice_before", "calendar_id", "attendees"}
+
+type csvDecoder struct {
+	r       *csv.Reader
+	columns map[string]int
+}
+
+func (d *csvDecoder) Next() (Row, error) {
+	if d.columns == nil {
+		if err := d.readHeader(); err != nil {
+			return Row{}, err
+		}
+	}
+	for {
+		fields, err := d.r.Read()
+		if errors.Is(err, io.EOF) {
+			return Row{}, io.EOF
+		}
+		line, _ := d.r.FieldPos(0)
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return Row{}, &RowError{Line: parseErr.StartLine, Err: parseErr.Err}
+		}
+		if err != nil {
+			return Row{}, err
+		}
+		if len(fields) == 1 && strings.TrimSpace(fields[0]) == "" {
+			continue // пустая строка
+		}
+		rec, err := d.record(fields)
+		if err == nil {
+			err = rec.Validate()
+		}
+		if err != nil {
+			return Row{}, &RowError{Line: line, Err: err}
+		}
+		return Row{Line: line, Record: rec}, nil
+	}
+}
+
+func (d *csvDecoder) readHeader() error {
+	header, err := d.r.Read()
+	if errors.Is(err, io.EOF) {
+		return io.EOF
+	}
+	if err != nil {
+		return fmt.Errorf("cannot read header: %w", err)
+	}
+	d.columns = make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))) // BOM, который пишет Excel
+		known := false
+		for _, c := range csvColumns {
+			known = known || c == name
+		}
+		if !known {
+			return fmt.Errorf("unknown column %q, expected %s", name, strings.Join(csvColumns, ","))
+		}
+		d.columns[name] = i
+	}
+	if _, ok := d.columns["start"]; !ok {
+		return errors.New("column start is required")
+	}
+	return nil
+}
+
+func (d *csvDecoder) record(fields []string) (Record, error) {
+	get := func(name string) string {
+		if i, ok := d.columns[name]; ok && i < len(fields) {
+			return strings.TrimSpace(fields[i])
+		}
+		return ""
+	}
+
+	rec := Record{
+		Title:       get("title"),
+		Description: get("description"),
+		Duration:    get("duration"),
+	}
+	var err error
+	if s := get("start"); s != "" {
+		if rec.Start, err = time.Parse(time.RFC3339, s); err != nil {
+			return rec, fmt.Errorf("start: %w", err)
+		}
+	}
+	if s := get("notice_before"); s != "" {
+		n, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return rec, fmt.Errorf("notice_before: %w", err)
+		}
+		rec.NoticeBefore = int32(n)
+	}
+	if s := get("calendar_id"); s != "" {
+		if rec.CalendarID, err = strconv.ParseInt(s, 10, 64); err != nil {
+			return rec, fmt.Errorf("calendar_id: %w", err)
+		}
+	}
+	for _, id := range strings.Split(get("attendees"), ";") {
+		if id = strings.TrimSpace(id); id != "" {
+			rec.Attendees = append(rec.Attendees, id)
+		}
+	}
+	return rec, nil
+}
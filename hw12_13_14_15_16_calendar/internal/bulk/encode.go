@@ -0,0 +1,77 @@
+package bulk
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"mycalendar/internal/storage"
+)
+
+// Encoder пишет события в формате, который читает Decoder. Записанное
+// буферизуется до Flush.
+type Encoder interface {
+	Encode(e storage.Event) error
+	Flush() error
+}
+
+func NewEncoder(w io.Writer, f Format) Encoder {
+	if f == CSV {
+		return &csvEncoder{w: csv.NewWriter(w)}
+	}
+	bw := bufio.NewWriter(w)
+	return &ndjsonEncoder{w: bw, enc: json.NewEncoder(bw)}
+}
+
+type ndjsonEncoder struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+func (e *ndjsonEncoder) Encode(ev storage.Event) error {
+	return e.enc.Encode(FromEvent(ev))
+}
+
+func (e *ndjsonEncoder) Flush() error {
+	return e.w.Flush()
+}
+
+type csvEncoder struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+func (e *csvEncoder) Encode(ev storage.Event) error {
+	if !e.wroteHeader {
+		if err := e.w.Write(csvColumns); err != nil {
+			return err
+		}
+		e.wroteHeader = true
+	}
+	r := FromEvent(ev)
+	return e.w.Write([]string{
+		r.Title,
+		r.Description,
+		r.Start.Format(time.RFC3339),
+		r.Duration,
+		strconv.Itoa(int(r.NoticeBefore)),
+		strconv.FormatInt(r.CalendarID, 10),
+		strings.Join(r.Attendees, ";"),
+	})
+}
+
+// Flush пишет заголовок и для пустой выгрузки.
+func (e *csvEncoder) Flush() error {
+	if !e.wroteHeader {
+		if err := e.w.Write(csvColumns); err != nil {
+			return err
+		}
+		e.wroteHeader = true
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
@@ -0,0 +1,247 @@
+// Package certs - TLS сертификаты серверов календаря: загрузка из файлов с
+// перечитыванием при изменении и самоподписанный сертификат для разработки.
+package certs
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+type Logger interface {
+	Info(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// Options - откуда брать сертификат. Если ClientCAFile задан, клиенты обязаны
+// предъявить сертификат, подписанный этим CA (mTLS).
+type Options struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+	// SelfSigned - сгенерировать самоподписанный сертификат для Hosts вместо файлов.
+	SelfSigned bool
+	Hosts      []string
+}
+
+// Enabled - нужен ли TLS при таких настройках.
+func (o Options) Enabled() bool {
+	return o.SelfSigned || o.CertFile != ""
+}
+
+// Manager хранит текущие сертификат и пул CA клиентов и отдаёт их при каждом
+// рукопожатии, так что новые файлы начинают действовать без перезапуска.
+type Manager struct {
+	opts   Options
+	logger Logger
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	clientCA *x509.CertPool
+	modTime  time.Time
+}
+
+func New(opts Options, logger Logger) (*Manager, error) {
+	if !opts.Enabled() {
+		return nil, errors.New("certs: neither certificate files nor self-signed mode configured")
+	}
+	m := &Manager{opts: opts, logger: logger}
+
+	if opts.SelfSigned {
+		cert, err := SelfSigned(opts.Hosts, 365*24*time.Hour)
+		if err != nil {
+			return nil, err
+		}
+		m.cert = &cert
+		if opts.ClientCAFile != "" {
+			if m.clientCA, err = loadCertPool(opts.ClientCAFile); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	}
+
+	if err := m.Reload(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TLSConfig возвращает конфиг для http.Server и grpc credentials.
+func (m *Manager) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			m.mu.RLock()
+			defer m.mu.RUnlock()
+
+			cfg := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*m.cert},
+				NextProtos:   []string{"h2", "http/1.1"},
+			}
+			if m.clientCA != nil {
+				cfg.ClientCAs = m.clientCA
+				cfg.ClientAuth = tls.RequireAndVerifyClientCert
+			}
+			return cfg, nil
+		},
+	}
+}
+
+// Leaf возвращает текущий сертификат сервера.
+func (m *Manager) Leaf() *x509.Certificate {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.cert.Leaf != nil {
+		return m.cert.Leaf
+	}
+	leaf, _ := x509.ParseCertificate(m.cert.Certificate[0])
+	return leaf
+}
+
+// Reload перечитывает сертификат, ключ и CA клиентов. При ошибке продолжают
+// действовать прежние.
+func (m *Manager) Reload() error {
+	if m.opts.SelfSigned {
+		return nil
+	}
+	cert, err := tls.LoadX509KeyPair(m.opts.CertFile, m.opts.KeyFile)
+	if err != nil {
+		return fmt.Errorf("load certificate: %w", err)
+	}
+	var pool *x509.CertPool
+	if m.opts.ClientCAFile != "" {
+		if pool, err = loadCertPool(m.opts.ClientCAFile); err != nil {
+			return err
+		}
+	}
+	modTime, err := m.latestModTime()
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.cert = &cert
+	m.clientCA = pool
+	m.modTime = modTime
+	m.mu.Unlock()
+	return nil
+}
+
+// Run проверяет файлы раз в interval и перечитывает их, если они изменились.
+func (m *Manager) Run(ctx context.Context, interval time.Duration) {
+	if m.opts.SelfSigned || interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			modTime, err := m.latestModTime()
+			if err != nil {
+				m.logger.Error("cannot stat certificate files", "err", err)
+				continue
+			}
+			m.mu.RLock()
+			changed := modTime.After(m.modTime)
+			m.mu.RUnlock()
+			if !changed {
+				continue
+			}
+			if err := m.Reload(); err != nil {
+				m.logger.Error("certificate reload failed", "err", err)
+				continue
+			}
+			m.logger.Info("certificate reloaded", "cert", m.opts.CertFile)
+		}
+	}
+}
+
+func (m *Manager) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, path := range []string{m.opts.CertFile, m.opts.KeyFile, m.opts.ClientCAFile} {
+		if path == "" {
+			continue
+		}
+		st, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if st.ModTime().After(latest) {
+			latest = st.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+func loadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read client CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("client CA %s: no certificates found", path)
+	}
+	return pool, nil
+}
+
+// SelfSigned генерирует самоподписанный сертификат для hosts (DNS имена или IP).
+// Подходит только для разработки: клиенты должны отключать проверку или доверять ему явно.
+func SelfSigned(hosts []string, ttl time.Duration) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"mycalendar dev"}},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(ttl),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			if !ip.IsUnspecified() {
+				tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+			}
+		} else if h != "" {
+			tmpl.DNSNames = append(tmpl.DNSNames, h)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, nil
+}
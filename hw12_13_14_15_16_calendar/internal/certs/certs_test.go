@@ -0,0 +1,177 @@
+package certs
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testCA - локальный CA, которым подписываются сертификаты сервера и клиента.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue выпускает сертификат и возвращает его и ключ в PEM.
+func (ca *testCA) issue(t *testing.T, cn string, usage x509.ExtKeyUsage) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func startServer(t *testing.T, m *Manager) string {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	srv.TLS = m.TLSConfig()
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func client(ca *testCA, certs ...tls.Certificate) *http.Client {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		RootCAs:      pool,
+		Certificates: certs,
+		MinVersion:   tls.VersionTLS12,
+	}}}
+}
+
+func get(c *http.Client, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+func TestMutualTLSAndReload(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	certPEM, keyPEM := ca.issue(t, "server-1", x509.ExtKeyUsageServerAuth)
+	opts := Options{
+		CertFile:     writeFile(t, dir, "server.crt", certPEM),
+		KeyFile:      writeFile(t, dir, "server.key", keyPEM),
+		ClientCAFile: writeFile(t, dir, "ca.crt", ca.pem),
+	}
+	m, err := New(opts, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	url := startServer(t, m)
+
+	// без клиентского сертификата соединение не устанавливается
+	_, err = get(client(ca), url)
+	require.Error(t, err)
+
+	clientPEM, clientKey := ca.issue(t, "client", x509.ExtKeyUsageClientAuth)
+	clientCert, err := tls.X509KeyPair(clientPEM, clientKey)
+	require.NoError(t, err)
+
+	c := client(ca, clientCert)
+	resp, err := get(c, url)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, "server-1", resp.TLS.PeerCertificates[0].Subject.CommonName)
+
+	// новый сертификат подхватывается без перезапуска сервера
+	certPEM, keyPEM = ca.issue(t, "server-2", x509.ExtKeyUsageServerAuth)
+	writeFile(t, dir, "server.crt", certPEM)
+	writeFile(t, dir, "server.key", keyPEM)
+	require.NoError(t, m.Reload())
+
+	c.CloseIdleConnections()
+	resp, err = get(c, url)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, "server-2", resp.TLS.PeerCertificates[0].Subject.CommonName)
+
+	// битые файлы не заменяют рабочий сертификат
+	writeFile(t, dir, "server.key", []byte("garbage"))
+	require.Error(t, m.Reload())
+	c.CloseIdleConnections()
+	resp, err = get(c, url)
+	require.NoError(t, err)
+	resp.Body.Close()
+}
+
+func TestSelfSigned(t *testing.T) {
+	m, err := New(Options{SelfSigned: true, Hosts: []string{"localhost", "127.0.0.1"}}, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	url := startServer(t, m)
+
+	// клиент, доверяющий этому сертификату, проверяет его как обычно
+	pool := x509.NewCertPool()
+	pool.AddCert(m.Leaf())
+	c := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12},
+	}}
+	resp, err := get(c, url)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	_, err = New(Options{}, slog.New(slog.DiscardHandler))
+	require.Error(t, err)
+}
@@ -0,0 +1,254 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix - префикс переменных окружения, переопределяющих конфиг.
+const EnvPrefix = "CALENDAR_"
+
+// Overrides - значения из флагов командной строки, ключ вида "http.port".
+type Overrides map[string]string
+
+// RegisterFlags регистрирует флаг -<ключ> для каждого поля конфига, например -http.port.
+func (o Overrides) RegisterFlags(fs *flag.FlagSet) {
+	for _, f := range fields(reflect.ValueOf(Default()).Elem()) {
+		key := f.key
+		fs.Func(key, fmt.Sprintf("override %s (env %s)", key, f.env), func(v string) error {
+			o[key] = v
+			return nil
+		})
+	}
+}
+
+// Load читает конфиг из файла (TOML или YAML по расширению) поверх значений по умолчанию,
+// затем применяет переменные окружения CALENDAR_* и флаги.
+// Пустой path означает "только значения по умолчанию, окружение и флаги".
+func Load(path string, overrides Overrides) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		fromFile, keys, err := readFile(path)
+		if err != nil {
+			return nil, err
+		}
+		merge(reflect.ValueOf(cfg).Elem(), reflect.ValueOf(fromFile).Elem(), keys)
+	}
+
+	byKey := make(map[string]field)
+	for _, f := range fields(reflect.ValueOf(cfg).Elem()) {
+		byKey[f.key] = f
+		if v, ok := os.LookupEnv(f.env); ok && v != "" {
+			if err := setValue(f.value, v); err != nil {
+				return nil, fmt.Errorf("env %s: %w", f.env, err)
+			}
+		}
+	}
+
+	for key, v := range overrides {
+		f, ok := byKey[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown config key %q", key)
+		}
+		if err := setValue(f.value, v); err != nil {
+			return nil, fmt.Errorf("flag -%s: %w", key, err)
+		}
+	}
+
+	return cfg, nil
+}
+
+// unsetEnv заменяет незаданную переменную окружения при поиске ключей файла:
+// значение "${VAR}" без VAR считается отсутствующим.
+const unsetEnv = "__calendar_unset_env__"
+
+// readFile возвращает конфиг из файла и дерево ключей, которые в файле заданы.
+func readFile(path string) (*Config, map[string]any, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read config: %w", err)
+	}
+
+	// Подставим переменные окружения в виде ${VAR}
+	expanded := os.ExpandEnv(string(raw))
+	marked := os.Expand(string(raw), func(name string) string {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+		return unsetEnv
+	})
+
+	var cfg Config
+	var keys map[string]any
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal([]byte(expanded), &cfg); err != nil {
+			return nil, nil, fmt.Errorf("decode yaml: %w", err)
+		}
+		if err := yaml.Unmarshal([]byte(marked), &keys); err != nil {
+			return nil, nil, fmt.Errorf("decode yaml: %w", err)
+		}
+	default:
+		if _, err := toml.Decode(expanded, &cfg); err != nil {
+			return nil, nil, fmt.Errorf("decode toml: %w", err)
+		}
+		if _, err := toml.Decode(marked, &keys); err != nil {
+			return nil, nil, fmt.Errorf("decode toml: %w", err)
+		}
+	}
+	return &cfg, keys, nil
+}
+
+// field - одно конечное поле конфига.
+type field struct {
+	key    string // http.port
+	env    string // CALENDAR_HTTP_PORT
+	secret bool
+	value  reflect.Value
+}
+
+// fields обходит структуру конфига и возвращает все конечные поля.
+func fields(v reflect.Value) []field {
+	return walk(v, "")
+}
+
+func walk(v reflect.Value, prefix string) []field {
+	var res []field
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name := sf.Tag.Get("toml")
+		if name == "" {
+			name = strings.ToLower(sf.Name)
+		}
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Struct {
+			res = append(res, walk(fv, key)...)
+			continue
+		}
+		res = append(res, field{
+			key:    key,
+			env:    envName(key),
+			secret: sf.Tag.Get("secret") == "true",
+			value:  fv,
+		})
+	}
+	return res
+}
+
+// envName превращает "scheduler.intervalSeconds" в "CALENDAR_SCHEDULER_INTERVAL_SECONDS",
+// а "http.tls.clientCAFile" - в "CALENDAR_HTTP_TLS_CLIENT_CA_FILE".
+func envName(key string) string {
+	var b strings.Builder
+	b.WriteString(EnvPrefix)
+	runes := []rune(key)
+	for i, r := range runes {
+		if r == '.' {
+			b.WriteRune('_')
+			continue
+		}
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteRune('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+// merge копирует в dst поля src, заданные в файле, в том числе нулевые:
+// "enabled = false" и "rps = 0" заменяют значения по умолчанию.
+func merge(dst, src reflect.Value, keys map[string]any) {
+	t := src.Type()
+	for i := 0; i < src.NumField(); i++ {
+		name := t.Field(i).Tag.Get("toml")
+		if name == "" {
+			name = strings.ToLower(t.Field(i).Name)
+		}
+		v, ok := keys[name]
+		if !ok || v == unsetEnv {
+			continue
+		}
+		sf, df := src.Field(i), dst.Field(i)
+		if sf.Kind() == reflect.Struct {
+			if sub, ok := v.(map[string]any); ok {
+				merge(df, sf, sub)
+			}
+			continue
+		}
+		df.Set(sf)
+	}
+}
+
+func setValue(v reflect.Value, s string) error {
+	switch v.Kind() { //nolint:exhaustive // в конфиге только эти типы
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		v.SetInt(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", s)
+		}
+		v.SetBool(b)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", s)
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported config type %s", v.Type())
+		}
+		var items []string
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String || v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported config type %s", v.Type())
+		}
+		items := make(map[string]string)
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			k, val, ok := strings.Cut(item, "=")
+			if !ok {
+				return fmt.Errorf("invalid pair %q, want key=value", item)
+			}
+			items[strings.TrimSpace(k)] = strings.TrimSpace(val)
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported config type %s", v.Kind())
+	}
+	return nil
+}
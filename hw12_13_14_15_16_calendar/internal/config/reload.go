@@ -0,0 +1,162 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+)
+
+type Logger interface {
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// Reloader перечитывает файл конфигурации по SIGHUP (и, при желании, по изменению файла)
+// и применяет на лету те поля, для которых зарегистрирован обработчик.
+// Про остальные изменённые поля пишет в лог, что они требуют перезапуска.
+type Reloader struct {
+	path      string
+	overrides Overrides
+	logger    Logger
+
+	mu       sync.Mutex
+	current  *Config
+	handlers map[string]func(*Config)
+	modTime  time.Time
+}
+
+func NewReloader(path string, overrides Overrides, current *Config, logger Logger) *Reloader {
+	r := &Reloader{
+		path:      path,
+		overrides: overrides,
+		logger:    logger,
+		current:   current,
+		handlers:  make(map[string]func(*Config)),
+	}
+	if st, err := os.Stat(path); err == nil {
+		r.modTime = st.ModTime()
+	}
+	return r
+}
+
+// OnChange регистрирует обработчик, который применяет новое значение поля key (например "logger.level").
+func (r *Reloader) OnChange(key string, apply func(*Config)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[key] = apply
+}
+
+// Current возвращает действующую конфигурацию.
+func (r *Reloader) Current() *Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current
+}
+
+// Reload перечитывает и проверяет конфиг, применяет безопасные изменения и
+// возвращает применённые ключи и ключи, которые требуют перезапуска.
+// При ошибке действующая конфигурация не меняется. Обработчики вызываются
+// без блокировки: они могут читать Current и ждать другие горутины.
+func (r *Reloader) Reload() (applied, restart []string, err error) {
+	next, err := Load(r.path, r.overrides)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := next.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	r.mu.Lock()
+	var apply []func(*Config)
+	for _, key := range Diff(r.current, next) {
+		h, ok := r.handlers[key]
+		if !ok {
+			restart = append(restart, key)
+			continue
+		}
+		apply = append(apply, h)
+		applied = append(applied, key)
+	}
+	r.current = next
+	r.mu.Unlock()
+
+	for _, h := range apply {
+		h(next)
+	}
+	return applied, restart, nil
+}
+
+// Run ждёт SIGHUP до отмены контекста. Если watch > 0, дополнительно
+// с этим интервалом проверяет время изменения файла конфигурации.
+func (r *Reloader) Run(ctx context.Context, watch time.Duration) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var tick <-chan time.Time
+	if watch > 0 {
+		ticker := time.NewTicker(watch)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			r.logger.Info("SIGHUP received, reloading config", "path", r.path)
+			r.reloadAndReport()
+		case <-tick:
+			if r.fileChanged() {
+				r.logger.Info("config file changed, reloading", "path", r.path)
+				r.reloadAndReport()
+			}
+		}
+	}
+}
+
+func (r *Reloader) reloadAndReport() {
+	applied, restart, err := r.Reload()
+	if err != nil {
+		r.logger.Error("config reload failed, keeping current config", "err", err)
+		return
+	}
+	r.logger.Info("config reloaded", "applied", applied)
+	if len(restart) > 0 {
+		r.logger.Warn("config changes require restart", "fields", restart)
+	}
+}
+
+func (r *Reloader) fileChanged() bool {
+	st, err := os.Stat(r.path)
+	if err != nil {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if st.ModTime().Equal(r.modTime) {
+		return false
+	}
+	r.modTime = st.ModTime()
+	return true
+}
+
+// Diff возвращает ключи полей, значения которых различаются в a и b.
+func Diff(a, b *Config) []string {
+	fa := fields(reflect.ValueOf(a).Elem())
+	fb := fields(reflect.ValueOf(b).Elem())
+
+	var changed []string
+	for i := range fa {
+		if !reflect.DeepEqual(fa[i].value.Interface(), fb[i].value.Interface()) { // Equal не сравнивает срезы
+			changed = append(changed, fa[i].key)
+		}
+	}
+	return changed
+}
@@ -0,0 +1,203 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"mycalendar/internal/holidays"
+	"mycalendar/internal/tenant"
+)
+
+// Validate проверяет конфиг и возвращает сразу все найденные проблемы.
+func (c *Config) Validate() error {
+	var errs []error
+	add := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	switch strings.ToLower(c.Logger.Level) {
+	case "debug", "info", "warn", "warning", "error":
+	default:
+		add("logger.level: unknown level %q", c.Logger.Level)
+	}
+	switch strings.ToLower(c.Logger.Format) {
+	case "json", "text":
+	default:
+		add("logger.format: must be json or text, got %q", c.Logger.Format)
+	}
+	if c.Logger.MaxSizeMB < 0 || c.Logger.MaxAgeDays < 0 || c.Logger.MaxBackups < 0 {
+		add("logger: rotation limits must not be negative")
+	}
+
+	switch c.Storage.Type {
+	case "memory":
+	case "sql":
+		if c.PSQL.DSN == "" {
+			add("psql.dsn: required for sql storage")
+		}
+	default:
+		add("storage.type: must be memory or sql, got %q", c.Storage.Type)
+	}
+
+	if c.PSQL.MaxOpenConns < 0 || c.PSQL.MaxIdleConns < 0 {
+		add("psql: pool limits must not be negative")
+	}
+	if c.PSQL.MaxOpenConns > 0 && c.PSQL.MaxIdleConns > c.PSQL.MaxOpenConns {
+		add("psql.maxIdleConns: must not exceed maxOpenConns %d, got %d", c.PSQL.MaxOpenConns, c.PSQL.MaxIdleConns)
+	}
+	if c.PSQL.ConnMaxLifetimeSeconds < 0 || c.PSQL.ConnMaxIdleTimeSeconds < 0 || c.PSQL.QueryTimeoutSeconds < 0 ||
+		c.PSQL.Retries < 0 || c.PSQL.RetryBackoffMs < 0 || c.PSQL.ConnectTimeoutSeconds < 0 {
+		add("psql: timeouts and retries must not be negative")
+	}
+
+	if c.Cache.Size < 0 {
+		add("cache.size: must not be negative, got %d", c.Cache.Size)
+	}
+	if c.Cache.TTLSeconds < 0 {
+		add("cache.ttlSeconds: must not be negative, got %d", c.Cache.TTLSeconds)
+	}
+
+	if err := validatePort(c.HTTP.Port); err != nil {
+		add("http.port: %w", err)
+	}
+	if err := validatePort(c.GRPC.Port); err != nil {
+		add("grpc.port: %w", err)
+	}
+	if err := c.HTTP.TLS.validate(); err != nil {
+		add("http.tls: %w", err)
+	}
+	if err := c.GRPC.TLS.validate(); err != nil {
+		add("grpc.tls: %w", err)
+	}
+
+	if c.Queue.URL == "" {
+		add("queue.url: required")
+	}
+	if c.Queue.Name == "" {
+		add("queue.name: required")
+	}
+
+	if c.Scheduler.IntervalSeconds <= 0 {
+		add("scheduler.intervalSeconds: must be positive, got %d", c.Scheduler.IntervalSeconds)
+	}
+	if c.Scheduler.CleanupOlderThanDays < 0 {
+		add("scheduler.cleanupOlderThanDays: must not be negative, got %d", c.Scheduler.CleanupOlderThanDays)
+	}
+
+	switch strings.ToLower(c.Sender.LogLevel) {
+	case "", "debug", "info", "warn", "warning", "error":
+	default:
+		add("sender.logLevel: unknown level %q", c.Sender.LogLevel)
+	}
+	switch c.Sender.Channel {
+	case "log", "stdout":
+	default:
+		add("sender.channel: must be log or stdout, got %q", c.Sender.Channel)
+	}
+	if c.Sender.Prefetch <= 0 {
+		add("sender.prefetch: must be positive, got %d", c.Sender.Prefetch)
+	}
+
+	if c.Reload.WatchSeconds < 0 {
+		add("reload.watchSeconds: must not be negative, got %d", c.Reload.WatchSeconds)
+	}
+
+	if c.Limits.RPS < 0 {
+		add("limits.rps: must not be negative, got %v", c.Limits.RPS)
+	}
+	if c.Limits.RPS > 0 && c.Limits.Burst < 1 {
+		add("limits.burst: must be positive when rps is set, got %d", c.Limits.Burst)
+	}
+	if c.Limits.MaxBodyBytes <= 0 {
+		add("limits.maxBodyBytes: must be positive, got %d", c.Limits.MaxBodyBytes)
+	}
+	if c.Limits.MaxImportBytes <= 0 {
+		add("limits.maxImportBytes: must be positive, got %d", c.Limits.MaxImportBytes)
+	}
+	if c.Shutdown.TimeoutSeconds <= 0 {
+		add("shutdown.timeoutSeconds: must be positive, got %d", c.Shutdown.TimeoutSeconds)
+	}
+
+	if w := c.Webhooks; w.Enabled {
+		if w.IntervalSeconds <= 0 || w.TimeoutSeconds <= 0 || w.MaxAttempts <= 0 || w.Workers <= 0 {
+			add("webhooks: intervalSeconds, timeoutSeconds, maxAttempts and workers must be positive")
+		}
+		if w.BackoffSeconds <= 0 || w.MaxBackoffSeconds < w.BackoffSeconds {
+			add("webhooks.backoffSeconds: must be positive and not exceed maxBackoffSeconds %d, got %d",
+				w.MaxBackoffSeconds, w.BackoffSeconds)
+		}
+		if w.DisableAfter < 0 {
+			add("webhooks.disableAfter: must not be negative, got %d", w.DisableAfter)
+		}
+		if w.RetentionDays <= 0 {
+			add("webhooks.retentionDays: must be positive, got %d", w.RetentionDays)
+		}
+	}
+
+	if len(c.Tenants.Names) == 0 {
+		add("tenants.names: at least one tenant is required")
+	}
+	seen := make(map[string]bool, len(c.Tenants.Names))
+	for _, name := range c.Tenants.Names {
+		switch {
+		case !tenant.Valid(name):
+			add("tenants.names: invalid tenant %q, use a-z, 0-9, '-' and '_'", name)
+		case seen[name]:
+			add("tenants.names: duplicate tenant %q", name)
+		}
+		seen[name] = true
+	}
+	for cn, name := range c.Tenants.Principals {
+		if !seen[name] {
+			add("tenants.principals: client %q is mapped to unknown tenant %q", cn, name)
+		}
+	}
+	if c.Tenants.RemindersPerRun < 0 {
+		add("tenants.remindersPerRun: must not be negative, got %d", c.Tenants.RemindersPerRun)
+	}
+	if c.Tenants.SendRPS < 0 {
+		add("tenants.sendRPS: must not be negative, got %v", c.Tenants.SendRPS)
+	}
+
+	if c.Attachments.MaxBytes <= 0 {
+		add("attachments.maxBytes: must be positive, got %d", c.Attachments.MaxBytes)
+	}
+	for _, t := range c.Attachments.Types {
+		if major, minor, ok := strings.Cut(t, "/"); !ok || major == "" || major == "*" || minor == "" {
+			add("attachments.types: invalid MIME type %q, use type/subtype or type/*", t)
+		}
+	}
+
+	for _, country := range c.Holidays.Countries {
+		if !slices.Contains(holidays.Builtin(), strings.ToUpper(country)) {
+			add("holidays.countries: no built-in holidays for %q, use one of %v or an ICS file", country, holidays.Builtin())
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (t TLSConfig) validate() error {
+	switch {
+	case t.SelfSigned && (t.CertFile != "" || t.KeyFile != ""):
+		return errors.New("selfSigned cannot be combined with certFile/keyFile")
+	case (t.CertFile == "") != (t.KeyFile == ""):
+		return errors.New("certFile and keyFile must be set together")
+	case t.ClientCAFile != "" && t.CertFile == "" && !t.SelfSigned:
+		return errors.New("clientCAFile requires a server certificate")
+	case t.WatchSeconds < 0:
+		return fmt.Errorf("watchSeconds must not be negative, got %d", t.WatchSeconds)
+	}
+	return nil
+}
+
+func validatePort(p string) error {
+	n, err := strconv.Atoi(p)
+	if err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("invalid port %q", p)
+	}
+	return nil
+}
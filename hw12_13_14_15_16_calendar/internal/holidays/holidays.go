@@ -0,0 +1,249 @@
+// Package holidays - выходные и праздники по странам: встроенные правила
+// и праздничные календари из ICS файлов.
+package holidays
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"mycalendar/internal/ics"
+)
+
+var ErrUnknownCountry = errors.New("unknown holiday calendar")
+
+// maxSearchDays - сколько дней вперёд искать рабочий день, чтобы календарь
+// из одних праздников не зациклил поиск.
+const maxSearchDays = 366
+
+// Day - день с точки зрения рабочего календаря.
+type Day struct {
+	Date    time.Time // полночь в зоне запроса
+	Weekend bool
+	Holiday string // название праздника, пусто - не праздник
+}
+
+// Working - рабочий день: не выходной и не праздник.
+func (d Day) Working() bool {
+	return !d.Weekend && d.Holiday == ""
+}
+
+// Calendar - выходные и праздники одной страны. Безопасен для параллельного
+// чтения, праздники из ICS добавляются при загрузке.
+type Calendar struct {
+	country string
+	rules   []rule
+
+	mu    sync.RWMutex
+	dates map[civil]string // из ICS файлов
+}
+
+// civil - день без времени и зоны.
+type civil struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+func civilOf(t time.Time) civil {
+	return civil{t.Year(), t.Month(), t.Day()}
+}
+
+func (c *Calendar) Country() string {
+	return c.country
+}
+
+// Day возвращает день, в который попадает t, в зоне t.
+func (c *Calendar) Day(t time.Time) Day {
+	d := civilOf(t)
+	wd := t.Weekday()
+	return Day{
+		Date:    time.Date(d.year, d.month, d.day, 0, 0, 0, 0, t.Location()),
+		Weekend: wd == time.Saturday || wd == time.Sunday,
+		Holiday: c.holiday(d),
+	}
+}
+
+// Days возвращает дни с дня from по день to включительно в зоне from.
+func (c *Calendar) Days(from, to time.Time) []Day {
+	day := c.Day(from).Date
+	last := c.Day(to.In(from.Location())).Date
+	var res []Day
+	for !day.After(last) {
+		res = append(res, c.Day(day))
+		day = day.AddDate(0, 0, 1)
+	}
+	return res
+}
+
+// IsWorkingDay - день, в который попадает t, рабочий.
+func (c *Calendar) IsWorkingDay(t time.Time) bool {
+	return c.Day(t).Working()
+}
+
+// NextWorkingDay возвращает полночь первого рабочего дня после дня t в зоне t.
+func (c *Calendar) NextWorkingDay(t time.Time) (time.Time, error) {
+	day := c.Day(t).Date
+	for range maxSearchDays {
+		day = day.AddDate(0, 0, 1)
+		if c.IsWorkingDay(day) {
+			return day, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%s: no working day within %d days after %s", c.country, maxSearchDays, t.Format(time.DateOnly))
+}
+
+// AddICS добавляет праздники из ICS: каждый день, который занимает событие.
+// Событие без длительности занимает один день.
+func (c *Calendar) AddICS(r io.Reader) error {
+	events, err := ics.Decode(r)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, e := range events {
+		name := e.Summary
+		if name == "" {
+			name = "Holiday"
+		}
+		day := time.Date(e.Start.Year(), e.Start.Month(), e.Start.Day(), 0, 0, 0, 0, time.UTC)
+		end := e.Start.Add(e.Duration)
+		for {
+			c.dates[civilOf(day)] = name
+			day = day.AddDate(0, 0, 1)
+			// DTEND у событий на целые дни не включается
+			if !day.Before(end) {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Calendar) holiday(d civil) string {
+	c.mu.RLock()
+	name, ok := c.dates[d]
+	c.mu.RUnlock()
+	if ok {
+		return name
+	}
+	// перенос праздника может попасть на прошлый год: 1 января в субботу -> 31 декабря
+	for _, year := range []int{d.year, d.year + 1} {
+		for _, r := range c.rules {
+			for _, hd := range r.dates(year) {
+				if hd == d {
+					return r.name
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// Registry - праздничные календари по кодам стран. Календарь с пустым кодом
+// есть всегда: только выходные, без праздников.
+type Registry struct {
+	calendars map[string]*Calendar
+}
+
+func NewRegistry() *Registry {
+	return &Registry{calendars: map[string]*Calendar{"": newCalendar("", nil)}}
+}
+
+func newCalendar(country string, rules []rule) *Calendar {
+	return &Calendar{country: country, rules: rules, dates: make(map[civil]string)}
+}
+
+// Load собирает календари встроенных стран и добавляет к ним праздники из ICS
+// файлов. Код страны файла - его имя без расширения: ru.ics -> RU; страна
+// из файла может и не быть встроенной.
+func Load(countries, files []string) (*Registry, error) {
+	r := NewRegistry()
+	for _, c := range countries {
+		if err := r.AddBuiltin(c); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range files {
+		if err := r.loadFile(name); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (r *Registry) loadFile(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	country := FileCountry(name)
+	if err := r.AddICS(country, f); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// FileCountry - код страны ICS файла: имя без расширения в верхнем регистре.
+func FileCountry(name string) string {
+	base := filepath.Base(name)
+	return strings.ToUpper(strings.TrimSuffix(base, filepath.Ext(base)))
+}
+
+// AddBuiltin добавляет календарь страны из встроенных правил, см. Builtin.
+func (r *Registry) AddBuiltin(country string) error {
+	country = strings.ToUpper(country)
+	rules, ok := builtin[country]
+	if !ok {
+		return fmt.Errorf("%w: no built-in rules for %q", ErrUnknownCountry, country)
+	}
+	if c, ok := r.calendars[country]; ok {
+		c.rules = rules
+		return nil
+	}
+	r.calendars[country] = newCalendar(country, rules)
+	return nil
+}
+
+// AddICS добавляет праздники страны из ICS, создавая её календарь при необходимости.
+func (r *Registry) AddICS(country string, rd io.Reader) error {
+	country = strings.ToUpper(country)
+	c, ok := r.calendars[country]
+	if !ok {
+		c = newCalendar(country, nil)
+	}
+	if err := c.AddICS(rd); err != nil {
+		return err
+	}
+	r.calendars[country] = c
+	return nil
+}
+
+// Get возвращает календарь страны, пустой код - календарь только с выходными.
+func (r *Registry) Get(country string) (*Calendar, error) {
+	c, ok := r.calendars[strings.ToUpper(country)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownCountry, country)
+	}
+	return c, nil
+}
+
+// Countries возвращает коды загруженных стран по алфавиту.
+func (r *Registry) Countries() []string {
+	res := make([]string, 0, len(r.calendars))
+	for code := range r.calendars {
+		if code != "" {
+			res = append(res, code)
+		}
+	}
+	slices.Sort(res)
+	return res
+}
@@ -0,0 +1,102 @@
+package holidays
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestBuiltin(t *testing.T) {
+	r := NewRegistry()
+	require.NoError(t, r.AddBuiltin("us"))
+	require.NoError(t, r.AddBuiltin("RU"))
+	require.ErrorIs(t, r.AddBuiltin("XX"), ErrUnknownCountry)
+	require.Equal(t, []string{"RU", "US"}, r.Countries())
+	require.Equal(t, []string{"RU", "US"}, Builtin())
+
+	us, err := r.Get("US")
+	require.NoError(t, err)
+	require.Equal(t, "Thanksgiving Day", us.Day(date(2025, 11, 27)).Holiday)
+	require.Equal(t, "Memorial Day", us.Day(date(2025, 5, 26)).Holiday)
+	// 4 июля 2026 - суббота, выходной переносится на пятницу
+	require.Equal(t, "Independence Day", us.Day(date(2026, 7, 3)).Holiday)
+	// 1 января 2022 - суббота, выходной 31 декабря прошлого года
+	require.Equal(t, "New Year's Day", us.Day(date(2021, 12, 31)).Holiday)
+	require.Empty(t, us.Day(date(2020, 6, 19)).Holiday) // Juneteenth с 2021
+	require.True(t, us.IsWorkingDay(date(2025, 11, 26)))
+
+	ru, err := r.Get("ru")
+	require.NoError(t, err)
+	require.Equal(t, "День России", ru.Day(date(2025, 6, 12)).Holiday)
+	// 4 ноября 2023 - суббота, выходной переносится на понедельник
+	require.Equal(t, "День народного единства", ru.Day(date(2023, 11, 6)).Holiday)
+	require.False(t, ru.IsWorkingDay(date(2026, 1, 5)))
+
+	_, err = r.Get("DE")
+	require.ErrorIs(t, err, ErrUnknownCountry)
+}
+
+func TestCalendar_Days(t *testing.T) {
+	r := NewRegistry()
+	require.NoError(t, r.AddBuiltin("RU"))
+	ru, err := r.Get("RU")
+	require.NoError(t, err)
+
+	msk := time.FixedZone("MSK", 3*60*60)
+	// 23:30 UTC 11 июня - уже 12 июня по Москве
+	days := ru.Days(time.Date(2025, 6, 11, 23, 30, 0, 0, time.UTC).In(msk), time.Date(2025, 6, 16, 0, 0, 0, 0, msk))
+	require.Len(t, days, 5)
+	require.Equal(t, time.Date(2025, 6, 12, 0, 0, 0, 0, msk), days[0].Date)
+	require.Equal(t, "День России", days[0].Holiday)
+	require.True(t, days[1].Working())
+	require.True(t, days[2].Weekend)
+	require.True(t, days[4].Working())
+
+	next, err := ru.NextWorkingDay(time.Date(2025, 12, 31, 15, 0, 0, 0, msk))
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2026, 1, 9, 0, 0, 0, 0, msk), next)
+
+	// без страны - только выходные
+	plain, err := r.Get("")
+	require.NoError(t, err)
+	require.True(t, plain.IsWorkingDay(date(2025, 6, 12)))
+	require.False(t, plain.IsWorkingDay(date(2025, 6, 14)))
+}
+
+func TestLoad_ICS(t *testing.T) {
+	dir := t.TempDir()
+	ics := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20260504\r\nDTEND;VALUE=DATE:20260506\r\nSUMMARY:Перенос выходных\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20260801\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+	ru := filepath.Join(dir, "ru.ics")
+	require.NoError(t, os.WriteFile(ru, []byte(ics), 0o600))
+	nl := filepath.Join(dir, "nl.ics")
+	require.NoError(t, os.WriteFile(nl, []byte(ics), 0o600))
+
+	r, err := Load([]string{"RU"}, []string{ru, nl})
+	require.NoError(t, err)
+	require.Equal(t, []string{"NL", "RU"}, r.Countries())
+
+	c, err := r.Get("RU")
+	require.NoError(t, err)
+	require.Equal(t, "Перенос выходных", c.Day(date(2026, 5, 4)).Holiday)
+	require.Equal(t, "Перенос выходных", c.Day(date(2026, 5, 5)).Holiday)
+	require.True(t, c.IsWorkingDay(date(2026, 5, 6)))
+	require.Equal(t, "День Победы", c.Day(date(2026, 5, 9)).Holiday) // встроенные правила остались
+	require.Equal(t, "Holiday", c.Day(date(2026, 8, 1)).Holiday)
+
+	_, err = Load(nil, []string{filepath.Join(dir, "missing.ics")})
+	require.Error(t, err)
+	require.NoError(t, os.WriteFile(ru, []byte(strings.Replace(ics, "20260504", "2026", 1)), 0o600))
+	_, err = Load(nil, []string{ru})
+	require.Error(t, err)
+}
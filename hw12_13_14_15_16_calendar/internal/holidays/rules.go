@@ -0,0 +1,128 @@
+package holidays
+
+import (
+	"slices"
+	"time"
+)
+
+// rule - ежегодный праздник: фиксированная дата или n-й день недели месяца.
+type rule struct {
+	name    string
+	month   time.Month
+	day     int          // фиксированная дата, 0 - по дню недели
+	weekday time.Weekday // с nth
+	nth     int          // 1..4, -1 - последний в месяце
+	since   int          // первый год праздника, 0 - всегда
+	// observe переносит праздник, выпавший на выходной, nil - без переноса.
+	observe func(civil, time.Weekday) (civil, bool)
+}
+
+// dates возвращает день праздника в году и день переноса, если он есть.
+func (r rule) dates(year int) []civil {
+	if year < r.since {
+		return nil
+	}
+	var t time.Time
+	if r.day != 0 {
+		t = time.Date(year, r.month, r.day, 0, 0, 0, 0, time.UTC)
+	} else {
+		t = nthWeekday(year, r.month, r.weekday, r.nth)
+	}
+	d := civilOf(t)
+	if r.observe != nil {
+		if o, ok := r.observe(d, t.Weekday()); ok {
+			return []civil{d, o}
+		}
+	}
+	return []civil{d}
+}
+
+func nthWeekday(year int, month time.Month, wd time.Weekday, nth int) time.Time {
+	if nth < 0 {
+		last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+		return last.AddDate(0, 0, -((int(last.Weekday()) - int(wd) + 7) % 7))
+	}
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	offset := (int(wd) - int(first.Weekday()) + 7) % 7
+	return first.AddDate(0, 0, offset+7*(nth-1))
+}
+
+// shift сдвигает день на n дней.
+func shift(d civil, n int) civil {
+	return civilOf(time.Date(d.year, d.month, d.day+n, 0, 0, 0, 0, time.UTC))
+}
+
+// observeUS - праздник в субботу отмечается в пятницу, в воскресенье - в понедельник.
+func observeUS(d civil, wd time.Weekday) (civil, bool) {
+	switch wd {
+	case time.Saturday:
+		return shift(d, -1), true
+	case time.Sunday:
+		return shift(d, 1), true
+	}
+	return d, false
+}
+
+// observeRU - выходной, совпавший с праздником, переносится на следующий рабочий
+// день (ТК РФ, ст. 112). Переносы новогодних каникул правительство назначает
+// каждый год отдельно, их нужно загружать из ICS.
+func observeRU(d civil, wd time.Weekday) (civil, bool) {
+	switch wd {
+	case time.Saturday:
+		return shift(d, 2), true
+	case time.Sunday:
+		return shift(d, 1), true
+	}
+	return d, false
+}
+
+func fixed(name string, month time.Month, day int, observe func(civil, time.Weekday) (civil, bool)) rule {
+	return rule{name: name, month: month, day: day, observe: observe}
+}
+
+func nth(name string, month time.Month, wd time.Weekday, n int) rule {
+	return rule{name: name, month: month, weekday: wd, nth: n}
+}
+
+// builtin - встроенный набор праздников по странам (ISO 3166-1 alpha-2).
+var builtin = map[string][]rule{
+	"RU": {
+		fixed("Новогодние каникулы", time.January, 1, nil),
+		fixed("Новогодние каникулы", time.January, 2, nil),
+		fixed("Новогодние каникулы", time.January, 3, nil),
+		fixed("Новогодние каникулы", time.January, 4, nil),
+		fixed("Новогодние каникулы", time.January, 5, nil),
+		fixed("Новогодние каникулы", time.January, 6, nil),
+		fixed("Рождество Христово", time.January, 7, nil),
+		fixed("Новогодние каникулы", time.January, 8, nil),
+		fixed("День защитника Отечества", time.February, 23, observeRU),
+		fixed("Международный женский день", time.March, 8, observeRU),
+		fixed("Праздник Весны и Труда", time.May, 1, observeRU),
+		fixed("День Победы", time.May, 9, observeRU),
+		fixed("День России", time.June, 12, observeRU),
+		fixed("День народного единства", time.November, 4, observeRU),
+	},
+	"US": {
+		fixed("New Year's Day", time.January, 1, observeUS),
+		nth("Martin Luther King Jr. Day", time.January, time.Monday, 3),
+		nth("Washington's Birthday", time.February, time.Monday, 3),
+		nth("Memorial Day", time.May, time.Monday, -1),
+		{name: "Juneteenth", month: time.June, day: 19, since: 2021, observe: observeUS},
+		fixed("Independence Day", time.July, 4, observeUS),
+		nth("Labor Day", time.September, time.Monday, 1),
+		nth("Columbus Day", time.October, time.Monday, 2),
+		fixed("Veterans Day", time.November, 11, observeUS),
+		nth("Thanksgiving Day", time.November, time.Thursday, 4),
+		fixed("Christmas Day", time.December, 25, observeUS),
+	},
+}
+
+// Builtin возвращает коды стран встроенного набора праздников.
+func Builtin() []string {
+	res := make([]string, 0, len(builtin))
+	for code := range builtin {
+		res = append(res, code)
+	}
+	slices.Sort(res)
+	return res
+}
@@ -0,0 +1,213 @@
+package ics
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalid = errors.New("invalid iCalendar data")
+
+// Decode читает события VEVENT из VCALENDAR. Понимает DTSTART и DTEND датой
+// (VALUE=DATE), временем в UTC, с TZID или без зоны (тогда как UTC), DURATION,
+// UID, SUMMARY и DESCRIPTION. Остальные свойства и вложенные компоненты
+// (VALARM) пропускаются.
+func Decode(r io.Reader) ([]Event, error) {
+	var (
+		events []Event
+		cur    *Event
+		end    time.Time
+		depth  int // вложенность компонентов внутри VEVENT
+		lineNo int
+		prev   string
+	)
+	handle := func(l string, n int) error {
+		name, params, value := splitProperty(l)
+		switch {
+		case name == "BEGIN" && value == "VEVENT" && cur == nil:
+			cur, end, depth = &Event{}, time.Time{}, 0
+		case cur == nil:
+		case name == "BEGIN":
+			depth++
+		case name == "END" && depth > 0:
+			depth--
+		case name == "END" && value == "VEVENT":
+			if cur.Start.IsZero() {
+				return fmt.Errorf("%w: line %d: event without DTSTART", ErrInvalid, n)
+			}
+			if !end.IsZero() {
+				cur.Duration = end.Sub(cur.Start)
+			}
+			events = append(events, *cur)
+			cur = nil
+		case depth > 0:
+		case name == "DTSTART":
+			t, allDay, err := parseTime(value, params)
+			if err != nil {
+				return fmt.Errorf("%w: line %d: DTSTART: %w", ErrInvalid, n, err)
+			}
+			cur.Start, cur.AllDay = t, allDay
+		case name == "DTEND":
+			t, _, err := parseTime(value, params)
+			if err != nil {
+				return fmt.Errorf("%w: line %d: DTEND: %w", ErrInvalid, n, err)
+			}
+			end = t
+		case name == "DURATION":
+			d, err := ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("%w: line %d: %w", ErrInvalid, n, err)
+			}
+			cur.Duration = d
+		case name == "UID":
+			cur.UID = unescape(value)
+		case name == "SUMMARY":
+			cur.Summary = unescape(value)
+		case name == "DESCRIPTION":
+			cur.Description = unescape(value)
+		}
+		return nil
+	}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for sc.Scan() {
+		lineNo++
+		l := strings.TrimRight(sc.Text(), "\r")
+		// продолжение свёрнутой строки начинается с пробела или табуляции
+		if strings.HasPrefix(l, " ") || strings.HasPrefix(l, "\t") {
+			prev += l[1:]
+			continue
+		}
+		if prev != "" {
+			if err := handle(prev, lineNo-1); err != nil {
+				return nil, err
+			}
+		}
+		prev = l
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if prev != "" {
+		if err := handle(prev, lineNo); err != nil {
+			return nil, err
+		}
+	}
+	if cur != nil {
+		return nil, fmt.Errorf("%w: unterminated VEVENT", ErrInvalid)
+	}
+	return events, nil
+}
+
+// splitProperty делит строку "NAME;PARAM=V:value". Двоеточия в кавычках
+// значений параметров разделителем не считаются.
+func splitProperty(l string) (name string, params map[string]string, value string) {
+	quoted := false
+	colon := -1
+	for i, r := range l {
+		if r == '"' {
+			quoted = !quoted
+		}
+		if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return strings.ToUpper(l), nil, ""
+	}
+	head, value := l[:colon], l[colon+1:]
+	parts := strings.Split(head, ";")
+	params = make(map[string]string, len(parts)-1)
+	for _, p := range parts[1:] {
+		k, v, _ := strings.Cut(p, "=")
+		params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return strings.ToUpper(parts[0]), params, value
+}
+
+// parseTime разбирает DATE (полночь UTC, allDay) и DATE-TIME.
+func parseTime(value string, params map[string]string) (t time.Time, allDay bool, err error) {
+	if params["VALUE"] == "DATE" || len(value) == len(dateLayout) {
+		t, err = time.Parse(dateLayout, value)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err = time.Parse(stampLayout, value)
+		return t, false, err
+	}
+	loc := time.UTC
+	if tz := params["TZID"]; tz != "" {
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return time.Time{}, false, err
+		}
+	}
+	t, err = time.ParseInLocation(strings.TrimSuffix(stampLayout, "Z"), value, loc)
+	return t, false, err
+}
+
+// ParseDuration разбирает длительность RFC 5545: P1D, PT1H30M, P2W, -PT15M.
+func ParseDuration(s string) (time.Duration, error) {
+	src := s
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign, s = -1, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	s, ok := strings.CutPrefix(s, "P")
+	if !ok || s == "" {
+		return 0, fmt.Errorf("invalid duration %q", src)
+	}
+	var (
+		d      time.Duration
+		inTime bool
+		num    string
+	)
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			num += string(r)
+			continue
+		case r == 'T' && !inTime && num == "":
+			inTime = true
+			continue
+		}
+		n, err := strconv.Atoi(num)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", src)
+		}
+		var unit time.Duration
+		switch {
+		case r == 'W' && !inTime:
+			unit = 7 * 24 * time.Hour
+		case r == 'D' && !inTime:
+			unit = 24 * time.Hour
+		case r == 'H' && inTime:
+			unit = time.Hour
+		case r == 'M' && inTime:
+			unit = time.Minute
+		case r == 'S' && inTime:
+			unit = time.Second
+		default:
+			return 0, fmt.Errorf("invalid duration %q", src)
+		}
+		d += time.Duration(n) * unit
+		num = ""
+	}
+	if num != "" {
+		return 0, fmt.Errorf("invalid duration %q", src)
+	}
+	return sign * d, nil
+}
+
+// unescape - обратное к escape.
+func unescape(s string) string {
+	return strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n").Replace(s)
+}
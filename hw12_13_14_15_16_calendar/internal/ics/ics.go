@@ -0,0 +1,122 @@
+// Package ics - выгрузка и разбор событий в формате iCalendar (RFC 5545).
+package ics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	stampLayout = "20060102T150405Z"
+	dateLayout  = "20060102"
+)
+
+// Event - событие в том виде, в каком оно попадает в VEVENT.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	AllDay      bool          // событие на целые дни: DTSTART - дата без времени
+	Duration    time.Duration // 0 - без DURATION
+	AlarmDays   int32         // напоминание за столько дней, 0 - без VALARM
+	Created     time.Time
+}
+
+// Encode пишет события одним VCALENDAR.
+func Encode(w io.Writer, events []Event) error {
+	bw := bufio.NewWriter(w)
+	line := func(s string) {
+		writeFolded(bw, s)
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//mycalendar//calendarctl//RU")
+	line("CALSCALE:GREGORIAN")
+	for _, e := range events {
+		line("BEGIN:VEVENT")
+		line("UID:" + escape(e.UID))
+		stamp := e.Created
+		if stamp.IsZero() {
+			stamp = time.Now()
+		}
+		line("DTSTAMP:" + stamp.UTC().Format(stampLayout))
+		if e.AllDay {
+			line("DTSTART;VALUE=DATE:" + e.Start.Format(dateLayout))
+		} else {
+			line("DTSTART:" + e.Start.UTC().Format(stampLayout))
+		}
+		if e.Duration > 0 {
+			line("DURATION:" + FormatDuration(e.Duration))
+		}
+		line("SUMMARY:" + escape(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION:" + escape(e.Description))
+		}
+		if e.AlarmDays > 0 {
+			line("BEGIN:VALARM")
+			line("ACTION:DISPLAY")
+			line("DESCRIPTION:" + escape(e.Summary))
+			line(fmt.Sprintf("TRIGGER:-P%dD", e.AlarmDays))
+			line("END:VALARM")
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return bw.Flush()
+}
+
+// FormatDuration переводит длительность в формат RFC 5545, например 90m -> PT1H30M.
+func FormatDuration(d time.Duration) string {
+	if d <= 0 {
+		return "PT0S"
+	}
+	var b strings.Builder
+	b.WriteString("P")
+	if days := d / (24 * time.Hour); days > 0 {
+		fmt.Fprintf(&b, "%dD", days)
+		d -= days * 24 * time.Hour
+	}
+	if d == 0 {
+		return b.String()
+	}
+	b.WriteString("T")
+	if h := d / time.Hour; h > 0 {
+		fmt.Fprintf(&b, "%dH", h)
+		d -= h * time.Hour
+	}
+	if m := d / time.Minute; m > 0 {
+		fmt.Fprintf(&b, "%dM", m)
+		d -= m * time.Minute
+	}
+	if s := d / time.Second; s > 0 {
+		fmt.Fprintf(&b, "%dS", s)
+	}
+	return b.String()
+}
+
+// escape экранирует спецсимволы текстовых значений.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// writeFolded пишет строку, перенося её каждые 75 байт, и завершает CRLF.
+// Переносы не разрывают многобайтовые символы.
+func writeFolded(w *bufio.Writer, s string) {
+	const limit = 75
+	n := 0
+	for _, r := range s {
+		size := len(string(r))
+		if n+size > limit {
+			w.WriteString("\r\n ")
+			n = 1
+		}
+		w.WriteRune(r)
+		n += size
+	}
+	w.WriteString("\r\n")
+}
@@ -0,0 +1,115 @@
+package ics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	start := time.Date(2025, 7, 21, 10, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	err := Encode(&buf, []Event{{
+		UID:         "1@mycalendar",
+		Summary:     "Встреча, важная",
+		Description: "строка1\nстрока2",
+		Start:       start,
+		Duration:    90 * time.Minute,
+		AlarmDays:   1,
+		Created:     start.Add(-time.Hour),
+	}})
+	require.NoError(t, err)
+
+	out := buf.String()
+	require.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	require.Contains(t, out, "DTSTART:20250721T100000Z\r\n")
+	require.Contains(t, out, "DTSTAMP:20250721T090000Z\r\n")
+	require.Contains(t, out, "DURATION:PT1H30M\r\n")
+	require.Contains(t, out, `SUMMARY:Встреча\, важная`+"\r\n")
+	require.Contains(t, out, `DESCRIPTION:строка1\nстрока2`+"\r\n")
+	require.Contains(t, out, "TRIGGER:-P1D\r\n")
+	require.True(t, strings.HasSuffix(out, "END:VEVENT\r\nEND:VCALENDAR\r\n"))
+}
+
+func TestFoldLongLines(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, []Event{{UID: "1", Summary: strings.Repeat("я", 100)}}))
+	for _, l := range strings.Split(buf.String(), "\r\n") {
+		require.LessOrEqual(t, len(l), 75)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	require.Equal(t, "PT1H", FormatDuration(time.Hour))
+	require.Equal(t, "P1DT2H", FormatDuration(26*time.Hour))
+	require.Equal(t, "P2D", FormatDuration(48*time.Hour))
+	require.Equal(t, "PT45S", FormatDuration(45*time.Second))
+	require.Equal(t, "PT0S", FormatDuration(0))
+}
+
+func TestDecode(t *testing.T) {
+	src := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:ny@example.com\r\n" +
+		"DTSTART;VALUE=DATE:20260101\r\n" +
+		"DTEND;VALUE=DATE:20260109\r\n" +
+		"SUMMARY:Новогодние\r\n  каникулы\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART;TZID=Europe/Moscow:20260305T100000\r\n" +
+		"DURATION:PT1H30M\r\n" +
+		"SUMMARY:Встреча\\, важная\r\n" +
+		"BEGIN:VALARM\r\n" +
+		"DESCRIPTION:не описание события\r\n" +
+		"END:VALARM\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	events, err := Decode(strings.NewReader(src))
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	require.True(t, events[0].AllDay)
+	require.Equal(t, "Новогодние каникулы", events[0].Summary)
+	require.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), events[0].Start)
+	require.Equal(t, 8*24*time.Hour, events[0].Duration)
+
+	require.False(t, events[1].AllDay)
+	require.Equal(t, "Встреча, важная", events[1].Summary)
+	require.Empty(t, events[1].Description)
+	require.Equal(t, time.Date(2026, 3, 5, 7, 0, 0, 0, time.UTC), events[1].Start.UTC())
+	require.Equal(t, 90*time.Minute, events[1].Duration)
+
+	// то, что пишет Encode, читается обратно
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, events))
+	again, err := Decode(&buf)
+	require.NoError(t, err)
+	require.Equal(t, events[0].Start, again[0].Start)
+	require.True(t, again[0].AllDay)
+	require.Equal(t, events[1].Summary, again[1].Summary)
+
+	_, err = Decode(strings.NewReader("BEGIN:VEVENT\r\nSUMMARY:x\r\nEND:VEVENT\r\n"))
+	require.ErrorIs(t, err, ErrInvalid)
+	_, err = Decode(strings.NewReader("BEGIN:VEVENT\r\nDTSTART:2026\r\nEND:VEVENT\r\n"))
+	require.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestParseDuration(t *testing.T) {
+	for s, want := range map[string]time.Duration{
+		"P1D": 24 * time.Hour, "PT1H30M": 90 * time.Minute, "P1DT2H": 26 * time.Hour,
+		"P2W": 14 * 24 * time.Hour, "-PT15M": -15 * time.Minute, "PT45S": 45 * time.Second,
+	} {
+		got, err := ParseDuration(s)
+		require.NoError(t, err, s)
+		require.Equal(t, want, got, s)
+	}
+	for _, s := range []string{"", "P", "1D", "PT1D", "P1H", "PT1"} {
+		_, err := ParseDuration(s)
+		require.Error(t, err, s)
+	}
+}
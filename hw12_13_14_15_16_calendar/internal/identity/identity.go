@@ -0,0 +1,21 @@
+// Package identity - кто выполняет запрос. Аутентификация выполняется перед
+// календарём (API gateway, прокси), сюда приходит уже проверенный ID пользователя.
+package identity
+
+import "context"
+
+// UserIDHeader - заголовок HTTP (и ключ метаданных gRPC в нижнем регистре) с ID пользователя.
+const UserIDHeader = "X-User-ID"
+
+type userIDKey struct{}
+
+// WithUserID кладёт ID пользователя в контекст.
+func WithUserID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, userIDKey{}, id)
+}
+
+// UserIDFromContext достаёт ID пользователя из контекста, пустая строка - анонимный запрос.
+func UserIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(userIDKey{}).(string)
+	return id
+}
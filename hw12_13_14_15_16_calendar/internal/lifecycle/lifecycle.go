@@ -0,0 +1,126 @@
+// Package lifecycle запускает компоненты сервиса и останавливает их по сигналу
+// или при первой ошибке любого из них.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type Logger interface {
+	Info(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+type component struct {
+	name string
+	run  func(ctx context.Context) error
+	stop func(ctx context.Context) error
+}
+
+type closer struct {
+	name  string
+	close func() error
+}
+
+// Group - набор компонентов с общим временем жизни.
+//
+// Порядок остановки: контекст компонентов отменяется, вызываются stop в порядке,
+// обратном добавлению (серверы дожидаются текущих запросов), затем ресурсы
+// из OnClose, тоже в обратном порядке - как defer.
+type Group struct {
+	logger     Logger
+	timeout    time.Duration
+	components []component
+	closers    []closer
+}
+
+// New создаёт группу; timeout ограничивает всю остановку.
+func New(logger Logger, timeout time.Duration) *Group {
+	return &Group{logger: logger, timeout: timeout}
+}
+
+// Add добавляет компонент. run должен работать, пока не отменён ctx; nil, вернувшийся
+// раньше, значит, что компонент просто закончил работу. Ошибка run останавливает всю группу.
+// stop необязателен и вызывается при остановке с контекстом, ограниченным timeout.
+func (g *Group) Add(name string, run func(ctx context.Context) error, stop func(ctx context.Context) error) {
+	g.components = append(g.components, component{name: name, run: run, stop: stop})
+}
+
+// OnClose регистрирует ресурс (хранилище, очередь), который закрывается после остановки компонентов.
+func (g *Group) OnClose(name string, closeFn func() error) {
+	g.closers = append(g.closers, closer{name: name, close: closeFn})
+}
+
+// Run запускает компоненты и блокируется до отмены ctx или первой ошибки.
+// Возвращает эту ошибку вместе с ошибками остановки.
+func (g *Group) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		name string
+		err  error
+	}
+	results := make(chan result, len(g.components))
+	for _, c := range g.components {
+		go func() {
+			results <- result{name: c.name, err: c.run(ctx)}
+		}()
+	}
+
+	var errs []error
+	running := len(g.components)
+	for running > 0 && ctx.Err() == nil {
+		select {
+		case r := <-results:
+			running--
+			if r.err != nil {
+				g.logger.Error("component failed", "component", r.name, "err", r.err)
+				errs = append(errs, fmt.Errorf("%s: %w", r.name, r.err))
+				cancel()
+			}
+		case <-ctx.Done():
+		}
+	}
+	cancel()
+	g.logger.Info("shutting down", "timeout", g.timeout)
+
+	stopCtx, stopCancel := context.WithTimeout(context.Background(), g.timeout)
+	defer stopCancel()
+
+	for i := len(g.components) - 1; i >= 0; i-- {
+		c := g.components[i]
+		if c.stop == nil {
+			continue
+		}
+		if err := c.stop(stopCtx); err != nil {
+			errs = append(errs, fmt.Errorf("stop %s: %w", c.name, err))
+		}
+	}
+
+	// ждём, пока компоненты вернутся из run, но не дольше timeout
+	for running > 0 {
+		select {
+		case r := <-results:
+			running--
+			if r.err != nil && !errors.Is(r.err, context.Canceled) {
+				errs = append(errs, fmt.Errorf("%s: %w", r.name, r.err))
+			}
+		case <-stopCtx.Done():
+			errs = append(errs, fmt.Errorf("%d component(s) did not stop in %s", running, g.timeout))
+			running = 0
+		}
+	}
+
+	for i := len(g.closers) - 1; i >= 0; i-- {
+		c := g.closers[i]
+		if err := c.close(); err != nil {
+			errs = append(errs, fmt.Errorf("close %s: %w", c.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,83 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type recorder struct {
+	mu    sync.Mutex
+	steps []string
+}
+
+func (r *recorder) add(step string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.steps = append(r.steps, step)
+}
+
+func blocking(r *recorder, name string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		<-ctx.Done()
+		r.add("exit " + name)
+		return nil
+	}
+}
+
+func TestRunStopsInOrder(t *testing.T) {
+	var r recorder
+	g := New(slog.New(slog.DiscardHandler), time.Second)
+	g.OnClose("storage", func() error { r.add("close storage"); return nil })
+	g.OnClose("mq", func() error { r.add("close mq"); return nil })
+	g.Add("http", blocking(&r, "http"), func(context.Context) error { r.add("stop http"); return nil })
+	g.Add("grpc", blocking(&r, "grpc"), func(context.Context) error { r.add("stop grpc"); return nil })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	require.NoError(t, g.Run(ctx))
+
+	// stop - в обратном порядке, ресурсы закрываются последними и тоже в обратном порядке
+	require.Equal(t, []string{"stop grpc", "stop http"}, r.steps[:2])
+	require.ElementsMatch(t, []string{"exit http", "exit grpc"}, r.steps[2:4])
+	require.Equal(t, []string{"close mq", "close storage"}, r.steps[4:])
+}
+
+func TestRunPropagatesFirstFailure(t *testing.T) {
+	var r recorder
+	boom := errors.New("listen failed")
+
+	g := New(slog.New(slog.DiscardHandler), time.Second)
+	g.Add("http", blocking(&r, "http"), nil)
+	g.Add("grpc", func(context.Context) error { return boom }, nil)
+	g.Add("oneshot", func(context.Context) error { return nil }, nil)
+
+	err := g.Run(context.Background())
+	require.ErrorIs(t, err, boom)
+	require.ErrorContains(t, err, "grpc: listen failed")
+	require.Equal(t, []string{"exit http"}, r.steps)
+}
+
+func TestRunShutdownTimeout(t *testing.T) {
+	stuck := make(chan struct{})
+	defer close(stuck)
+
+	g := New(slog.New(slog.DiscardHandler), 20*time.Millisecond)
+	g.Add("stuck", func(context.Context) error { <-stuck; return nil }, nil)
+	var closed bool
+	g.OnClose("storage", func() error { closed = true; return nil })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := g.Run(ctx)
+	require.ErrorContains(t, err, "did not stop")
+	require.True(t, closed)
+}
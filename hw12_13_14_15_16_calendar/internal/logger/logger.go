@@ -1,74 +1,119 @@
 package logger
 
 import (
+	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"os"
 	"strings"
-)
-
-type Level int
 
-const (
-	DEBUG Level = iota
-	INFO
-	ERROR
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
+// Options описывает настройки логгера.
+type Options struct {
+	Level  string // debug, info, warn, error
+	Format string // json или text
+	// Path - файл для логов, при пустом значении пишем только в stdout.
+	Path string
+	// Ротация файла по размеру и возрасту.
+	MaxSizeMB  int
+	MaxAgeDays int
+	MaxBackups int
+}
+
+// Logger - обёртка над slog.Logger с изменяемым уровнем и закрываемым файлом.
 type Logger struct {
-	level  Level
-	logger *log.Logger
+	*slog.Logger
+	level *slog.LevelVar
+	file  io.Closer
 }
 
-// New создаёт логгер с заданным уровнем и файлом.
-func New(levelStr, logPath string) (*Logger, error) {
-	level := parseLevel(levelStr)
+// New создаёт логгер, который пишет в stdout и, если задан путь, в файл с ротацией.
+func New(opts Options) (*Logger, error) {
+	var out io.Writer = os.Stdout
+	var file io.Closer
+
+	if opts.Path != "" {
+		rotator := &lumberjack.Logger{
+			Filename:   opts.Path,
+			MaxSize:    opts.MaxSizeMB,
+			MaxAge:     opts.MaxAgeDays,
+			MaxBackups: opts.MaxBackups,
+		}
+		out = io.MultiWriter(os.Stdout, rotator)
+		file = rotator
+	}
 
-	// открываем файл
-	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	l, err := newWithWriter(out, opts)
 	if err != nil {
 		return nil, err
 	}
+	l.file = file
+	return l, nil
+}
 
-	multiWriter := io.MultiWriter(os.Stdout, logFile)
+func newWithWriter(w io.Writer, opts Options) (*Logger, error) {
+	lvl, err := ParseLevel(opts.Level)
+	if err != nil {
+		return nil, err
+	}
+	level := new(slog.LevelVar)
+	level.Set(lvl)
+
+	handlerOpts := &slog.HandlerOptions{Level: level}
+
+	var h slog.Handler
+	switch strings.ToLower(opts.Format) {
+	case "", "text":
+		h = slog.NewTextHandler(w, handlerOpts)
+	case "json":
+		h = slog.NewJSONHandler(w, handlerOpts)
+	default:
+		return nil, fmt.Errorf("unknown log format: %q", opts.Format)
+	}
 
 	return &Logger{
+		Logger: slog.New(contextHandler{h}),
 		level:  level,
-		logger: log.New(multiWriter, "", 0),
 	}, nil
 }
 
-func (l *Logger) Printf(format string, v ...any) {
-	if l.level <= INFO {
-		l.logger.Printf(format, v...)
-	}
+// Component возвращает логгер с атрибутом component.
+func (l *Logger) Component(name string) *slog.Logger {
+	return l.With("component", name)
 }
 
-func (l *Logger) Debug(msg string) {
-	if l.level <= DEBUG {
-		l.logger.Printf("[DEBUG] %s", msg)
-	}
-}
-
-func (l *Logger) Info(msg string) {
-	if l.level <= INFO {
-		l.logger.Printf("[INFO] %s", msg)
+// SetLevel меняет уровень логирования на лету.
+func (l *Logger) SetLevel(s string) error {
+	lvl, err := ParseLevel(s)
+	if err != nil {
+		return err
 	}
+	l.level.Set(lvl)
+	return nil
 }
 
-func (l *Logger) Error(msg string) {
-	if l.level <= ERROR {
-		l.logger.Printf("[ERROR] %s", msg)
+// Close закрывает файл логов, если он был открыт.
+func (l *Logger) Close() error {
+	if l.file == nil {
+		return nil
 	}
+	return l.file.Close()
 }
 
-func parseLevel(s string) Level {
+// ParseLevel разбирает уровень логирования, пустая строка означает info.
+func ParseLevel(s string) (slog.Level, error) {
 	switch strings.ToLower(s) {
 	case "debug":
-		return DEBUG
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
 	case "error":
-		return ERROR
+		return slog.LevelError, nil
 	default:
-		return INFO
+		return slog.LevelInfo, fmt.Errorf("unknown log level: %q", s)
 	}
 }
@@ -2,9 +2,9 @@ package logger
 
 import (
 	"bytes"
-	"io"
-	"log"
-	"os"
+	"context"
+	"encoding/json"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -13,16 +13,8 @@ import (
 func TestLoggerLevels(t *testing.T) {
 	var buf bytes.Buffer
 
-	// подменяем файл и stdout на буфер
-	tmpFile, err := os.CreateTemp("", "logger_test")
+	l, err := newWithWriter(&buf, Options{Level: "info", Format: "text"})
 	require.NoError(t, err)
-	defer os.Remove(tmpFile.Name())
-
-	multi := io.MultiWriter(&buf, tmpFile)
-	l := &Logger{
-		level:  INFO,
-		logger: log.New(multi, "", 0),
-	}
 
 	l.Debug("debug line") // не должен попасть
 	l.Info("info line")   // должен попасть
@@ -32,4 +24,45 @@ func TestLoggerLevels(t *testing.T) {
 	require.NotContains(t, output, "debug line")
 	require.Contains(t, output, "info line")
 	require.Contains(t, output, "error line")
+
+	// уменьшаем уровень на лету
+	require.NoError(t, l.SetLevel("debug"))
+	l.Debug("debug after change")
+	require.Contains(t, buf.String(), "debug after change")
+}
+
+func TestLoggerJSONWithRequestID(t *testing.T) {
+	var buf bytes.Buffer
+
+	l, err := newWithWriter(&buf, Options{Level: "debug", Format: "json"})
+	require.NoError(t, err)
+
+	ctx := WithRequestID(context.Background(), "req-42")
+	l.Component("http").InfoContext(ctx, "handled", "status", 200)
+
+	var rec map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &rec))
+	require.Equal(t, "handled", rec["msg"])
+	require.Equal(t, "http", rec["component"])
+	require.Equal(t, "req-42", rec["request_id"])
+	require.Equal(t, float64(200), rec["status"])
+}
+
+func TestLoggerBadOptions(t *testing.T) {
+	_, err := newWithWriter(&bytes.Buffer{}, Options{Level: "verbose"})
+	require.Error(t, err)
+
+	_, err = newWithWriter(&bytes.Buffer{}, Options{Format: "xml"})
+	require.Error(t, err)
+}
+
+func TestLoggerWritesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calendar.log")
+
+	l, err := New(Options{Level: "info", Format: "json", Path: path, MaxSizeMB: 1})
+	require.NoError(t, err)
+	l.Info("to file")
+	require.NoError(t, l.Close())
+
+	require.FileExists(t, path)
 }
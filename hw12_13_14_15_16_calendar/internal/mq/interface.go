@@ -5,7 +5,10 @@ type Publisher interface {
 	Close() error
 }
 
+// Consumer выдаёт обработчику не больше prefetch неподтверждённых сообщений.
+// Сообщение подтверждается, когда обработчик вернул nil; после ошибки оно
+// возвращается в очередь один раз, а после повторной ошибки отбрасывается.
 type Consumer interface {
-	Consume(queueName string, handler func([]byte)) error
+	Consume(queueName string, prefetch int, handler func([]byte) error) error
 	Close() error
 }
@@ -34,20 +34,31 @@ func (r *RabbitMQ) Publish(queue string, body []byte) error {
 	})
 }
 
-func (r *RabbitMQ) Consume(queue string, handler func([]byte)) error {
+// Consume читает очередь с ручным подтверждением: сообщение, которое не успели
+// обработать до закрытия соединения, очередь выдаст снова.
+func (r *RabbitMQ) Consume(queue string, prefetch int, handler func([]byte) error) error {
 	_, err := r.channel.QueueDeclare(queue, true, false, false, false, nil)
 	if err != nil {
 		return err
 	}
 
-	msgs, err := r.channel.Consume(queue, "", true, false, false, false, nil)
+	// Лимит действует на каждого следующего потребителя канала, поэтому
+	// задаётся перед Consume.
+	if err := r.channel.Qos(prefetch, 0, false); err != nil {
+		return err
+	}
+	msgs, err := r.channel.Consume(queue, "", false, false, false, false, nil)
 	if err != nil {
 		return err
 	}
 
 	go func() {
 		for msg := range msgs {
-			handler(msg.Body)
+			if err := handler(msg.Body); err != nil {
+				_ = msg.Nack(false, !msg.Redelivered)
+				continue
+			}
+			_ = msg.Ack(false)
 		}
 	}()
 	return nil
@@ -7,4 +7,5 @@ type Notification struct {
 	Title   string    `json:"title"`
 	StartAt time.Time `json:"startAt"`
 	UserID  string    `json:"userId"`
+	Tenant  string    `json:"tenant,omitempty"` // пусто - тенант по умолчанию
 }
@@ -0,0 +1,134 @@
+// Package ratelimit - ограничение частоты запросов по алгоритму token bucket,
+// отдельная корзина на каждого клиента.
+package ratelimit
+
+import (
+	"crypto/x509"
+	"math"
+	"net"
+	"sync"
+	"time"
+)
+
+// Корзины, которые не использовались дольше idleTTL и успели наполниться, удаляются.
+const idleTTL = 10 * time.Minute
+
+// maxBuckets ограничивает память: при переполнении вытесняются самые давние корзины.
+const maxBuckets = 100_000
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Limiter выдаёт каждому ключу rps токенов в секунду, но не больше burst за раз.
+type Limiter struct {
+	rps        float64
+	burst      float64
+	maxBuckets int
+	now        func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func New(rps float64, burst int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter{
+		rps:        rps,
+		burst:      float64(burst),
+		maxBuckets: maxBuckets,
+		now:        time.Now,
+		buckets:    make(map[string]*bucket),
+	}
+}
+
+// Allow забирает токен из корзины ключа. Если токенов нет, возвращает false и время,
+// через которое запрос можно повторить.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= l.maxBuckets {
+			l.evict(now)
+		}
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rps)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) / l.rps * float64(time.Second))
+	return false, wait
+}
+
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < idleTTL {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if now.Sub(b.last) > idleTTL {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// evict освобождает место под новую корзину. Сначала удаляются корзины, которые
+// уже наполнились (для клиента это то же, что новая), иначе - самая давняя.
+func (l *Limiter) evict(now time.Time) {
+	full := time.Duration(l.burst / l.rps * float64(time.Second))
+	var oldestKey string
+	var oldest time.Time
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= full {
+			delete(l.buckets, key)
+			continue
+		}
+		if oldestKey == "" || b.last.Before(oldest) {
+			oldestKey, oldest = key, b.last
+		}
+	}
+	if len(l.buckets) >= l.maxBuckets {
+		delete(l.buckets, oldestKey)
+	}
+}
+
+// ClientKey - ключ корзины клиента: субъект проверенного клиентского сертификата,
+// если соединение по mTLS, иначе IP из адреса соединения. Заголовкам вроде
+// X-User-ID верить нельзя - их подставляет сам клиент.
+func ClientKey(remoteAddr string, certs []*x509.Certificate) string {
+	if len(certs) > 0 {
+		return "cert:" + certs[0].Subject.String()
+	}
+	if remoteAddr == "" {
+		return "ip:unknown"
+	}
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	return "ip:" + host
+}
+
+// RetryAfter округляет ожидание вверх до целых секунд для заголовка Retry-After.
+func RetryAfter(wait time.Duration) int {
+	secs := int(math.Ceil(wait.Seconds()))
+	if secs < 1 {
+		secs = 1
+	}
+	return secs
+}
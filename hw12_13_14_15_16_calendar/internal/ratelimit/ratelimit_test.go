@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := New(2, 3)
+	l.now = func() time.Time { return now }
+
+	// burst запросов проходит сразу
+	for i := 0; i < 3; i++ {
+		ok, _ := l.Allow("alice")
+		require.True(t, ok)
+	}
+	ok, wait := l.Allow("alice")
+	require.False(t, ok)
+	require.Equal(t, 500*time.Millisecond, wait)
+	require.Equal(t, 1, RetryAfter(wait))
+
+	// у другого клиента своя корзина
+	ok, _ = l.Allow("bob")
+	require.True(t, ok)
+
+	// за полсекунды набегает один токен
+	now = now.Add(500 * time.Millisecond)
+	ok, _ = l.Allow("alice")
+	require.True(t, ok)
+	ok, _ = l.Allow("alice")
+	require.False(t, ok)
+
+	// простаивающие корзины удаляются
+	now = now.Add(2 * idleTTL)
+	l.Allow("carol")
+	require.Len(t, l.buckets, 1)
+}
+
+func TestLimiterMaxBuckets(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := New(1, 2)
+	l.maxBuckets = 2
+	l.now = func() time.Time { return now }
+
+	l.Allow("alice")
+	now = now.Add(time.Millisecond)
+	l.Allow("bob")
+	now = now.Add(time.Millisecond)
+
+	// места нет: вытесняется самая давняя корзина
+	l.Allow("carol")
+	require.Len(t, l.buckets, 2)
+	require.NotContains(t, l.buckets, "alice")
+
+	// наполнившиеся корзины удаляются первыми
+	now = now.Add(5 * time.Second)
+	l.Allow("dave")
+	require.Len(t, l.buckets, 1)
+	require.Contains(t, l.buckets, "dave")
+}
+
+func TestClientKey(t *testing.T) {
+	require.Equal(t, "ip:10.0.0.1", ClientKey("10.0.0.1:5000", nil))
+	require.Equal(t, "ip:::1", ClientKey("[::1]:5000", nil))
+	require.Equal(t, "ip:unknown", ClientKey("", nil))
+
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "svc", Organization: []string{"acme"}}}
+	require.Equal(t, "cert:CN=svc,O=acme", ClientKey("10.0.0.1:5000", []*x509.Certificate{cert}))
+}
@@ -3,61 +3,305 @@ package scheduler
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"sync/atomic"
 	"time"
 
+	"mycalendar/internal/blob"
+	"mycalendar/internal/holidays"
 	"mycalendar/internal/mq"
 	"mycalendar/internal/notifier"
 	"mycalendar/internal/storage"
+	"mycalendar/internal/tenant"
 )
 
+type Logger interface {
+	Info(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// Archiver сохраняет события с их вложениями перед окончательным удалением из корзины.
+type Archiver interface {
+	Write(ctx context.Context, events []storage.Event, attachments []storage.Attachment) error
+}
+
+// Reminders ставит в очередь напоминания подписчикам вебхуков.
+type Reminders interface {
+	Remind(ctx context.Context, e storage.Event) error
+}
+
+// Attachments - вложения событий корзины. Они архивируются вместе с событиями,
+// их файлы удаляются после очистки корзины, записи о вложениях хранилище удаляет
+// вместе с событиями.
+type Attachments interface {
+	TrashAttachments(ctx context.Context, before time.Time) ([]storage.Attachment, error)
+	AttachmentsExist(ctx context.Context, ids []int64) (map[int64]bool, error)
+}
+
+// Calendars - настройки календарей событий для переноса напоминаний.
+type Calendars interface {
+	GetCalendar(ctx context.Context, id int64) (storage.Calendar, error)
+}
+
 type Scheduler struct {
 	storage       storage.EventsStorage
 	publisher     mq.Publisher
 	topic         string
-	retentionDays int
+	retentionDays atomic.Int64
+	archiver      Archiver
+	webhooks      Reminders
+	attachments   Attachments
+	blobs         blob.Store
+	tenants       []string
+	quota         int // событий тенанта с напоминаниями за проход, 0 - без ограничения
+	calendars     Calendars
+	holidays      *holidays.Registry
+	logger        Logger
+}
+
+func NewScheduler(s storage.EventsStorage, p mq.Publisher, t string, days int, l Logger) *Scheduler {
+	sch := &Scheduler{storage: s, publisher: p, topic: t, tenants: []string{tenant.Default}, logger: l}
+	sch.SetRetention(days)
+	return sch
+}
+
+// SetRetention меняет срок хранения событий в корзине (0 - не очищать), можно вызывать на лету.
+func (s *Scheduler) SetRetention(days int) {
+	s.retentionDays.Store(int64(days))
+}
+
+// SetArchiver включает архивацию: корзина очищается, только если события удалось
+// сохранить в архив. Вызывается до первого Run.
+func (s *Scheduler) SetArchiver(a Archiver) {
+	s.archiver = a
+}
+
+// SetWebhooks включает напоминания подписчикам вебхуков. Вызывается до первого Run.
+func (s *Scheduler) SetWebhooks(r Reminders) {
+	s.webhooks = r
 }
 
-func NewScheduler(s storage.EventsStorage, p mq.Publisher, t string, days int) *Scheduler {
-	return &Scheduler{storage: s, publisher: p, topic: t, retentionDays: days}
+// SetAttachments включает архивацию вложений и удаление файлов вложений событий,
+// окончательно удалённых из корзины (blobs nil - файлы не удаляются). Вызывается
+// до первого Run.
+func (s *Scheduler) SetAttachments(a Attachments, blobs blob.Store) {
+	s.attachments = a
+	s.blobs = blobs
 }
 
+// SetTenants задаёт тенантов, которых обслуживает планировщик, и о скольких
+// событиях каждого напоминать за проход (0 - без ограничения). Квота считается в
+// событиях, а не в сообщениях: напоминание о событии получают все его адресаты,
+// остальные события ждут следующего прохода. Напоминания тенанта идут в его
+// очередь (см. tenant.Queue). Вызывается до первого Run.
+func (s *Scheduler) SetTenants(names []string, quota int) {
+	s.tenants = names
+	s.quota = quota
+}
+
+// SetHolidays включает перенос напоминаний с нерабочих дней: если у календаря
+// события включён DeferReminders и сегодня в его зоне выходной или праздник,
+// напоминания о событиях начиная со следующего рабочего дня не отправляются -
+// их отправит проход в рабочий день. Вызывается до первого Run.
+func (s *Scheduler) SetHolidays(c Calendars, r *holidays.Registry) {
+	s.calendars = c
+	s.holidays = r
+}
+
+// Run проходит по тенантам по очереди: ошибка одного не мешает остальным.
 func (s *Scheduler) Run(ctx context.Context) {
-	events, err := s.storage.GetUpcomingEvents(ctx, time.Now())
+	for _, t := range s.tenants {
+		s.runTenant(tenant.With(ctx, t), t)
+	}
+}
+
+func (s *Scheduler) runTenant(ctx context.Context, t string) {
+	now := time.Now()
+	events, err := s.storage.GetUpcomingEvents(ctx, now)
+	if err != nil {
+		s.logger.Error("failed to get events", "tenant", t, "err", err)
+		return
+	}
+
+	count := 0    // отправленных сообщений, по одному на адресата
+	reminded := 0 // событий, о которых напомнили, - в них считается квота
+	deferred := 0
+	var deferFrom map[int64]time.Time // по календарям за проход
+	if s.calendars != nil {
+		deferFrom = make(map[int64]time.Time)
+	}
+	queue := tenant.Queue(s.topic, t)
+	for i, e := range events {
+		if s.quota > 0 && reminded >= s.quota {
+			s.logger.Info("reminder quota exhausted", "tenant", t, "quota_events", s.quota, "skipped_events", len(events)-i)
+			break
+		}
+		if s.deferred(ctx, deferFrom, e, now) {
+			deferred++
+			continue
+		}
+		// напоминание получают владелец и все, кто принял приглашение
+		for _, userID := range e.Recipients() {
+			notif := notifier.Notification{
+				EventID: e.EventID,
+				Title:   e.Title,
+				StartAt: e.StartDateTime,
+				UserID:  userID,
+			}
+			if t != tenant.Default {
+				notif.Tenant = t
+			}
+			data, err := json.Marshal(notif)
+			if err != nil {
+				s.logger.Error("marshal error", "event_id", e.EventID, "err", err)
+				continue
+			}
+			err = s.publisher.Publish(queue, data)
+			if err != nil {
+				s.logger.Error("publish error", "event_id", e.EventID, "user_id", userID, "err", err)
+				continue
+			}
+			count++
+		}
+		reminded++
+		// повторные напоминания о том же событии хранилище отбрасывает
+		if s.webhooks != nil {
+			if err := s.webhooks.Remind(ctx, e); err != nil {
+				s.logger.Error("cannot enqueue webhook reminder", "event_id", e.EventID, "err", err)
+			}
+		}
+	}
+
+	s.logger.Info("reminders sent to queue", "tenant", t, "queue", queue, "events", reminded, "count", count)
+	if deferred > 0 {
+		s.logger.Info("reminders deferred to a working day", "tenant", t, "events", deferred)
+	}
+	days := int(s.retentionDays.Load())
+	if days == 0 {
+		return
+	}
+	// окончательно удаляются только события, пролежавшие в корзине дольше срока хранения
+	before := time.Now().AddDate(0, 0, -days)
+	var files []storage.Attachment
+	if s.attachments != nil {
+		if files, err = s.attachments.TrashAttachments(ctx, before); err != nil {
+			s.logger.Error("trash is not purged, cannot list attachments", "tenant", t, "err", err)
+			return
+		}
+	}
+	if err := s.archive(ctx, before, files); err != nil {
+		s.logger.Error("trash is not purged, archive failed", "tenant", t, "err", err)
+		return
+	}
+	purged, err := s.storage.PurgeTrash(ctx, before)
 	if err != nil {
-		log.Printf("failed to get events: %v", err)
+		s.logger.Error("error purging trash", "tenant", t, "err", err)
 		return
 	}
+	s.logger.Info("trash purged", "tenant", t, "older_than_days", days, "count", purged)
+	s.deleteFiles(ctx, files)
+}
+
+// deferred - напоминание о событии откладывается до рабочего дня. Ошибки настроек
+// календаря не мешают напоминать.
+func (s *Scheduler) deferred(ctx context.Context, deferFrom map[int64]time.Time, e storage.Event, now time.Time) bool {
+	if deferFrom == nil || e.CalendarID == 0 {
+		return false
+	}
+	from, ok := deferFrom[e.CalendarID]
+	if !ok {
+		from = s.deferFrom(ctx, e.CalendarID, now)
+		deferFrom[e.CalendarID] = from
+	}
+	return !from.IsZero() && !e.StartDateTime.Before(from)
+}
 
-	count := 0
+// deferFrom возвращает полночь следующего рабочего дня, если сегодня в календаре
+// нерабочий день и перенос включён. Нулевое время - напоминать обо всех событиях.
+func (s *Scheduler) deferFrom(ctx context.Context, calendarID int64, now time.Time) time.Time {
+	c, err := s.calendars.GetCalendar(ctx, calendarID)
+	if err != nil {
+		s.logger.Error("cannot get calendar, reminders are not deferred", "calendar_id", calendarID, "err", err)
+		return time.Time{}
+	}
+	if !c.DeferReminders {
+		return time.Time{}
+	}
+	hc, err := s.holidays.Get(c.HolidayCountry)
+	if err != nil {
+		s.logger.Error("reminders are not deferred", "calendar_id", calendarID, "err", err)
+		return time.Time{}
+	}
+	loc, err := time.LoadLocation(c.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	today := now.In(loc)
+	if hc.IsWorkingDay(today) {
+		return time.Time{}
+	}
+	next, err := hc.NextWorkingDay(today)
+	if err != nil {
+		s.logger.Error("reminders are not deferred", "calendar_id", calendarID, "err", err)
+		return time.Time{}
+	}
+	return next
+}
 
-	for _, e := range events {
-		notif := notifier.Notification{
-			EventID: e.EventID,
-			Title:   e.Title,
-			StartAt: e.StartDateTime,
-			UserID:  e.UserID,
+// deleteFiles удаляет содержимое вложений, чьих записей больше нет. Событие могли
+// восстановить между выборкой и очисткой, его файлы остаются. Если удалить не
+// удалось, файл остаётся лежать, но никому не виден.
+func (s *Scheduler) deleteFiles(ctx context.Context, files []storage.Attachment) {
+	ids := make([]int64, 0, len(files))
+	for _, f := range files {
+		if !f.IsLink() {
+			ids = append(ids, f.ID)
 		}
-		data, err := json.Marshal(notif)
-		if err != nil {
-			log.Printf("marshal error: %v", err)
+	}
+	if len(ids) == 0 || s.blobs == nil {
+		return
+	}
+	exist, err := s.attachments.AttachmentsExist(ctx, ids)
+	if err != nil {
+		s.logger.Error("attachment files are not deleted", "tenant", tenant.FromContext(ctx), "err", err)
+		return
+	}
+	deleted := 0
+	for _, f := range files {
+		if f.IsLink() || exist[f.ID] {
 			continue
 		}
-		err = s.publisher.Publish(s.topic, data)
-		if err != nil {
-			log.Printf("publish error: %v", err)
+		if err := s.blobs.Delete(ctx, f.BlobKey); err != nil {
+			s.logger.Error("cannot delete attachment file", "attachment_id", f.ID, "key", f.BlobKey, "err", err)
 			continue
 		}
-		count++
+		deleted++
 	}
+	s.logger.Info("attachment files deleted", "tenant", tenant.FromContext(ctx), "count", deleted)
+}
 
-	log.Printf("sending %d reminders to queue", count)
-	if s.retentionDays == 0 {
-		return
+// archive сохраняет события корзины, удалённые раньше before, с их вложениями из
+// files. Между архивацией и очисткой события могут только уйти из корзины, поэтому
+// в архиве окажутся все удаляемые.
+func (s *Scheduler) archive(ctx context.Context, before time.Time, files []storage.Attachment) error {
+	if s.archiver == nil {
+		return nil
 	}
-	err = s.storage.DeleteOldEvents(ctx, time.Now().AddDate(0, 0, -s.retentionDays))
-	log.Println("deleting old events")
+	trash, err := s.storage.ListTrash(ctx, "")
 	if err != nil {
-		log.Printf("error deleting old events: %v", err)
+		return err
+	}
+	var expired []storage.Event
+	for _, e := range trash {
+		if e.DeletedAt.Before(before) {
+			expired = append(expired, e)
+		}
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+	if err := s.archiver.Write(ctx, expired, files); err != nil {
+		return err
 	}
+	s.logger.Info("trash archived", "tenant", tenant.FromContext(ctx), "count", len(expired))
+	return nil
 }
@@ -2,13 +2,22 @@ package scheduler_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"io"
+	"log/slog"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"mycalendar/internal/blob"
+	"mycalendar/internal/holidays"
+	"mycalendar/internal/notifier"
 	"mycalendar/internal/scheduler"
 	"mycalendar/internal/storage"
+	"mycalendar/internal/tenant"
 )
 
 type MockStorage struct {
@@ -20,17 +29,26 @@ func (m *MockStorage) GetUpcomingEvents(ctx context.Context, from time.Time) ([]
 	return args.Get(0).([]storage.Event), args.Error(1)
 }
 
-func (m *MockStorage) DeleteOldEvents(ctx context.Context, before time.Time) error {
+func (m *MockStorage) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
 	args := m.Called(ctx, before)
-	return args.Error(0)
+	return int64(args.Int(0)), args.Error(1)
 }
 
-func (m *MockStorage) AddEvent(_ context.Context, _ storage.Event) error    { return nil }
-func (m *MockStorage) UpdateEvent(_ context.Context, _ storage.Event) error { return nil }
+func (m *MockStorage) DeleteOldEvents(_ context.Context, _ time.Time) error { return nil }
+
+func (m *MockStorage) AddEvent(_ context.Context, _ storage.Event) (int64, error) { return 0, nil }
+func (m *MockStorage) UpdateEvent(_ context.Context, _ storage.Event) error       { return nil }
 func (m *MockStorage) DeleteEvent(_ context.Context, _ string, _ time.Time) error {
 	return nil
 }
+func (m *MockStorage) DeleteEventByID(_ context.Context, _ int64) error { return nil }
+func (m *MockStorage) GetEvent(_ context.Context, _ int64) (storage.Event, error) {
+	return storage.Event{}, nil
+}
 func (m *MockStorage) GetEvents(_ context.Context) ([]storage.Event, error) { return nil, nil }
+func (m *MockStorage) ListEvents(_ context.Context, _ storage.EventFilter) ([]storage.Event, error) {
+	return nil, nil
+}
 func (m *MockStorage) GetEventsByDay(_ context.Context, _ time.Time) ([]storage.Event, error) {
 	return nil, nil
 }
@@ -43,6 +61,21 @@ func (m *MockStorage) GetEventsByMonth(_ context.Context, _ time.Time) ([]storag
 	return nil, nil
 }
 
+func (m *MockStorage) InviteAttendees(_ context.Context, _ int64, _ []string) error { return nil }
+func (m *MockStorage) SetAttendeeStatus(_ context.Context, _ int64, _ string, _ storage.RSVPStatus) error {
+	return nil
+}
+
+func (m *MockStorage) ListTrash(ctx context.Context, userID string) ([]storage.Event, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]storage.Event), args.Error(1)
+}
+func (m *MockStorage) RestoreEvent(_ context.Context, _ int64) error { return nil }
+
+func (m *MockStorage) GetBusyEvents(_ context.Context, _ []string, _, _ time.Time) ([]storage.Event, error) {
+	return nil, nil
+}
+
 type MockPublisher struct {
 	mock.Mock
 }
@@ -57,6 +90,60 @@ func (m *MockPublisher) Close() error {
 	return args.Error(0)
 }
 
+type MockArchiver struct {
+	mock.Mock
+}
+
+func (m *MockArchiver) Write(_ context.Context, events []storage.Event, attachments []storage.Attachment) error {
+	args := m.Called(events, attachments)
+	return args.Error(0)
+}
+
+type MockReminders struct {
+	mock.Mock
+}
+
+func (m *MockReminders) Remind(_ context.Context, e storage.Event) error {
+	args := m.Called(e)
+	return args.Error(0)
+}
+
+type MockAttachments struct {
+	mock.Mock
+}
+
+func (m *MockAttachments) TrashAttachments(_ context.Context, _ time.Time) ([]storage.Attachment, error) {
+	args := m.Called()
+	return args.Get(0).([]storage.Attachment), args.Error(1)
+}
+
+func (m *MockAttachments) AttachmentsExist(_ context.Context, ids []int64) (map[int64]bool, error) {
+	args := m.Called(ids)
+	return args.Get(0).(map[int64]bool), args.Error(1)
+}
+
+type calendars map[int64]storage.Calendar
+
+func (c calendars) GetCalendar(_ context.Context, id int64) (storage.Calendar, error) {
+	cal, ok := c[id]
+	if !ok {
+		return storage.Calendar{}, storage.ErrCalendarNotFound
+	}
+	return cal, nil
+}
+
+// blobs запоминает удалённые ключи.
+type blobs struct {
+	deleted []string
+}
+
+func (b *blobs) Put(context.Context, string, io.Reader) (int64, error) { return 0, nil }
+func (b *blobs) Open(context.Context, string) (io.ReadCloser, error)   { return nil, blob.ErrNotFound }
+func (b *blobs) Delete(_ context.Context, key string) error {
+	b.deleted = append(b.deleted, key)
+	return nil
+}
+
 func TestScheduler_Run_Success(t *testing.T) {
 	ctx := context.Background()
 	mockStorage := new(MockStorage)
@@ -71,9 +158,9 @@ func TestScheduler_Run_Success(t *testing.T) {
 
 	mockStorage.On("GetUpcomingEvents", mock.Anything, mock.Anything).Return([]storage.Event{event}, nil)
 	mockPublisher.On("Publish", "reminders", mock.Anything).Return(nil)
-	mockStorage.On("DeleteOldEvents", mock.Anything, mock.Anything).Return(nil)
+	mockStorage.On("PurgeTrash", mock.Anything, mock.Anything).Return(0, nil)
 
-	s := scheduler.NewScheduler(mockStorage, mockPublisher, "reminders", 30)
+	s := scheduler.NewScheduler(mockStorage, mockPublisher, "reminders", 30, slog.New(slog.DiscardHandler))
 	s.Run(ctx)
 
 	mockStorage.AssertExpectations(t)
@@ -87,7 +174,7 @@ func TestScheduler_Run_StorageError(t *testing.T) {
 
 	mockStorage.On("GetUpcomingEvents", mock.Anything, mock.Anything).Return([]storage.Event{}, errors.New("db error"))
 
-	s := scheduler.NewScheduler(mockStorage, mockPublisher, "reminders", 30)
+	s := scheduler.NewScheduler(mockStorage, mockPublisher, "reminders", 30, slog.New(slog.DiscardHandler))
 	s.Run(ctx)
 
 	mockStorage.AssertExpectations(t)
@@ -107,9 +194,9 @@ func TestScheduler_Run_PublishError(t *testing.T) {
 
 	mockStorage.On("GetUpcomingEvents", mock.Anything, mock.Anything).Return([]storage.Event{event}, nil)
 	mockPublisher.On("Publish", "reminders", mock.Anything).Return(errors.New("publish error"))
-	mockStorage.On("DeleteOldEvents", mock.Anything, mock.Anything).Return(nil)
+	mockStorage.On("PurgeTrash", mock.Anything, mock.Anything).Return(0, nil)
 
-	s := scheduler.NewScheduler(mockStorage, mockPublisher, "reminders", 30)
+	s := scheduler.NewScheduler(mockStorage, mockPublisher, "reminders", 30, slog.New(slog.DiscardHandler))
 	s.Run(ctx)
 
 	mockPublisher.AssertExpectations(t)
@@ -130,8 +217,268 @@ func TestScheduler_Run_NoDeletionIfZeroRetention(t *testing.T) {
 	mockStorage.On("GetUpcomingEvents", mock.Anything, mock.Anything).Return([]storage.Event{event}, nil)
 	mockPublisher.On("Publish", "reminders", mock.Anything).Return(nil)
 
-	s := scheduler.NewScheduler(mockStorage, mockPublisher, "reminders", 0)
+	s := scheduler.NewScheduler(mockStorage, mockPublisher, "reminders", 0, slog.New(slog.DiscardHandler))
+	s.Run(ctx)
+
+	mockStorage.AssertNotCalled(t, "PurgeTrash", mock.Anything, mock.Anything)
+}
+
+func TestScheduler_SetRetention(t *testing.T) {
+	ctx := context.Background()
+	mockStorage := new(MockStorage)
+	mockPublisher := new(MockPublisher)
+
+	mockStorage.On("GetUpcomingEvents", mock.Anything, mock.Anything).Return([]storage.Event{}, nil)
+	mockStorage.On("PurgeTrash", mock.Anything, mock.Anything).Return(0, nil)
+
+	s := scheduler.NewScheduler(mockStorage, mockPublisher, "reminders", 0, slog.New(slog.DiscardHandler))
+	s.Run(ctx)
+	mockStorage.AssertNotCalled(t, "PurgeTrash", mock.Anything, mock.Anything)
+
+	// включаем очистку на лету
+	s.SetRetention(30)
+	s.Run(ctx)
+	mockStorage.AssertCalled(t, "PurgeTrash", mock.Anything, mock.Anything)
+}
+
+func TestScheduler_Run_NotifiesAcceptedAttendees(t *testing.T) {
+	ctx := context.Background()
+	mockStorage := new(MockStorage)
+	mockPublisher := new(MockPublisher)
+
+	event := storage.Event{
+		EventID:       1,
+		Title:         "Meeting",
+		StartDateTime: time.Now().Add(time.Hour),
+		UserID:        "owner",
+		Attendees: []storage.Attendee{
+			{UserID: "alice", Status: storage.RSVPAccepted},
+			{UserID: "bob", Status: storage.RSVPDeclined},
+			{UserID: "carol", Status: storage.RSVPNeedsAction},
+		},
+	}
+
+	var recipients []string
+	mockStorage.On("GetUpcomingEvents", mock.Anything, mock.Anything).Return([]storage.Event{event}, nil)
+	mockPublisher.On("Publish", "reminders", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		var n notifier.Notification
+		require.NoError(t, json.Unmarshal(args.Get(1).([]byte), &n))
+		recipients = append(recipients, n.UserID)
+	})
+
+	s := scheduler.NewScheduler(mockStorage, mockPublisher, "reminders", 0, slog.New(slog.DiscardHandler))
+	s.Run(ctx)
+
+	require.Equal(t, []string{"owner", "alice"}, recipients)
+}
+
+func TestScheduler_Run_ArchivesBeforePurge(t *testing.T) {
+	ctx := context.Background()
+	mockStorage := new(MockStorage)
+	mockArchiver := new(MockArchiver)
+
+	expired := storage.Event{EventID: 1, UserID: "user1", DeletedAt: time.Now().AddDate(0, 0, -40)}
+	recent := storage.Event{EventID: 2, UserID: "user1", DeletedAt: time.Now().AddDate(0, 0, -1)}
+	mockStorage.On("GetUpcomingEvents", mock.Anything, mock.Anything).Return([]storage.Event{}, nil)
+	mockStorage.On("ListTrash", mock.Anything, "").Return([]storage.Event{expired, recent}, nil)
+	mockArchiver.On("Write", []storage.Event{expired}, []storage.Attachment(nil)).Return(nil)
+	mockStorage.On("PurgeTrash", mock.Anything, mock.Anything).Return(1, nil)
+
+	s := scheduler.NewScheduler(mockStorage, new(MockPublisher), "reminders", 30, slog.New(slog.DiscardHandler))
+	s.SetArchiver(mockArchiver)
+	s.Run(ctx)
+
+	mockStorage.AssertExpectations(t)
+	mockArchiver.AssertExpectations(t)
+}
+
+func TestScheduler_Run_ArchivesAttachments(t *testing.T) {
+	ctx := context.Background()
+	mockStorage := new(MockStorage)
+	mockArchiver := new(MockArchiver)
+	mockAttachments := new(MockAttachments)
+
+	expired := storage.Event{EventID: 10, UserID: "user1", DeletedAt: time.Now().AddDate(0, 0, -40)}
+	files := []storage.Attachment{
+		{ID: 1, EventID: 10, BlobKey: "default/10/a"},
+		{ID: 2, EventID: 10, URL: "https://example.com/agenda"},
+	}
+	mockStorage.On("GetUpcomingEvents", mock.Anything, mock.Anything).Return([]storage.Event{}, nil)
+	mockStorage.On("ListTrash", mock.Anything, "").Return([]storage.Event{expired}, nil)
+	mockAttachments.On("TrashAttachments").Return(files, nil)
+	mockArchiver.On("Write", []storage.Event{expired}, files).Return(nil)
+	mockStorage.On("PurgeTrash", mock.Anything, mock.Anything).Return(1, nil)
+	mockAttachments.On("AttachmentsExist", []int64{1}).Return(map[int64]bool{}, nil)
+
+	s := scheduler.NewScheduler(mockStorage, new(MockPublisher), "reminders", 30, slog.New(slog.DiscardHandler))
+	s.SetArchiver(mockArchiver)
+	s.SetAttachments(mockAttachments, &blobs{})
+	s.Run(ctx)
+
+	mockArchiver.AssertExpectations(t)
+	mockAttachments.AssertExpectations(t)
+}
+
+func TestScheduler_Run_NoPurgeIfArchiveFails(t *testing.T) {
+	ctx := context.Background()
+	mockStorage := new(MockStorage)
+	mockArchiver := new(MockArchiver)
+
+	expired := storage.Event{EventID: 1, UserID: "user1", DeletedAt: time.Now().AddDate(0, 0, -40)}
+	mockStorage.On("GetUpcomingEvents", mock.Anything, mock.Anything).Return([]storage.Event{}, nil)
+	mockStorage.On("ListTrash", mock.Anything, "").Return([]storage.Event{expired}, nil)
+	mockArchiver.On("Write", mock.Anything, mock.Anything).Return(errors.New("disk full"))
+
+	s := scheduler.NewScheduler(mockStorage, new(MockPublisher), "reminders", 30, slog.New(slog.DiscardHandler))
+	s.SetArchiver(mockArchiver)
+	s.Run(ctx)
+
+	mockStorage.AssertNotCalled(t, "PurgeTrash", mock.Anything, mock.Anything)
+}
+
+func TestScheduler_Run_WebhookReminders(t *testing.T) {
+	ctx := context.Background()
+	mockStorage := new(MockStorage)
+	mockPublisher := new(MockPublisher)
+	mockReminders := new(MockReminders)
+
+	first := storage.Event{EventID: 1, UserID: "user1", StartDateTime: time.Now().Add(time.Hour)}
+	second := storage.Event{EventID: 2, UserID: "user2", StartDateTime: time.Now().Add(2 * time.Hour)}
+	mockStorage.On("GetUpcomingEvents", mock.Anything, mock.Anything).Return([]storage.Event{first, second}, nil)
+	mockPublisher.On("Publish", "reminders", mock.Anything).Return(nil)
+	// ошибка одного напоминания не мешает остальным
+	mockReminders.On("Remind", first).Return(errors.New("db error"))
+	mockReminders.On("Remind", second).Return(nil)
+
+	s := scheduler.NewScheduler(mockStorage, mockPublisher, "reminders", 0, slog.New(slog.DiscardHandler))
+	s.SetWebhooks(mockReminders)
+	s.Run(ctx)
+
+	mockReminders.AssertExpectations(t)
+}
+
+func TestScheduler_Run_TenantQueuesAndQuota(t *testing.T) {
+	ctx := context.Background()
+	mockStorage := new(MockStorage)
+	mockPublisher := new(MockPublisher)
+
+	start := time.Now().Add(time.Hour)
+	ofTenant := func(id string) any {
+		return mock.MatchedBy(func(ctx context.Context) bool { return tenant.FromContext(ctx) == id })
+	}
+	mockStorage.On("GetUpcomingEvents", ofTenant(tenant.Default), mock.Anything).Return([]storage.Event{
+		{EventID: 1, UserID: "user1", StartDateTime: start},
+	}, nil)
+	mockStorage.On("GetUpcomingEvents", ofTenant("acme"), mock.Anything).Return([]storage.Event{
+		{EventID: 2, UserID: "user1", StartDateTime: start, Attendees: []storage.Attendee{
+			{UserID: "user4", Status: storage.RSVPAccepted},
+			{UserID: "user5", Status: storage.RSVPAccepted},
+			{UserID: "user6", Status: storage.RSVPDeclined},
+		}},
+		{EventID: 3, UserID: "user2", StartDateTime: start},
+		{EventID: 4, UserID: "user3", StartDateTime: start},
+	}, nil)
+
+	sent := make(map[string][]notifier.Notification)
+	mockPublisher.On("Publish", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		var n notifier.Notification
+		require.NoError(t, json.Unmarshal(args.Get(1).([]byte), &n))
+		sent[args.String(0)] = append(sent[args.String(0)], n)
+	})
+
+	s := scheduler.NewScheduler(mockStorage, mockPublisher, "reminders", 0, slog.New(slog.DiscardHandler))
+	s.SetTenants([]string{tenant.Default, "acme"}, 2)
+	s.Run(ctx)
+
+	require.Len(t, sent, 2)
+	require.Len(t, sent["reminders"], 1)
+	require.Empty(t, sent["reminders"][0].Tenant)
+	// у acme своя очередь; квота в событиях: о первом напоминание получают все
+	// три адресата, о втором - владелец, третье ждёт следующего прохода
+	acme := sent["reminders.acme"]
+	require.Len(t, acme, 4)
+	require.Equal(t, "acme", acme[0].Tenant)
+	var users []string
+	for _, n := range acme[:3] {
+		require.Equal(t, int64(2), n.EventID)
+		users = append(users, n.UserID)
+	}
+	require.ElementsMatch(t, []string{"user1", "user4", "user5"}, users)
+	require.Equal(t, int64(3), acme[3].EventID)
+}
+
+func TestScheduler_Run_DeletesPurgedAttachmentFiles(t *testing.T) {
+	ctx := context.Background()
+	mockStorage := new(MockStorage)
+	mockAttachments := new(MockAttachments)
+	store := &blobs{}
+
+	mockStorage.On("GetUpcomingEvents", mock.Anything, mock.Anything).Return([]storage.Event{}, nil)
+	mockAttachments.On("TrashAttachments").Return([]storage.Attachment{
+		{ID: 1, EventID: 10, BlobKey: "default/10/a"},
+		{ID: 2, EventID: 11, BlobKey: "default/11/b"},
+		{ID: 3, EventID: 10, URL: "https://example.com/agenda"},
+	}, nil)
+	mockStorage.On("PurgeTrash", mock.Anything, mock.Anything).Return(1, nil)
+	// событие 11 восстановили, пока шла очистка
+	mockAttachments.On("AttachmentsExist", []int64{1, 2}).Return(map[int64]bool{2: true}, nil)
+
+	s := scheduler.NewScheduler(mockStorage, new(MockPublisher), "reminders", 30, slog.New(slog.DiscardHandler))
+	s.SetAttachments(mockAttachments, store)
+	s.Run(ctx)
+
+	mockAttachments.AssertExpectations(t)
+	require.Equal(t, []string{"default/10/a"}, store.deleted)
+}
+
+func TestScheduler_Run_NoPurgeIfAttachmentsFail(t *testing.T) {
+	ctx := context.Background()
+	mockStorage := new(MockStorage)
+	mockAttachments := new(MockAttachments)
+
+	mockStorage.On("GetUpcomingEvents", mock.Anything, mock.Anything).Return([]storage.Event{}, nil)
+	mockAttachments.On("TrashAttachments").Return([]storage.Attachment(nil), errors.New("db error"))
+
+	s := scheduler.NewScheduler(mockStorage, new(MockPublisher), "reminders", 30, slog.New(slog.DiscardHandler))
+	s.SetAttachments(mockAttachments, &blobs{})
+	s.Run(ctx)
+
+	mockStorage.AssertNotCalled(t, "PurgeTrash", mock.Anything, mock.Anything)
+}
+
+func TestScheduler_Run_DefersRemindersOnHolidays(t *testing.T) {
+	ctx := context.Background()
+	mockStorage := new(MockStorage)
+	mockPublisher := new(MockPublisher)
+
+	// сегодня и завтра - праздники, следующий рабочий день не позже чем через 4 дня
+	today := time.Now().UTC()
+	ics := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART;VALUE=DATE:" + today.Format("20060102") +
+		"\r\nDURATION:P2D\r\nSUMMARY:Holiday\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	reg := holidays.NewRegistry()
+	require.NoError(t, reg.AddICS("XX", strings.NewReader(ics)))
+
+	cals := calendars{
+		1: {ID: 1, TimeZone: "UTC", HolidayCountry: "XX", DeferReminders: true},
+		2: {ID: 2, TimeZone: "UTC", HolidayCountry: "XX"},
+	}
+	soon := storage.Event{EventID: 1, UserID: "user1", CalendarID: 1, StartDateTime: time.Now().Add(time.Hour)}
+	later := storage.Event{EventID: 2, UserID: "user1", CalendarID: 1, StartDateTime: time.Now().AddDate(0, 0, 10)}
+	noDefer := storage.Event{EventID: 3, UserID: "user1", CalendarID: 2, StartDateTime: time.Now().AddDate(0, 0, 10)}
+	unknown := storage.Event{EventID: 4, UserID: "user1", CalendarID: 3, StartDateTime: time.Now().AddDate(0, 0, 10)}
+	mockStorage.On("GetUpcomingEvents", mock.Anything, mock.Anything).Return([]storage.Event{soon, later, noDefer, unknown}, nil)
+
+	var sent []int64
+	mockPublisher.On("Publish", "reminders", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		var n notifier.Notification
+		require.NoError(t, json.Unmarshal(args.Get(1).([]byte), &n))
+		sent = append(sent, n.EventID)
+	})
+
+	s := scheduler.NewScheduler(mockStorage, mockPublisher, "reminders", 0, slog.New(slog.DiscardHandler))
+	s.SetHolidays(cals, reg)
 	s.Run(ctx)
 
-	mockStorage.AssertNotCalled(t, "DeleteOldEvents", mock.Anything, mock.Anything)
+	// о событии до рабочего дня напоминаем сразу, без календаря - тоже
+	require.Equal(t, []int64{1, 3, 4}, sent)
 }
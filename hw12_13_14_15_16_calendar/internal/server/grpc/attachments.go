@@ -0,0 +1,63 @@
+package grpcserver
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+	pb "mycalendar/api/calendarpb"
+	"mycalendar/internal/identity"
+	"mycalendar/internal/storage"
+)
+
+func (s *Server) ListAttachments(ctx context.Context, req *pb.AttachmentsRequest) (*pb.AttachmentsResponse, error) {
+	userID := identity.UserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errMissingUser
+	}
+	list, err := s.app.ListAttachments(ctx, userID, req.EventId)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	res := &pb.AttachmentsResponse{Attachments: make([]*pb.Attachment, 0, len(list))}
+	for _, a := range list {
+		res.Attachments = append(res.Attachments, convertAttachment(a))
+	}
+	return res, nil
+}
+
+func (s *Server) AddLink(ctx context.Context, req *pb.LinkRequest) (*pb.Attachment, error) {
+	userID := identity.UserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errMissingUser
+	}
+	a, err := s.app.AddLink(ctx, userID, req.EventId, req.Name, req.Url)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return convertAttachment(a), nil
+}
+
+func (s *Server) DeleteAttachment(ctx context.Context, req *pb.AttachmentIDRequest) (*pb.Empty, error) {
+	userID := identity.UserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errMissingUser
+	}
+	if err := s.app.DeleteAttachment(ctx, userID, req.Id); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.Empty{}, nil
+}
+
+// ConvertAttachment нужен и HTTP серверу: ответ на загрузку файла - то же сообщение.
+func convertAttachment(a storage.Attachment) *pb.Attachment {
+	return &pb.Attachment{
+		Id:          a.ID,
+		EventId:     a.EventID,
+		Name:        a.Name,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		Url:         a.URL,
+		CreatedBy:   a.CreatedBy,
+		CreatedAt:   timestamppb.New(a.CreatedAt),
+	}
+}
@@ -0,0 +1,44 @@
+package grpcserver
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+	pb "mycalendar/api/calendarpb"
+	"mycalendar/internal/identity"
+	"mycalendar/internal/storage"
+)
+
+func (s *Server) GetEventHistory(ctx context.Context, req *pb.GetEventRequest) (*pb.EventHistoryResponse, error) {
+	userID := identity.UserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errMissingUser
+	}
+	records, err := s.app.EventHistory(ctx, userID, req.Id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	res := &pb.EventHistoryResponse{Records: make([]*pb.AuditRecord, 0, len(records))}
+	for _, r := range records {
+		res.Records = append(res.Records, convertAuditRecord(r))
+	}
+	return res, nil
+}
+
+func convertAuditRecord(r storage.AuditRecord) *pb.AuditRecord {
+	res := &pb.AuditRecord{
+		Id:        r.ID,
+		EventId:   r.EventID,
+		Actor:     r.Actor,
+		Action:    string(r.Action),
+		RequestId: r.RequestID,
+		At:        timestamppb.New(r.At),
+	}
+	if r.Before != nil {
+		res.Before = convertEvent(*r.Before)
+	}
+	if r.After != nil {
+		res.After = convertEvent(*r.After)
+	}
+	return res
+}
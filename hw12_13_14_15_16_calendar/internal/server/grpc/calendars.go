@@ -0,0 +1,153 @@
+package grpcserver
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	pb "mycalendar/api/calendarpb"
+	"mycalendar/internal/identity"
+	"mycalendar/internal/storage"
+)
+
+// CreateCalendar создаёт календарь пользователя из x-user-id, owner_id можно не указывать.
+func (s *Server) CreateCalendar(ctx context.Context, req *pb.CalendarRequest) (*pb.Calendar, error) {
+	c := req.GetCalendar()
+	if c == nil {
+		return nil, errMissingCalendar
+	}
+	owner := identity.UserIDFromContext(ctx)
+	if owner == "" {
+		return nil, errMissingUser
+	}
+	if c.OwnerId != "" && c.OwnerId != owner {
+		return nil, status.Error(codes.PermissionDenied, "cannot create a calendar for another user")
+	}
+	created, err := s.app.CreateCalendar(ctx, storage.Calendar{
+		OwnerID:        owner,
+		Name:           c.Name,
+		Color:          c.Color,
+		TimeZone:       c.TimeZone,
+		HolidayCountry: c.HolidayCountry,
+		DeferReminders: c.DeferReminders,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return convertCalendar(created), nil
+}
+
+func (s *Server) UpdateCalendar(ctx context.Context, req *pb.CalendarRequest) (*pb.Calendar, error) {
+	c := req.GetCalendar()
+	if c == nil {
+		return nil, errMissingCalendar
+	}
+	actor := identity.UserIDFromContext(ctx)
+	if actor == "" {
+		return nil, errMissingUser
+	}
+	updated, err := s.app.UpdateCalendar(ctx, actor, storage.Calendar{
+		ID:             c.Id,
+		Name:           c.Name,
+		Color:          c.Color,
+		TimeZone:       c.TimeZone,
+		HolidayCountry: c.HolidayCountry,
+		DeferReminders: c.DeferReminders,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return convertCalendar(updated), nil
+}
+
+func (s *Server) ListCalendars(ctx context.Context, req *pb.ListCalendarsRequest) (*pb.CalendarsResponse, error) {
+	userID := req.UserId
+	if userID == "" {
+		userID = identity.UserIDFromContext(ctx)
+	}
+	if userID == "" {
+		return nil, errMissingUser
+	}
+	calendars, err := s.app.ListCalendars(ctx, userID)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	res := &pb.CalendarsResponse{Calendars: make([]*pb.Calendar, 0, len(calendars))}
+	for _, c := range calendars {
+		res.Calendars = append(res.Calendars, convertCalendar(c))
+	}
+	return res, nil
+}
+
+func (s *Server) ShareCalendar(ctx context.Context, req *pb.ShareCalendarRequest) (*pb.Calendar, error) {
+	actor := identity.UserIDFromContext(ctx)
+	if actor == "" {
+		return nil, errMissingUser
+	}
+	c, err := s.app.ShareCalendar(ctx, actor, req.CalendarId, req.UserId, permissionFromProto(req.Permission))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return convertCalendar(c), nil
+}
+
+func (s *Server) ListCalendarEvents(ctx context.Context, req *pb.CalendarEventsRequest) (*pb.EventsResponse, error) {
+	viewer := identity.UserIDFromContext(ctx)
+	if viewer == "" {
+		return nil, errMissingUser
+	}
+	var from, to time.Time
+	if req.From != nil {
+		from = req.From.AsTime()
+	}
+	if req.To != nil {
+		to = req.To.AsTime()
+	}
+	events, err := s.app.ListCalendarEvents(ctx, viewer, req.CalendarId, from, to)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return convertEvents(events), nil
+}
+
+func convertCalendar(c storage.Calendar) *pb.Calendar {
+	res := &pb.Calendar{
+		Id:        c.ID,
+		OwnerId:   c.OwnerID,
+		Name:      c.Name,
+		Color:     c.Color,
+		TimeZone:  c.TimeZone,
+		IsDefault: c.IsDefault,
+		CreatedAt: timestamppb.New(c.CreatedAt),
+		Shares:    make([]*pb.CalendarShare, 0, len(c.Shares)),
+
+		HolidayCountry: c.HolidayCountry,
+		DeferReminders: c.DeferReminders,
+	}
+	for _, sh := range c.Shares {
+		res.Shares = append(res.Shares, &pb.CalendarShare{UserId: sh.UserID, Permission: permissions[sh.Permission]})
+	}
+	return res
+}
+
+var permissions = map[storage.Permission]pb.Permission{
+	storage.PermFreeBusy: pb.Permission_PERMISSION_FREE_BUSY,
+	storage.PermRead:     pb.Permission_PERMISSION_READ,
+	storage.PermWrite:    pb.Permission_PERMISSION_WRITE,
+}
+
+// permissionFromProto для PERMISSION_UNSPECIFIED возвращает пустые права - отзыв доступа,
+// неизвестные значения хранилище отклонит.
+func permissionFromProto(p pb.Permission) storage.Permission {
+	if p == pb.Permission_PERMISSION_UNSPECIFIED {
+		return ""
+	}
+	for k, v := range permissions {
+		if v == p {
+			return k
+		}
+	}
+	return storage.Permission(p.String())
+}
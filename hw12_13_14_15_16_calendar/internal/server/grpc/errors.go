@@ -0,0 +1,46 @@
+package grpcserver
+
+import (
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"mycalendar/internal/app"
+	"mycalendar/internal/storage"
+)
+
+var (
+	errMissingEvent    = status.Error(codes.InvalidArgument, "event is required")
+	errMissingCalendar = status.Error(codes.InvalidArgument, "calendar is required")
+	errMissingUser     = status.Error(codes.Unauthenticated, "x-user-id is required")
+)
+
+// toStatus переводит ошибки хранилища и приложения в коды gRPC.
+func toStatus(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, storage.ErrNotFound), errors.Is(err, storage.ErrCalendarNotFound),
+		errors.Is(err, storage.ErrWebhookNotFound), errors.Is(err, storage.ErrAttachmentNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, storage.ErrDateBusy):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, storage.ErrInvalidStatus):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, app.ErrInvalidQuery), errors.Is(err, app.ErrInvalidCalendar),
+		errors.Is(err, storage.ErrInvalidPermission), errors.Is(err, app.ErrInvalidWebhook),
+		errors.Is(err, app.ErrInvalidAttachment), errors.Is(err, app.ErrInvalidQuickAdd),
+		errors.Is(err, app.ErrInvalidWorkdays), errors.Is(err, app.ErrInvalidRecurrence):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, app.ErrForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, app.ErrAttachmentTooLarge):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, app.ErrAttachmentsOff):
+		return status.Error(codes.Unimplemented, err.Error())
+	case errors.Is(err, storage.ErrNotAttendee):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
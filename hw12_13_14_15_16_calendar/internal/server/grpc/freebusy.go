@@ -0,0 +1,93 @@
+package grpcserver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	pb "mycalendar/api/calendarpb"
+	"mycalendar/internal/app"
+	"mycalendar/internal/storage"
+)
+
+func (s *Server) GetFreeBusy(ctx context.Context, req *pb.FreeBusyRequest) (*pb.FreeBusyResponse, error) {
+	q, err := freeBusyQuery(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	fb, err := s.app.FreeBusy(ctx, q)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	res := &pb.FreeBusyResponse{
+		Busy:  make([]*pb.UserBusy, 0, len(q.UserIDs)),
+		Slots: make([]*pb.Slot, 0, len(fb.Slots)),
+	}
+	for _, u := range q.UserIDs {
+		ub := &pb.UserBusy{UserId: u}
+		for _, iv := range fb.Busy[u] {
+			ub.Busy = append(ub.Busy, &pb.Interval{Start: timestamppb.New(iv.Start), End: timestamppb.New(iv.End)})
+		}
+		res.Busy = append(res.Busy, ub)
+	}
+	for _, sl := range fb.Slots {
+		res.Slots = append(res.Slots, &pb.Slot{
+			Start:     timestamppb.New(sl.Start),
+			End:       timestamppb.New(sl.End),
+			Available: sl.Available,
+			Busy:      sl.Busy,
+		})
+	}
+	return res, nil
+}
+
+func freeBusyQuery(req *pb.FreeBusyRequest) (app.FreeBusyQuery, error) {
+	q := app.FreeBusyQuery{
+		UserIDs:         req.UserIds,
+		IncludeWeekends: req.IncludeWeekends,
+		Limit:           int(req.Limit),
+	}
+	if req.From != nil {
+		q.From = req.From.AsTime()
+	}
+	if req.To != nil {
+		q.To = req.To.AsTime()
+	}
+
+	var err error
+	if q.Duration, err = storage.ParseDuration(req.Duration); err != nil {
+		return q, fmt.Errorf("duration: %w", err)
+	}
+	if q.WorkDayStart, err = parseClock(req.WorkDayStart, app.DefaultWorkDayStart); err != nil {
+		return q, fmt.Errorf("work_day_start: %w", err)
+	}
+	if q.WorkDayEnd, err = parseClock(req.WorkDayEnd, app.DefaultWorkDayEnd); err != nil {
+		return q, fmt.Errorf("work_day_end: %w", err)
+	}
+	if req.TimeZone != "" {
+		if q.Location, err = time.LoadLocation(req.TimeZone); err != nil {
+			return q, fmt.Errorf("time_zone: %w", err)
+		}
+	}
+	return q, nil
+}
+
+// parseClock переводит "HH:MM" в смещение от полуночи, "24:00" - конец дня,
+// пустая строка - def.
+func parseClock(s string, def time.Duration) (time.Duration, error) {
+	switch s {
+	case "":
+		return def, nil
+	case "24:00":
+		return 24 * time.Hour, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("expected HH:MM, got %q", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
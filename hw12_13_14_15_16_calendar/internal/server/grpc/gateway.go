@@ -0,0 +1,30 @@
+package grpcserver
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/protobuf/encoding/protojson"
+	pb "mycalendar/api/calendarpb"
+)
+
+// NewGateway возвращает HTTP/JSON обработчик, построенный по аннотациям google.api.http
+// из EventService.proto. Запросы обслуживаются теми же методами Server без сетевого вызова,
+// поэтому у HTTP и gRPC API один контракт и одни обработчики.
+func NewGateway(ctx context.Context, srv pb.CalendarServiceServer) (http.Handler, error) {
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions: protojson.MarshalOptions{
+				EmitUnpopulated: true,
+			},
+			UnmarshalOptions: protojson.UnmarshalOptions{
+				DiscardUnknown: true,
+			},
+		}),
+	)
+	if err := pb.RegisterCalendarServiceHandlerServer(ctx, mux, srv); err != nil {
+		return nil, err
+	}
+	return mux, nil
+}
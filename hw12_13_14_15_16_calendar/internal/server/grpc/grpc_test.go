@@ -2,30 +2,42 @@ package grpcserver_test
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"log/slog"
 	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	pb "mycalendar/api/calendarpb"
 	"mycalendar/internal/app"
+	"mycalendar/internal/certs"
+	"mycalendar/internal/holidays"
+	"mycalendar/internal/ratelimit"
 	grpcserver "mycalendar/internal/server/grpc"
 	memorystorage "mycalendar/internal/storage/memory"
+	"mycalendar/internal/tenant"
+	"mycalendar/internal/webhook"
 )
 
-type testLogger struct{}
-
-func (testLogger) Printf(_ string, _ ...any) {}
-func (testLogger) Info(_ string)             {}
-func (testLogger) Error(_ string)            {}
-
 func TestIntegration_GRPC_AddGetDeleteEvent(t *testing.T) {
 	// --- Setup application
 	store := memorystorage.New()
-	logger := testLogger{}
+	logger := slog.New(slog.DiscardHandler)
 	appInstance, err := app.New(logger, store)
 	require.NoError(t, err)
 
@@ -33,7 +45,7 @@ func TestIntegration_GRPC_AddGetDeleteEvent(t *testing.T) {
 	lis, err := net.Listen("tcp", "localhost:0")
 	require.NoError(t, err)
 
-	grpcSrv := grpc.NewServer()
+	grpcSrv := grpc.NewServer(grpc.ChainUnaryInterceptor(grpcserver.IdentityInterceptor()))
 	server := grpcserver.NewServer(appInstance)
 	pb.RegisterCalendarServiceServer(grpcSrv, server)
 
@@ -49,7 +61,7 @@ func TestIntegration_GRPC_AddGetDeleteEvent(t *testing.T) {
 	defer conn.Close()
 
 	client := pb.NewCalendarServiceClient(conn)
-	ctx := context.Background()
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-user-id", "user1")
 
 	startAt := time.Now().UTC().Truncate(time.Second)
 
@@ -88,14 +100,14 @@ func TestIntegration_GRPC_AddGetDeleteEvent(t *testing.T) {
 func TestIntegration_GRPC_UpdateEvent(t *testing.T) {
 	// --- Setup
 	store := memorystorage.New()
-	logger := testLogger{}
+	logger := slog.New(slog.DiscardHandler)
 	appInstance, err := app.New(logger, store)
 	require.NoError(t, err)
 
 	lis, err := net.Listen("tcp", "localhost:0")
 	require.NoError(t, err)
 
-	grpcSrv := grpc.NewServer()
+	grpcSrv := grpc.NewServer(grpc.ChainUnaryInterceptor(grpcserver.IdentityInterceptor()))
 	pb.RegisterCalendarServiceServer(grpcSrv, grpcserver.NewServer(appInstance))
 	go grpcSrv.Serve(lis)
 	defer grpcSrv.Stop()
@@ -105,7 +117,7 @@ func TestIntegration_GRPC_UpdateEvent(t *testing.T) {
 	defer conn.Close()
 
 	client := pb.NewCalendarServiceClient(conn)
-	ctx := context.Background()
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-user-id", "user1")
 
 	startAt := time.Now().UTC().Truncate(time.Second)
 
@@ -157,14 +169,14 @@ func TestIntegration_GRPC_UpdateEvent(t *testing.T) {
 func TestIntegration_GRPC_GetEventsByDayWeekMonth(t *testing.T) {
 	// --- Setup
 	store := memorystorage.New()
-	logger := testLogger{}
+	logger := slog.New(slog.DiscardHandler)
 	appInstance, err := app.New(logger, store)
 	require.NoError(t, err)
 
 	lis, err := net.Listen("tcp", "localhost:0")
 	require.NoError(t, err)
 
-	grpcSrv := grpc.NewServer()
+	grpcSrv := grpc.NewServer(grpc.ChainUnaryInterceptor(grpcserver.IdentityInterceptor()))
 	pb.RegisterCalendarServiceServer(grpcSrv, grpcserver.NewServer(appInstance))
 	go grpcSrv.Serve(lis)
 	defer grpcSrv.Stop()
@@ -174,7 +186,7 @@ func TestIntegration_GRPC_GetEventsByDayWeekMonth(t *testing.T) {
 	defer conn.Close()
 
 	client := pb.NewCalendarServiceClient(conn)
-	ctx := context.Background()
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-user-id", "user1")
 
 	startAt := time.Now().UTC().Truncate(time.Second)
 
@@ -216,3 +228,815 @@ func TestIntegration_GRPC_GetEventsByDayWeekMonth(t *testing.T) {
 	})
 	require.NoError(t, err)
 }
+
+func TestIntegration_GRPC_RequestID(t *testing.T) {
+	store := memorystorage.New()
+	logger := slog.New(slog.DiscardHandler)
+	appInstance, err := app.New(logger, store)
+	require.NoError(t, err)
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	grpcSrv := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		grpcserver.RequestIDInterceptor(),
+		grpcserver.LoggingInterceptor(logger),
+		grpcserver.IdentityInterceptor(),
+	))
+	pb.RegisterCalendarServiceServer(grpcSrv, grpcserver.NewServer(appInstance))
+	go grpcSrv.Serve(lis)
+	defer grpcSrv.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := pb.NewCalendarServiceClient(conn)
+
+	// ID из метаданных возвращается клиенту
+	var header metadata.MD
+	user := metadata.AppendToOutgoingContext(context.Background(), "x-user-id", "user1")
+	ctx := metadata.AppendToOutgoingContext(user, "x-request-id", "req-1")
+	_, err = client.GetEvents(ctx, &pb.Empty{}, grpc.Header(&header))
+	require.NoError(t, err)
+	require.Equal(t, []string{"req-1"}, header.Get("x-request-id"))
+
+	// без x-request-id ID генерируется
+	_, err = client.GetEvents(user, &pb.Empty{}, grpc.Header(&header))
+	require.NoError(t, err)
+	require.Len(t, header.Get("x-request-id"), 1)
+	require.NotEmpty(t, header.Get("x-request-id")[0])
+}
+
+func TestIntegration_GRPC_GetListAndBatch(t *testing.T) {
+	// --- Setup
+	store := memorystorage.New()
+	logger := slog.New(slog.DiscardHandler)
+	appInstance, err := app.New(logger, store)
+	require.NoError(t, err)
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	grpcSrv := grpc.NewServer(grpc.ChainUnaryInterceptor(grpcserver.IdentityInterceptor()))
+	pb.RegisterCalendarServiceServer(grpcSrv, grpcserver.NewServer(appInstance))
+	go grpcSrv.Serve(lis)
+	defer grpcSrv.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := pb.NewCalendarServiceClient(conn)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-user-id", "user1")
+	user2 := metadata.AppendToOutgoingContext(context.Background(), "x-user-id", "user2")
+
+	day := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+
+	// --- BatchCreateEvents: второе событие конфликтует с первым, третье - чужое
+	batch, err := client.BatchCreateEvents(ctx, &pb.BatchCreateEventsRequest{Events: []*pb.Event{
+		{UserId: "user1", Title: "first", StartAt: timestamppb.New(day.Add(9 * time.Hour)), Duration: "1h"},
+		{UserId: "user1", Title: "dup", StartAt: timestamppb.New(day.Add(9 * time.Hour)), Duration: "1h"},
+		{UserId: "user2", Title: "forged", StartAt: timestamppb.New(day.Add(11 * time.Hour)), Duration: "1h"},
+	}})
+	require.NoError(t, err)
+	require.Len(t, batch.Results, 3)
+	require.Empty(t, batch.Results[0].Error)
+	require.NotEmpty(t, batch.Results[1].Error)
+	require.Contains(t, batch.Results[2].Error, "permission denied")
+	firstID := batch.Results[0].Id
+
+	batch, err = client.BatchCreateEvents(user2, &pb.BatchCreateEventsRequest{Events: []*pb.Event{
+		{Title: "second", StartAt: timestamppb.New(day.Add(10 * time.Hour)), Duration: "1h"},
+	}})
+	require.NoError(t, err)
+	require.Empty(t, batch.Results[0].Error)
+	secondID := batch.Results[0].Id
+
+	// --- GetEvent возвращает id и created_at
+	ev, err := client.GetEvent(ctx, &pb.GetEventRequest{Id: firstID})
+	require.NoError(t, err)
+	require.Equal(t, firstID, ev.Id)
+	require.Equal(t, "first", ev.Title)
+	require.NotNil(t, ev.CreatedAt)
+
+	_, err = client.GetEvent(ctx, &pb.GetEventRequest{Id: 999})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	// --- ListEvents с фильтрами, чужие события не видны
+	list, err := client.ListEvents(user2, &pb.ListEventsRequest{UserId: "user2"})
+	require.NoError(t, err)
+	require.Len(t, list.Events, 1)
+	require.Equal(t, "second", list.Events[0].Title)
+	list, err = client.ListEvents(ctx, &pb.ListEventsRequest{UserId: "user2"})
+	require.NoError(t, err)
+	require.Empty(t, list.Events)
+	_, err = client.GetEvent(ctx, &pb.GetEventRequest{Id: secondID})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = client.ListEvents(context.Background(), &pb.ListEventsRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	list, err = client.ListEvents(ctx, &pb.ListEventsRequest{
+		From: timestamppb.New(day.Add(9 * time.Hour)),
+		To:   timestamppb.New(day.Add(10 * time.Hour)),
+	})
+	require.NoError(t, err)
+	require.Len(t, list.Events, 1)
+	require.Equal(t, "first", list.Events[0].Title)
+
+	// --- GetUpcomingEvents
+	upcoming, err := client.GetUpcomingEvents(ctx, &pb.UpcomingEventsRequest{From: timestamppb.New(day)})
+	require.NoError(t, err)
+	require.Len(t, upcoming.Events, 1)
+
+	// --- BatchDeleteEvents: второй ID не существует, третий - чужое событие
+	del, err := client.BatchDeleteEvents(ctx, &pb.BatchDeleteEventsRequest{Ids: []int64{firstID, 999, secondID}})
+	require.NoError(t, err)
+	require.Empty(t, del.Results[0].Error)
+	require.NotEmpty(t, del.Results[1].Error)
+	require.Contains(t, del.Results[2].Error, "permission denied")
+
+	// --- DeleteOldEvents удаляет только события вызывающего, без x-user-id - ничего
+	before := &pb.DeleteOldEventsRequest{Before: timestamppb.New(day.AddDate(0, 0, 1))}
+	_, err = client.DeleteOldEvents(context.Background(), before)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = client.DeleteOldEvents(ctx, before)
+	require.NoError(t, err)
+	_, err = client.GetEvent(user2, &pb.GetEventRequest{Id: secondID})
+	require.NoError(t, err)
+
+	_, err = client.DeleteOldEvents(user2, before)
+	require.NoError(t, err)
+	getResp, err := client.GetEvents(ctx, &pb.Empty{})
+	require.NoError(t, err)
+	require.Len(t, getResp.Events, 0)
+}
+
+func TestIntegration_GRPC_RateLimit(t *testing.T) {
+	store := memorystorage.New()
+	logger := slog.New(slog.DiscardHandler)
+	appInstance, err := app.New(logger, store)
+	require.NoError(t, err)
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	grpcSrv := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		grpcserver.IdentityInterceptor(),
+		grpcserver.RateLimitInterceptor(ratelimit.New(0.1, 2)),
+	))
+	pb.RegisterCalendarServiceServer(grpcSrv, grpcserver.NewServer(appInstance))
+	go grpcSrv.Serve(lis)
+	defer grpcSrv.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := pb.NewCalendarServiceClient(conn)
+	alice := metadata.AppendToOutgoingContext(context.Background(), "x-user-id", "alice")
+
+	for i := 0; i < 2; i++ {
+		_, err = client.GetEvents(alice, &pb.Empty{})
+		require.NoError(t, err)
+	}
+
+	var header metadata.MD
+	_, err = client.GetEvents(alice, &pb.Empty{}, grpc.Header(&header))
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	require.Equal(t, []string{"10"}, header.Get("retry-after"))
+
+	// сменой x-user-id лимит не обойти, он считается на адрес клиента
+	bob := metadata.AppendToOutgoingContext(context.Background(), "x-user-id", "bob")
+	_, err = client.GetEvents(bob, &pb.Empty{})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestIntegration_GRPC_TLS(t *testing.T) {
+	store := memorystorage.New()
+	logger := slog.New(slog.DiscardHandler)
+	appInstance, err := app.New(logger, store)
+	require.NoError(t, err)
+
+	m, err := certs.New(certs.Options{SelfSigned: true, Hosts: []string{"127.0.0.1"}}, logger)
+	require.NoError(t, err)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	grpcSrv := grpc.NewServer(
+		grpc.Creds(credentials.NewTLS(m.TLSConfig())),
+		grpc.ChainUnaryInterceptor(grpcserver.IdentityInterceptor()),
+	)
+	pb.RegisterCalendarServiceServer(grpcSrv, grpcserver.NewServer(appInstance))
+	go grpcSrv.Serve(lis)
+	defer grpcSrv.Stop()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(m.Leaf())
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(
+		credentials.NewTLS(&tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}),
+	))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-user-id", "user1")
+	_, err = pb.NewCalendarServiceClient(conn).GetEvents(ctx, &pb.Empty{})
+	require.NoError(t, err)
+
+	// клиент без TLS не подключается
+	plain, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer plain.Close()
+	_, err = pb.NewCalendarServiceClient(plain).GetEvents(ctx, &pb.Empty{})
+	require.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestIntegration_GRPC_InviteAndRSVP(t *testing.T) {
+	store := memorystorage.New()
+	logger := slog.New(slog.DiscardHandler)
+	appInstance, err := app.New(logger, store)
+	require.NoError(t, err)
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	grpcSrv := grpc.NewServer(grpc.ChainUnaryInterceptor(grpcserver.IdentityInterceptor()))
+	pb.RegisterCalendarServiceServer(grpcSrv, grpcserver.NewServer(appInstance))
+	go grpcSrv.Serve(lis)
+	defer grpcSrv.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := pb.NewCalendarServiceClient(conn)
+	ctx := context.Background()
+	startAt := time.Date(2025, 6, 10, 9, 0, 0, 0, time.UTC)
+
+	// участники из запроса на создание сразу приглашаются
+	added, err := client.AddEvent(ctx, &pb.EventRequest{Event: &pb.Event{
+		UserId:    "owner",
+		Title:     "Planning",
+		StartAt:   timestamppb.New(startAt),
+		Duration:  "1h",
+		Attendees: []*pb.Attendee{{UserId: "alice"}},
+	}})
+	require.NoError(t, err)
+
+	// приглашает владелец, участник или посторонний - нет
+	invite := &pb.InviteRequest{EventId: added.Id, UserIds: []string{"bob"}}
+	aliceCtx := metadata.AppendToOutgoingContext(ctx, "x-user-id", "alice")
+	malloryCtx := metadata.AppendToOutgoingContext(ctx, "x-user-id", "mallory")
+	for _, caller := range []context.Context{ctx, aliceCtx, malloryCtx} {
+		_, err = client.InviteAttendees(caller, invite)
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	}
+	ownerCtx := metadata.AppendToOutgoingContext(ctx, "x-user-id", "owner")
+	e, err := client.InviteAttendees(ownerCtx, invite)
+	require.NoError(t, err)
+	require.Len(t, e.Attendees, 2)
+	require.Equal(t, pb.RSVPStatus_RSVP_STATUS_NEEDS_ACTION, e.Attendees[1].Status)
+
+	// отвечает пользователь из x-user-id
+	e, err = client.RespondToInvite(aliceCtx, &pb.RespondRequest{
+		EventId: added.Id,
+		Status:  pb.RSVPStatus_RSVP_STATUS_ACCEPTED,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "alice", e.Attendees[0].UserId)
+	require.Equal(t, pb.RSVPStatus_RSVP_STATUS_ACCEPTED, e.Attendees[0].Status)
+
+	bobCtx := metadata.AppendToOutgoingContext(ctx, "x-user-id", "bob")
+	_, err = client.RespondToInvite(bobCtx, &pb.RespondRequest{
+		EventId: added.Id, UserId: "bob", Status: pb.RSVPStatus_RSVP_STATUS_DECLINED,
+	})
+	require.NoError(t, err)
+
+	// за другого участника ответить нельзя, без x-user-id - тоже
+	_, err = client.RespondToInvite(bobCtx, &pb.RespondRequest{
+		EventId: added.Id, UserId: "alice", Status: pb.RSVPStatus_RSVP_STATUS_DECLINED,
+	})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = client.RespondToInvite(ctx, &pb.RespondRequest{
+		EventId: added.Id, Status: pb.RSVPStatus_RSVP_STATUS_DECLINED,
+	})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.RespondToInvite(malloryCtx, &pb.RespondRequest{
+		EventId: added.Id, Status: pb.RSVPStatus_RSVP_STATUS_ACCEPTED,
+	})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = client.RespondToInvite(aliceCtx, &pb.RespondRequest{EventId: added.Id})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// событие есть в днях, неделях и месяцах участника, но не у отказавшегося
+	date := timestamppb.New(startAt.Add(3 * time.Hour))
+	for user, want := range map[string]int{"owner": 1, "alice": 1, "bob": 0} {
+		as := metadata.AppendToOutgoingContext(ctx, "x-user-id", user)
+		day, err := client.GetEventsByDay(as, &pb.DateRequest{Date: date, UserId: user})
+		require.NoError(t, err)
+		require.Len(t, day.Events, want, user)
+		week, err := client.GetEventsByWeek(as, &pb.DateRequest{Date: date, UserId: user})
+		require.NoError(t, err)
+		require.Len(t, week.Events, want, user)
+		month, err := client.GetEventsByMonth(as, &pb.DateRequest{Date: date, UserId: user})
+		require.NoError(t, err)
+		require.Len(t, month.Events, want, user)
+	}
+}
+
+func TestIntegration_GRPC_FreeBusy(t *testing.T) {
+	store := memorystorage.New()
+	appInstance, err := app.New(slog.New(slog.DiscardHandler), store)
+	require.NoError(t, err)
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	grpcSrv := grpc.NewServer()
+	pb.RegisterCalendarServiceServer(grpcSrv, grpcserver.NewServer(appInstance))
+	go grpcSrv.Serve(lis)
+	defer grpcSrv.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := pb.NewCalendarServiceClient(conn)
+	ctx := context.Background()
+
+	// вторник, 10:00-11:00 по Москве занято у alice
+	msk, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	day := time.Date(2025, 6, 10, 0, 0, 0, 0, msk)
+	_, err = client.AddEvent(ctx, &pb.EventRequest{Event: &pb.Event{
+		UserId: "alice", Title: "Standup", StartAt: timestamppb.New(day.Add(10 * time.Hour)), Duration: "1h",
+	}})
+	require.NoError(t, err)
+
+	res, err := client.GetFreeBusy(ctx, &pb.FreeBusyRequest{
+		UserIds:      []string{"alice", "bob"},
+		From:         timestamppb.New(day),
+		To:           timestamppb.New(day.AddDate(0, 0, 1)),
+		Duration:     "1h",
+		WorkDayStart: "10:00",
+		WorkDayEnd:   "13:00",
+		TimeZone:     "Europe/Moscow",
+		Limit:        2,
+	})
+	require.NoError(t, err)
+	require.Len(t, res.Busy, 2)
+	require.Equal(t, "alice", res.Busy[0].UserId)
+	require.Len(t, res.Busy[0].Busy, 1)
+	require.Empty(t, res.Busy[1].Busy)
+
+	require.Len(t, res.Slots, 2)
+	require.True(t, day.Add(11*time.Hour).Equal(res.Slots[0].Start.AsTime()))
+	require.Equal(t, []string{"alice", "bob"}, res.Slots[0].Available)
+	require.True(t, day.Add(12*time.Hour).Equal(res.Slots[1].Start.AsTime()))
+
+	_, err = client.GetFreeBusy(ctx, &pb.FreeBusyRequest{
+		UserIds: []string{"alice"}, From: timestamppb.New(day), To: timestamppb.New(day.AddDate(0, 0, 1)),
+		Duration: "1h", WorkDayStart: "9am",
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.GetFreeBusy(ctx, &pb.FreeBusyRequest{UserIds: []string{"alice"}, Duration: "1h"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestIntegration_GRPC_Calendars(t *testing.T) {
+	appInstance, err := app.New(slog.New(slog.DiscardHandler), memorystorage.New())
+	require.NoError(t, err)
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	grpcSrv := grpc.NewServer(grpc.ChainUnaryInterceptor(grpcserver.IdentityInterceptor()))
+	pb.RegisterCalendarServiceServer(grpcSrv, grpcserver.NewServer(appInstance))
+	go grpcSrv.Serve(lis)
+	defer grpcSrv.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := pb.NewCalendarServiceClient(conn)
+	alice := metadata.AppendToOutgoingContext(context.Background(), "x-user-id", "alice")
+	bob := metadata.AppendToOutgoingContext(context.Background(), "x-user-id", "bob")
+
+	team, err := client.CreateCalendar(alice, &pb.CalendarRequest{Calendar: &pb.Calendar{Name: "Team"}})
+	require.NoError(t, err)
+	require.Equal(t, "alice", team.OwnerId)
+	_, err = client.CreateCalendar(context.Background(), &pb.CalendarRequest{Calendar: &pb.Calendar{Name: "Anon"}})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = client.CreateCalendar(bob, &pb.CalendarRequest{Calendar: &pb.Calendar{OwnerId: "alice", Name: "Fake"}})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = client.AddEvent(alice, &pb.EventRequest{Event: &pb.Event{
+		UserId: "alice", Title: "Retro", StartAt: timestamppb.Now(), Duration: "1h", CalendarId: team.Id,
+	}})
+	require.NoError(t, err)
+
+	_, err = client.ListCalendarEvents(bob, &pb.CalendarEventsRequest{CalendarId: team.Id})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = client.ShareCalendar(bob, &pb.ShareCalendarRequest{
+		CalendarId: team.Id, UserId: "bob", Permission: pb.Permission_PERMISSION_WRITE,
+	})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = client.ShareCalendar(context.Background(), &pb.ShareCalendarRequest{CalendarId: team.Id, UserId: "bob"})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	shared, err := client.ShareCalendar(alice, &pb.ShareCalendarRequest{
+		CalendarId: team.Id, UserId: "bob", Permission: pb.Permission_PERMISSION_FREE_BUSY,
+	})
+	require.NoError(t, err)
+	require.Len(t, shared.Shares, 1)
+
+	res, err := client.ListCalendarEvents(bob, &pb.CalendarEventsRequest{CalendarId: team.Id})
+	require.NoError(t, err)
+	require.Len(t, res.Events, 1)
+	require.Empty(t, res.Events[0].Title)
+	require.Equal(t, team.Id, res.Events[0].CalendarId)
+
+	list, err := client.ListCalendars(bob, &pb.ListCalendarsRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.Calendars, 2)
+	require.Equal(t, pb.Permission_PERMISSION_FREE_BUSY, list.Calendars[1].Shares[0].Permission)
+
+	_, err = client.ListCalendarEvents(bob, &pb.CalendarEventsRequest{CalendarId: 1000})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestIntegration_GRPC_TrashAndRestore(t *testing.T) {
+	appInstance, err := app.New(slog.New(slog.DiscardHandler), memorystorage.New())
+	require.NoError(t, err)
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	grpcSrv := grpc.NewServer(grpc.ChainUnaryInterceptor(grpcserver.IdentityInterceptor()))
+	pb.RegisterCalendarServiceServer(grpcSrv, grpcserver.NewServer(appInstance))
+	go grpcSrv.Serve(lis)
+	defer grpcSrv.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := pb.NewCalendarServiceClient(conn)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-user-id", "user1")
+	startAt := timestamppb.New(time.Date(2025, 6, 10, 9, 0, 0, 0, time.UTC))
+
+	added, err := client.AddEvent(ctx, &pb.EventRequest{Event: &pb.Event{UserId: "user1", Title: "Oops", StartAt: startAt}})
+	require.NoError(t, err)
+	_, err = client.DeleteEvent(ctx, &pb.DeleteRequest{UserId: "user1", Start: startAt})
+	require.NoError(t, err)
+
+	_, err = client.GetEvent(ctx, &pb.GetEventRequest{Id: added.Id})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	trash, err := client.ListTrash(ctx, &pb.TrashRequest{})
+	require.NoError(t, err)
+	require.Len(t, trash.Events, 1)
+	require.NotNil(t, trash.Events[0].DeletedAt)
+	_, err = client.ListTrash(context.Background(), &pb.TrashRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// чужую корзину не видно и не восстановить
+	other := metadata.AppendToOutgoingContext(context.Background(), "x-user-id", "user2")
+	_, err = client.ListTrash(other, &pb.TrashRequest{UserId: "user1"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = client.RestoreEvent(other, &pb.RestoreRequest{Id: added.Id})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.GetEventHistory(other, &pb.GetEventRequest{Id: added.Id})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	restored, err := client.RestoreEvent(ctx, &pb.RestoreRequest{Id: added.Id})
+	require.NoError(t, err)
+	require.Equal(t, "Oops", restored.Title)
+	require.Nil(t, restored.DeletedAt)
+
+	_, err = client.RestoreEvent(ctx, &pb.RestoreRequest{Id: added.Id})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestIntegration_GRPC_Tenants(t *testing.T) {
+	appInstance, err := app.New(slog.New(slog.DiscardHandler), memorystorage.New())
+	require.NoError(t, err)
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	grpcSrv := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		clientCertInterceptor,
+		grpcserver.IdentityInterceptor(),
+		grpcserver.TenantInterceptor(tenant.NewSet([]string{tenant.Default, "acme"}, map[string]string{"billing": "acme"})),
+	))
+	pb.RegisterCalendarServiceServer(grpcSrv, grpcserver.NewServer(appInstance))
+	go grpcSrv.Serve(lis)
+	defer grpcSrv.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := pb.NewCalendarServiceClient(conn)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-user-id", "user1")
+	acme := metadata.AppendToOutgoingContext(ctx, "x-test-client-cn", "billing")
+	startAt := timestamppb.New(time.Date(2025, 6, 10, 9, 0, 0, 0, time.UTC))
+
+	added, err := client.AddEvent(acme, &pb.EventRequest{Event: &pb.Event{UserId: "user1", Title: "Acme", StartAt: startAt}})
+	require.NoError(t, err)
+	_, err = client.GetEvent(acme, &pb.GetEventRequest{Id: added.Id})
+	require.NoError(t, err)
+
+	// тенант по умолчанию не видит событие и может занять то же время
+	_, err = client.GetEvent(ctx, &pb.GetEventRequest{Id: added.Id})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.AddEvent(ctx, &pb.EventRequest{Event: &pb.Event{UserId: "user1", Title: "Mine", StartAt: startAt}})
+	require.NoError(t, err)
+
+	// без сертификата назваться другим тенантом нельзя
+	unauthenticated := metadata.AppendToOutgoingContext(ctx, "x-tenant-id", "acme")
+	_, err = client.GetEvent(unauthenticated, &pb.GetEventRequest{Id: added.Id})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	mismatch := metadata.AppendToOutgoingContext(acme, "x-tenant-id", tenant.Default)
+	_, err = client.GetEvent(mismatch, &pb.GetEventRequest{Id: added.Id})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	invalid := metadata.AppendToOutgoingContext(ctx, "x-tenant-id", "Acme Corp")
+	_, err = client.GetEvent(invalid, &pb.GetEventRequest{Id: added.Id})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// clientCertInterceptor подставляет в peer клиентский сертификат с CN из
+// метаданных x-test-client-cn, как будто клиент пришёл по mTLS.
+func clientCertInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if cn := md.Get("x-test-client-cn"); len(cn) > 0 {
+		cert := &x509.Certificate{Subject: pkix.Name{CommonName: cn[0]}}
+		ctx = peer.NewContext(ctx, &peer.Peer{AuthInfo: credentials.TLSInfo{
+			State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}},
+		}})
+	}
+	return handler(ctx, req)
+}
+
+func TestIntegration_GRPC_EventHistory(t *testing.T) {
+	appInstance, err := app.New(slog.New(slog.DiscardHandler), memorystorage.New())
+	require.NoError(t, err)
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	grpcSrv := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		grpcserver.RequestIDInterceptor(),
+		grpcserver.IdentityInterceptor(),
+	))
+	pb.RegisterCalendarServiceServer(grpcSrv, grpcserver.NewServer(appInstance))
+	go grpcSrv.Serve(lis)
+	defer grpcSrv.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := pb.NewCalendarServiceClient(conn)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-user-id", "user1", "x-request-id", "req-7")
+	startAt := timestamppb.New(time.Date(2025, 6, 10, 9, 0, 0, 0, time.UTC))
+
+	added, err := client.AddEvent(ctx, &pb.EventRequest{Event: &pb.Event{UserId: "user1", Title: "Draft", StartAt: startAt}})
+	require.NoError(t, err)
+	_, err = client.UpdateEvent(ctx, &pb.EventRequest{Event: &pb.Event{UserId: "user1", Title: "Final", StartAt: startAt}})
+	require.NoError(t, err)
+	_, err = client.DeleteEvent(ctx, &pb.DeleteRequest{UserId: "user1", Start: startAt})
+	require.NoError(t, err)
+
+	history, err := client.GetEventHistory(ctx, &pb.GetEventRequest{Id: added.Id})
+	require.NoError(t, err)
+	require.Len(t, history.Records, 3)
+
+	create, update, del := history.Records[0], history.Records[1], history.Records[2]
+	require.Equal(t, "create", create.Action)
+	require.Nil(t, create.Before)
+	require.Equal(t, "Draft", create.After.Title)
+	require.Equal(t, "user1", create.Actor)
+	require.Equal(t, "req-7", create.RequestId)
+
+	require.Equal(t, "update", update.Action)
+	require.Equal(t, "Draft", update.Before.Title)
+	require.Equal(t, "Final", update.After.Title)
+
+	require.Equal(t, "delete", del.Action)
+	require.Equal(t, "Final", del.Before.Title)
+	require.Nil(t, del.After)
+
+	_, err = client.GetEventHistory(ctx, &pb.GetEventRequest{Id: 999})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.GetEventHistory(context.Background(), &pb.GetEventRequest{Id: added.Id})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestIntegration_GRPC_Webhooks(t *testing.T) {
+	store := memorystorage.New()
+	appInstance, err := app.New(slog.New(slog.DiscardHandler), store)
+	require.NoError(t, err)
+	// получатель слушает на loopback
+	appInstance.SetWebhooks(webhook.New(store), webhook.Guard{AllowPrivate: true})
+	dispatcher := webhook.NewDispatcher(store, webhook.Options{AllowPrivate: true}, slog.New(slog.DiscardHandler))
+
+	var (
+		mu       sync.Mutex
+		secret   string
+		received []string
+	)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		if webhook.Verify(secret, r.Header, body, time.Minute, time.Now()) != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		received = append(received, r.Header.Get(webhook.HeaderEvent))
+	}))
+	defer receiver.Close()
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	grpcSrv := grpc.NewServer(grpc.ChainUnaryInterceptor(grpcserver.IdentityInterceptor()))
+	pb.RegisterCalendarServiceServer(grpcSrv, grpcserver.NewServer(appInstance))
+	go grpcSrv.Serve(lis)
+	defer grpcSrv.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := pb.NewCalendarServiceClient(conn)
+	alice := metadata.AppendToOutgoingContext(context.Background(), "x-user-id", "alice")
+	bob := metadata.AppendToOutgoingContext(context.Background(), "x-user-id", "bob")
+
+	_, err = client.CreateWebhook(alice, &pb.WebhookRequest{Webhook: &pb.Webhook{Url: "not a url"}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	hook, err := client.CreateWebhook(alice, &pb.WebhookRequest{Webhook: &pb.Webhook{
+		Url: receiver.URL, Events: []string{"event.created"},
+	}})
+	require.NoError(t, err)
+	require.NotEmpty(t, hook.Secret)
+	mu.Lock()
+	secret = hook.Secret
+	mu.Unlock()
+
+	_, err = client.AddEvent(alice, &pb.EventRequest{Event: &pb.Event{
+		UserId: "alice", Title: "Demo", StartAt: timestamppb.Now(), Duration: "1h",
+	}})
+	require.NoError(t, err)
+	_, err = dispatcher.ProcessDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"event.created"}, received)
+
+	deliveries, err := client.ListWebhookDeliveries(alice, &pb.WebhookIDRequest{Id: hook.Id})
+	require.NoError(t, err)
+	require.Len(t, deliveries.Deliveries, 1)
+	require.Equal(t, "delivered", deliveries.Deliveries[0].State)
+	require.Contains(t, deliveries.Deliveries[0].Payload, `"title":"Demo"`)
+
+	list, err := client.ListWebhooks(alice, &pb.Empty{})
+	require.NoError(t, err)
+	require.Len(t, list.Webhooks, 1)
+	require.Empty(t, list.Webhooks[0].Secret)
+
+	_, err = client.DeleteWebhook(bob, &pb.WebhookIDRequest{Id: hook.Id})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = client.DeleteWebhook(alice, &pb.WebhookIDRequest{Id: hook.Id})
+	require.NoError(t, err)
+	_, err = client.EnableWebhook(alice, &pb.WebhookIDRequest{Id: hook.Id})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestIntegration_GRPC_QuickAdd(t *testing.T) {
+	appInstance, err := app.New(slog.New(slog.DiscardHandler), memorystorage.New())
+	require.NoError(t, err)
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	grpcSrv := grpc.NewServer(grpc.ChainUnaryInterceptor(grpcserver.IdentityInterceptor()))
+	pb.RegisterCalendarServiceServer(grpcSrv, grpcserver.NewServer(appInstance))
+	go grpcSrv.Serve(lis)
+	defer grpcSrv.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := pb.NewCalendarServiceClient(conn)
+	alice := metadata.AppendToOutgoingContext(context.Background(), "x-user-id", "alice")
+	text := "standup 2030-03-04 10:00 for 15m remind 10m before"
+
+	preview, err := client.QuickAdd(alice, &pb.QuickAddRequest{Text: text, Preview: true, TimeZone: "Europe/Moscow"})
+	require.NoError(t, err)
+	require.Zero(t, preview.Event.Id)
+	require.Nil(t, preview.Event.CreatedAt)
+	require.Equal(t, "standup", preview.Event.Title)
+	require.Equal(t, time.Date(2030, 3, 4, 7, 0, 0, 0, time.UTC), preview.Event.StartAt.AsTime())
+	require.Len(t, preview.Warnings, 1)
+	all, err := client.GetEvents(alice, &pb.Empty{})
+	require.NoError(t, err)
+	require.Empty(t, all.Events)
+
+	created, err := client.QuickAdd(alice, &pb.QuickAddRequest{Text: text, TimeZone: "Europe/Moscow"})
+	require.NoError(t, err)
+	got, err := client.GetEvent(alice, &pb.GetEventRequest{Id: created.Event.Id})
+	require.NoError(t, err)
+	require.Equal(t, "alice", got.UserId)
+	require.Equal(t, "15m", got.Duration)
+	require.EqualValues(t, 1, got.NoticeBefore)
+
+	_, err = client.QuickAdd(alice, &pb.QuickAddRequest{Text: "standup"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.QuickAdd(alice, &pb.QuickAddRequest{Text: text, TimeZone: "Mars/Olympus"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.QuickAdd(context.Background(), &pb.QuickAddRequest{Text: text})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestIntegration_GRPC_WorkingDays(t *testing.T) {
+	appInstance, err := app.New(slog.New(slog.DiscardHandler), memorystorage.New())
+	require.NoError(t, err)
+	reg, err := holidays.Load([]string{"US"}, nil)
+	require.NoError(t, err)
+	appInstance.SetHolidays(reg)
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	grpcSrv := grpc.NewServer(grpc.ChainUnaryInterceptor(grpcserver.IdentityInterceptor()))
+	pb.RegisterCalendarServiceServer(grpcSrv, grpcserver.NewServer(appInstance))
+	go grpcSrv.Serve(lis)
+	defer grpcSrv.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := pb.NewCalendarServiceClient(conn)
+	alice := metadata.AppendToOutgoingContext(context.Background(), "x-user-id", "alice")
+	bob := metadata.AppendToOutgoingContext(context.Background(), "x-user-id", "bob")
+
+	c, err := client.CreateCalendar(alice, &pb.CalendarRequest{Calendar: &pb.Calendar{Name: "Work"}})
+	require.NoError(t, err)
+	require.Empty(t, c.HolidayCountry)
+	c.HolidayCountry = "us"
+	c.DeferReminders = true
+	c, err = client.UpdateCalendar(alice, &pb.CalendarRequest{Calendar: c})
+	require.NoError(t, err)
+	require.Equal(t, "US", c.HolidayCountry)
+	require.True(t, c.DeferReminders)
+	_, err = client.UpdateCalendar(bob, &pb.CalendarRequest{Calendar: c})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	res, err := client.GetWorkingDays(alice, &pb.WorkingDaysRequest{From: "2025-11-24", To: "2025-11-30", CalendarId: c.Id})
+	require.NoError(t, err)
+	require.Equal(t, "US", res.Country)
+	require.Len(t, res.Days, 7)
+	require.Equal(t, "2025-11-27", res.Days[3].Date)
+	require.Equal(t, "Thanksgiving Day", res.Days[3].Holiday)
+	require.EqualValues(t, 4, res.WorkingCount)
+
+	_, err = client.GetWorkingDays(alice, &pb.WorkingDaysRequest{From: "2025-11-24", To: "2025-11-30", Country: "DE"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.GetWorkingDays(alice, &pb.WorkingDaysRequest{From: "24.11.2025", To: "2025-11-30"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.GetWorkingDays(bob, &pb.WorkingDaysRequest{From: "2025-11-24", To: "2025-11-30", CalendarId: c.Id})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// еженедельная серия по четвергам обходит День благодарения
+	first := time.Date(2025, 11, 20, 15, 0, 0, 0, time.UTC)
+	series, err := client.AddRecurringEvent(alice, &pb.RecurringEventRequest{
+		Event:        &pb.Event{Title: "Sync", StartAt: timestamppb.New(first), CalendarId: c.Id},
+		Frequency:    "weekly",
+		Until:        "2025-12-04",
+		SkipHolidays: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, series.Events, 2)
+	require.True(t, series.Events[1].StartAt.AsTime().Equal(first.AddDate(0, 0, 14)))
+	require.Len(t, series.Skipped, 1)
+	require.True(t, series.Skipped[0].AsTime().Equal(first.AddDate(0, 0, 7)))
+
+	_, err = client.AddRecurringEvent(alice, &pb.RecurringEventRequest{
+		Event: &pb.Event{Title: "Sync", StartAt: timestamppb.New(first)}, Frequency: "weekly", Until: "04.12.2025",
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.AddRecurringEvent(alice, &pb.RecurringEventRequest{
+		Event: &pb.Event{Title: "Sync", StartAt: timestamppb.New(first)}, Frequency: "hourly", Count: 3,
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.AddRecurringEvent(bob, &pb.RecurringEventRequest{
+		Event: &pb.Event{Title: "Sync", StartAt: timestamppb.New(first), CalendarId: c.Id}, Frequency: "daily", Count: 2,
+	})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+}
@@ -0,0 +1,134 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"mycalendar/internal/identity"
+	"mycalendar/internal/logger"
+	"mycalendar/internal/ratelimit"
+	"mycalendar/internal/tenant"
+)
+
+type Logger interface {
+	InfoContext(ctx context.Context, msg string, args ...any)
+}
+
+var requestIDKey = strings.ToLower(logger.RequestIDHeader)
+
+// RequestIDInterceptor берёт ID запроса из метаданных x-request-id или генерирует новый,
+// кладёт его в контекст и отдаёт клиенту в заголовках ответа.
+func RequestIDInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var id string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if vals := md.Get(requestIDKey); len(vals) > 0 {
+				id = vals[0]
+			}
+		}
+		if id == "" {
+			id = logger.NewRequestID()
+		}
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, id))
+
+		return handler(logger.WithRequestID(ctx, id), req)
+	}
+}
+
+// LoggingInterceptor пишет в лог каждый вызов: метод, код ответа и длительность.
+func LoggingInterceptor(log Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		log.InfoContext(ctx, "grpc request",
+			"method", info.FullMethod,
+			"code", status.Code(err).String(),
+			"duration_ms", time.Since(start).Milliseconds(),
+		)
+		return resp, err
+	}
+}
+
+var userIDKey = strings.ToLower(identity.UserIDHeader)
+
+// IdentityInterceptor кладёт в контекст ID пользователя из метаданных x-user-id.
+func IdentityInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if vals := md.Get(userIDKey); len(vals) > 0 && vals[0] != "" {
+				ctx = identity.WithUserID(ctx, vals[0])
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+var tenantKey = strings.ToLower(tenant.Header)
+
+// TenantInterceptor кладёт в контекст тенанта из клиентского сертификата или
+// метаданных x-tenant-id и отклоняет вызовы тенантов не из tenants и вызовы
+// без сертификата, если тенантов несколько.
+func TenantInterceptor(tenants tenant.Set) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var header string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if vals := md.Get(tenantKey); len(vals) > 0 {
+				header = vals[0]
+			}
+		}
+		var certs []*x509.Certificate
+		if p, ok := peer.FromContext(ctx); ok {
+			if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+				certs = info.State.PeerCertificates
+			}
+		}
+		id, err := tenants.Resolve(header, certs)
+		if err != nil {
+			code := codes.PermissionDenied
+			switch {
+			case errors.Is(err, tenant.ErrInvalid):
+				code = codes.InvalidArgument
+			case errors.Is(err, tenant.ErrUnauthenticated):
+				code = codes.Unauthenticated
+			}
+			return nil, status.Error(code, err.Error())
+		}
+		return handler(tenant.With(ctx, id), req)
+	}
+}
+
+// RateLimitInterceptor ограничивает частоту вызовов каждого клиента. Сверх лимита
+// возвращает RESOURCE_EXHAUSTED и заголовок retry-after в секундах.
+func RateLimitInterceptor(limiter *ratelimit.Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if ok, wait := limiter.Allow(clientKey(ctx)); !ok {
+			_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(ratelimit.RetryAfter(wait))))
+			return nil, status.Error(codes.ResourceExhausted, "too many requests")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// clientKey - ключ лимита по соединению: сертификат клиента или IP.
+func clientKey(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ratelimit.ClientKey("", nil)
+	}
+	var certs []*x509.Certificate
+	if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+		certs = info.State.PeerCertificates
+	}
+	return ratelimit.ClientKey(p.Addr.String(), certs)
+}
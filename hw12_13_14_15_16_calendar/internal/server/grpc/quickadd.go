@@ -0,0 +1,35 @@
+package grpcserver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	pb "mycalendar/api/calendarpb"
+	"mycalendar/internal/identity"
+)
+
+func (s *Server) QuickAdd(ctx context.Context, req *pb.QuickAddRequest) (*pb.QuickAddResponse, error) {
+	userID := identity.UserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errMissingUser
+	}
+	var loc *time.Location
+	if req.TimeZone != "" {
+		var err error
+		if loc, err = time.LoadLocation(req.TimeZone); err != nil {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("time_zone: %v", err))
+		}
+	}
+	res, err := s.app.QuickAdd(ctx, userID, req.Text, req.CalendarId, loc, req.Preview)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	e := convertEvent(res.Event)
+	if req.Preview {
+		e.CreatedAt = nil
+	}
+	return &pb.QuickAddResponse{Event: e, Warnings: res.Warnings}, nil
+}
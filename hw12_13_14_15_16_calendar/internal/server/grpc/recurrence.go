@@ -0,0 +1,60 @@
+package grpcserver
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	pb "mycalendar/api/calendarpb"
+	"mycalendar/internal/app"
+	"mycalendar/internal/storage"
+)
+
+func (s *Server) AddRecurringEvent(ctx context.Context, req *pb.RecurringEventRequest) (*pb.RecurringEventResponse, error) {
+	e := req.GetEvent()
+	if e == nil {
+		return nil, errMissingEvent
+	}
+	r := app.Recurrence{
+		Freq:         app.Frequency(req.Frequency),
+		Interval:     int(req.Interval),
+		Count:        int(req.Count),
+		SkipHolidays: req.SkipHolidays,
+	}
+	if req.Until != "" {
+		var err error
+		if r.Until, err = parseDate("until", req.Until); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	attendees := make([]storage.Attendee, 0, len(e.Attendees))
+	for _, a := range e.Attendees {
+		attendees = append(attendees, storage.Attendee{UserID: a.UserId})
+	}
+	res, err := s.app.AddRecurringEvent(ctx, storage.Event{
+		UserID:        e.UserId,
+		Title:         e.Title,
+		Description:   e.Description,
+		StartDateTime: e.StartAt.AsTime(),
+		Duration:      e.Duration,
+		NoticeBefore:  e.NoticeBefore,
+		CalendarID:    e.CalendarId,
+		Attendees:     attendees,
+	}, r)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &pb.RecurringEventResponse{
+		Events:  make([]*pb.Event, 0, len(res.Events)),
+		Skipped: make([]*timestamppb.Timestamp, 0, len(res.Skipped)),
+	}
+	for _, created := range res.Events {
+		resp.Events = append(resp.Events, convertEvent(created))
+	}
+	for _, t := range res.Skipped {
+		resp.Skipped = append(resp.Skipped, timestamppb.New(t))
+	}
+	return resp, nil
+}
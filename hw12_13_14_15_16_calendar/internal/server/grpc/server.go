@@ -2,21 +2,59 @@ package grpcserver
 
 import (
 	"context"
+	"net"
 	"time"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	pb "mycalendar/api/calendarpb"
+	"mycalendar/internal/app"
+	"mycalendar/internal/identity"
 	"mycalendar/internal/storage"
 )
 
 type Application interface {
-	CreateEvent(ctx context.Context, uID, title, desc, dur string, noticeBefore int32, startAt time.Time) error
+	CreateEvent(ctx context.Context, uID, title, desc, dur string, noticeBefore int32, startAt time.Time) (int64, error)
+	AddEvent(ctx context.Context, e storage.Event) (int64, error)
+	QuickAdd(ctx context.Context, userID, text string, calendarID int64, loc *time.Location, preview bool) (app.QuickAddResult, error)
+	AddRecurringEvent(ctx context.Context, e storage.Event, r app.Recurrence) (app.RecurringResult, error)
 	UpdateEvent(ctx context.Context, uID, title, desc, dur string, noticeBefore int32, startAt time.Time) error
 	DeleteEvent(ctx context.Context, userID string, start time.Time) error
+	DeleteOldEvents(ctx context.Context, before time.Time) error
+	BatchCreateEvents(ctx context.Context, events []storage.Event) []app.BatchResult
+	BatchDeleteEvents(ctx context.Context, ids []int64) []app.BatchResult
+	GetEvent(ctx context.Context, id int64) (storage.Event, error)
 	GetEvents(ctx context.Context) ([]storage.Event, error)
-	GetEventsByDay(ctx context.Context, date time.Time) ([]storage.Event, error)
-	GetEventsByWeek(ctx context.Context, date time.Time) ([]storage.Event, error)
-	GetEventsByMonth(ctx context.Context, date time.Time) ([]storage.Event, error)
+	ListEvents(ctx context.Context, f storage.EventFilter) ([]storage.Event, error)
+	GetUpcomingEvents(ctx context.Context, from time.Time) ([]storage.Event, error)
+	GetEventsByDay(ctx context.Context, date time.Time, userID string) ([]storage.Event, error)
+	GetEventsByWeek(ctx context.Context, date time.Time, userID string) ([]storage.Event, error)
+	GetEventsByMonth(ctx context.Context, date time.Time, userID string) ([]storage.Event, error)
+	InviteAttendees(ctx context.Context, eventID int64, userIDs []string) (storage.Event, error)
+	RespondToInvite(ctx context.Context, eventID int64, userID string, status storage.RSVPStatus) (storage.Event, error)
+	FreeBusy(ctx context.Context, q app.FreeBusyQuery) (app.FreeBusy, error)
+	ListTrash(ctx context.Context, userID string) ([]storage.Event, error)
+	RestoreEvent(ctx context.Context, userID string, id int64) (storage.Event, error)
+	EventHistory(ctx context.Context, userID string, eventID int64) ([]storage.AuditRecord, error)
+
+	CreateCalendar(ctx context.Context, c storage.Calendar) (storage.Calendar, error)
+	UpdateCalendar(ctx context.Context, actor string, c storage.Calendar) (storage.Calendar, error)
+	ListCalendars(ctx context.Context, userID string) ([]storage.Calendar, error)
+	ShareCalendar(ctx context.Context, actor string, calendarID int64, userID string, perm storage.Permission) (storage.Calendar, error)
+	ListCalendarEvents(ctx context.Context, viewer string, calendarID int64, from, to time.Time) ([]storage.Event, error)
+	WorkingDays(ctx context.Context, userID, country string, calendarID int64, loc *time.Location, from, to time.Time) (app.WorkingDays, error)
+
+	CreateWebhook(ctx context.Context, w storage.Webhook) (storage.Webhook, error)
+	ListWebhooks(ctx context.Context, userID string) ([]storage.Webhook, error)
+	DeleteWebhook(ctx context.Context, userID string, id int64) error
+	EnableWebhook(ctx context.Context, userID string, id int64) (storage.Webhook, error)
+	WebhookDeliveries(ctx context.Context, userID string, id int64) ([]storage.Delivery, error)
+
+	ListAttachments(ctx context.Context, userID string, eventID int64) ([]storage.Attachment, error)
+	AddLink(ctx context.Context, userID string, eventID int64, name, link string) (storage.Attachment, error)
+	DeleteAttachment(ctx context.Context, userID string, id int64) error
 }
 
 type Server struct {
@@ -28,65 +66,305 @@ func NewServer(app Application) *Server {
 	return &Server{app: app}
 }
 
-func (s *Server) AddEvent(ctx context.Context, req *pb.EventRequest) (*pb.Empty, error) {
-	e := req.Event
-	err := s.app.CreateEvent(ctx, e.UserId, e.Title, e.Description, e.Duration, e.NoticeBefore, e.StartAt.AsTime())
-	return &pb.Empty{}, err
+func (s *Server) AddEvent(ctx context.Context, req *pb.EventRequest) (*pb.AddEventResponse, error) {
+	e := req.GetEvent()
+	if e == nil {
+		return nil, errMissingEvent
+	}
+	attendees := make([]storage.Attendee, 0, len(e.Attendees))
+	for _, a := range e.Attendees {
+		attendees = append(attendees, storage.Attendee{UserID: a.UserId})
+	}
+	id, err := s.app.AddEvent(ctx, storage.Event{
+		UserID:        e.UserId,
+		Title:         e.Title,
+		Description:   e.Description,
+		StartDateTime: e.StartAt.AsTime(),
+		Duration:      e.Duration,
+		NoticeBefore:  e.NoticeBefore,
+		CalendarID:    e.CalendarId,
+		Attendees:     attendees,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.AddEventResponse{Id: id}, nil
 }
 
 func (s *Server) UpdateEvent(ctx context.Context, req *pb.EventRequest) (*pb.Empty, error) {
-	e := req.Event
+	e := req.GetEvent()
+	if e == nil {
+		return nil, errMissingEvent
+	}
 	err := s.app.UpdateEvent(ctx, e.UserId, e.Title, e.Description, e.Duration, e.NoticeBefore, e.StartAt.AsTime())
-	return &pb.Empty{}, err
+	return &pb.Empty{}, toStatus(err)
 }
 
 func (s *Server) DeleteEvent(ctx context.Context, req *pb.DeleteRequest) (*pb.Empty, error) {
-	return &pb.Empty{}, s.app.DeleteEvent(ctx, req.UserId, req.Start.AsTime())
+	return &pb.Empty{}, toStatus(s.app.DeleteEvent(ctx, req.UserId, req.Start.AsTime()))
+}
+
+// DeleteOldEvents удаляет старые события только пользователя из x-user-id.
+func (s *Server) DeleteOldEvents(ctx context.Context, req *pb.DeleteOldEventsRequest) (*pb.Empty, error) {
+	if identity.UserIDFromContext(ctx) == "" {
+		return nil, errMissingUser
+	}
+	return &pb.Empty{}, toStatus(s.app.DeleteOldEvents(ctx, req.Before.AsTime()))
+}
+
+func (s *Server) ListTrash(ctx context.Context, req *pb.TrashRequest) (*pb.EventsResponse, error) {
+	userID := identity.UserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errMissingUser
+	}
+	if req.UserId != "" && req.UserId != userID {
+		return nil, status.Error(codes.PermissionDenied, "cannot list trash of another user")
+	}
+	events, err := s.app.ListTrash(ctx, userID)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return convertEvents(events), nil
+}
+
+func (s *Server) RestoreEvent(ctx context.Context, req *pb.RestoreRequest) (*pb.Event, error) {
+	userID := identity.UserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errMissingUser
+	}
+	e, err := s.app.RestoreEvent(ctx, userID, req.Id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return convertEvent(e), nil
+}
+
+// GetEvent и списки событий возвращают только то, что видит пользователь из
+// x-user-id: свои события, приглашения и события календарей, расшаренных ему.
+func (s *Server) GetEvent(ctx context.Context, req *pb.GetEventRequest) (*pb.Event, error) {
+	if identity.UserIDFromContext(ctx) == "" {
+		return nil, errMissingUser
+	}
+	e, err := s.app.GetEvent(ctx, req.Id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return convertEvent(e), nil
 }
 
 func (s *Server) GetEvents(ctx context.Context, _ *pb.Empty) (*pb.EventsResponse, error) {
+	if identity.UserIDFromContext(ctx) == "" {
+		return nil, errMissingUser
+	}
 	events, err := s.app.GetEvents(ctx)
 	if err != nil {
-		return nil, err
+		return nil, toStatus(err)
+	}
+	return convertEvents(events), nil
+}
+
+func (s *Server) ListEvents(ctx context.Context, req *pb.ListEventsRequest) (*pb.EventsResponse, error) {
+	if identity.UserIDFromContext(ctx) == "" {
+		return nil, errMissingUser
+	}
+	f := storage.EventFilter{UserID: req.UserId}
+	if req.From != nil {
+		f.From = req.From.AsTime()
+	}
+	if req.To != nil {
+		f.To = req.To.AsTime()
+	}
+	events, err := s.app.ListEvents(ctx, f)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return convertEvents(events), nil
+}
+
+func (s *Server) GetUpcomingEvents(ctx context.Context, req *pb.UpcomingEventsRequest) (*pb.EventsResponse, error) {
+	if identity.UserIDFromContext(ctx) == "" {
+		return nil, errMissingUser
+	}
+	from := time.Now()
+	if req.From != nil {
+		from = req.From.AsTime()
+	}
+	events, err := s.app.GetUpcomingEvents(ctx, from)
+	if err != nil {
+		return nil, toStatus(err)
 	}
 	return convertEvents(events), nil
 }
 
 func (s *Server) GetEventsByDay(ctx context.Context, req *pb.DateRequest) (*pb.EventsResponse, error) {
-	events, err := s.app.GetEventsByDay(ctx, req.Date.AsTime())
+	if identity.UserIDFromContext(ctx) == "" {
+		return nil, errMissingUser
+	}
+	events, err := s.app.GetEventsByDay(ctx, req.Date.AsTime(), req.UserId)
 	if err != nil {
-		return nil, err
+		return nil, toStatus(err)
 	}
 	return convertEvents(events), nil
 }
 
 func (s *Server) GetEventsByWeek(ctx context.Context, req *pb.DateRequest) (*pb.EventsResponse, error) {
-	events, err := s.app.GetEventsByWeek(ctx, req.Date.AsTime())
+	if identity.UserIDFromContext(ctx) == "" {
+		return nil, errMissingUser
+	}
+	events, err := s.app.GetEventsByWeek(ctx, req.Date.AsTime(), req.UserId)
 	if err != nil {
-		return nil, err
+		return nil, toStatus(err)
 	}
 	return convertEvents(events), nil
 }
 
 func (s *Server) GetEventsByMonth(ctx context.Context, req *pb.DateRequest) (*pb.EventsResponse, error) {
-	events, err := s.app.GetEventsByMonth(ctx, req.Date.AsTime())
+	if identity.UserIDFromContext(ctx) == "" {
+		return nil, errMissingUser
+	}
+	events, err := s.app.GetEventsByMonth(ctx, req.Date.AsTime(), req.UserId)
 	if err != nil {
-		return nil, err
+		return nil, toStatus(err)
 	}
 	return convertEvents(events), nil
 }
 
+func (s *Server) InviteAttendees(ctx context.Context, req *pb.InviteRequest) (*pb.Event, error) {
+	e, err := s.app.InviteAttendees(ctx, req.EventId, req.UserIds)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return convertEvent(e), nil
+}
+
+// RespondToInvite отвечает на приглашение от имени пользователя из x-user-id:
+// за другого участника ответить нельзя.
+func (s *Server) RespondToInvite(ctx context.Context, req *pb.RespondRequest) (*pb.Event, error) {
+	userID := identity.UserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errMissingUser
+	}
+	if req.UserId != "" && req.UserId != userID {
+		return nil, status.Error(codes.PermissionDenied, "cannot respond for another user")
+	}
+	e, err := s.app.RespondToInvite(ctx, req.EventId, userID, rsvpFromProto(req.Status))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return convertEvent(e), nil
+}
+
+func (s *Server) BatchCreateEvents(ctx context.Context, req *pb.BatchCreateEventsRequest) (*pb.BatchResponse, error) {
+	events := make([]storage.Event, 0, len(req.Events))
+	for _, e := range req.Events {
+		events = append(events, storage.Event{
+			UserID:        e.UserId,
+			Title:         e.Title,
+			Description:   e.Description,
+			StartDateTime: e.StartAt.AsTime(),
+			Duration:      e.Duration,
+			NoticeBefore:  e.NoticeBefore,
+			CalendarID:    e.CalendarId,
+		})
+	}
+	return convertBatch(s.app.BatchCreateEvents(ctx, events)), nil
+}
+
+func (s *Server) BatchDeleteEvents(ctx context.Context, req *pb.BatchDeleteEventsRequest) (*pb.BatchResponse, error) {
+	return convertBatch(s.app.BatchDeleteEvents(ctx, req.Ids)), nil
+}
+
+func convertEvent(e storage.Event) *pb.Event {
+	res := &pb.Event{
+		Id:           e.EventID,
+		UserId:       e.UserID,
+		Title:        e.Title,
+		Description:  e.Description,
+		StartAt:      timestamppb.New(e.StartDateTime),
+		Duration:     e.Duration,
+		NoticeBefore: e.NoticeBefore,
+		CreatedAt:    timestamppb.New(e.CreatedAt),
+		Attendees:    convertAttendees(e.Attendees),
+		CalendarId:   e.CalendarID,
+	}
+	if !e.DeletedAt.IsZero() {
+		res.DeletedAt = timestamppb.New(e.DeletedAt)
+	}
+	return res
+}
+
+func convertAttendees(attendees []storage.Attendee) []*pb.Attendee {
+	res := make([]*pb.Attendee, 0, len(attendees))
+	for _, a := range attendees {
+		res = append(res, &pb.Attendee{UserId: a.UserID, Status: rsvpToProto(a.Status)})
+	}
+	return res
+}
+
+var rsvpStatuses = map[storage.RSVPStatus]pb.RSVPStatus{
+	storage.RSVPNeedsAction: pb.RSVPStatus_RSVP_STATUS_NEEDS_ACTION,
+	storage.RSVPAccepted:    pb.RSVPStatus_RSVP_STATUS_ACCEPTED,
+	storage.RSVPDeclined:    pb.RSVPStatus_RSVP_STATUS_DECLINED,
+	storage.RSVPTentative:   pb.RSVPStatus_RSVP_STATUS_TENTATIVE,
+}
+
+func rsvpToProto(st storage.RSVPStatus) pb.RSVPStatus {
+	return rsvpStatuses[st]
+}
+
+// rsvpFromProto для неизвестного статуса возвращает пустую строку, хранилище её отклонит.
+func rsvpFromProto(st pb.RSVPStatus) storage.RSVPStatus {
+	for k, v := range rsvpStatuses {
+		if v == st {
+			return k
+		}
+	}
+	return ""
+}
+
 func convertEvents(events []storage.Event) *pb.EventsResponse {
 	res := make([]*pb.Event, 0, len(events))
 	for _, e := range events {
-		res = append(res, &pb.Event{
-			UserId:       e.UserID,
-			Title:        e.Title,
-			Description:  e.Description,
-			StartAt:      timestamppb.New(e.StartDateTime),
-			Duration:     e.Duration,
-			NoticeBefore: e.NoticeBefore,
-		})
+		res = append(res, convertEvent(e))
 	}
 	return &pb.EventsResponse{Events: res}
 }
+
+func convertBatch(results []app.BatchResult) *pb.BatchResponse {
+	res := make([]*pb.BatchResult, 0, len(results))
+	for i, r := range results {
+		item := &pb.BatchResult{Index: int32(i), Id: r.ID} //nolint:gosec // размер пакета мал
+		if r.Err != nil {
+			item.Error = r.Err.Error()
+		}
+		res = append(res, item)
+	}
+	return &pb.BatchResponse{Results: res}
+}
+
+// Serve слушает addr и обслуживает вызовы, пока srv не остановят.
+func Serve(ctx context.Context, srv *grpc.Server, addr string) error {
+	var lc net.ListenConfig
+	lis, err := lc.Listen(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	return srv.Serve(lis)
+}
+
+// GracefulStop дожидается завершения текущих вызовов; если ctx истёк раньше,
+// оставшиеся соединения закрываются принудительно.
+func GracefulStop(ctx context.Context, srv *grpc.Server) error {
+	done := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		srv.Stop()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,128 @@
+package grpcserver
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	pb "mycalendar/api/calendarpb"
+	"mycalendar/internal/identity"
+	"mycalendar/internal/storage"
+)
+
+var errMissingWebhook = status.Error(codes.InvalidArgument, "webhook is required")
+
+func (s *Server) CreateWebhook(ctx context.Context, req *pb.WebhookRequest) (*pb.Webhook, error) {
+	w := req.GetWebhook()
+	if w == nil {
+		return nil, errMissingWebhook
+	}
+	owner := identity.UserIDFromContext(ctx)
+	if owner == "" {
+		return nil, errMissingUser
+	}
+	hook := storage.Webhook{OwnerID: owner, URL: w.Url}
+	for _, e := range w.Events {
+		hook.Events = append(hook.Events, storage.WebhookEvent(e))
+	}
+	created, err := s.app.CreateWebhook(ctx, hook)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return convertWebhook(created), nil
+}
+
+func (s *Server) ListWebhooks(ctx context.Context, _ *pb.Empty) (*pb.WebhooksResponse, error) {
+	owner := identity.UserIDFromContext(ctx)
+	if owner == "" {
+		return nil, errMissingUser
+	}
+	hooks, err := s.app.ListWebhooks(ctx, owner)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	res := &pb.WebhooksResponse{Webhooks: make([]*pb.Webhook, 0, len(hooks))}
+	for _, w := range hooks {
+		res.Webhooks = append(res.Webhooks, convertWebhook(w))
+	}
+	return res, nil
+}
+
+func (s *Server) DeleteWebhook(ctx context.Context, req *pb.WebhookIDRequest) (*pb.Empty, error) {
+	owner := identity.UserIDFromContext(ctx)
+	if owner == "" {
+		return nil, errMissingUser
+	}
+	if err := s.app.DeleteWebhook(ctx, owner, req.Id); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.Empty{}, nil
+}
+
+func (s *Server) EnableWebhook(ctx context.Context, req *pb.WebhookIDRequest) (*pb.Webhook, error) {
+	owner := identity.UserIDFromContext(ctx)
+	if owner == "" {
+		return nil, errMissingUser
+	}
+	w, err := s.app.EnableWebhook(ctx, owner, req.Id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return convertWebhook(w), nil
+}
+
+func (s *Server) ListWebhookDeliveries(ctx context.Context, req *pb.WebhookIDRequest) (*pb.DeliveriesResponse, error) {
+	owner := identity.UserIDFromContext(ctx)
+	if owner == "" {
+		return nil, errMissingUser
+	}
+	ds, err := s.app.WebhookDeliveries(ctx, owner, req.Id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	res := &pb.DeliveriesResponse{Deliveries: make([]*pb.Delivery, 0, len(ds))}
+	for _, d := range ds {
+		res.Deliveries = append(res.Deliveries, &pb.Delivery{
+			Id:            d.ID,
+			WebhookId:     d.WebhookID,
+			Event:         string(d.Event),
+			State:         string(d.State),
+			Attempts:      int32(d.Attempts),
+			NextAttemptAt: optionalTimestamp(d.NextAttempt),
+			LastStatus:    int32(d.LastStatus),
+			LastError:     d.LastError,
+			CreatedAt:     timestamppb.New(d.CreatedAt),
+			DeliveredAt:   optionalTimestamp(d.DeliveredAt),
+			Payload:       string(d.Payload),
+		})
+	}
+	return res, nil
+}
+
+func convertWebhook(w storage.Webhook) *pb.Webhook {
+	res := &pb.Webhook{
+		Id:         w.ID,
+		OwnerId:    w.OwnerID,
+		Url:        w.URL,
+		Secret:     w.Secret,
+		Active:     w.Active,
+		Failures:   int32(w.Failures),
+		DisabledAt: optionalTimestamp(w.DisabledAt),
+		CreatedAt:  timestamppb.New(w.CreatedAt),
+		Events:     make([]string, 0, len(w.Events)),
+	}
+	for _, e := range w.Events {
+		res.Events = append(res.Events, string(e))
+	}
+	return res
+}
+
+// optionalTimestamp - nil для нулевого времени.
+func optionalTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,56 @@
+package grpcserver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	pb "mycalendar/api/calendarpb"
+	"mycalendar/internal/identity"
+)
+
+func (s *Server) GetWorkingDays(ctx context.Context, req *pb.WorkingDaysRequest) (*pb.WorkingDaysResponse, error) {
+	userID := identity.UserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errMissingUser
+	}
+	from, err := parseDate("from", req.From)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	to, err := parseDate("to", req.To)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	wd, err := s.app.WorkingDays(ctx, userID, req.Country, req.CalendarId, nil, from, to)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	res := &pb.WorkingDaysResponse{Country: wd.Country, Days: make([]*pb.WorkingDay, 0, len(wd.Days))}
+	for _, d := range wd.Days {
+		res.Days = append(res.Days, &pb.WorkingDay{
+			Date:    d.Date.Format(time.DateOnly),
+			Working: d.Working(),
+			Weekend: d.Weekend,
+			Holiday: d.Holiday,
+		})
+		if d.Working() {
+			res.WorkingCount++
+		}
+	}
+	return res, nil
+}
+
+func parseDate(field, s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, fmt.Errorf("%s is required", field)
+	}
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", field, err)
+	}
+	return t, nil
+}
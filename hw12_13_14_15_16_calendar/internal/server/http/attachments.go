@@ -0,0 +1,131 @@
+package internalhttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"mycalendar/internal/app"
+	"mycalendar/internal/blob"
+	"mycalendar/internal/identity"
+	"mycalendar/internal/storage"
+)
+
+// AttachmentApp - загрузка и скачивание файлов вложений. Как и импорт, они идут
+// мимо gateway: содержимое читается и пишется потоком. Список, ссылки и удаление
+// вложений - в gateway.
+type AttachmentApp interface {
+	AddAttachment(ctx context.Context, userID string, eventID int64, name, contentType string, r io.Reader) (storage.Attachment, error)
+	OpenAttachment(ctx context.Context, userID string, id int64) (storage.Attachment, io.ReadCloser, error)
+}
+
+type attachmentHandler struct {
+	app    AttachmentApp
+	logger Logger
+}
+
+// attachmentResponse - поля как у Attachment в gateway.
+type attachmentResponse struct {
+	ID          int64     `json:"id"`
+	EventID     int64     `json:"eventId"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"contentType"`
+	Size        int64     `json:"size"`
+	CreatedBy   string    `json:"createdBy"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// upload - POST /events/{id}/attachments?name=agenda.pdf, тело - содержимое файла.
+// Без name имя берётся из Content-Disposition.
+func (h *attachmentHandler) upload(w http.ResponseWriter, r *http.Request) {
+	userID := identity.UserIDFromContext(r.Context())
+	if userID == "" {
+		http.Error(w, identity.UserIDHeader+" is required", http.StatusUnauthorized)
+		return
+	}
+	eventID, _ := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		if _, params, err := mime.ParseMediaType(r.Header.Get("Content-Disposition")); err == nil {
+			name = params["filename"]
+		}
+	}
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	a, err := h.app.AddAttachment(r.Context(), userID, eventID, name, contentType, r.Body)
+	if err != nil {
+		h.fail(w, "upload failed", userID, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(attachmentResponse{
+		ID:          a.ID,
+		EventID:     a.EventID,
+		Name:        a.Name,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		CreatedBy:   a.CreatedBy,
+		CreatedAt:   a.CreatedAt,
+	})
+}
+
+// download - GET /attachments/{id}/content.
+func (h *attachmentHandler) download(w http.ResponseWriter, r *http.Request) {
+	userID := identity.UserIDFromContext(r.Context())
+	if userID == "" {
+		http.Error(w, identity.UserIDHeader+" is required", http.StatusUnauthorized)
+		return
+	}
+	id, _ := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	a, content, err := h.app.OpenAttachment(r.Context(), userID, id)
+	if err != nil {
+		h.fail(w, "download failed", userID, err)
+		return
+	}
+	defer content.Close()
+
+	// браузер должен сохранить файл, а не показать его на нашем домене
+	w.Header().Set("Content-Type", a.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.Name}))
+	w.Header().Set("Content-Length", strconv.FormatInt(a.Size, 10))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if _, err := io.Copy(w, content); err != nil {
+		h.logger.Error("download interrupted", "user_id", userID, "attachment_id", id, "err", err)
+	}
+}
+
+func (h *attachmentHandler) fail(w http.ResponseWriter, msg, userID string, err error) {
+	status := attachmentStatus(err)
+	if status == http.StatusInternalServerError {
+		h.logger.Error(msg, "user_id", userID, "err", err)
+	}
+	http.Error(w, err.Error(), status)
+}
+
+func attachmentStatus(err error) int {
+	switch {
+	case errors.Is(err, storage.ErrNotFound), errors.Is(err, storage.ErrAttachmentNotFound),
+		errors.Is(err, blob.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, app.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, app.ErrInvalidAttachment):
+		return http.StatusBadRequest
+	case errors.Is(err, app.ErrAttachmentTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, app.ErrAttachmentsOff):
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,180 @@
+package internalhttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"mycalendar/internal/app"
+	"mycalendar/internal/bulk"
+	"mycalendar/internal/identity"
+	"mycalendar/internal/storage"
+)
+
+// BulkApp - массовые загрузка и выгрузка событий. Они идут мимо gateway:
+// тело читается и ответ пишется потоком, а не одним сообщением.
+type BulkApp interface {
+	ImportEvents(ctx context.Context, userID string, dec bulk.Decoder, dryRun bool) (app.ImportReport, error)
+	ExportEvents(ctx context.Context, f storage.EventFilter, fn func(storage.Event) error) error
+}
+
+type bulkHandler struct {
+	app      BulkApp
+	logger   Logger
+	maxBytes int64
+}
+
+type rowError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+type importResponse struct {
+	Imported int        `json:"imported"`
+	Failed   int        `json:"failed"`
+	DryRun   bool       `json:"dryRun"`
+	Errors   []rowError `json:"errors,omitempty"`
+	Error    string     `json:"error,omitempty"` // загрузка прервана, отчёт - о том, что успело загрузиться
+}
+
+// importEvents - POST /events/import?format=csv|ndjson&dry_run=true. Формат
+// берётся из параметра или Content-Type.
+func (h *bulkHandler) importEvents(w http.ResponseWriter, r *http.Request) {
+	userID := identity.UserIDFromContext(r.Context())
+	if userID == "" {
+		http.Error(w, identity.UserIDHeader+" is required", http.StatusUnauthorized)
+		return
+	}
+	format, err := requestFormat(r, r.Header.Get("Content-Type"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var dryRun bool
+	if s := r.URL.Query().Get("dry_run"); s != "" {
+		if dryRun, err = strconv.ParseBool(s); err != nil {
+			http.Error(w, "dry_run: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	body := r.Body
+	if h.maxBytes > 0 {
+		body = http.MaxBytesReader(w, r.Body, h.maxBytes)
+	}
+	report, err := h.app.ImportEvents(r.Context(), userID, bulk.NewDecoder(body, format), dryRun)
+
+	resp := importResponse{Imported: report.Imported, Failed: report.Failed, DryRun: report.DryRun}
+	for _, e := range report.Errors {
+		resp.Errors = append(resp.Errors, rowError{Line: e.Line, Error: e.Err.Error()})
+	}
+	status := http.StatusOK
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		} else {
+			status = http.StatusInternalServerError
+			h.logger.Error("import failed", "user_id", userID, "imported", report.Imported, "err", err)
+		}
+		resp.Error = err.Error()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// exportEvents - GET /events/export?format=csv|ndjson&from=&to=&calendar_id=,
+// события пользователя из X-User-ID, которыми он владеет.
+func (h *bulkHandler) exportEvents(w http.ResponseWriter, r *http.Request) {
+	userID := identity.UserIDFromContext(r.Context())
+	if userID == "" {
+		http.Error(w, identity.UserIDHeader+" is required", http.StatusUnauthorized)
+		return
+	}
+	format, err := requestFormat(r, r.Header.Get("Accept"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	f, err := exportFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	f.UserID = userID
+
+	w.Header().Set("Content-Type", format.ContentType())
+	out := &sentWriter{w: w}
+	enc := bulk.NewEncoder(out, format)
+	flusher, _ := w.(http.Flusher)
+	n := 0
+	err = h.app.ExportEvents(r.Context(), f, func(e storage.Event) error {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+		if n++; n%100 == 0 && flusher != nil {
+			if err := enc.Flush(); err != nil {
+				return err
+			}
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = enc.Flush()
+	}
+	if err != nil {
+		h.logger.Error("export failed", "user_id", userID, "exported", n, "err", err)
+		if !out.sent { // иначе статус уже ушёл, клиент увидит оборванный файл
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+// sentWriter запоминает, начался ли ответ.
+type sentWriter struct {
+	w    http.ResponseWriter
+	sent bool
+}
+
+func (s *sentWriter) Write(p []byte) (int, error) {
+	s.sent = true
+	return s.w.Write(p)
+}
+
+// requestFormat - параметр format, иначе MIME тип из заголовка, по умолчанию ndjson.
+func requestFormat(r *http.Request, mime string) (bulk.Format, error) {
+	if s := r.URL.Query().Get("format"); s != "" {
+		return bulk.ParseFormat(s)
+	}
+	if f, err := bulk.ParseFormat(mime); err == nil {
+		return f, nil
+	}
+	return bulk.NDJSON, nil
+}
+
+func exportFilter(r *http.Request) (storage.EventFilter, error) {
+	var f storage.EventFilter
+	q := r.URL.Query()
+	var err error
+	if s := q.Get("from"); s != "" {
+		if f.From, err = time.Parse(time.RFC3339, s); err != nil {
+			return f, errors.New("from: use RFC3339")
+		}
+	}
+	if s := q.Get("to"); s != "" {
+		if f.To, err = time.Parse(time.RFC3339, s); err != nil {
+			return f, errors.New("to: use RFC3339")
+		}
+	}
+	if s := q.Get("calendar_id"); s != "" {
+		if f.CalendarID, err = strconv.ParseInt(s, 10, 64); err != nil {
+			return f, errors.New("calendar_id: must be a number")
+		}
+	}
+	return f, nil
+}
@@ -2,32 +2,45 @@ package internalhttp_test
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 	"mycalendar/internal/app"
+	"mycalendar/internal/blob"
+	"mycalendar/internal/ratelimit"
+	grpcserver "mycalendar/internal/server/grpc"
 	internalhttp "mycalendar/internal/server/http"
+	"mycalendar/internal/storage"
 	memorystorage "mycalendar/internal/storage/memory"
+	"mycalendar/internal/tenant"
 )
 
-type testLogger struct{}
-
-func (testLogger) Printf(_ string, _ ...any) {}
-func (testLogger) Info(_ string)             {}
-func (testLogger) Error(_ string)            {}
+// eventsResponse - ответ gateway на запросы списков событий (EventsResponse в JSON).
+type eventsResponse struct {
+	Events []map[string]any `json:"events"`
+}
 
 func TestIntegration_CreateGetDeleteEvent(t *testing.T) {
 	// Создаём реальные компоненты
 	store := memorystorage.New()
-	logger := testLogger{}
+	logger := slog.New(slog.DiscardHandler)
 	appInstance, err := app.New(logger, store)
 	require.NoError(t, err)
 
-	server := internalhttp.NewServer(logger, appInstance, "localhost", "8080")
+	gateway, err := grpcserver.NewGateway(context.Background(), grpcserver.NewServer(appInstance))
+	require.NoError(t, err)
+	server := internalhttp.NewServer(logger, gateway, "localhost", "8080", internalhttp.Options{})
 
 	// --- POST /events: создаём событие
 	startAt := time.Now().UTC().Truncate(time.Second)
@@ -43,49 +56,55 @@ func TestIntegration_CreateGetDeleteEvent(t *testing.T) {
 	require.NoError(t, err)
 
 	req := httptest.NewRequest(http.MethodPost, "/events", bytes.NewReader(body))
+	req.Header.Set("X-User-ID", "user1")
 	req.Header.Set("Content-Type", "application/json")
 	rec := httptest.NewRecorder()
 	server.Handler().ServeHTTP(rec, req)
-	require.Equal(t, http.StatusCreated, rec.Code)
+	require.Equal(t, http.StatusOK, rec.Code)
 
 	// --- GET /events: проверяем, что событие есть
 	req = httptest.NewRequest(http.MethodGet, "/events", nil)
+	req.Header.Set("X-User-ID", "user1")
 	rec = httptest.NewRecorder()
 	server.Handler().ServeHTTP(rec, req)
 	require.Equal(t, http.StatusOK, rec.Code)
 
-	var events []map[string]any
+	var events eventsResponse
 	err = json.NewDecoder(rec.Body).Decode(&events)
 	require.NoError(t, err)
-	require.Len(t, events, 1)
-	require.Equal(t, "Integration test event", events[0]["Title"])
+	require.Len(t, events.Events, 1)
+	require.Equal(t, "Integration test event", events.Events[0]["title"])
 
 	// --- DELETE /events: удаляем событие
-	url := "/events?userId=user1&startAt=" + startAt.Format(time.RFC3339)
+	url := "/events?userId=user1&start=" + startAt.Format(time.RFC3339)
 	req = httptest.NewRequest(http.MethodDelete, url, nil)
+	req.Header.Set("X-User-ID", "user1")
 	rec = httptest.NewRecorder()
 	server.Handler().ServeHTTP(rec, req)
-	require.Equal(t, http.StatusNoContent, rec.Code)
+	require.Equal(t, http.StatusOK, rec.Code)
 
 	// --- GET /events: проверяем, что событий нет
 	req = httptest.NewRequest(http.MethodGet, "/events", nil)
+	req.Header.Set("X-User-ID", "user1")
 	rec = httptest.NewRecorder()
 	server.Handler().ServeHTTP(rec, req)
 	require.Equal(t, http.StatusOK, rec.Code)
 
 	err = json.NewDecoder(rec.Body).Decode(&events)
 	require.NoError(t, err)
-	require.Len(t, events, 0)
+	require.Len(t, events.Events, 0)
 }
 
 func TestIntegration_UpdateEvent(t *testing.T) {
 	// Setup
 	store := memorystorage.New()
-	logger := testLogger{}
+	logger := slog.New(slog.DiscardHandler)
 	appInstance, err := app.New(logger, store)
 	require.NoError(t, err)
 
-	server := internalhttp.NewServer(logger, appInstance, "localhost", "8080")
+	gateway, err := grpcserver.NewGateway(context.Background(), grpcserver.NewServer(appInstance))
+	require.NoError(t, err)
+	server := internalhttp.NewServer(logger, gateway, "localhost", "8080", internalhttp.Options{})
 	startAt := time.Now().UTC().Truncate(time.Second)
 
 	// Step 1: Create event
@@ -101,10 +120,11 @@ func TestIntegration_UpdateEvent(t *testing.T) {
 	require.NoError(t, err)
 
 	req := httptest.NewRequest(http.MethodPost, "/events", bytes.NewReader(body))
+	req.Header.Set("X-User-ID", "user1")
 	req.Header.Set("Content-Type", "application/json")
 	rec := httptest.NewRecorder()
 	server.Handler().ServeHTTP(rec, req)
-	require.Equal(t, http.StatusCreated, rec.Code)
+	require.Equal(t, http.StatusOK, rec.Code)
 
 	// Step 2: Update event
 	updateEvent := map[string]any{
@@ -119,6 +139,7 @@ func TestIntegration_UpdateEvent(t *testing.T) {
 	require.NoError(t, err)
 
 	req = httptest.NewRequest(http.MethodPut, "/events", bytes.NewReader(body))
+	req.Header.Set("X-User-ID", "user1")
 	req.Header.Set("Content-Type", "application/json")
 	rec = httptest.NewRecorder()
 	server.Handler().ServeHTTP(rec, req)
@@ -126,37 +147,41 @@ func TestIntegration_UpdateEvent(t *testing.T) {
 
 	// Step 3: Fetch events and verify update
 	req = httptest.NewRequest(http.MethodGet, "/events", nil)
+	req.Header.Set("X-User-ID", "user1")
 	rec = httptest.NewRecorder()
 	server.Handler().ServeHTTP(rec, req)
 	require.Equal(t, http.StatusOK, rec.Code)
 
-	var events []map[string]any
+	var events eventsResponse
 	err = json.NewDecoder(rec.Body).Decode(&events)
 	require.NoError(t, err)
-	require.Len(t, events, 1)
+	require.Len(t, events.Events, 1)
 
-	updated := events[0]
-	require.Equal(t, "Updated Title", updated["Title"])
-	require.Equal(t, "Updated description", updated["Description"])
-	require.Equal(t, "2h", updated["Duration"])
-	require.Equal(t, float64(20), updated["NoticeBefore"])
+	updated := events.Events[0]
+	require.Equal(t, "Updated Title", updated["title"])
+	require.Equal(t, "Updated description", updated["description"])
+	require.Equal(t, "2h", updated["duration"])
+	require.Equal(t, float64(20), updated["noticeBefore"])
 
 	// Cleanup
-	url := "/events?userId=user1&startAt=" + startAt.Format(time.RFC3339)
+	url := "/events?userId=user1&start=" + startAt.Format(time.RFC3339)
 	req = httptest.NewRequest(http.MethodDelete, url, nil)
+	req.Header.Set("X-User-ID", "user1")
 	rec = httptest.NewRecorder()
 	server.Handler().ServeHTTP(rec, req)
-	require.Equal(t, http.StatusNoContent, rec.Code)
+	require.Equal(t, http.StatusOK, rec.Code)
 }
 
 func TestIntegration_GetEventsByDayWeekMonth(t *testing.T) {
 	// Setup components
 	store := memorystorage.New()
-	logger := testLogger{}
+	logger := slog.New(slog.DiscardHandler)
 	appInstance, err := app.New(logger, store)
 	require.NoError(t, err)
 
-	server := internalhttp.NewServer(logger, appInstance, "localhost", "8080")
+	gateway, err := grpcserver.NewGateway(context.Background(), grpcserver.NewServer(appInstance))
+	require.NoError(t, err)
+	server := internalhttp.NewServer(logger, gateway, "localhost", "8080", internalhttp.Options{})
 
 	startAt := time.Now().UTC().Truncate(time.Second)
 
@@ -173,24 +198,26 @@ func TestIntegration_GetEventsByDayWeekMonth(t *testing.T) {
 	require.NoError(t, err)
 
 	req := httptest.NewRequest(http.MethodPost, "/events", bytes.NewReader(body))
+	req.Header.Set("X-User-ID", "user1")
 	req.Header.Set("Content-Type", "application/json")
 	rec := httptest.NewRecorder()
 	server.Handler().ServeHTTP(rec, req)
-	require.Equal(t, http.StatusCreated, rec.Code)
+	require.Equal(t, http.StatusOK, rec.Code)
 
 	// Helper to test a given path
 	testRangeEndpoint := func(path string) {
-		url := path + "?date=" + startAt.Format("2006-01-02")
+		url := path + "?date=" + startAt.Format(time.RFC3339)
 		req := httptest.NewRequest(http.MethodGet, url, nil)
+		req.Header.Set("X-User-ID", "user1")
 		rec := httptest.NewRecorder()
 		server.Handler().ServeHTTP(rec, req)
 		require.Equal(t, http.StatusOK, rec.Code)
 
-		var events []map[string]any
+		var events eventsResponse
 		err := json.NewDecoder(rec.Body).Decode(&events)
 		require.NoError(t, err)
-		require.Len(t, events, 1)
-		require.Equal(t, "Event for range testing", events[0]["Title"])
+		require.Len(t, events.Events, 1)
+		require.Equal(t, "Event for range testing", events.Events[0]["title"])
 	}
 
 	// Test day/week/month endpoints
@@ -199,9 +226,324 @@ func TestIntegration_GetEventsByDayWeekMonth(t *testing.T) {
 	testRangeEndpoint("/events/month")
 
 	// Cleanup
-	url := "/events?userId=user1&startAt=" + startAt.Format(time.RFC3339)
+	url := "/events?userId=user1&start=" + startAt.Format(time.RFC3339)
 	req = httptest.NewRequest(http.MethodDelete, url, nil)
+	req.Header.Set("X-User-ID", "user1")
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestIntegration_RequestID(t *testing.T) {
+	store := memorystorage.New()
+	logger := slog.New(slog.DiscardHandler)
+	appInstance, err := app.New(logger, store)
+	require.NoError(t, err)
+
+	gateway, err := grpcserver.NewGateway(context.Background(), grpcserver.NewServer(appInstance))
+	require.NoError(t, err)
+	server := internalhttp.NewServer(logger, gateway, "localhost", "8080", internalhttp.Options{})
+
+	// ID из запроса возвращается клиенту
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	req.Header.Set("X-Request-ID", "req-1")
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	require.Equal(t, "req-1", rec.Header().Get("X-Request-ID"))
+
+	// без заголовка ID генерируется
+	req = httptest.NewRequest(http.MethodGet, "/events", nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	require.NotEmpty(t, rec.Header().Get("X-Request-ID"))
+}
+
+func TestIntegration_GetEventByID(t *testing.T) {
+	store := memorystorage.New()
+	logger := slog.New(slog.DiscardHandler)
+	appInstance, err := app.New(logger, store)
+	require.NoError(t, err)
+
+	gateway, err := grpcserver.NewGateway(context.Background(), grpcserver.NewServer(appInstance))
+	require.NoError(t, err)
+	server := internalhttp.NewServer(logger, gateway, "localhost", "8080", internalhttp.Options{})
+
+	body, err := json.Marshal(map[string]any{
+		"userId":  "user1",
+		"title":   "By id",
+		"startAt": time.Now().UTC().Truncate(time.Second).Format(time.RFC3339),
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/events", bytes.NewReader(body))
+	req.Header.Set("X-User-ID", "user1")
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// int64 в protojson передаётся строкой
+	var created struct {
+		ID string `json:"id"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&created))
+	require.NotEmpty(t, created.ID)
+
+	req = httptest.NewRequest(http.MethodGet, "/events/"+created.ID, nil)
+	req.Header.Set("X-User-ID", "user1")
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var event map[string]any
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&event))
+	require.Equal(t, "By id", event["title"])
+	require.Equal(t, created.ID, event["id"])
+
+	// чужое событие не отдаётся, без X-User-ID - тоже
+	req = httptest.NewRequest(http.MethodGet, "/events/"+created.ID, nil)
+	req.Header.Set("X-User-ID", "user2")
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	require.Equal(t, http.StatusForbidden, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/events/"+created.ID, nil)
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// коды gRPC переводятся в HTTP статусы
+	req = httptest.NewRequest(http.MethodGet, "/events/999", nil)
+	req.Header.Set("X-User-ID", "user1")
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/events", bytes.NewReader(body))
+	req.Header.Set("X-User-ID", "user1")
 	rec = httptest.NewRecorder()
 	server.Handler().ServeHTTP(rec, req)
-	require.Equal(t, http.StatusNoContent, rec.Code)
+	require.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestIntegration_Tenants(t *testing.T) {
+	store := memorystorage.New()
+	logger := slog.New(slog.DiscardHandler)
+	appInstance, err := app.New(logger, store)
+	require.NoError(t, err)
+
+	gateway, err := grpcserver.NewGateway(context.Background(), grpcserver.NewServer(appInstance))
+	require.NoError(t, err)
+	server := internalhttp.NewServer(logger, gateway, "localhost", "8080", internalhttp.Options{
+		Tenants: tenant.NewSet([]string{tenant.Default, "acme"}, map[string]string{"billing": "acme"}),
+	})
+	// client - CN клиентского сертификата, пустой - запрос без сертификата
+	do := func(method, path, client, tenantID string, body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("X-User-ID", "user1")
+		if client != "" {
+			req.TLS = &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: client}}},
+			}
+		}
+		if tenantID != "" {
+			req.Header.Set(tenant.Header, tenantID)
+		}
+		rec := httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"userId":  "user1",
+		"title":   "Acme",
+		"startAt": time.Now().UTC().Truncate(time.Second).Format(time.RFC3339),
+	})
+	require.NoError(t, err)
+	rec := do(http.MethodPost, "/events", "billing", "", body)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var created struct {
+		ID string `json:"id"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&created))
+
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/events/"+created.ID, "billing", "acme", nil).Code)
+	require.Equal(t, http.StatusNotFound, do(http.MethodGet, "/events/"+created.ID, "", "", nil).Code)
+	// без сертификата назваться другим тенантом нельзя
+	require.Equal(t, http.StatusUnauthorized, do(http.MethodGet, "/events/"+created.ID, "", "acme", nil).Code)
+	require.Equal(t, http.StatusUnauthorized, do(http.MethodGet, "/events/"+created.ID, "crm", "acme", nil).Code)
+	require.Equal(t, http.StatusForbidden, do(http.MethodGet, "/events/"+created.ID, "billing", tenant.Default, nil).Code)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/events/"+created.ID, "", "Acme Corp", nil).Code)
+}
+
+func TestIntegration_Limits(t *testing.T) {
+	store := memorystorage.New()
+	logger := slog.New(slog.DiscardHandler)
+	appInstance, err := app.New(logger, store)
+	require.NoError(t, err)
+
+	gateway, err := grpcserver.NewGateway(context.Background(), grpcserver.NewServer(appInstance))
+	require.NoError(t, err)
+	server := internalhttp.NewServer(logger, gateway, "localhost", "8080", internalhttp.Options{
+		Limiter:      ratelimit.New(0.1, 2),
+		MaxBodyBytes: 64,
+	})
+
+	get := func(addr, user string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/events", nil)
+		req.RemoteAddr = addr
+		req.Header.Set("X-User-ID", user)
+		rec := httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	require.Equal(t, http.StatusOK, get("10.0.0.1:1000", "alice").Code)
+	require.Equal(t, http.StatusOK, get("10.0.0.1:1001", "alice").Code)
+	rec := get("10.0.0.1:1002", "alice")
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	require.Equal(t, "10", rec.Header().Get("Retry-After"))
+
+	// сменой X-User-ID лимит не обойти, он считается на адрес клиента
+	require.Equal(t, http.StatusTooManyRequests, get("10.0.0.1:1003", "bob").Code)
+	require.Equal(t, http.StatusOK, get("10.0.0.2:1000", "bob").Code)
+
+	// слишком большое тело отклоняется до разбора
+	body := bytes.Repeat([]byte("x"), 65)
+	req := httptest.NewRequest(http.MethodPost, "/events", bytes.NewReader(body))
+	req.Header.Set("X-User-ID", "carol")
+	rec = httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
+
+func TestIntegration_BulkImportExport(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+	appInstance, err := app.New(logger, memorystorage.New())
+	require.NoError(t, err)
+	gateway, err := grpcserver.NewGateway(context.Background(), grpcserver.NewServer(appInstance))
+	require.NoError(t, err)
+	server := internalhttp.NewServer(logger, gateway, "localhost", "8080", internalhttp.Options{
+		MaxBodyBytes:   64,
+		MaxImportBytes: 1024,
+		Bulk:           appInstance,
+	})
+	do := func(method, target, user, contentType, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if user != "" {
+			req.Header.Set("X-User-ID", user)
+		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		rec := httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	// тело больше MaxBodyBytes: у импорта свой лимит
+	csv := "title,start,duration\n" +
+		"Standup,2030-01-02T09:00:00Z,15m\n" +
+		"Review,2030-01-02T10:00:00Z,1h\n" +
+		"Broken,tomorrow,1h\n"
+	rec := do(http.MethodPost, "/events/import?dry_run=true", "alice", "text/csv", csv)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"imported":2,"failed":1,"dryRun":true,
+		"errors":[{"line":4,"error":"start: parsing time \"tomorrow\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"tomorrow\" as \"2006\""}]}`,
+		rec.Body.String())
+
+	rec = do(http.MethodPost, "/events/import?format=csv", "alice", "", csv)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"imported":2`)
+
+	rec = do(http.MethodGet, "/events/export?format=ndjson&from=2030-01-02T09:30:00Z", "alice", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	require.Len(t, lines, 1)
+	require.Contains(t, lines[0], `"title":"Review"`)
+
+	require.Equal(t, http.StatusUnauthorized, do(http.MethodPost, "/events/import", "", "text/csv", csv).Code)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/events/export?format=xml", "alice", "", "").Code)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/events/export?from=today", "alice", "", "").Code)
+
+	big := "title,start\n" + strings.Repeat("x,2030-01-02T09:00:00Z\n", 100)
+	rec = do(http.MethodPost, "/events/import", "alice", "text/csv", big)
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	require.Contains(t, rec.Body.String(), `"error"`)
+}
+
+func TestIntegration_Attachments(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+	appInstance, err := app.New(logger, memorystorage.New())
+	require.NoError(t, err)
+	blobs, err := blob.NewFS(t.TempDir())
+	require.NoError(t, err)
+	appInstance.SetAttachments(blobs, app.AttachmentLimits{MaxBytes: 128, Types: []string{"text/plain", "application/pdf"}})
+	gateway, err := grpcserver.NewGateway(context.Background(), grpcserver.NewServer(appInstance))
+	require.NoError(t, err)
+	server := internalhttp.NewServer(logger, gateway, "localhost", "8080", internalhttp.Options{
+		MaxBodyBytes: 64,
+		Attachments:  appInstance,
+	})
+	do := func(method, target, user, contentType, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if user != "" {
+			req.Header.Set("X-User-ID", user)
+		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		rec := httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	id, err := appInstance.AddEvent(context.Background(), storage.Event{
+		UserID: "alice", Title: "Planning", StartDateTime: time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC), Duration: "1h",
+	})
+	require.NoError(t, err)
+	events := "/events/" + strconv.FormatInt(id, 10)
+
+	// файл больше MaxBodyBytes: у вложений свой лимит
+	agenda := strings.Repeat("agenda ", 12)
+	rec := do(http.MethodPost, events+"/attachments?name=agenda.txt", "alice", "text/plain", agenda)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var file struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+		Size int64  `json:"size"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &file))
+	require.Equal(t, "agenda.txt", file.Name)
+	require.EqualValues(t, len(agenda), file.Size)
+
+	require.Equal(t, http.StatusRequestEntityTooLarge,
+		do(http.MethodPost, events+"/attachments?name=big.txt", "alice", "text/plain", strings.Repeat("x", 129)).Code)
+	require.Equal(t, http.StatusBadRequest,
+		do(http.MethodPost, events+"/attachments?name=x.exe", "alice", "application/x-msdownload", "MZ").Code)
+	require.Equal(t, http.StatusForbidden,
+		do(http.MethodPost, events+"/attachments?name=a.txt", "bob", "text/plain", "a").Code)
+	require.Equal(t, http.StatusNotFound,
+		do(http.MethodPost, "/events/999/attachments?name=a.txt", "alice", "text/plain", "a").Code)
+	require.Equal(t, http.StatusUnauthorized,
+		do(http.MethodPost, events+"/attachments?name=a.txt", "", "text/plain", "a").Code)
+
+	// ссылки, список и удаление - через gateway
+	rec = do(http.MethodPost, events+"/links", "alice", "application/json", `{"url":"https://example.com/doc"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = do(http.MethodGet, events+"/attachments", "alice", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"name":"agenda.txt"`)
+	require.Contains(t, rec.Body.String(), `"url":"https://example.com/doc"`)
+
+	content := "/attachments/" + strconv.FormatInt(file.ID, 10)
+	rec = do(http.MethodGet, content+"/content", "alice", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, agenda, rec.Body.String())
+	require.Equal(t, "text/plain", rec.Header().Get("Content-Type"))
+	require.Equal(t, `attachment; filename=agenda.txt`, rec.Header().Get("Content-Disposition"))
+	require.Equal(t, http.StatusForbidden, do(http.MethodGet, content+"/content", "bob", "", "").Code)
+
+	require.Equal(t, http.StatusOK, do(http.MethodDelete, content, "alice", "", "").Code)
+	require.Equal(t, http.StatusNotFound, do(http.MethodGet, content+"/content", "alice", "", "").Code)
 }
@@ -1,12 +1,33 @@
 package internalhttp
 
 import (
+	"crypto/x509"
+	"errors"
 	"net"
 	"net/http"
+	"strconv"
 	"time"
+
+	"mycalendar/internal/identity"
+	"mycalendar/internal/logger"
+	"mycalendar/internal/ratelimit"
+	"mycalendar/internal/tenant"
 )
 
-func loggingMiddleware(logger Logger) func(http.Handler) http.Handler {
+// requestIDMiddleware берёт ID запроса из заголовка или генерирует новый,
+// кладёт его в контекст и возвращает клиенту.
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(logger.RequestIDHeader)
+		if id == "" {
+			id = logger.NewRequestID()
+		}
+		w.Header().Set(logger.RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(logger.WithRequestID(r.Context(), id)))
+	})
+}
+
+func loggingMiddleware(log Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
@@ -14,8 +35,6 @@ func loggingMiddleware(logger Logger) func(http.Handler) http.Handler {
 			lrw := &loggingResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
 			next.ServeHTTP(lrw, r)
 
-			duration := time.Since(start)
-
 			ip, _, err := net.SplitHostPort(r.RemoteAddr)
 			if err != nil {
 				ip = r.RemoteAddr
@@ -26,15 +45,14 @@ func loggingMiddleware(logger Logger) func(http.Handler) http.Handler {
 				userAgent = "-"
 			}
 
-			logger.Printf(`%s [%s] %s %s %s %d %d "%s"`,
-				ip,
-				time.Now().Format("02/Jan/2006:15:04:05 -0700"),
-				r.Method,
-				r.RequestURI,
-				r.Proto,
-				lrw.statusCode,
-				duration.Milliseconds(),
-				userAgent,
+			log.InfoContext(r.Context(), "http request",
+				"ip", ip,
+				"method", r.Method,
+				"uri", r.RequestURI,
+				"proto", r.Proto,
+				"status", lrw.statusCode,
+				"duration_ms", time.Since(start).Milliseconds(),
+				"user_agent", userAgent,
 			)
 		})
 	}
@@ -49,3 +67,78 @@ func (lrw *loggingResponseWriter) WriteHeader(code int) {
 	lrw.statusCode = code
 	lrw.ResponseWriter.WriteHeader(code)
 }
+
+// identityMiddleware кладёт в контекст ID пользователя из заголовка X-User-ID.
+func identityMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := r.Header.Get(identity.UserIDHeader); id != "" {
+			r = r.WithContext(identity.WithUserID(r.Context(), id))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// tenantMiddleware кладёт в контекст тенанта из клиентского сертификата или
+// заголовка X-Tenant-ID и отклоняет запросы тенантов не из tenants и запросы
+// без сертификата, если тенантов несколько.
+func tenantMiddleware(tenants tenant.Set) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var certs []*x509.Certificate
+			if r.TLS != nil {
+				certs = r.TLS.PeerCertificates
+			}
+			id, err := tenants.Resolve(r.Header.Get(tenant.Header), certs)
+			if err != nil {
+				code := http.StatusForbidden
+				switch {
+				case errors.Is(err, tenant.ErrInvalid):
+					code = http.StatusBadRequest
+				case errors.Is(err, tenant.ErrUnauthenticated):
+					code = http.StatusUnauthorized
+				}
+				http.Error(w, err.Error(), code)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(tenant.With(r.Context(), id)))
+		})
+	}
+}
+
+// rateLimitMiddleware ограничивает частоту запросов каждого клиента и отвечает
+// 429 с Retry-After, когда лимит исчерпан.
+func rateLimitMiddleware(limiter *ratelimit.Limiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ok, wait := limiter.Allow(clientKey(r)); !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(ratelimit.RetryAfter(wait)))
+				http.Error(w, "too many requests", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// bodyLimitMiddleware не даёт прочитать тело запроса больше limit байт.
+func bodyLimitMiddleware(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > limit {
+				http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientKey - ключ лимита по соединению: сертификат клиента или IP.
+func clientKey(r *http.Request) string {
+	var certs []*x509.Certificate
+	if r.TLS != nil {
+		certs = r.TLS.PeerCertificates
+	}
+	return ratelimit.ClientKey(r.RemoteAddr, certs)
+}
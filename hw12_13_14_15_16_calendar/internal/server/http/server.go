@@ -2,69 +2,92 @@ package internalhttp
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"net"
 	"net/http"
 	"time"
 
 	"github.com/gorilla/mux"
-	"mycalendar/internal/storage"
+	"mycalendar/internal/ratelimit"
+	"mycalendar/internal/tenant"
 )
 
 type Logger interface {
-	Printf(format string, v ...any)
-	Info(msg string)
-	Error(msg string)
+	Info(msg string, args ...any)
+	Error(msg string, args ...any)
+	InfoContext(ctx context.Context, msg string, args ...any)
 }
 
-type Application interface {
-	CreateEvent(ctx context.Context, uID, title, desc, dur string, noticeBefore int32, startAt time.Time) error
-	UpdateEvent(ctx context.Context, uID, title, desc, dur string, noticeBefore int32, startAt time.Time) error
-	DeleteEvent(ctx context.Context, userID string, start time.Time) error
-	DeleteOldEvents(ctx context.Context, before time.Time) error
-	GetEvents(ctx context.Context) ([]storage.Event, error)
-	GetUpcomingEvents(ctx context.Context, from time.Time) ([]storage.Event, error)
-	GetEventsByDay(ctx context.Context, date time.Time) ([]storage.Event, error)
-	GetEventsByWeek(ctx context.Context, date time.Time) ([]storage.Event, error)
-	GetEventsByMonth(ctx context.Context, date time.Time) ([]storage.Event, error)
+// Options - необязательные настройки сервера, нулевые значения отключают соответствующую функцию.
+type Options struct {
+	Limiter      *ratelimit.Limiter // общий с gRPC сервером
+	MaxBodyBytes int64
+	TLS          *tls.Config
+	Tenants      tenant.Set // обслуживаемые тенанты, пустой - любой
+
+	Bulk           BulkApp // POST /events/import и GET /events/export
+	MaxImportBytes int64   // лимит тела импорта вместо MaxBodyBytes
+
+	// POST /events/{id}/attachments и GET /attachments/{id}/content, лимит
+	// размера файла проверяет приложение
+	Attachments AttachmentApp
 }
 
 type Server struct {
 	server *http.Server
 	logger Logger
-	app    Application
 }
 
-func NewServer(logger Logger, app Application, host string, port string) *Server {
+// NewServer создаёт HTTP сервер. Маршруты API (/events...) обслуживает api -
+// gateway, сгенерированный из EventService.proto (см. grpcserver.NewGateway).
+func NewServer(logger Logger, api http.Handler, host string, port string, opts Options) *Server {
 	s := &Server{
 		logger: logger,
-		app:    app,
 	}
 	r := mux.NewRouter()
+	r.Use(requestIDMiddleware)
 	r.Use(loggingMiddleware(logger))
+	r.Use(identityMiddleware)
+	r.Use(tenantMiddleware(opts.Tenants))
+	if opts.Limiter != nil {
+		r.Use(rateLimitMiddleware(opts.Limiter))
+	}
 
-	// RESTful endpoints
-	r.HandleFunc("/events", s.createEventHandler).Methods(http.MethodPost)
-	r.HandleFunc("/events", s.updateEventHandler).Methods(http.MethodPut)
-	r.HandleFunc("/events", s.deleteEventHandler).Methods(http.MethodDelete)
-	r.HandleFunc("/events", s.getEventsHandler).Methods(http.MethodGet)
+	// Импорт и экспорт раньше gateway, иначе /events/export разберётся как /events/{id}
+	if opts.Bulk != nil {
+		h := &bulkHandler{app: opts.Bulk, logger: logger, maxBytes: opts.MaxImportBytes}
+		r.HandleFunc("/events/import", h.importEvents).Methods(http.MethodPost)
+		r.HandleFunc("/events/export", h.exportEvents).Methods(http.MethodGet)
+	}
+	if opts.Attachments != nil {
+		h := &attachmentHandler{app: opts.Attachments, logger: logger}
+		r.HandleFunc("/events/{id:[0-9]+}/attachments", h.upload).Methods(http.MethodPost)
+		r.HandleFunc("/attachments/{id:[0-9]+}/content", h.download).Methods(http.MethodGet)
+	}
 
-	// Чтение
-	r.HandleFunc("/events/day", s.getEventsByDayHandler).Methods(http.MethodGet)
-	r.HandleFunc("/events/week", s.getEventsByWeekHandler).Methods(http.MethodGet)
-	r.HandleFunc("/events/month", s.getEventsByMonthHandler).Methods(http.MethodGet)
+	// у остальных маршрутов общий лимит тела
+	rest := r.NewRoute().Subrouter()
+	if opts.MaxBodyBytes > 0 {
+		rest.Use(bodyLimitMiddleware(opts.MaxBodyBytes))
+	}
 
 	// Простой hello
-	r.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+	rest.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
 		w.Write([]byte("Hello, world!"))
 	})
-	r.HandleFunc("/hello", func(w http.ResponseWriter, _ *http.Request) {
+	rest.HandleFunc("/hello", func(w http.ResponseWriter, _ *http.Request) {
 		w.Write([]byte("Hello, world!"))
 	})
 
+	// RESTful endpoints
+	rest.PathPrefix("/").Handler(api)
+
 	s.server = &http.Server{
 		Addr:              net.JoinHostPort(host, port),
 		Handler:           r,
 		ReadHeaderTimeout: 5 * time.Second, // защита от Slowloris
+		TLSConfig:         opts.TLS,
 	}
 
 	return s
@@ -74,19 +97,34 @@ func (s *Server) Handler() http.Handler {
 	return s.server.Handler
 }
 
+// Start слушает адрес и обслуживает запросы, пока сервер не остановят через Stop.
 func (s *Server) Start(ctx context.Context) error {
-	go func() {
-		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			s.logger.Printf("HTTP server error: %v", err)
-		}
-	}()
-	s.logger.Printf("HTTP server listening on %s", s.server.Addr)
-
-	<-ctx.Done()
-	return s.Stop(context.Background())
+	var lc net.ListenConfig
+	lis, err := lc.Listen(ctx, "tcp", s.server.Addr)
+	if err != nil {
+		return err
+	}
+	s.logger.Info("HTTP server listening", "addr", s.server.Addr, "tls", s.server.TLSConfig != nil)
+
+	if s.server.TLSConfig != nil {
+		// сертификат отдаёт TLSConfig, поэтому пути к файлам пустые
+		err = s.server.ServeTLS(lis, "", "")
+	} else {
+		err = s.server.Serve(lis)
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
 }
 
+// Stop дожидается завершения текущих запросов; если ctx истёк раньше,
+// оставшиеся соединения закрываются принудительно.
 func (s *Server) Stop(ctx context.Context) error {
-	s.logger.Printf("Server stopping...")
-	return s.server.Shutdown(ctx)
+	s.logger.Info("HTTP server stopping")
+	if err := s.server.Shutdown(ctx); err != nil {
+		_ = s.server.Close()
+		return err
+	}
+	return nil
 }
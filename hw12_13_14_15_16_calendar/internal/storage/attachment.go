@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// AttachmentStorage - метаданные файлов и ссылок, прикреплённых к событиям.
+// Содержимое файлов хранится отдельно (см. пакет blob), здесь только его ключ.
+type AttachmentStorage interface {
+	// AddAttachment прикрепляет вложение к действующему событию, ErrNotFound - если его нет.
+	AddAttachment(ctx context.Context, a Attachment) (int64, error)
+	// GetAttachment находит вложение действующего события. Вложения событий
+	// из корзины не видны, но возвращаются вместе с событием при восстановлении.
+	GetAttachment(ctx context.Context, id int64) (Attachment, error)
+	// ListAttachments возвращает вложения события в порядке добавления.
+	ListAttachments(ctx context.Context, eventID int64) ([]Attachment, error)
+	DeleteAttachment(ctx context.Context, id int64) error
+	// TrashAttachments возвращает вложения событий, попавших в корзину раньше
+	// before, - то, что удалит PurgeTrash с тем же before.
+	TrashAttachments(ctx context.Context, before time.Time) ([]Attachment, error)
+	// AttachmentsExist возвращает, какие из вложений ещё существуют, в том числе
+	// у событий в корзине.
+	AttachmentsExist(ctx context.Context, ids []int64) (map[int64]bool, error)
+}
+
+// Attachment - файл или ссылка. У файла есть BlobKey, у ссылки - URL.
+type Attachment struct {
+	ID          int64
+	EventID     int64
+	Name        string
+	ContentType string // у ссылки пустой
+	Size        int64
+	BlobKey     string
+	URL         string
+	CreatedBy   string
+	CreatedAt   time.Time
+}
+
+// IsLink - вложение-ссылка, без содержимого в blob хранилище.
+func (a Attachment) IsLink() bool {
+	return a.BlobKey == ""
+}
@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+type AuditStorage interface {
+	// AppendAudit добавляет запись в журнал, записи не меняются и не удаляются.
+	AppendAudit(ctx context.Context, r AuditRecord) error
+	// EventHistory возвращает записи о событии в порядке появления.
+	EventHistory(ctx context.Context, eventID int64) ([]AuditRecord, error)
+}
+
+type AuditAction string
+
+const (
+	AuditCreate  AuditAction = "create"
+	AuditUpdate  AuditAction = "update"
+	AuditDelete  AuditAction = "delete"
+	AuditRestore AuditAction = "restore"
+	AuditInvite  AuditAction = "invite"
+	AuditRSVP    AuditAction = "rsvp"
+)
+
+// AuditRecord - изменение события: кто, что сделал и состояние до и после.
+type AuditRecord struct {
+	ID        int64
+	EventID   int64
+	Actor     string // пользователь из x-user-id, пустой - не передан
+	Action    AuditAction
+	Before    *Event // nil при создании
+	After     *Event // nil при удалении
+	RequestID string
+	At        time.Time
+}
@@ -0,0 +1,14 @@
+package storage
+
+import "context"
+
+// BulkStorage - массовая загрузка и выгрузка событий.
+type BulkStorage interface {
+	// ImportEvents добавляет события одной транзакцией и возвращает их ID в том же
+	// порядке. 0 - событие не добавлено, потому что время занято, в том числе
+	// событием раньше в том же пакете. В InTx пакет становится частью её транзакции.
+	ImportEvents(ctx context.Context, events []Event) ([]int64, error)
+	// ExportEvents передаёт fn события по мере чтения, в порядке начала. В отличие
+	// от ListEvents, f.UserID - только владелец, приглашения не выгружаются.
+	ExportEvents(ctx context.Context, f EventFilter, fn func(Event) error) error
+}
@@ -0,0 +1,52 @@
+package cachestorage
+
+import (
+	"fmt"
+	"time"
+)
+
+// period - ключ кэша и дни UTC [from, to), события которых могут попасть в ответ.
+//
+// Хранилища считают границы по-разному: memorystorage - в зоне даты запроса,
+// sqlstorage - частично в UTC. Поэтому ключ различает и то и другое, а границы
+// взяты с запасом в день.
+type period struct {
+	key      string
+	from, to time.Time
+}
+
+const dateLayout = "2006-01-02"
+
+func localMidnight(date time.Time) time.Time {
+	y, m, d := date.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func dayPeriod(date time.Time) period {
+	start := localMidnight(date)
+	return period{
+		key:  "day:" + start.Format(dateLayout) + "/" + date.UTC().Format(dateLayout),
+		from: start.AddDate(0, 0, -1),
+		to:   start.AddDate(0, 0, 2),
+	}
+}
+
+func weekPeriod(date time.Time) period {
+	year, week := date.ISOWeek()
+	offset := (int(date.Weekday()) + 6) % 7 // дней с понедельника
+	monday := localMidnight(date).AddDate(0, 0, -offset)
+	return period{
+		key:  fmt.Sprintf("week:%d-W%02d", year, week),
+		from: monday.AddDate(0, 0, -1),
+		to:   monday.AddDate(0, 0, 8),
+	}
+}
+
+func monthPeriod(date time.Time) period {
+	first := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return period{
+		key:  "month:" + first.Format("2006-01"),
+		from: first.AddDate(0, 0, -1),
+		to:   first.AddDate(0, 1, 1),
+	}
+}
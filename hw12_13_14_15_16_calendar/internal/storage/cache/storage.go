@@ -0,0 +1,191 @@
+// Package cachestorage - кэширующая обёртка над хранилищем для представлений
+// календаря по дням, неделям и месяцам. Работает с любым хранилищем.
+package cachestorage
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	lru "github.com/ksa75/go-hw/hw04_lru_cache"
+	"mycalendar/internal/storage"
+	"mycalendar/internal/tenant"
+)
+
+type Logger interface {
+	Info(msg string, args ...any)
+}
+
+// Stats - счётчики обращений к кэшу с момента запуска.
+type Stats struct {
+	Hits   int64
+	Misses int64
+	Stale  int64 // найденные, но устаревшие по TTL или после изменения событий
+}
+
+// Storage кэширует GetEventsByDay/Week/Month, остальные методы вызываются напрямую.
+//
+// Вместо поиска и удаления затронутых записей (LRU-кэш так не умеет) у каждого дня
+// UTC есть версия, которая растёт при изменении событий, начинающихся в этот день.
+// Запись в кэше помнит версии дней своего периода и устаревает, если какая-то из них
+// выросла. Периоды берутся с запасом в день с каждой стороны, поэтому запросы
+// с датой в любой временной зоне инвалидируются правильно.
+type Storage struct {
+	storage.Storage
+	cache lru.Cache
+	ttl   time.Duration
+
+	mu       sync.RWMutex
+	epoch    uint64           // растёт при изменениях, затрагивающих неизвестные дни
+	versions map[int64]uint64 // номер дня UTC -> версия
+
+	hits, misses, stale atomic.Int64
+
+	tx *txChanges // не nil у хранилища внутри InTx
+}
+
+// New оборачивает s кэшем на size периодов. Записи живут не дольше ttl, 0 - без срока.
+func New(s storage.Storage, size int, ttl time.Duration) *Storage {
+	return &Storage{
+		Storage:  s,
+		cache:    lru.NewCache(size),
+		ttl:      ttl,
+		versions: make(map[int64]uint64),
+	}
+}
+
+type entry struct {
+	events   []storage.Event
+	expires  time.Time
+	epoch    uint64
+	firstDay int64
+	versions []uint64 // версии дней firstDay, firstDay+1, ...
+}
+
+func (s *Storage) GetEventsByDay(ctx context.Context, date time.Time) ([]storage.Event, error) {
+	return s.cached(ctx, dayPeriod(date), func(ctx context.Context) ([]storage.Event, error) {
+		return s.Storage.GetEventsByDay(ctx, date)
+	})
+}
+
+func (s *Storage) GetEventsByWeek(ctx context.Context, date time.Time) ([]storage.Event, error) {
+	return s.cached(ctx, weekPeriod(date), func(ctx context.Context) ([]storage.Event, error) {
+		return s.Storage.GetEventsByWeek(ctx, date)
+	})
+}
+
+func (s *Storage) GetEventsByMonth(ctx context.Context, date time.Time) ([]storage.Event, error) {
+	return s.cached(ctx, monthPeriod(date), func(ctx context.Context) ([]storage.Event, error) {
+		return s.Storage.GetEventsByMonth(ctx, date)
+	})
+}
+
+func (s *Storage) Stats() Stats {
+	return Stats{Hits: s.hits.Load(), Misses: s.misses.Load(), Stale: s.stale.Load()}
+}
+
+// ReportStats пишет счётчики в лог раз в every, пока не отменён ctx.
+func (s *Storage) ReportStats(ctx context.Context, logger Logger, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			st := s.Stats()
+			logger.Info("cache stats", "hits", st.Hits, "misses", st.Misses, "stale", st.Stale)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Storage) cached(ctx context.Context, p period, load func(context.Context) ([]storage.Event, error)) ([]storage.Event, error) {
+	if s.tx != nil {
+		return load(ctx)
+	}
+	// версии дней общие для всех тенантов: изменение у одного лишь зря
+	// сбросит записи других, а ключ не даст тенантам увидеть чужие события
+	key := lru.Key(tenant.FromContext(ctx) + "/" + p.key)
+	if v, ok := s.cache.Get(key); ok {
+		if e := v.(*entry); s.fresh(e) {
+			s.hits.Add(1)
+			return slices.Clone(e.events), nil
+		}
+		s.stale.Add(1)
+	}
+	s.misses.Add(1)
+
+	// версии запоминаются до чтения: изменение во время чтения сделает запись устаревшей
+	e := s.newEntry(p)
+	events, err := load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	e.events = events
+	s.cache.Set(key, e)
+	return slices.Clone(events), nil
+}
+
+func (s *Storage) newEntry(p period) *entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e := &entry{epoch: s.epoch, firstDay: dayNumber(p.from)}
+	if s.ttl > 0 {
+		e.expires = time.Now().Add(s.ttl)
+	}
+	for day := e.firstDay; day < dayNumber(p.to); day++ {
+		e.versions = append(e.versions, s.versions[day])
+	}
+	return e
+}
+
+func (s *Storage) fresh(e *entry) bool {
+	if !e.expires.IsZero() && time.Now().After(e.expires) {
+		return false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if e.epoch != s.epoch {
+		return false
+	}
+	for i, v := range e.versions {
+		if s.versions[e.firstDay+int64(i)] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// invalidate делает устаревшими периоды, в которые попадают времена начала событий.
+func (s *Storage) invalidate(starts ...time.Time) {
+	if s.tx != nil {
+		s.tx.starts = append(s.tx.starts, starts...)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range starts {
+		s.versions[dayNumber(t)]++
+	}
+}
+
+// invalidateAll делает устаревшим весь кэш.
+func (s *Storage) invalidateAll() {
+	if s.tx != nil {
+		s.tx.all = true
+		return
+	}
+	s.mu.Lock()
+	s.epoch++
+	s.mu.Unlock()
+	s.cache.Clear()
+}
+
+// dayNumber - номер дня UTC, в который попадает t.
+func dayNumber(t time.Time) int64 {
+	return t.Truncate(24*time.Hour).Unix() / (24 * 60 * 60)
+}
@@ -0,0 +1,160 @@
+package cachestorage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"mycalendar/internal/storage"
+	cachestorage "mycalendar/internal/storage/cache"
+	memorystorage "mycalendar/internal/storage/memory"
+)
+
+// countingStorage считает обращения к хранилищу за представлениями.
+type countingStorage struct {
+	*memorystorage.Storage
+	reads int
+}
+
+func (c *countingStorage) GetEventsByDay(ctx context.Context, date time.Time) ([]storage.Event, error) {
+	c.reads++
+	return c.Storage.GetEventsByDay(ctx, date)
+}
+
+func (c *countingStorage) GetEventsByWeek(ctx context.Context, date time.Time) ([]storage.Event, error) {
+	c.reads++
+	return c.Storage.GetEventsByWeek(ctx, date)
+}
+
+func (c *countingStorage) GetEventsByMonth(ctx context.Context, date time.Time) ([]storage.Event, error) {
+	c.reads++
+	return c.Storage.GetEventsByMonth(ctx, date)
+}
+
+func TestStorage_HitsAndInvalidation(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingStorage{Storage: memorystorage.New()}
+	s := cachestorage.New(inner, 16, time.Hour)
+
+	monday := time.Date(2025, 6, 9, 12, 0, 0, 0, time.UTC)
+	_, err := s.AddEvent(ctx, storage.Event{UserID: "u1", Title: "Mon", StartDateTime: monday})
+	require.NoError(t, err)
+
+	events, err := s.GetEventsByWeek(ctx, monday)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	// другой день той же недели - тот же ключ
+	events, err = s.GetEventsByWeek(ctx, monday.AddDate(0, 0, 3))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, 1, inner.reads)
+
+	// изменение в соседнем месяце неделю не затрагивает
+	_, err = s.AddEvent(ctx, storage.Event{UserID: "u1", Title: "Aug", StartDateTime: monday.AddDate(0, 2, 0)})
+	require.NoError(t, err)
+	_, err = s.GetEventsByWeek(ctx, monday)
+	require.NoError(t, err)
+	require.Equal(t, 1, inner.reads)
+
+	// изменение внутри недели - затрагивает
+	_, err = s.AddEvent(ctx, storage.Event{UserID: "u2", Title: "Wed", StartDateTime: monday.AddDate(0, 0, 2)})
+	require.NoError(t, err)
+	events, err = s.GetEventsByWeek(ctx, monday)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, 2, inner.reads)
+
+	// приглашение по ID инвалидирует день события
+	events, err = s.GetEventsByDay(ctx, monday)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, 3, inner.reads)
+	require.NoError(t, s.InviteAttendees(ctx, events[0].EventID, []string{"u3"}))
+	_, err = s.GetEventsByDay(ctx, monday)
+	require.NoError(t, err)
+	require.Equal(t, 4, inner.reads)
+
+	require.Equal(t, cachestorage.Stats{Hits: 2, Misses: 4, Stale: 2}, s.Stats())
+}
+
+func TestStorage_OtherTimeZone(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingStorage{Storage: memorystorage.New()}
+	s := cachestorage.New(inner, 16, time.Hour)
+
+	// 10 июня 01:00 в Москве - это ещё 9 июня в UTC
+	msk := time.FixedZone("MSK", 3*60*60)
+	date := time.Date(2025, 6, 10, 1, 0, 0, 0, msk)
+	events, err := s.GetEventsByDay(ctx, date)
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	_, err = s.AddEvent(ctx, storage.Event{UserID: "u1", StartDateTime: time.Date(2025, 6, 10, 23, 0, 0, 0, msk)})
+	require.NoError(t, err)
+	events, err = s.GetEventsByDay(ctx, date)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+}
+
+func TestStorage_TTLAndBulkChanges(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingStorage{Storage: memorystorage.New()}
+	s := cachestorage.New(inner, 16, time.Millisecond)
+
+	june := time.Date(2025, 6, 10, 9, 0, 0, 0, time.UTC)
+	_, err := s.GetEventsByMonth(ctx, june)
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+	_, err = s.GetEventsByMonth(ctx, june)
+	require.NoError(t, err)
+	require.Equal(t, 2, inner.reads)
+
+	s = cachestorage.New(inner, 16, 0)
+	id, err := s.AddEvent(ctx, storage.Event{UserID: "u1", StartDateTime: june})
+	require.NoError(t, err)
+	require.NoError(t, s.DeleteEventByID(ctx, id))
+	events, err := s.GetEventsByMonth(ctx, june)
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	// восстановление из корзины сбрасывает весь кэш
+	require.NoError(t, s.RestoreEvent(ctx, id))
+	events, err = s.GetEventsByMonth(ctx, june)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+}
+
+func TestStorage_InTx(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingStorage{Storage: memorystorage.New()}
+	s := cachestorage.New(inner, 16, time.Hour)
+
+	monday := time.Date(2025, 6, 9, 12, 0, 0, 0, time.UTC)
+	_, err := s.GetEventsByDay(ctx, monday)
+	require.NoError(t, err)
+
+	// откаченная транзакция кэш не трогает
+	err = s.InTx(ctx, func(ctx context.Context, tx storage.Storage) error {
+		if _, err := tx.AddEvent(ctx, storage.Event{UserID: "u1", Title: "Mon", StartDateTime: monday}); err != nil {
+			return err
+		}
+		return storage.ErrDateBusy
+	})
+	require.ErrorIs(t, err, storage.ErrDateBusy)
+	events, err := s.GetEventsByDay(ctx, monday)
+	require.NoError(t, err)
+	require.Empty(t, events)
+	require.Equal(t, 1, inner.reads)
+
+	// зафиксированная - инвалидирует дни изменённых событий
+	err = s.InTx(ctx, func(ctx context.Context, tx storage.Storage) error {
+		_, err := tx.AddEvent(ctx, storage.Event{UserID: "u1", Title: "Mon", StartDateTime: monday})
+		return err
+	})
+	require.NoError(t, err)
+	events, err = s.GetEventsByDay(ctx, monday)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, 2, inner.reads)
+}
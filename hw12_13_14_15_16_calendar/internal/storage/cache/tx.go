@@ -0,0 +1,36 @@
+package cachestorage
+
+import (
+	"context"
+	"time"
+
+	"mycalendar/internal/storage"
+)
+
+// txChanges - дни, изменённые в транзакции. Кэш сбрасывается только после
+// фиксации: раньше другие запросы успели бы закэшировать старые данные снова.
+type txChanges struct {
+	starts []time.Time
+	all    bool
+}
+
+// InTx передаёт fn транзакцию хранилища без кэша: внутри неё чтения идут
+// напрямую, а изменения запоминаются и инвалидируют кэш после фиксации.
+func (s *Storage) InTx(ctx context.Context, fn func(ctx context.Context, tx storage.Storage) error) error {
+	if s.tx != nil {
+		return s.Storage.InTx(ctx, func(ctx context.Context, _ storage.Storage) error { return fn(ctx, s) })
+	}
+	changes := &txChanges{}
+	err := s.Storage.InTx(ctx, func(ctx context.Context, tx storage.Storage) error {
+		return fn(ctx, &Storage{Storage: tx, tx: changes})
+	})
+	if err != nil {
+		return err
+	}
+	if changes.all {
+		s.invalidateAll()
+	} else if len(changes.starts) > 0 {
+		s.invalidate(changes.starts...)
+	}
+	return nil
+}
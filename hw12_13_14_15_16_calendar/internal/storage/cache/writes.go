@@ -0,0 +1,93 @@
+package cachestorage
+
+import (
+	"context"
+	"time"
+
+	"mycalendar/internal/storage"
+)
+
+// Изменения событий проходят в хранилище и инвалидируют дни их начала.
+
+func (s *Storage) AddEvent(ctx context.Context, e storage.Event) (int64, error) {
+	id, err := s.Storage.AddEvent(ctx, e)
+	if err == nil {
+		s.invalidate(e.StartDateTime)
+	}
+	return id, err
+}
+
+func (s *Storage) UpdateEvent(ctx context.Context, e storage.Event) error {
+	err := s.Storage.UpdateEvent(ctx, e)
+	if err == nil {
+		s.invalidate(e.StartDateTime)
+	}
+	return err
+}
+
+func (s *Storage) DeleteEvent(ctx context.Context, userID string, start time.Time) error {
+	err := s.Storage.DeleteEvent(ctx, userID, start)
+	if err == nil {
+		s.invalidate(start)
+	}
+	return err
+}
+
+func (s *Storage) DeleteEventByID(ctx context.Context, id int64) error {
+	return s.byID(ctx, id, func() error { return s.Storage.DeleteEventByID(ctx, id) })
+}
+
+func (s *Storage) InviteAttendees(ctx context.Context, eventID int64, userIDs []string) error {
+	return s.byID(ctx, eventID, func() error { return s.Storage.InviteAttendees(ctx, eventID, userIDs) })
+}
+
+func (s *Storage) SetAttendeeStatus(ctx context.Context, eventID int64, userID string, status storage.RSVPStatus) error {
+	return s.byID(ctx, eventID, func() error { return s.Storage.SetAttendeeStatus(ctx, eventID, userID, status) })
+}
+
+// Время начала восстановленного события до восстановления не узнать, а удаление
+// старых событий затрагивает много дней, поэтому они сбрасывают весь кэш.
+
+func (s *Storage) RestoreEvent(ctx context.Context, id int64) error {
+	err := s.Storage.RestoreEvent(ctx, id)
+	if err == nil {
+		s.invalidateAll()
+	}
+	return err
+}
+
+func (s *Storage) DeleteOldEvents(ctx context.Context, before time.Time) error {
+	err := s.Storage.DeleteOldEvents(ctx, before)
+	if err == nil {
+		s.invalidateAll()
+	}
+	return err
+}
+
+// ImportEvents инвалидирует дни добавленных событий.
+func (s *Storage) ImportEvents(ctx context.Context, events []storage.Event) ([]int64, error) {
+	ids, err := s.Storage.ImportEvents(ctx, events)
+	if err != nil {
+		return ids, err
+	}
+	for i, id := range ids {
+		if id != 0 {
+			s.invalidate(events[i].StartDateTime)
+		}
+	}
+	return ids, nil
+}
+
+// byID выполняет изменение события id и инвалидирует день его начала.
+func (s *Storage) byID(ctx context.Context, id int64, change func() error) error {
+	e, lookupErr := s.Storage.GetEvent(ctx, id)
+	if err := change(); err != nil {
+		return err
+	}
+	if lookupErr != nil {
+		s.invalidateAll()
+	} else {
+		s.invalidate(e.StartDateTime)
+	}
+	return nil
+}
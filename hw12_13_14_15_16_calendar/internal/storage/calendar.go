@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+type CalendarsStorage interface {
+	CreateCalendar(ctx context.Context, c Calendar) (int64, error)
+	GetCalendar(ctx context.Context, id int64) (Calendar, error)
+	// ListCalendars возвращает календари пользователя и расшаренные ему.
+	ListCalendars(ctx context.Context, userID string) ([]Calendar, error)
+	// DefaultCalendar возвращает календарь пользователя по умолчанию, создавая его при первом обращении.
+	DefaultCalendar(ctx context.Context, ownerID string) (Calendar, error)
+	// UpdateCalendar меняет название, цвет, зону и настройки рабочих дней календаря c.ID.
+	UpdateCalendar(ctx context.Context, c Calendar) error
+	// ShareCalendar выдаёт или меняет права пользователя на календарь.
+	ShareCalendar(ctx context.Context, calendarID int64, userID string, perm Permission) error
+	UnshareCalendar(ctx context.Context, calendarID int64, userID string) error
+}
+
+// Storage - события, календари, журнал изменений, вебхуки и вложения, с ним работает приложение.
+type Storage interface {
+	EventsStorage
+	CalendarsStorage
+	AuditStorage
+	BulkStorage
+	WebhookStorage
+	AttachmentStorage
+	TxStorage
+}
+
+const DefaultCalendarName = "Default"
+
+type Calendar struct {
+	ID        int64
+	OwnerID   string
+	Name      string
+	Color     string // #rrggbb, пустой - на усмотрение клиента
+	TimeZone  string // IANA
+	IsDefault bool
+	CreatedAt time.Time
+	Shares    []Share
+
+	// HolidayCountry - праздники какой страны нерабочие, пусто - только выходные.
+	HolidayCountry string
+	// DeferReminders - в нерабочий день напоминания откладываются до рабочего,
+	// если событие начинается позже.
+	DeferReminders bool
+}
+
+// Permission - права на чужой календарь, от слабых к сильным.
+type Permission string
+
+const (
+	PermFreeBusy Permission = "free-busy" // только занятость
+	PermRead     Permission = "read"
+	PermWrite    Permission = "write"
+)
+
+var permRank = map[Permission]int{PermFreeBusy: 1, PermRead: 2, PermWrite: 3}
+
+// Valid проверяет, что права одни из известных.
+func (p Permission) Valid() bool {
+	_, ok := permRank[p]
+	return ok
+}
+
+// Allows - права p не слабее need.
+func (p Permission) Allows(need Permission) bool {
+	return p.Valid() && permRank[p] >= permRank[need]
+}
+
+type Share struct {
+	UserID     string
+	Permission Permission
+}
+
+// Access - права пользователя на календарь: у владельца PermWrite,
+// у остальных - из Shares, пустая строка - доступа нет.
+func (c Calendar) Access(userID string) Permission {
+	if c.OwnerID == userID {
+		return PermWrite
+	}
+	for _, s := range c.Shares {
+		if s.UserID == userID {
+			return s.Permission
+		}
+	}
+	return ""
+}
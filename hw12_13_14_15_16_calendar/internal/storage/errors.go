@@ -5,4 +5,14 @@ import "errors"
 var (
 	ErrDateBusy = errors.New("the selected datetime is already booked")
 	ErrNotFound = errors.New("event not found")
+
+	ErrNotAttendee   = errors.New("user is not invited to the event")
+	ErrInvalidStatus = errors.New("invalid RSVP status")
+
+	ErrCalendarNotFound  = errors.New("calendar not found")
+	ErrInvalidPermission = errors.New("invalid calendar permission")
+
+	ErrWebhookNotFound = errors.New("webhook not found")
+
+	ErrAttachmentNotFound = errors.New("attachment not found")
 )
@@ -2,26 +2,50 @@ package storage
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
 type EventsStorage interface {
-	AddEvent(ctx context.Context, e Event) error
+	AddEvent(ctx context.Context, e Event) (int64, error)
 	UpdateEvent(ctx context.Context, e Event) error
+	// DeleteEvent, DeleteEventByID и DeleteOldEvents переносят события в корзину,
+	// остальные методы удалённые события не видят.
 	DeleteEvent(ctx context.Context, userID string, start time.Time) error
+	DeleteEventByID(ctx context.Context, id int64) error
 	DeleteOldEvents(ctx context.Context, before time.Time) error
+	GetEvent(ctx context.Context, id int64) (Event, error)
 	GetEvents(ctx context.Context) ([]Event, error)
+	ListEvents(ctx context.Context, f EventFilter) ([]Event, error)
 	GetUpcomingEvents(ctx context.Context, from time.Time) ([]Event, error)
 	GetEventsByDay(ctx context.Context, date time.Time) ([]Event, error)
 	GetEventsByWeek(ctx context.Context, date time.Time) ([]Event, error)
 	GetEventsByMonth(ctx context.Context, date time.Time) ([]Event, error)
+
+	// InviteAttendees добавляет участников со статусом needs-action, уже приглашённые не меняются.
+	InviteAttendees(ctx context.Context, eventID int64, userIDs []string) error
+	// SetAttendeeStatus записывает ответ участника на приглашение.
+	SetAttendeeStatus(ctx context.Context, eventID int64, userID string, status RSVPStatus) error
+
+	// GetBusyEvents возвращает события, пересекающие интервал [from, to), в которых
+	// участвует хотя бы один из пользователей (см. Event.Involves).
+	GetBusyEvents(ctx context.Context, userIDs []string, from, to time.Time) ([]Event, error)
+
+	// ListTrash возвращает удалённые события владельца userID, с пустым userID - всех.
+	ListTrash(ctx context.Context, userID string) ([]Event, error)
+	// RestoreEvent возвращает событие из корзины, ErrDateBusy - если время уже занято.
+	RestoreEvent(ctx context.Context, id int64) error
+	// PurgeTrash окончательно удаляет события, попавшие в корзину раньше before.
+	PurgeTrash(ctx context.Context, before time.Time) (int64, error)
 }
 
 type BaseStorage interface {
 	Connect(ctx context.Context, dsn string) error
 	Migrate(ctx context.Context, migrate string) error
 	Close() error
-	EventsStorage
+	Storage
 }
 
 type Event struct {
@@ -33,4 +57,117 @@ type Event struct {
 	Duration      string
 	NoticeBefore  int32
 	CreatedAt     time.Time
+	Attendees     []Attendee // кроме владельца UserID
+	CalendarID    int64      // 0 при создании - календарь владельца по умолчанию
+	DeletedAt     time.Time  // когда событие попало в корзину, нулевое у действующих
+}
+
+// RSVPStatus - ответ участника на приглашение.
+type RSVPStatus string
+
+const (
+	RSVPNeedsAction RSVPStatus = "needs-action"
+	RSVPAccepted    RSVPStatus = "accepted"
+	RSVPDeclined    RSVPStatus = "declined"
+	RSVPTentative   RSVPStatus = "tentative"
+)
+
+// Valid проверяет, что статус один из известных.
+func (s RSVPStatus) Valid() bool {
+	switch s {
+	case RSVPNeedsAction, RSVPAccepted, RSVPDeclined, RSVPTentative:
+		return true
+	}
+	return false
+}
+
+type Attendee struct {
+	UserID string
+	Status RSVPStatus
+}
+
+// Involves - событие видно пользователю: он владелец или приглашён и не отказался.
+func (e Event) Involves(userID string) bool {
+	if e.UserID == userID {
+		return true
+	}
+	for _, a := range e.Attendees {
+		if a.UserID == userID {
+			return a.Status != RSVPDeclined
+		}
+	}
+	return false
+}
+
+// End - время окончания события. Длительность, которую не удалось разобрать,
+// считается нулевой.
+func (e Event) End() time.Time {
+	d, err := ParseDuration(e.Duration)
+	if err != nil {
+		return e.StartDateTime
+	}
+	return e.StartDateTime.Add(d)
+}
+
+// Overlaps - событие пересекает интервал [from, to).
+func (e Event) Overlaps(from, to time.Time) bool {
+	return e.StartDateTime.Before(to) && e.End().After(from)
+}
+
+// ParseDuration разбирает длительность события: формат time.ParseDuration
+// и дни с суффиксом d ("1d", "2d12h").
+func ParseDuration(s string) (time.Duration, error) {
+	days, rest, ok := strings.Cut(s, "d")
+	if !ok {
+		return time.ParseDuration(s)
+	}
+	n, err := strconv.Atoi(days)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	d := time.Duration(n) * 24 * time.Hour
+	if rest == "" {
+		return d, nil
+	}
+	r, err := time.ParseDuration(rest)
+	if err != nil {
+		return 0, err
+	}
+	return d + r, nil
+}
+
+// Recipients - кому отправлять напоминание: владельцу и принявшим приглашение.
+func (e Event) Recipients() []string {
+	res := []string{e.UserID}
+	for _, a := range e.Attendees {
+		if a.Status == RSVPAccepted {
+			res = append(res, a.UserID)
+		}
+	}
+	return res
+}
+
+// EventFilter - условия выборки событий, пустые поля не ограничивают выборку.
+type EventFilter struct {
+	UserID     string    // владелец или участник, не отказавшийся от приглашения
+	From       time.Time // начало события >= From
+	To         time.Time // начало события < To
+	CalendarID int64
+}
+
+// Match проверяет, подходит ли событие под фильтр.
+func (f EventFilter) Match(e Event) bool {
+	if f.UserID != "" && !e.Involves(f.UserID) {
+		return false
+	}
+	if f.CalendarID != 0 && e.CalendarID != f.CalendarID {
+		return false
+	}
+	if !f.From.IsZero() && e.StartDateTime.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !e.StartDateTime.Before(f.To) {
+		return false
+	}
+	return true
 }
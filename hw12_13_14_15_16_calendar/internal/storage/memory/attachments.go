@@ -0,0 +1,92 @@
+package memorystorage
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"mycalendar/internal/storage"
+)
+
+func (s *Storage) AddAttachment(ctx context.Context, a storage.Attachment) (int64, error) {
+	defer s.lock(ctx)()
+	sp := s.space(ctx)
+	if _, err := sp.findLocked(a.EventID); err != nil {
+		return 0, err
+	}
+	s.lastAttachmentID++
+	a.ID = s.lastAttachmentID
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now()
+	}
+	sp.attachments[a.ID] = a
+	return a.ID, nil
+}
+
+func (s *Storage) GetAttachment(ctx context.Context, id int64) (storage.Attachment, error) {
+	defer s.rlock(ctx)()
+	sp := s.view(ctx)
+	a, ok := sp.attachments[id]
+	if !ok {
+		return storage.Attachment{}, storage.ErrAttachmentNotFound
+	}
+	if _, err := sp.findLocked(a.EventID); err != nil { // событие в корзине
+		return storage.Attachment{}, storage.ErrAttachmentNotFound
+	}
+	return a, nil
+}
+
+func (s *Storage) ListAttachments(ctx context.Context, eventID int64) ([]storage.Attachment, error) {
+	defer s.rlock(ctx)()
+	sp := s.view(ctx)
+	var result []storage.Attachment
+	for _, a := range sp.attachments {
+		if a.EventID == eventID {
+			result = append(result, a)
+		}
+	}
+	slices.SortFunc(result, func(a, b storage.Attachment) int { return cmp.Compare(a.ID, b.ID) })
+	return result, nil
+}
+
+func (s *Storage) DeleteAttachment(ctx context.Context, id int64) error {
+	defer s.lock(ctx)()
+	sp := s.space(ctx)
+	if _, ok := sp.attachments[id]; !ok {
+		return storage.ErrAttachmentNotFound
+	}
+	delete(sp.attachments, id)
+	return nil
+}
+
+func (s *Storage) TrashAttachments(ctx context.Context, before time.Time) ([]storage.Attachment, error) {
+	defer s.rlock(ctx)()
+	sp := s.view(ctx)
+	expired := make(map[int64]bool)
+	for _, e := range sp.trash {
+		if e.DeletedAt.Before(before) {
+			expired[e.EventID] = true
+		}
+	}
+	var result []storage.Attachment
+	for _, a := range sp.attachments {
+		if expired[a.EventID] {
+			result = append(result, a)
+		}
+	}
+	slices.SortFunc(result, func(a, b storage.Attachment) int { return cmp.Compare(a.ID, b.ID) })
+	return result, nil
+}
+
+func (s *Storage) AttachmentsExist(ctx context.Context, ids []int64) (map[int64]bool, error) {
+	defer s.rlock(ctx)()
+	sp := s.view(ctx)
+	result := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if _, ok := sp.attachments[id]; ok {
+			result[id] = true
+		}
+	}
+	return result, nil
+}
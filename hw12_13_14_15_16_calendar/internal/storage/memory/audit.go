@@ -0,0 +1,32 @@
+package memorystorage
+
+import (
+	"context"
+	"time"
+
+	"mycalendar/internal/storage"
+)
+
+func (s *Storage) AppendAudit(ctx context.Context, r storage.AuditRecord) error {
+	defer s.lock(ctx)()
+	sp := s.space(ctx)
+	r.ID = int64(len(sp.audit)) + 1
+	if r.At.IsZero() {
+		r.At = time.Now()
+	}
+	sp.audit = append(sp.audit, r)
+	return nil
+}
+
+func (s *Storage) EventHistory(ctx context.Context, eventID int64) ([]storage.AuditRecord, error) {
+	defer s.rlock(ctx)()
+	sp := s.view(ctx)
+
+	var result []storage.AuditRecord
+	for _, r := range sp.audit {
+		if r.EventID == eventID {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,110 @@
+package memorystorage
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"mycalendar/internal/storage"
+)
+
+var benchStart = time.Date(2032, 1, 5, 9, 0, 0, 0, time.UTC)
+
+// benchStorage заполняет хранилище: users пользователей по perUser событий в час.
+func benchStorage(b *testing.B, users, perUser int) (*Storage, []int64) {
+	b.Helper()
+	s := New()
+	ctx := context.Background()
+	ids := make([]int64, 0, users*perUser)
+	for u := range users {
+		for i := range perUser {
+			id, err := s.AddEvent(ctx, storage.Event{
+				UserID:        fmt.Sprintf("user-%d", u),
+				Title:         "event",
+				StartDateTime: benchStart.Add(time.Duration(i) * time.Hour),
+				Duration:      "30m",
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+			ids = append(ids, id)
+		}
+	}
+	return s, ids
+}
+
+// BenchmarkStorage_GetEvent показывает, как поиск по id зависит от числа событий.
+func BenchmarkStorage_GetEvent(b *testing.B) {
+	for _, n := range []int{1_000, 10_000, 100_000} {
+		b.Run(fmt.Sprintf("events=%d", n), func(b *testing.B) {
+			s, ids := benchStorage(b, n/100, 100)
+			ctx := context.Background()
+			b.ResetTimer()
+			for i := range b.N {
+				if _, err := s.GetEvent(ctx, ids[i%len(ids)]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkStorage_AddEvent(b *testing.B) {
+	s, _ := benchStorage(b, 100, 100)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		_, err := s.AddEvent(ctx, storage.Event{
+			UserID:        fmt.Sprintf("user-%d", i%100),
+			StartDateTime: benchStart.Add(time.Duration(100+i) * time.Hour),
+			Duration:      "30m",
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkStorage_ListEvents(b *testing.B) {
+	s, _ := benchStorage(b, 100, 100)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		f := storage.EventFilter{UserID: fmt.Sprintf("user-%d", i%100), From: benchStart, To: benchStart.AddDate(0, 0, 1)}
+		if _, err := s.ListEvents(ctx, f); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkStorage_GetBusyEvents(b *testing.B) {
+	s, _ := benchStorage(b, 100, 100)
+	ctx := context.Background()
+	users := []string{"user-1", "user-2", "user-3"}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		if _, err := s.GetBusyEvents(ctx, users, benchStart, benchStart.AddDate(0, 0, 7)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkStorage_ParallelReads(b *testing.B) {
+	s, ids := benchStorage(b, 100, 100)
+	ctx := context.Background()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if _, err := s.GetEvent(ctx, ids[i%len(ids)]); err != nil {
+				b.Error(err)
+				return
+			}
+			i += 7
+		}
+	})
+}
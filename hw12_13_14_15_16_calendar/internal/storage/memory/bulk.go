@@ -0,0 +1,62 @@
+package memorystorage
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+
+	"mycalendar/internal/storage"
+)
+
+func (s *Storage) ImportEvents(ctx context.Context, events []storage.Event) ([]int64, error) {
+	defer s.lock(ctx)()
+	sp := s.space(ctx)
+
+	// календари проверяются до вставки, чтобы пакет добавился целиком или никак
+	for _, e := range events {
+		if _, ok := sp.calendars[e.CalendarID]; e.CalendarID != 0 && !ok {
+			return nil, storage.ErrCalendarNotFound
+		}
+	}
+
+	ids := make([]int64, len(events))
+	for i, e := range events {
+		id, err := s.addLocked(sp, e)
+		if err != nil && !errors.Is(err, storage.ErrDateBusy) {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+func (s *Storage) ExportEvents(ctx context.Context, f storage.EventFilter, fn func(storage.Event) error) error {
+	owner := f.UserID
+	f.UserID = "" // Match понимает UserID шире: владелец или участник
+
+	unlock := s.rlock(ctx)
+	sp := s.view(ctx)
+	var events []storage.Event
+	for userID, evs := range sp.events {
+		if owner != "" && userID != owner {
+			continue
+		}
+		for _, e := range evs {
+			if f.Match(e) {
+				events = append(events, e)
+			}
+		}
+	}
+	unlock()
+
+	slices.SortFunc(events, func(a, b storage.Event) int {
+		return cmp.Or(a.StartDateTime.Compare(b.StartDateTime), cmp.Compare(a.EventID, b.EventID))
+	})
+	for _, e := range events {
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package memorystorage
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"mycalendar/internal/storage"
+)
+
+func (s *Storage) CreateCalendar(ctx context.Context, c storage.Calendar) (int64, error) {
+	defer s.lock(ctx)()
+	return s.createCalendarLocked(s.space(ctx), c).ID, nil
+}
+
+func (s *Storage) GetCalendar(ctx context.Context, id int64) (storage.Calendar, error) {
+	defer s.rlock(ctx)()
+	sp := s.view(ctx)
+	c, ok := sp.calendars[id]
+	if !ok {
+		return storage.Calendar{}, storage.ErrCalendarNotFound
+	}
+	return c, nil
+}
+
+func (s *Storage) ListCalendars(ctx context.Context, userID string) ([]storage.Calendar, error) {
+	defer s.rlock(ctx)()
+	sp := s.view(ctx)
+
+	var result []storage.Calendar
+	for _, c := range sp.calendars {
+		if c.Access(userID) != "" {
+			result = append(result, c)
+		}
+	}
+	slices.SortFunc(result, func(a, b storage.Calendar) int { return int(a.ID - b.ID) })
+	return result, nil
+}
+
+func (s *Storage) DefaultCalendar(ctx context.Context, ownerID string) (storage.Calendar, error) {
+	defer s.lock(ctx)()
+	return s.defaultCalendarLocked(s.space(ctx), ownerID), nil
+}
+
+func (s *Storage) UpdateCalendar(ctx context.Context, c storage.Calendar) error {
+	defer s.lock(ctx)()
+	sp := s.space(ctx)
+
+	cur, ok := sp.calendars[c.ID]
+	if !ok {
+		return storage.ErrCalendarNotFound
+	}
+	cur.Name = c.Name
+	cur.Color = c.Color
+	cur.TimeZone = c.TimeZone
+	cur.HolidayCountry = c.HolidayCountry
+	cur.DeferReminders = c.DeferReminders
+	sp.calendars[c.ID] = cur
+	return nil
+}
+
+// Права, как и участники событий, меняются копированием среза Shares.
+
+func (s *Storage) ShareCalendar(ctx context.Context, calendarID int64, userID string, perm storage.Permission) error {
+	if !perm.Valid() {
+		return storage.ErrInvalidPermission
+	}
+	defer s.lock(ctx)()
+	sp := s.space(ctx)
+
+	c, ok := sp.calendars[calendarID]
+	if !ok {
+		return storage.ErrCalendarNotFound
+	}
+	shares := slices.Clone(c.Shares)
+	i := slices.IndexFunc(shares, func(sh storage.Share) bool { return sh.UserID == userID })
+	if i < 0 {
+		shares = append(shares, storage.Share{UserID: userID, Permission: perm})
+		slices.SortFunc(shares, func(a, b storage.Share) int { return strings.Compare(a.UserID, b.UserID) })
+	} else {
+		shares[i].Permission = perm
+	}
+	c.Shares = shares
+	sp.calendars[calendarID] = c
+	return nil
+}
+
+func (s *Storage) UnshareCalendar(ctx context.Context, calendarID int64, userID string) error {
+	defer s.lock(ctx)()
+	sp := s.space(ctx)
+
+	c, ok := sp.calendars[calendarID]
+	if !ok {
+		return storage.ErrCalendarNotFound
+	}
+	c.Shares = slices.DeleteFunc(slices.Clone(c.Shares), func(sh storage.Share) bool { return sh.UserID == userID })
+	sp.calendars[calendarID] = c
+	return nil
+}
+
+func (s *Storage) createCalendarLocked(sp *space, c storage.Calendar) storage.Calendar {
+	s.lastCalendarID++
+	c.ID = s.lastCalendarID
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = time.Now()
+	}
+	c.Shares = nil
+	sp.calendars[c.ID] = c
+	return c
+}
+
+func (s *Storage) defaultCalendarLocked(sp *space, ownerID string) storage.Calendar {
+	for _, c := range sp.calendars {
+		if c.IsDefault && c.OwnerID == ownerID {
+			return c
+		}
+	}
+	return s.createCalendarLocked(sp, storage.Calendar{
+		OwnerID:   ownerID,
+		Name:      storage.DefaultCalendarName,
+		TimeZone:  "UTC",
+		IsDefault: true,
+	})
+}
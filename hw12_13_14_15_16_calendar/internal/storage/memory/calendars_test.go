@@ -0,0 +1,66 @@
+package memorystorage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"mycalendar/internal/storage"
+)
+
+func TestStorage_Calendars(t *testing.T) {
+	mem := New()
+	ctx := context.Background()
+
+	// событие без календаря попадает в календарь владельца по умолчанию
+	id, err := mem.AddEvent(ctx, storage.Event{UserID: "u1", StartDateTime: time.Now()})
+	require.NoError(t, err)
+	e, err := mem.GetEvent(ctx, id)
+	require.NoError(t, err)
+	def, err := mem.DefaultCalendar(ctx, "u1")
+	require.NoError(t, err)
+	require.True(t, def.IsDefault)
+	require.Equal(t, def.ID, e.CalendarID)
+
+	work, err := mem.CreateCalendar(ctx, storage.Calendar{OwnerID: "u1", Name: "Work", TimeZone: "Europe/Moscow"})
+	require.NoError(t, err)
+	_, err = mem.AddEvent(ctx, storage.Event{UserID: "u1", StartDateTime: time.Now().Add(time.Hour), CalendarID: work})
+	require.NoError(t, err)
+	_, err = mem.AddEvent(ctx, storage.Event{UserID: "u1", StartDateTime: time.Now().Add(2 * time.Hour), CalendarID: 100})
+	require.ErrorIs(t, err, storage.ErrCalendarNotFound)
+
+	evs, err := mem.ListEvents(ctx, storage.EventFilter{CalendarID: work})
+	require.NoError(t, err)
+	require.Len(t, evs, 1)
+
+	require.NoError(t, mem.ShareCalendar(ctx, work, "u2", storage.PermRead))
+	require.NoError(t, mem.ShareCalendar(ctx, work, "u2", storage.PermFreeBusy))
+	require.ErrorIs(t, mem.ShareCalendar(ctx, work, "u3", "admin"), storage.ErrInvalidPermission)
+	require.ErrorIs(t, mem.ShareCalendar(ctx, 100, "u3", storage.PermRead), storage.ErrCalendarNotFound)
+
+	calendars, err := mem.ListCalendars(ctx, "u2")
+	require.NoError(t, err)
+	require.Len(t, calendars, 1)
+	require.Equal(t, storage.PermFreeBusy, calendars[0].Access("u2"))
+
+	require.NoError(t, mem.UnshareCalendar(ctx, work, "u2"))
+	calendars, err = mem.ListCalendars(ctx, "u2")
+	require.NoError(t, err)
+	require.Empty(t, calendars)
+
+	calendars, err = mem.ListCalendars(ctx, "u1")
+	require.NoError(t, err)
+	require.Len(t, calendars, 2)
+
+	require.NoError(t, mem.UpdateCalendar(ctx, storage.Calendar{
+		ID: work, Name: "Office", TimeZone: "UTC", HolidayCountry: "RU", DeferReminders: true,
+	}))
+	c, err := mem.GetCalendar(ctx, work)
+	require.NoError(t, err)
+	require.Equal(t, "Office", c.Name)
+	require.Equal(t, "u1", c.OwnerID)
+	require.Equal(t, "RU", c.HolidayCountry)
+	require.True(t, c.DeferReminders)
+	require.ErrorIs(t, mem.UpdateCalendar(ctx, storage.Calendar{ID: 100, Name: "x"}), storage.ErrCalendarNotFound)
+}
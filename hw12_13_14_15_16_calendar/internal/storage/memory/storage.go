@@ -2,45 +2,119 @@ package memorystorage
 
 import (
 	"context"
+	"maps"
+	"slices"
 	"sync"
 	"time"
 
 	"mycalendar/internal/storage"
+	"mycalendar/internal/tenant"
 )
 
+// Storage хранит данные каждого тенанта отдельно. ID общие для всех тенантов,
+// как и в sql хранилище.
 type Storage struct {
 	mu     sync.RWMutex
+	spaces map[string]*space // key = tenant ID
+
+	lastID           int64
+	lastCalendarID   int64
+	lastHookID       int64
+	lastAttachmentID int64
+	lastDeliveryID   int64
+	deliveries       []storage.Delivery // всех тенантов, по возрастанию ID
+}
+
+// space - данные одного тенанта.
+type space struct {
 	events map[string][]storage.Event // key = userID
+	trash  []storage.Event            // удалённые события, в порядке удаления
+
+	calendars map[int64]storage.Calendar
+	audit     []storage.AuditRecord // только дописывается
+	webhooks  map[int64]storage.Webhook
+
+	attachments map[int64]storage.Attachment // и событий в корзине
 }
 
 func New() *Storage {
-	return &Storage{
-		events: make(map[string][]storage.Event),
+	return &Storage{spaces: make(map[string]*space)}
+}
+
+// space возвращает данные тенанта из ctx, создавая их при первой записи.
+// Нужна блокировка на запись.
+func (s *Storage) space(ctx context.Context) *space {
+	id := tenant.FromContext(ctx)
+	sp, ok := s.spaces[id]
+	if !ok {
+		sp = &space{
+			events:    make(map[string][]storage.Event),
+			calendars: make(map[int64]storage.Calendar),
+			webhooks:  make(map[int64]storage.Webhook),
+
+			attachments: make(map[int64]storage.Attachment),
+		}
+		s.spaces[id] = sp
 	}
+	return sp
 }
 
-func (s *Storage) AddEvent(ctx context.Context, e storage.Event) error {
-	_ = ctx
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	// Проверка на занятость времени
-	for _, ev := range s.events[e.UserID] {
-		if ev.StartDateTime.Equal(e.StartDateTime) {
-			return storage.ErrDateBusy
+// view - данные тенанта из ctx для чтения, у тенанта без данных они пустые.
+func (s *Storage) view(ctx context.Context) *space {
+	if sp, ok := s.spaces[tenant.FromContext(ctx)]; ok {
+		return sp
+	}
+	return &space{}
+}
+
+func (s *Storage) AddEvent(ctx context.Context, e storage.Event) (int64, error) {
+	defer s.lock(ctx)()
+	return s.addLocked(s.space(ctx), e)
+}
+
+func (s *Storage) addLocked(sp *space, e storage.Event) (int64, error) {
+	if sp.busyLocked(e.UserID, e.StartDateTime) {
+		return 0, storage.ErrDateBusy
+	}
+	if e.CalendarID == 0 {
+		e.CalendarID = s.defaultCalendarLocked(sp, e.UserID).ID
+	} else if _, ok := sp.calendars[e.CalendarID]; !ok {
+		return 0, storage.ErrCalendarNotFound
+	}
+	s.lastID++
+	e.EventID = s.lastID
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now()
+	}
+	e.Attendees = nil // участники добавляются через InviteAttendees, как и в sql хранилище
+	sp.events[e.UserID] = append(sp.events[e.UserID], e)
+	return e.EventID, nil
+}
+
+// busyLocked - у пользователя уже есть событие с этим временем начала.
+func (sp *space) busyLocked(userID string, start time.Time) bool {
+	for _, ev := range sp.events[userID] {
+		if ev.StartDateTime.Equal(start) {
+			return true
 		}
 	}
-	s.events[e.UserID] = append(s.events[e.UserID], e)
-	return nil
+	return false
 }
 
 func (s *Storage) UpdateEvent(ctx context.Context, e storage.Event) error {
-	_ = ctx
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	evs := s.events[e.UserID]
+	defer s.lock(ctx)()
+	sp := s.space(ctx)
+	evs := sp.events[e.UserID]
 	for i, ev := range evs {
 		if ev.StartDateTime.Equal(e.StartDateTime) {
-			s.events[e.UserID][i] = e
+			// ID, время создания, участники и календарь не меняются при обновлении
+			e.EventID = ev.EventID
+			e.CreatedAt = ev.CreatedAt
+			e.Attendees = ev.Attendees
+			e.CalendarID = ev.CalendarID
+			evs = slices.Clone(evs)
+			evs[i] = e
+			sp.events[e.UserID] = evs
 			return nil
 		}
 	}
@@ -48,59 +122,163 @@ func (s *Storage) UpdateEvent(ctx context.Context, e storage.Event) error {
 }
 
 func (s *Storage) DeleteEvent(ctx context.Context, userID string, start time.Time) error {
-	_ = ctx
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	evs := s.events[userID]
+	defer s.lock(ctx)()
+	sp := s.space(ctx)
+	evs := sp.events[userID]
 	for i, ev := range evs {
 		if ev.StartDateTime.Equal(start) {
-			// replacing append(s[:i], s[i+1]...) by slices.Delete(s, i, i+1), added in go1.21
-			s.events[userID] = append(evs[:i], evs[i+1:]...)
+			sp.trashLocked(userID, i, time.Now())
 			return nil
 		}
 	}
 	return storage.ErrNotFound
 }
 
+func (s *Storage) DeleteEventByID(ctx context.Context, id int64) error {
+	defer s.lock(ctx)()
+	sp := s.space(ctx)
+	for userID, evs := range sp.events {
+		for i, ev := range evs {
+			if ev.EventID == id {
+				sp.trashLocked(userID, i, time.Now())
+				return nil
+			}
+		}
+	}
+	return storage.ErrNotFound
+}
+
+// trashLocked переносит i-е событие пользователя в корзину.
+func (sp *space) trashLocked(userID string, i int, now time.Time) {
+	evs := sp.events[userID]
+	e := evs[i]
+	e.DeletedAt = now
+	sp.trash = append(sp.trash, e)
+	sp.events[userID] = slices.Delete(slices.Clone(evs), i, i+1)
+	if len(sp.events[userID]) == 0 {
+		delete(sp.events, userID)
+	}
+}
+
+func (s *Storage) ListTrash(ctx context.Context, userID string) ([]storage.Event, error) {
+	defer s.rlock(ctx)()
+	sp := s.view(ctx)
+
+	var result []storage.Event
+	for _, e := range sp.trash {
+		if userID == "" || e.UserID == userID {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+func (s *Storage) RestoreEvent(ctx context.Context, id int64) error {
+	defer s.lock(ctx)()
+	sp := s.space(ctx)
+
+	i := slices.IndexFunc(sp.trash, func(e storage.Event) bool { return e.EventID == id })
+	if i < 0 {
+		return storage.ErrNotFound
+	}
+	e := sp.trash[i]
+	for _, ev := range sp.events[e.UserID] {
+		if ev.StartDateTime.Equal(e.StartDateTime) {
+			return storage.ErrDateBusy
+		}
+	}
+	e.DeletedAt = time.Time{}
+	sp.events[e.UserID] = append(sp.events[e.UserID], e)
+	sp.trash = slices.Delete(slices.Clone(sp.trash), i, i+1)
+	return nil
+}
+
+func (s *Storage) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	defer s.lock(ctx)()
+	sp := s.space(ctx)
+
+	n := len(sp.trash)
+	purged := make(map[int64]bool)
+	sp.trash = slices.DeleteFunc(slices.Clone(sp.trash), func(e storage.Event) bool {
+		if e.DeletedAt.Before(before) {
+			purged[e.EventID] = true
+			return true
+		}
+		return false
+	})
+	// вложения удаляются вместе с событием, как ON DELETE CASCADE в sql хранилище
+	maps.DeleteFunc(sp.attachments, func(_ int64, a storage.Attachment) bool { return purged[a.EventID] })
+	return int64(n - len(sp.trash)), nil
+}
+
+func (s *Storage) GetEvent(ctx context.Context, id int64) (storage.Event, error) {
+	defer s.rlock(ctx)()
+	sp := s.view(ctx)
+	for _, evs := range sp.events {
+		for _, ev := range evs {
+			if ev.EventID == id {
+				return ev, nil
+			}
+		}
+	}
+	return storage.Event{}, storage.ErrNotFound
+}
+
+func (s *Storage) ListEvents(ctx context.Context, f storage.EventFilter) ([]storage.Event, error) {
+	defer s.rlock(ctx)()
+	sp := s.view(ctx)
+
+	var result []storage.Event
+	for _, evs := range sp.events {
+		for _, e := range evs {
+			if f.Match(e) {
+				result = append(result, e)
+			}
+		}
+	}
+	return result, nil
+}
+
 func (s *Storage) GetEvents(ctx context.Context) ([]storage.Event, error) {
-	_ = ctx
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	defer s.rlock(ctx)()
+	sp := s.view(ctx)
 	var result []storage.Event
-	for _, evs := range s.events {
+	for _, evs := range sp.events {
 		result = append(result, evs...)
 	}
 	return result, nil
 }
 
 func (s *Storage) DeleteOldEvents(ctx context.Context, before time.Time) error {
-	_ = ctx
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	defer s.lock(ctx)()
+	sp := s.space(ctx)
 
-	for userID, evs := range s.events {
+	now := time.Now()
+	for userID, evs := range sp.events {
 		var filtered []storage.Event
 		for _, e := range evs {
 			if !e.StartDateTime.Before(before) {
 				filtered = append(filtered, e)
+				continue
 			}
+			e.DeletedAt = now
+			sp.trash = append(sp.trash, e)
 		}
 		if len(filtered) == 0 {
-			delete(s.events, userID) // clean up empty slice
+			delete(sp.events, userID) // clean up empty slice
 		} else {
-			s.events[userID] = filtered
+			sp.events[userID] = filtered
 		}
 	}
 	return nil
 }
 
 func (s *Storage) GetUpcomingEvents(ctx context.Context, from time.Time) ([]storage.Event, error) {
-	_ = ctx
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	defer s.rlock(ctx)()
+	sp := s.view(ctx)
 
 	var result []storage.Event
-	for _, evs := range s.events {
+	for _, evs := range sp.events {
 		for _, e := range evs {
 			if !e.StartDateTime.Before(from) {
 				result = append(result, e)
@@ -111,11 +289,10 @@ func (s *Storage) GetUpcomingEvents(ctx context.Context, from time.Time) ([]stor
 }
 
 func (s *Storage) GetEventsByDay(ctx context.Context, date time.Time) ([]storage.Event, error) {
-	_ = ctx
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	defer s.rlock(ctx)()
+	sp := s.view(ctx)
 	var result []storage.Event
-	for _, evs := range s.events {
+	for _, evs := range sp.events {
 		for _, e := range evs {
 			if e.StartDateTime.Year() == date.Year() &&
 				e.StartDateTime.YearDay() == date.YearDay() {
@@ -127,14 +304,13 @@ func (s *Storage) GetEventsByDay(ctx context.Context, date time.Time) ([]storage
 }
 
 func (s *Storage) GetEventsByWeek(ctx context.Context, date time.Time) ([]storage.Event, error) {
-	_ = ctx
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	defer s.rlock(ctx)()
+	sp := s.view(ctx)
 
 	var result []storage.Event
 
 	year, weekNum := date.ISOWeek()
-	for _, evs := range s.events {
+	for _, evs := range sp.events {
 		for _, e := range evs {
 			evYear, evWeek := e.StartDateTime.ISOWeek()
 			if evYear == year && evWeek == weekNum {
@@ -146,16 +322,15 @@ func (s *Storage) GetEventsByWeek(ctx context.Context, date time.Time) ([]storag
 }
 
 func (s *Storage) GetEventsByMonth(ctx context.Context, date time.Time) ([]storage.Event, error) {
-	_ = ctx
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	defer s.rlock(ctx)()
+	sp := s.view(ctx)
 
 	var result []storage.Event
 
 	year := date.Year()
 	monthNum := date.Month()
 
-	for _, evs := range s.events {
+	for _, evs := range sp.events {
 		for _, e := range evs {
 			if e.StartDateTime.Year() == year && e.StartDateTime.Month() == monthNum {
 				result = append(result, e)
@@ -164,3 +339,80 @@ func (s *Storage) GetEventsByMonth(ctx context.Context, date time.Time) ([]stora
 	}
 	return result, nil
 }
+
+// Участники меняются копированием среза, поэтому события, уже отданные
+// читателям, не меняются у них под руками.
+
+func (s *Storage) InviteAttendees(ctx context.Context, eventID int64, userIDs []string) error {
+	defer s.lock(ctx)()
+	sp := s.space(ctx)
+
+	e, err := sp.findLocked(eventID)
+	if err != nil {
+		return err
+	}
+	attendees := slices.Clone(e.Attendees)
+	for _, id := range userIDs {
+		invited := slices.ContainsFunc(attendees, func(a storage.Attendee) bool { return a.UserID == id })
+		if id == "" || id == e.UserID || invited {
+			continue
+		}
+		attendees = append(attendees, storage.Attendee{UserID: id, Status: storage.RSVPNeedsAction})
+	}
+	e.Attendees = attendees
+	return nil
+}
+
+func (s *Storage) SetAttendeeStatus(ctx context.Context, eventID int64, userID string, status storage.RSVPStatus) error {
+	if !status.Valid() {
+		return storage.ErrInvalidStatus
+	}
+	defer s.lock(ctx)()
+	sp := s.space(ctx)
+
+	e, err := sp.findLocked(eventID)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(e.Attendees, func(a storage.Attendee) bool { return a.UserID == userID })
+	if i < 0 {
+		return storage.ErrNotAttendee
+	}
+	attendees := slices.Clone(e.Attendees)
+	attendees[i].Status = status
+	e.Attendees = attendees
+	return nil
+}
+
+func (s *Storage) GetBusyEvents(ctx context.Context, userIDs []string, from, to time.Time) ([]storage.Event, error) {
+	defer s.rlock(ctx)()
+	sp := s.view(ctx)
+
+	var result []storage.Event
+	for _, evs := range sp.events {
+		for _, e := range evs {
+			if !e.Overlaps(from, to) {
+				continue
+			}
+			if slices.ContainsFunc(userIDs, e.Involves) {
+				result = append(result, e)
+			}
+		}
+	}
+	return result, nil
+}
+
+// findLocked возвращает указатель на событие внутри sp.events, нужна блокировка на запись.
+// Срез событий владельца перед этим копируется, чтобы не менять снимок транзакции.
+func (sp *space) findLocked(id int64) (*storage.Event, error) {
+	for userID, evs := range sp.events {
+		for i := range evs {
+			if evs[i].EventID == id {
+				evs = slices.Clone(evs)
+				sp.events[userID] = evs
+				return &evs[i], nil
+			}
+		}
+	}
+	return nil, storage.ErrNotFound
+}
@@ -2,11 +2,13 @@ package memorystorage
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 	"mycalendar/internal/storage"
+	"mycalendar/internal/tenant"
 )
 
 func TestStorage_AddEvent(t *testing.T) {
@@ -19,11 +21,12 @@ func TestStorage_AddEvent(t *testing.T) {
 		StartDateTime: time.Date(2025, 5, 13, 15, 0, 0, 0, time.UTC),
 	}
 
-	err := mem.AddEvent(ctx, event)
+	id, err := mem.AddEvent(ctx, event)
 	require.NoError(t, err)
+	require.NotZero(t, id)
 
 	// попытка добавить в то же время
-	err = mem.AddEvent(ctx, event)
+	_, err = mem.AddEvent(ctx, event)
 	require.ErrorIs(t, err, storage.ErrDateBusy)
 
 	events, err := mem.GetEvents(ctx)
@@ -43,7 +46,8 @@ func TestStorage_UpdateAndDelete(t *testing.T) {
 		StartDateTime: start,
 	}
 
-	require.NoError(t, mem.AddEvent(ctx, event))
+	_, err := mem.AddEvent(ctx, event)
+	require.NoError(t, err)
 
 	// update
 	event.Title = "updated call"
@@ -53,7 +57,7 @@ func TestStorage_UpdateAndDelete(t *testing.T) {
 	require.Equal(t, "updated call", evs[0].Title)
 
 	// delete
-	err := mem.DeleteEvent(ctx, "u1", start)
+	err = mem.DeleteEvent(ctx, "u1", start)
 	require.NoError(t, err)
 
 	evs, _ = mem.GetEvents(ctx)
@@ -187,3 +191,303 @@ func TestStorage_GetEventsByMonth(t *testing.T) {
 	require.Len(t, events, 1)
 	require.Equal(t, "June event", events[0].Title)
 }
+
+func TestStorage_GetAndDeleteByID(t *testing.T) {
+	mem := New()
+	ctx := context.Background()
+
+	id, err := mem.AddEvent(ctx, storage.Event{
+		UserID:        "u1",
+		Title:         "by id",
+		StartDateTime: time.Date(2025, 6, 10, 10, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	e, err := mem.GetEvent(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, "by id", e.Title)
+	require.Equal(t, id, e.EventID)
+	require.False(t, e.CreatedAt.IsZero())
+
+	require.NoError(t, mem.DeleteEventByID(ctx, id))
+	_, err = mem.GetEvent(ctx, id)
+	require.ErrorIs(t, err, storage.ErrNotFound)
+	require.ErrorIs(t, mem.DeleteEventByID(ctx, id), storage.ErrNotFound)
+}
+
+func TestStorage_ListEvents(t *testing.T) {
+	mem := New()
+	ctx := context.Background()
+
+	day := time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC)
+	mem.AddEvent(ctx, storage.Event{UserID: "u1", Title: "morning", StartDateTime: day.Add(9 * time.Hour)})
+	mem.AddEvent(ctx, storage.Event{UserID: "u1", Title: "next day", StartDateTime: day.Add(33 * time.Hour)})
+	mem.AddEvent(ctx, storage.Event{UserID: "u2", Title: "other user", StartDateTime: day.Add(10 * time.Hour)})
+
+	events, err := mem.ListEvents(ctx, storage.EventFilter{UserID: "u1"})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	events, err = mem.ListEvents(ctx, storage.EventFilter{From: day, To: day.AddDate(0, 0, 1)})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	events, err = mem.ListEvents(ctx, storage.EventFilter{UserID: "u1", From: day, To: day.AddDate(0, 0, 1)})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "morning", events[0].Title)
+}
+
+func TestStorage_Attendees(t *testing.T) {
+	mem := New()
+	ctx := context.Background()
+
+	day := time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC)
+	id, err := mem.AddEvent(ctx, storage.Event{UserID: "owner", Title: "meeting", StartDateTime: day.Add(9 * time.Hour)})
+	require.NoError(t, err)
+
+	// владелец и повторные приглашения пропускаются
+	require.NoError(t, mem.InviteAttendees(ctx, id, []string{"alice", "bob", "owner"}))
+	require.NoError(t, mem.InviteAttendees(ctx, id, []string{"alice"}))
+	before, err := mem.GetEvent(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, []storage.Attendee{
+		{UserID: "alice", Status: storage.RSVPNeedsAction},
+		{UserID: "bob", Status: storage.RSVPNeedsAction},
+	}, before.Attendees)
+
+	require.NoError(t, mem.SetAttendeeStatus(ctx, id, "alice", storage.RSVPAccepted))
+	require.NoError(t, mem.SetAttendeeStatus(ctx, id, "bob", storage.RSVPDeclined))
+	require.ErrorIs(t, mem.SetAttendeeStatus(ctx, id, "carol", storage.RSVPAccepted), storage.ErrNotAttendee)
+	require.ErrorIs(t, mem.SetAttendeeStatus(ctx, id, "alice", "maybe"), storage.ErrInvalidStatus)
+	require.ErrorIs(t, mem.InviteAttendees(ctx, 42, []string{"alice"}), storage.ErrNotFound)
+
+	// ранее полученная копия события не меняется
+	require.Equal(t, storage.RSVPNeedsAction, before.Attendees[0].Status)
+
+	// участник видит событие у себя, отказавшийся - нет
+	events, err := mem.ListEvents(ctx, storage.EventFilter{UserID: "alice"})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	events, err = mem.ListEvents(ctx, storage.EventFilter{UserID: "bob"})
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	// обновление события не сбрасывает участников
+	require.NoError(t, mem.UpdateEvent(ctx, storage.Event{UserID: "owner", Title: "renamed", StartDateTime: day.Add(9 * time.Hour)}))
+	e, err := mem.GetEvent(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, e.Attendees, 2)
+	require.Equal(t, []string{"owner", "alice"}, e.Recipients())
+}
+
+func TestStorage_GetBusyEvents(t *testing.T) {
+	mem := New()
+	ctx := context.Background()
+	day := time.Date(2025, 5, 13, 0, 0, 0, 0, time.UTC)
+
+	_, err := mem.AddEvent(ctx, storage.Event{UserID: "u1", StartDateTime: day.Add(9 * time.Hour), Duration: "1h"})
+	require.NoError(t, err)
+	// началось раньше окна, но заканчивается внутри него
+	_, err = mem.AddEvent(ctx, storage.Event{UserID: "u1", StartDateTime: day.Add(-time.Hour), Duration: "1d"})
+	require.NoError(t, err)
+	id, err := mem.AddEvent(ctx, storage.Event{UserID: "u2", StartDateTime: day.Add(14 * time.Hour), Duration: "30m"})
+	require.NoError(t, err)
+	require.NoError(t, mem.InviteAttendees(ctx, id, []string{"u3"}))
+	_, err = mem.AddEvent(ctx, storage.Event{UserID: "u1", StartDateTime: day.AddDate(0, 0, 1), Duration: "1h"})
+	require.NoError(t, err)
+
+	evs, err := mem.GetBusyEvents(ctx, []string{"u1"}, day, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.Len(t, evs, 2)
+
+	evs, err = mem.GetBusyEvents(ctx, []string{"u3"}, day, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.Len(t, evs, 1)
+
+	require.NoError(t, mem.SetAttendeeStatus(ctx, id, "u3", storage.RSVPDeclined))
+	evs, err = mem.GetBusyEvents(ctx, []string{"u3"}, day, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.Empty(t, evs)
+}
+
+func TestStorage_TrashAndRestore(t *testing.T) {
+	mem := New()
+	ctx := context.Background()
+	start := time.Date(2025, 5, 13, 15, 0, 0, 0, time.UTC)
+
+	id, err := mem.AddEvent(ctx, storage.Event{UserID: "u1", Title: "call", StartDateTime: start})
+	require.NoError(t, err)
+	require.NoError(t, mem.DeleteEventByID(ctx, id))
+	require.ErrorIs(t, mem.DeleteEventByID(ctx, id), storage.ErrNotFound)
+
+	_, err = mem.GetEvent(ctx, id)
+	require.ErrorIs(t, err, storage.ErrNotFound)
+	trash, err := mem.ListTrash(ctx, "u1")
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+	require.False(t, trash[0].DeletedAt.IsZero())
+	trash, err = mem.ListTrash(ctx, "u2")
+	require.NoError(t, err)
+	require.Empty(t, trash)
+
+	// время удалённого события свободно, поэтому восстановить его уже нельзя
+	newID, err := mem.AddEvent(ctx, storage.Event{UserID: "u1", Title: "new call", StartDateTime: start})
+	require.NoError(t, err)
+	require.ErrorIs(t, mem.RestoreEvent(ctx, id), storage.ErrDateBusy)
+
+	require.NoError(t, mem.DeleteEvent(ctx, "u1", start))
+	require.NoError(t, mem.RestoreEvent(ctx, id))
+	e, err := mem.GetEvent(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, "call", e.Title)
+	require.True(t, e.DeletedAt.IsZero())
+	require.ErrorIs(t, mem.RestoreEvent(ctx, id), storage.ErrNotFound)
+
+	// в корзине осталось событие newID, очищается только то, что старше порога
+	n, err := mem.PurgeTrash(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Zero(t, n)
+	n, err = mem.PurgeTrash(ctx, time.Now().Add(time.Second))
+	require.NoError(t, err)
+	require.EqualValues(t, 1, n)
+	require.ErrorIs(t, mem.RestoreEvent(ctx, newID), storage.ErrNotFound)
+}
+
+func TestStorage_Audit(t *testing.T) {
+	mem := New()
+	ctx := context.Background()
+	e := storage.Event{EventID: 1, Title: "call"}
+
+	require.NoError(t, mem.AppendAudit(ctx, storage.AuditRecord{EventID: 1, Action: storage.AuditCreate, After: &e}))
+	require.NoError(t, mem.AppendAudit(ctx, storage.AuditRecord{EventID: 2, Action: storage.AuditCreate}))
+	require.NoError(t, mem.AppendAudit(ctx, storage.AuditRecord{EventID: 1, Action: storage.AuditDelete, Before: &e}))
+
+	history, err := mem.EventHistory(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, storage.AuditCreate, history[0].Action)
+	require.Equal(t, storage.AuditDelete, history[1].Action)
+	require.Less(t, history[0].ID, history[1].ID)
+	require.False(t, history[0].At.IsZero())
+}
+
+func TestStorage_TenantIsolation(t *testing.T) {
+	mem := New()
+	acme := tenant.With(context.Background(), "acme")
+	globex := tenant.With(context.Background(), "globex")
+	start := time.Date(2025, 5, 13, 15, 0, 0, 0, time.UTC)
+
+	id, err := mem.AddEvent(acme, storage.Event{UserID: "u1", Title: "acme", StartDateTime: start})
+	require.NoError(t, err)
+	// то же время того же пользователя в другом тенанте свободно
+	_, err = mem.AddEvent(globex, storage.Event{UserID: "u1", Title: "globex", StartDateTime: start})
+	require.NoError(t, err)
+
+	_, err = mem.GetEvent(globex, id)
+	require.ErrorIs(t, err, storage.ErrNotFound)
+	require.ErrorIs(t, mem.DeleteEventByID(globex, id), storage.ErrNotFound)
+	require.ErrorIs(t, mem.InviteAttendees(globex, id, []string{"u2"}), storage.ErrNotFound)
+
+	events, err := mem.GetEventsByDay(globex, start)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "globex", events[0].Title)
+
+	e, err := mem.GetEvent(acme, id)
+	require.NoError(t, err)
+	_, err = mem.GetCalendar(globex, e.CalendarID)
+	require.ErrorIs(t, err, storage.ErrCalendarNotFound)
+
+	require.NoError(t, mem.AppendAudit(acme, storage.AuditRecord{EventID: id, Action: storage.AuditCreate}))
+	history, err := mem.EventHistory(globex, id)
+	require.NoError(t, err)
+	require.Empty(t, history)
+
+	// без тенанта в контексте - тенант по умолчанию, у него ничего нет
+	events, err = mem.GetEvents(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, events)
+}
+
+func TestStorage_Attachments(t *testing.T) {
+	ctx := context.Background()
+	mem := New()
+	start := time.Date(2025, 5, 13, 15, 0, 0, 0, time.UTC)
+	id, err := mem.AddEvent(ctx, storage.Event{UserID: "u1", StartDateTime: start})
+	require.NoError(t, err)
+
+	_, err = mem.AddAttachment(ctx, storage.Attachment{EventID: id + 1, Name: "x"})
+	require.ErrorIs(t, err, storage.ErrNotFound)
+	fileID, err := mem.AddAttachment(ctx, storage.Attachment{EventID: id, Name: "agenda.pdf", BlobKey: "default/1/a", Size: 10})
+	require.NoError(t, err)
+	linkID, err := mem.AddAttachment(ctx, storage.Attachment{EventID: id, Name: "doc", URL: "https://example.com"})
+	require.NoError(t, err)
+
+	list, err := mem.ListAttachments(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	require.Equal(t, fileID, list[0].ID)
+	require.True(t, list[1].IsLink())
+
+	require.NoError(t, mem.DeleteAttachment(ctx, linkID))
+	require.ErrorIs(t, mem.DeleteAttachment(ctx, linkID), storage.ErrAttachmentNotFound)
+
+	// в корзине вложение не видно, но существует до очистки
+	require.NoError(t, mem.DeleteEventByID(ctx, id))
+	_, err = mem.GetAttachment(ctx, fileID)
+	require.ErrorIs(t, err, storage.ErrAttachmentNotFound)
+	trash, err := mem.TrashAttachments(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+
+	_, err = mem.PurgeTrash(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	exist, err := mem.AttachmentsExist(ctx, []int64{fileID})
+	require.NoError(t, err)
+	require.False(t, exist[fileID])
+}
+
+func TestStorage_InTx(t *testing.T) {
+	mem := New()
+	ctx := context.Background()
+	day := time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC)
+
+	id, err := mem.AddEvent(ctx, storage.Event{UserID: "owner", Title: "kept", StartDateTime: day.Add(9 * time.Hour)})
+	require.NoError(t, err)
+
+	// ошибка откатывает все изменения транзакции
+	errAbort := errors.New("abort")
+	err = mem.InTx(ctx, func(ctx context.Context, tx storage.Storage) error {
+		if _, err := tx.AddEvent(ctx, storage.Event{UserID: "owner", Title: "new", StartDateTime: day.Add(10 * time.Hour)}); err != nil {
+			return err
+		}
+		require.NoError(t, tx.InviteAttendees(ctx, id, []string{"alice"}))
+		require.NoError(t, tx.UpdateEvent(ctx, storage.Event{UserID: "owner", Title: "renamed", StartDateTime: day.Add(9 * time.Hour)}))
+		require.NoError(t, tx.DeleteEventByID(ctx, id))
+		return errAbort
+	})
+	require.ErrorIs(t, err, errAbort)
+
+	events, err := mem.GetEvents(ctx)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "kept", events[0].Title)
+	require.Empty(t, events[0].Attendees)
+	trash, err := mem.ListTrash(ctx, "")
+	require.NoError(t, err)
+	require.Empty(t, trash)
+
+	// без ошибки изменения сохраняются, вложенная транзакция - часть внешней
+	var added int64
+	err = mem.InTx(ctx, func(ctx context.Context, tx storage.Storage) error {
+		return tx.InTx(ctx, func(ctx context.Context, tx storage.Storage) error {
+			added, err = tx.AddEvent(ctx, storage.Event{UserID: "owner", Title: "new", StartDateTime: day.Add(10 * time.Hour)})
+			return err
+		})
+	})
+	require.NoError(t, err)
+	require.Equal(t, id+1, added)
+	_, err = mem.GetEvent(ctx, added)
+	require.NoError(t, err)
+}
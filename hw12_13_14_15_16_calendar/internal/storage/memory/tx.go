@@ -0,0 +1,93 @@
+package memorystorage
+
+import (
+	"context"
+	"maps"
+
+	"mycalendar/internal/storage"
+)
+
+type txKey struct{}
+
+// InTx держит блокировку на запись всё время fn, поэтому транзакции и обычные
+// вызовы выполняются по очереди. Методы, вызванные с ctx транзакции, блокировку
+// не берут. При ошибке данные возвращаются к снимку, снятому перед fn.
+func (s *Storage) InTx(ctx context.Context, fn func(ctx context.Context, tx storage.Storage) error) error {
+	if ctx.Value(txKey{}) == s {
+		return fn(ctx, s)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved := s.snapshotLocked()
+	if err := fn(context.WithValue(ctx, txKey{}, s), s); err != nil {
+		s.restoreLocked(saved)
+		return err
+	}
+	return nil
+}
+
+// lock берёт блокировку на запись, если её уже не держит транзакция из ctx.
+func (s *Storage) lock(ctx context.Context) (unlock func()) {
+	if ctx.Value(txKey{}) == s {
+		return func() {}
+	}
+	s.mu.Lock()
+	return s.mu.Unlock
+}
+
+// rlock - то же для чтения.
+func (s *Storage) rlock(ctx context.Context) (unlock func()) {
+	if ctx.Value(txKey{}) == s {
+		return func() {}
+	}
+	s.mu.RLock()
+	return s.mu.RUnlock
+}
+
+// snapshot - копия данных хранилища для отката транзакции. Срезы событий,
+// корзины, участников и прав меняются копированием, а журнал и доставки внутри
+// транзакции только дописываются, поэтому копируются лишь отображения.
+type snapshot struct {
+	spaces map[string]*space
+
+	lastID           int64
+	lastCalendarID   int64
+	lastHookID       int64
+	lastAttachmentID int64
+	lastDeliveryID   int64
+	deliveries       []storage.Delivery
+}
+
+func (s *Storage) snapshotLocked() snapshot {
+	spaces := make(map[string]*space, len(s.spaces))
+	for id, sp := range s.spaces {
+		spaces[id] = &space{
+			events:      maps.Clone(sp.events),
+			trash:       sp.trash,
+			calendars:   maps.Clone(sp.calendars),
+			audit:       sp.audit,
+			webhooks:    maps.Clone(sp.webhooks),
+			attachments: maps.Clone(sp.attachments),
+		}
+	}
+	return snapshot{
+		spaces:           spaces,
+		lastID:           s.lastID,
+		lastCalendarID:   s.lastCalendarID,
+		lastHookID:       s.lastHookID,
+		lastAttachmentID: s.lastAttachmentID,
+		lastDeliveryID:   s.lastDeliveryID,
+		deliveries:       s.deliveries,
+	}
+}
+
+func (s *Storage) restoreLocked(saved snapshot) {
+	s.spaces = saved.spaces
+	s.lastID = saved.lastID
+	s.lastCalendarID = saved.lastCalendarID
+	s.lastHookID = saved.lastHookID
+	s.lastAttachmentID = saved.lastAttachmentID
+	s.lastDeliveryID = saved.lastDeliveryID
+	s.deliveries = saved.deliveries
+}
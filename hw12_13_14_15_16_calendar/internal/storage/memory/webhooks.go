@@ -0,0 +1,187 @@
+package memorystorage
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"mycalendar/internal/storage"
+	"mycalendar/internal/tenant"
+)
+
+func (s *Storage) CreateWebhook(ctx context.Context, w storage.Webhook) (int64, error) {
+	defer s.lock(ctx)()
+	sp := s.space(ctx)
+	s.lastHookID++
+	w.ID = s.lastHookID
+	if w.CreatedAt.IsZero() {
+		w.CreatedAt = time.Now()
+	}
+	w.Events = slices.Clone(w.Events)
+	sp.webhooks[w.ID] = w
+	return w.ID, nil
+}
+
+func (s *Storage) GetWebhook(ctx context.Context, id int64) (storage.Webhook, error) {
+	defer s.rlock(ctx)()
+	sp := s.view(ctx)
+	w, ok := sp.webhooks[id]
+	if !ok {
+		return storage.Webhook{}, storage.ErrWebhookNotFound
+	}
+	return w, nil
+}
+
+func (s *Storage) ListWebhooks(ctx context.Context, ownerID string) ([]storage.Webhook, error) {
+	defer s.rlock(ctx)()
+	sp := s.view(ctx)
+	var result []storage.Webhook
+	for _, w := range sp.webhooks {
+		if w.OwnerID == ownerID {
+			result = append(result, w)
+		}
+	}
+	slices.SortFunc(result, func(a, b storage.Webhook) int { return cmp.Compare(a.ID, b.ID) })
+	return result, nil
+}
+
+func (s *Storage) DeleteWebhook(ctx context.Context, id int64) error {
+	defer s.lock(ctx)()
+	sp := s.space(ctx)
+	if _, ok := sp.webhooks[id]; !ok {
+		return storage.ErrWebhookNotFound
+	}
+	delete(sp.webhooks, id)
+	// доставки удалённой подписки перестают выдаваться и удаляются PruneDeliveries
+	return nil
+}
+
+func (s *Storage) EnableWebhook(ctx context.Context, id int64) error {
+	defer s.lock(ctx)()
+	sp := s.space(ctx)
+	w, ok := sp.webhooks[id]
+	if !ok {
+		return storage.ErrWebhookNotFound
+	}
+	w.Active, w.Failures, w.DisabledAt = true, 0, time.Time{}
+	sp.webhooks[id] = w
+	return nil
+}
+
+func (s *Storage) RecordWebhookResult(ctx context.Context, id int64, ok bool, disableAfter int) (bool, error) {
+	defer s.lock(ctx)()
+	sp := s.space(ctx)
+	w, found := sp.webhooks[id]
+	if !found {
+		return false, storage.ErrWebhookNotFound
+	}
+	if ok {
+		w.Failures = 0
+	} else {
+		w.Failures++
+		if w.Active && disableAfter > 0 && w.Failures >= disableAfter {
+			w.Active, w.DisabledAt = false, time.Now()
+		}
+	}
+	sp.webhooks[id] = w
+	return w.Active, nil
+}
+
+func (s *Storage) EnqueueDeliveries(ctx context.Context, ds []storage.Delivery) error {
+	defer s.lock(ctx)()
+	id := tenant.FromContext(ctx)
+	for _, d := range ds {
+		if d.Key != "" && slices.ContainsFunc(s.deliveries, func(old storage.Delivery) bool {
+			return old.WebhookID == d.WebhookID && old.Key == d.Key
+		}) {
+			continue
+		}
+		s.lastDeliveryID++
+		d.ID = s.lastDeliveryID
+		d.Tenant = id
+		if d.State == "" {
+			d.State = storage.DeliveryPending
+		}
+		if d.CreatedAt.IsZero() {
+			d.CreatedAt = time.Now()
+		}
+		if d.NextAttempt.IsZero() {
+			d.NextAttempt = d.CreatedAt
+		}
+		s.deliveries = append(s.deliveries, d)
+	}
+	return nil
+}
+
+// ClaimDeliveries выдаёт доставки всех тенантов, тенант записан в каждой.
+func (s *Storage) ClaimDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]storage.Delivery, error) {
+	_ = ctx
+	defer s.lock(ctx)()
+	var result []storage.Delivery
+	for i, d := range s.deliveries {
+		if len(result) == limit {
+			break
+		}
+		var w storage.Webhook
+		sp, ok := s.spaces[d.Tenant]
+		if ok {
+			w, ok = sp.webhooks[d.WebhookID]
+		}
+		if !ok || !w.Active || d.State != storage.DeliveryPending || d.NextAttempt.After(now) {
+			continue
+		}
+		result = append(result, d)
+		s.deliveries[i].NextAttempt = now.Add(lease)
+	}
+	return result, nil
+}
+
+func (s *Storage) UpdateDelivery(ctx context.Context, d storage.Delivery) error {
+	defer s.lock(ctx)()
+	i, ok := slices.BinarySearchFunc(s.deliveries, d.ID, func(d storage.Delivery, id int64) int { return cmp.Compare(d.ID, id) })
+	if !ok || s.deliveries[i].Tenant != tenant.FromContext(ctx) {
+		return storage.ErrNotFound
+	}
+	old := &s.deliveries[i]
+	old.State = d.State
+	old.Attempts = d.Attempts
+	old.NextAttempt = d.NextAttempt
+	old.LastStatus = d.LastStatus
+	old.LastError = d.LastError
+	old.DeliveredAt = d.DeliveredAt
+	return nil
+}
+
+func (s *Storage) ListDeliveries(ctx context.Context, webhookID int64, limit int) ([]storage.Delivery, error) {
+	defer s.rlock(ctx)()
+	sp := s.view(ctx)
+	if _, ok := sp.webhooks[webhookID]; !ok {
+		return nil, storage.ErrWebhookNotFound
+	}
+	var result []storage.Delivery
+	for i := len(s.deliveries) - 1; i >= 0 && len(result) < limit; i-- {
+		if s.deliveries[i].WebhookID == webhookID { // ID вебхуков общие, тенант уже проверен
+			result = append(result, s.deliveries[i])
+		}
+	}
+	return result, nil
+}
+
+// PruneDeliveries удаляет доставки всех тенантов, созданные до before: завершённые
+// и оставшиеся от удалённых подписок.
+func (s *Storage) PruneDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	defer s.lock(ctx)()
+	n := len(s.deliveries)
+	s.deliveries = slices.DeleteFunc(slices.Clone(s.deliveries), func(d storage.Delivery) bool {
+		if !d.CreatedAt.Before(before) {
+			return false
+		}
+		sp, ok := s.spaces[d.Tenant]
+		if ok {
+			_, ok = sp.webhooks[d.WebhookID]
+		}
+		return !ok || d.State != storage.DeliveryPending
+	})
+	return int64(n - len(s.deliveries)), nil
+}
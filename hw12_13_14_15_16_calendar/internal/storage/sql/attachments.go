@@ -0,0 +1,133 @@
+package sqlstorage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"mycalendar/internal/storage"
+	"mycalendar/internal/tenant"
+)
+
+// тенант вложения - тенант его события, поэтому запросы идут через events
+const attachmentColumns = `a.id, a.event_id, a.name, a.content_type, a.size, a.blob_key, a.url,
+	a.created_by, a.created_at`
+
+func scanAttachment(sc scanner) (storage.Attachment, error) {
+	var a storage.Attachment
+	err := sc.Scan(&a.ID, &a.EventID, &a.Name, &a.ContentType, &a.Size, &a.BlobKey, &a.URL, &a.CreatedBy, &a.CreatedAt)
+	return a, err
+}
+
+func (s *Storage) AddAttachment(ctx context.Context, a storage.Attachment) (int64, error) {
+	var id int64
+	err := s.q.QueryRowContext(ctx, `
+		INSERT INTO event_attachments (event_id, name, content_type, size, blob_key, url, created_by)
+		SELECT id, $2, $3, $4, $5, $6, $7
+		FROM events
+		WHERE tenant_id = $8 AND id = $1 AND deleted_at IS NULL
+		RETURNING id
+	`, a.EventID, a.Name, a.ContentType, a.Size, a.BlobKey, a.URL, a.CreatedBy, tenant.FromContext(ctx)).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, storage.ErrNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("cannot insert attachment: %w", err)
+	}
+	return id, nil
+}
+
+func (s *Storage) GetAttachment(ctx context.Context, id int64) (storage.Attachment, error) {
+	a, err := scanAttachment(s.q.QueryRowContext(ctx, `
+		SELECT `+attachmentColumns+`
+		FROM event_attachments a JOIN events e ON e.id = a.event_id
+		WHERE e.tenant_id = $2 AND a.id = $1 AND e.deleted_at IS NULL
+	`, id, tenant.FromContext(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return storage.Attachment{}, storage.ErrAttachmentNotFound
+	}
+	if err != nil {
+		return storage.Attachment{}, fmt.Errorf("cannot select attachment: %w", err)
+	}
+	return a, nil
+}
+
+func (s *Storage) ListAttachments(ctx context.Context, eventID int64) ([]storage.Attachment, error) {
+	return s.queryAttachments(ctx, `
+		SELECT `+attachmentColumns+`
+		FROM event_attachments a JOIN events e ON e.id = a.event_id
+		WHERE e.tenant_id = $2 AND a.event_id = $1
+		ORDER BY a.id
+	`, eventID, tenant.FromContext(ctx))
+}
+
+func (s *Storage) DeleteAttachment(ctx context.Context, id int64) error {
+	res, err := s.q.ExecContext(ctx, `
+		DELETE FROM event_attachments
+		WHERE id = $1 AND event_id IN (SELECT id FROM events WHERE tenant_id = $2)
+	`, id, tenant.FromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("cannot delete attachment: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return storage.ErrAttachmentNotFound
+	}
+	return nil
+}
+
+func (s *Storage) TrashAttachments(ctx context.Context, before time.Time) ([]storage.Attachment, error) {
+	return s.queryAttachments(ctx, `
+		SELECT `+attachmentColumns+`
+		FROM event_attachments a JOIN events e ON e.id = a.event_id
+		WHERE e.tenant_id = $2 AND e.deleted_at < $1
+		ORDER BY a.id
+	`, before, tenant.FromContext(ctx))
+}
+
+func (s *Storage) AttachmentsExist(ctx context.Context, ids []int64) (map[int64]bool, error) {
+	result := make(map[int64]bool, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+	rows, err := s.q.QueryContext(ctx, `
+		SELECT a.id
+		FROM event_attachments a JOIN events e ON e.id = a.event_id
+		WHERE e.tenant_id = $2 AND a.id = ANY($1)
+	`, ids, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("cannot select attachments: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("cannot scan attachment: %w", err)
+		}
+		result[id] = true
+	}
+	return result, rows.Err()
+}
+
+func (s *Storage) queryAttachments(ctx context.Context, query string, args ...any) ([]storage.Attachment, error) {
+	rows, err := s.q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("cannot select attachments: %w", err)
+	}
+	defer rows.Close()
+
+	var result []storage.Attachment
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("cannot scan attachment: %w", err)
+		}
+		result = append(result, a)
+	}
+	return result, rows.Err()
+}
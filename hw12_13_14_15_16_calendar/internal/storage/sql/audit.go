@@ -0,0 +1,85 @@
+package sqlstorage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"mycalendar/internal/storage"
+	"mycalendar/internal/tenant"
+)
+
+func (s *Storage) AppendAudit(ctx context.Context, r storage.AuditRecord) error {
+	before, err := marshalSnapshot(r.Before)
+	if err != nil {
+		return err
+	}
+	after, err := marshalSnapshot(r.After)
+	if err != nil {
+		return err
+	}
+	_, err = s.q.ExecContext(ctx, `
+		INSERT INTO audit_log (event_id, actor, action, before, after, request_id, at, tenant_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, r.EventID, r.Actor, r.Action, before, after, r.RequestID, r.At, tenant.FromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("cannot insert audit record: %w", err)
+	}
+	return nil
+}
+
+func (s *Storage) EventHistory(ctx context.Context, eventID int64) ([]storage.AuditRecord, error) {
+	rows, err := s.q.QueryContext(ctx, `
+		SELECT id, event_id, actor, action, before, after, request_id, at
+		FROM audit_log
+		WHERE tenant_id = $2 AND event_id = $1
+		ORDER BY id
+	`, eventID, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("cannot select audit records: %w", err)
+	}
+	defer rows.Close()
+
+	var records []storage.AuditRecord
+	for rows.Next() {
+		var (
+			r             storage.AuditRecord
+			before, after []byte
+		)
+		if err := rows.Scan(&r.ID, &r.EventID, &r.Actor, &r.Action, &before, &after, &r.RequestID, &r.At); err != nil {
+			return nil, fmt.Errorf("cannot scan audit record: %w", err)
+		}
+		if r.Before, err = unmarshalSnapshot(before); err != nil {
+			return nil, err
+		}
+		if r.After, err = unmarshalSnapshot(after); err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
+
+// Снимки событий хранятся в jsonb, nil - NULL.
+
+func marshalSnapshot(e *storage.Event) ([]byte, error) {
+	if e == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal snapshot: %w", err)
+	}
+	return data, nil
+}
+
+func unmarshalSnapshot(data []byte) (*storage.Event, error) {
+	if data == nil {
+		return nil, nil
+	}
+	var e storage.Event
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal snapshot: %w", err)
+	}
+	return &e, nil
+}
@@ -0,0 +1,198 @@
+package sqlstorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/stdlib"
+	"mycalendar/internal/storage"
+	"mycalendar/internal/tenant"
+)
+
+// exportBatch - сколько событий выгрузки читается одним запросом.
+const exportBatch = 500
+
+// importColumns - колонки, которые ImportEvents копирует во временную таблицу.
+var importColumns = []string{
+	"user_id", "title", "description", "start_date_time", "duration", "notice_before", "created_at",
+	"end_date_time", "calendar_id", "tenant_id",
+}
+
+// ImportEvents копирует пакет через COPY во временную таблицу и переносит его
+// в events одним INSERT ... ON CONFLICT DO NOTHING: занятое время не прерывает
+// загрузку, а возвращается нулевым ID. Вне InTx пакет добавляется своей
+// транзакцией, внутри - транзакцией InTx, так что несколько пакетов можно
+// загрузить атомарно.
+func (s *Storage) ImportEvents(ctx context.Context, events []storage.Event) ([]int64, error) {
+	var ids []int64
+	err := s.inTx(ctx, func(ctx context.Context, tx *Storage) error {
+		var err error
+		ids, err = tx.importEvents(ctx, events)
+		return err
+	})
+	return ids, err
+}
+
+func (s *Storage) importEvents(ctx context.Context, events []storage.Event) ([]int64, error) {
+	events = slices.Clone(events)
+	defaults := make(map[string]int64)
+	for i, e := range events {
+		if e.CalendarID != 0 {
+			continue
+		}
+		id, ok := defaults[e.UserID]
+		if !ok {
+			c, err := s.DefaultCalendar(ctx, e.UserID)
+			if err != nil {
+				return nil, err
+			}
+			id = c.ID
+			defaults[e.UserID] = id
+		}
+		events[i].CalendarID = id
+	}
+
+	ctx, cancel := s.db.withTimeout(ctx)
+	defer cancel()
+	var ids []int64
+	err := s.conn.Raw(func(driverConn any) error {
+		c, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return errors.New("COPY needs the pgx driver")
+		}
+		var err error
+		ids, err = copyEvents(ctx, c.Conn(), tenant.FromContext(ctx), events)
+		return err
+	})
+	return ids, err
+}
+
+// copyEvents выполняется на соединении открытой транзакции. Временная таблица
+// живёт до конца транзакции и очищается перед каждым пакетом.
+func copyEvents(ctx context.Context, conn *pgx.Conn, tenantID string, events []storage.Event) ([]int64, error) {
+	_, err := conn.Exec(ctx, `
+		CREATE TEMP TABLE IF NOT EXISTS import_events ON COMMIT DROP AS
+		SELECT user_id, title, description, start_date_time, duration, notice_before, created_at,
+			end_date_time, calendar_id, tenant_id
+		FROM events WITH NO DATA
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create import table: %w", err)
+	}
+	if _, err := conn.Exec(ctx, `TRUNCATE import_events`); err != nil {
+		return nil, fmt.Errorf("cannot clear import table: %w", err)
+	}
+	_, err = conn.CopyFrom(ctx, pgx.Identifier{"import_events"}, importColumns,
+		pgx.CopyFromSlice(len(events), func(i int) ([]any, error) {
+			e := events[i]
+			createdAt := e.CreatedAt
+			if createdAt.IsZero() {
+				createdAt = time.Now()
+			}
+			return []any{
+				e.UserID, e.Title, e.Description, e.StartDateTime, e.Duration, e.NoticeBefore, createdAt,
+				e.End(), e.CalendarID, tenantID,
+			}, nil
+		}))
+	if err != nil {
+		return nil, fmt.Errorf("cannot copy events: %w", err)
+	}
+
+	// ctid сохраняет порядок COPY, поэтому из двух событий пакета с одним
+	// временем добавляется первое
+	rows, err := conn.Query(ctx, `
+		INSERT INTO events (user_id, title, description, start_date_time, duration, notice_before, created_at,
+			end_date_time, calendar_id, tenant_id)
+		SELECT user_id, title, description, start_date_time, duration, notice_before, created_at,
+			end_date_time, calendar_id, tenant_id
+		FROM import_events
+		ORDER BY ctid
+		ON CONFLICT DO NOTHING
+		RETURNING id, user_id, start_date_time
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("cannot insert events: %w", err)
+	}
+	type key struct {
+		userID string
+		start  int64
+	}
+	inserted := make(map[key]int64, len(events))
+	for rows.Next() {
+		var (
+			id     int64
+			userID string
+			start  time.Time
+		)
+		if err := rows.Scan(&id, &userID, &start); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("cannot scan: %w", err)
+		}
+		inserted[key{userID, start.UnixMicro()}] = id
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("cannot insert events: %w", err)
+	}
+
+	ids := make([]int64, len(events))
+	for i, e := range events {
+		k := key{e.UserID, e.StartDateTime.UnixMicro()}
+		ids[i] = inserted[k]
+		delete(inserted, k) // повтор времени в пакете получает 0
+	}
+	return ids, nil
+}
+
+// ExportEvents читает события страницами по exportBatch: каждая страница - свой
+// запрос с таймаутом и повторами, а участники загружаются после того, как её
+// строки прочитаны. Поэтому выгрузка может идти дольше QueryTimeout, её
+// ограничивает только ctx.
+func (s *Storage) ExportEvents(ctx context.Context, f storage.EventFilter, fn func(storage.Event) error) error {
+	q := sq.Select(eventColumns).
+		From("events").
+		Where(sq.Eq{"tenant_id": tenant.FromContext(ctx)}).
+		Where("deleted_at IS NULL").
+		OrderBy("start_date_time", "id").
+		Limit(exportBatch).
+		PlaceholderFormat(sq.Dollar)
+	if f.UserID != "" {
+		q = q.Where(sq.Eq{"user_id": f.UserID})
+	}
+	if !f.From.IsZero() {
+		q = q.Where(sq.GtOrEq{"start_date_time": f.From})
+	}
+	if !f.To.IsZero() {
+		q = q.Where(sq.Lt{"start_date_time": f.To})
+	}
+	if f.CalendarID != 0 {
+		q = q.Where(sq.Eq{"calendar_id": f.CalendarID})
+	}
+
+	page := q
+	for {
+		query, args, err := page.ToSql()
+		if err != nil {
+			return fmt.Errorf("cannot build query: %w", err)
+		}
+		events, err := s.queryEvents(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		for _, e := range events {
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+		if len(events) < exportBatch {
+			return nil
+		}
+		last := events[len(events)-1]
+		page = q.Where(sq.Expr("(start_date_time, id) > (?, ?)", last.StartDateTime, last.EventID))
+	}
+}
@@ -0,0 +1,187 @@
+package sqlstorage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"mycalendar/internal/storage"
+	"mycalendar/internal/tenant"
+)
+
+const calendarColumns = `id, owner_id, name, color, time_zone, is_default, created_at, holiday_country, defer_reminders`
+
+func scanCalendar(sc scanner) (storage.Calendar, error) {
+	var c storage.Calendar
+	err := sc.Scan(&c.ID, &c.OwnerID, &c.Name, &c.Color, &c.TimeZone, &c.IsDefault, &c.CreatedAt,
+		&c.HolidayCountry, &c.DeferReminders)
+	return c, err
+}
+
+func (s *Storage) CreateCalendar(ctx context.Context, c storage.Calendar) (int64, error) {
+	var id int64
+	err := s.q.QueryRowContext(ctx, `
+		INSERT INTO calendars (owner_id, name, color, time_zone, is_default, tenant_id, holiday_country, defer_reminders)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`, c.OwnerID, c.Name, c.Color, c.TimeZone, c.IsDefault, tenant.FromContext(ctx),
+		c.HolidayCountry, c.DeferReminders).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("cannot insert calendar: %w", err)
+	}
+	return id, nil
+}
+
+func (s *Storage) GetCalendar(ctx context.Context, id int64) (storage.Calendar, error) {
+	c, err := scanCalendar(s.q.QueryRowContext(ctx, `
+		SELECT `+calendarColumns+`
+		FROM calendars
+		WHERE tenant_id = $2 AND id = $1
+	`, id, tenant.FromContext(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return storage.Calendar{}, storage.ErrCalendarNotFound
+	}
+	if err != nil {
+		return storage.Calendar{}, fmt.Errorf("cannot select calendar: %w", err)
+	}
+	calendars := []storage.Calendar{c}
+	if err := s.loadShares(ctx, calendars); err != nil {
+		return storage.Calendar{}, err
+	}
+	return calendars[0], nil
+}
+
+func (s *Storage) ListCalendars(ctx context.Context, userID string) ([]storage.Calendar, error) {
+	rows, err := s.q.QueryContext(ctx, `
+		SELECT `+calendarColumns+`
+		FROM calendars c
+		WHERE tenant_id = $2
+		  AND (owner_id = $1
+		   OR EXISTS (SELECT 1 FROM calendar_shares sh WHERE sh.calendar_id = c.id AND sh.user_id = $1))
+		ORDER BY id
+	`, userID, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("cannot select calendars: %w", err)
+	}
+	defer rows.Close()
+
+	var calendars []storage.Calendar
+	for rows.Next() {
+		c, err := scanCalendar(rows)
+		if err != nil {
+			return nil, fmt.Errorf("cannot scan calendar: %w", err)
+		}
+		calendars = append(calendars, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return calendars, s.loadShares(ctx, calendars)
+}
+
+// DefaultCalendar опирается на уникальный индекс calendars_default_idx, поэтому
+// параллельные вызовы не создадут два календаря по умолчанию.
+func (s *Storage) DefaultCalendar(ctx context.Context, ownerID string) (storage.Calendar, error) {
+	_, err := s.q.ExecContext(ctx, `
+		INSERT INTO calendars (owner_id, name, is_default, tenant_id)
+		VALUES ($1, $2, true, $3)
+		ON CONFLICT (tenant_id, owner_id) WHERE is_default DO NOTHING
+	`, ownerID, storage.DefaultCalendarName, tenant.FromContext(ctx))
+	if err != nil {
+		return storage.Calendar{}, fmt.Errorf("cannot create default calendar: %w", err)
+	}
+
+	var id int64
+	err = s.q.QueryRowContext(ctx, `
+		SELECT id FROM calendars WHERE tenant_id = $2 AND owner_id = $1 AND is_default
+	`, ownerID, tenant.FromContext(ctx)).Scan(&id)
+	if err != nil {
+		return storage.Calendar{}, fmt.Errorf("cannot select default calendar: %w", err)
+	}
+	return s.GetCalendar(ctx, id)
+}
+
+func (s *Storage) UpdateCalendar(ctx context.Context, c storage.Calendar) error {
+	res, err := s.q.ExecContext(ctx, `
+		UPDATE calendars
+		SET name = $1, color = $2, time_zone = $3, holiday_country = $4, defer_reminders = $5
+		WHERE tenant_id = $6 AND id = $7
+	`, c.Name, c.Color, c.TimeZone, c.HolidayCountry, c.DeferReminders, tenant.FromContext(ctx), c.ID)
+	if err != nil {
+		return fmt.Errorf("cannot update calendar: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return storage.ErrCalendarNotFound
+	}
+	return nil
+}
+
+func (s *Storage) ShareCalendar(ctx context.Context, calendarID int64, userID string, perm storage.Permission) error {
+	if !perm.Valid() {
+		return storage.ErrInvalidPermission
+	}
+	res, err := s.q.ExecContext(ctx, `
+		INSERT INTO calendar_shares (calendar_id, user_id, permission)
+		SELECT id, $2, $3 FROM calendars WHERE id = $1 AND tenant_id = $4
+		ON CONFLICT (calendar_id, user_id) DO UPDATE SET permission = EXCLUDED.permission
+	`, calendarID, userID, perm, tenant.FromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("cannot share calendar: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return storage.ErrCalendarNotFound
+	}
+	return nil
+}
+
+func (s *Storage) UnshareCalendar(ctx context.Context, calendarID int64, userID string) error {
+	if _, err := s.GetCalendar(ctx, calendarID); err != nil {
+		return err
+	}
+	_, err := s.q.ExecContext(ctx, `
+		DELETE FROM calendar_shares WHERE calendar_id = $1 AND user_id = $2
+	`, calendarID, userID)
+	if err != nil {
+		return fmt.Errorf("cannot unshare calendar: %w", err)
+	}
+	return nil
+}
+
+// loadShares заполняет права на календари одним запросом.
+func (s *Storage) loadShares(ctx context.Context, calendars []storage.Calendar) error {
+	if len(calendars) == 0 {
+		return nil
+	}
+	byID := make(map[int64]int, len(calendars))
+	ids := make([]int64, 0, len(calendars))
+	for i, c := range calendars {
+		byID[c.ID] = i
+		ids = append(ids, c.ID)
+	}
+
+	rows, err := s.q.QueryContext(ctx, `
+		SELECT calendar_id, user_id, permission
+		FROM calendar_shares
+		WHERE calendar_id = ANY($1)
+		ORDER BY calendar_id, user_id
+	`, ids)
+	if err != nil {
+		return fmt.Errorf("cannot select shares: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			calendarID int64
+			sh         storage.Share
+		)
+		if err := rows.Scan(&calendarID, &sh.UserID, &sh.Permission); err != nil {
+			return fmt.Errorf("cannot scan share: %w", err)
+		}
+		if i, ok := byID[calendarID]; ok {
+			calendars[i].Shares = append(calendars[i].Shares, sh)
+		}
+	}
+	return rows.Err()
+}
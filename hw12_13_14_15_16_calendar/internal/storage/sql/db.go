@@ -0,0 +1,287 @@
+package sqlstorage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"mycalendar/internal/config"
+)
+
+// Options - настройки пула соединений, таймаутов и повторов. Нулевые значения
+// оставляют поведение database/sql по умолчанию и отключают повторы.
+type Options struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+
+	QueryTimeout   time.Duration // на один запрос или транзакцию
+	Retries        int           // сколько раз повторять запрос при временных ошибках
+	RetryBackoff   time.Duration // первая пауза между попытками, дальше удваивается
+	ConnectTimeout time.Duration // сколько ждать базу при запуске
+
+	NoPrepare bool // выполнять запросы без подготовленных выражений
+}
+
+// OptionsFromConfig переводит настройки psql из конфига в Options.
+func OptionsFromConfig(c config.PSQLConfig) Options {
+	return Options{
+		MaxOpenConns:    c.MaxOpenConns,
+		MaxIdleConns:    c.MaxIdleConns,
+		ConnMaxLifetime: time.Duration(c.ConnMaxLifetimeSeconds) * time.Second,
+		ConnMaxIdleTime: time.Duration(c.ConnMaxIdleTimeSeconds) * time.Second,
+		QueryTimeout:    time.Duration(c.QueryTimeoutSeconds) * time.Second,
+		Retries:         c.Retries,
+		RetryBackoff:    time.Duration(c.RetryBackoffMs) * time.Millisecond,
+		ConnectTimeout:  time.Duration(c.ConnectTimeoutSeconds) * time.Second,
+	}
+}
+
+const (
+	defaultRetryBackoff = 100 * time.Millisecond
+	maxRetryBackoff     = 5 * time.Second
+
+	// maxStatements ограничивает кэш выражений: запросы ListEvents собираются
+	// squirrel, но вариантов у них немного.
+	maxStatements = 64
+)
+
+// retryDB - *sql.DB, у которого запросы выполняются с таймаутом и повторяются
+// при временных ошибках. Методы повторяют сигнатуры *sql.DB, поэтому код хранилища
+// пользуется ими как обычно.
+//
+// Запросы готовятся при первом выполнении и дальше берутся из кэша stmts:
+// заранее нельзя, Connect вызывается до миграций.
+type retryDB struct {
+	*sql.DB
+	opts Options
+
+	mu    sync.Mutex
+	stmts map[string]*sql.Stmt
+}
+
+// stmt возвращает подготовленное выражение для query или nil, если его
+// подготовить не удалось - тогда запрос выполняется как есть.
+func (d *retryDB) stmt(ctx context.Context, query string) *sql.Stmt {
+	if d.opts.NoPrepare {
+		return nil
+	}
+	d.mu.Lock()
+	st, ok := d.stmts[query]
+	full := len(d.stmts) >= maxStatements
+	d.mu.Unlock()
+	if ok || full {
+		return st
+	}
+
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
+	st, err := d.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if prev, ok := d.stmts[query]; ok { // успел подготовить другой запрос
+		_ = st.Close()
+		return prev
+	}
+	if d.stmts == nil {
+		d.stmts = make(map[string]*sql.Stmt)
+	}
+	d.stmts[query] = st
+	return st
+}
+
+// Close закрывает подготовленные выражения и пул.
+func (d *retryDB) Close() error {
+	d.mu.Lock()
+	for _, st := range d.stmts {
+		_ = st.Close()
+	}
+	d.stmts = nil
+	d.mu.Unlock()
+	return d.DB.Close()
+}
+
+func (d *retryDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	st := d.stmt(ctx, query)
+	var res sql.Result
+	err := d.retry(ctx, query, func(ctx context.Context) error {
+		ctx, cancel := d.withTimeout(ctx)
+		defer cancel()
+		var err error
+		if st != nil {
+			res, err = st.ExecContext(ctx, args...)
+		} else {
+			res, err = d.DB.ExecContext(ctx, query, args...)
+		}
+		return err
+	})
+	return res, err
+}
+
+// rows отменяет контекст запроса при закрытии.
+type rows struct {
+	*sql.Rows
+	cancel context.CancelFunc
+}
+
+func (r *rows) Close() error {
+	err := r.Rows.Close()
+	r.cancel()
+	return err
+}
+
+func (d *retryDB) QueryContext(ctx context.Context, query string, args ...any) (*rows, error) {
+	st := d.stmt(ctx, query)
+	var res *rows
+	err := d.retry(ctx, query, func(ctx context.Context) error {
+		ctx, cancel := d.withTimeout(ctx)
+		var (
+			r   *sql.Rows
+			err error
+		)
+		if st != nil {
+			r, err = st.QueryContext(ctx, args...)
+		} else {
+			r, err = d.DB.QueryContext(ctx, query, args...)
+		}
+		if err != nil {
+			cancel()
+			return err
+		}
+		res = &rows{Rows: r, cancel: cancel}
+		return nil
+	})
+	return res, err
+}
+
+// row выполняет запрос при вызове Scan, как и *sql.Row.
+type row struct {
+	scan func(dest ...any) error
+}
+
+func (r *row) Scan(dest ...any) error {
+	return r.scan(dest...)
+}
+
+func (d *retryDB) QueryRowContext(ctx context.Context, query string, args ...any) *row {
+	return &row{scan: func(dest ...any) error {
+		st := d.stmt(ctx, query)
+		return d.retry(ctx, query, func(ctx context.Context) error {
+			ctx, cancel := d.withTimeout(ctx)
+			defer cancel()
+			if st != nil {
+				return st.QueryRowContext(ctx, args...).Scan(dest...)
+			}
+			return d.DB.QueryRowContext(ctx, query, args...).Scan(dest...)
+		})
+	}}
+}
+
+func (d *retryDB) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if d.opts.QueryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d.opts.QueryTimeout)
+}
+
+func (d *retryDB) retry(ctx context.Context, query string, op func(context.Context) error) error {
+	readOnly := isSelect(query)
+	backoff := d.backoff()
+	for attempt := 0; ; attempt++ {
+		err := op(ctx)
+		if err == nil || attempt >= d.opts.Retries || !isTransient(err, readOnly) {
+			return err
+		}
+		if !sleep(ctx, backoff) {
+			return err
+		}
+		backoff = min(2*backoff, maxRetryBackoff)
+	}
+}
+
+func (d *retryDB) backoff() time.Duration {
+	if d.opts.RetryBackoff > 0 {
+		return d.opts.RetryBackoff
+	}
+	return defaultRetryBackoff
+}
+
+// waitReady пингует базу, пока она не ответит или не истечёт ConnectTimeout.
+func (d *retryDB) waitReady(ctx context.Context) error {
+	deadline := time.Now().Add(d.opts.ConnectTimeout)
+	backoff := d.backoff()
+	for {
+		err := d.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 || !sleep(ctx, min(backoff, remaining)) {
+			return err
+		}
+		backoff = min(2*backoff, maxRetryBackoff)
+	}
+}
+
+// sleep ждёт d и возвращает false, если раньше отменили ctx.
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func isSelect(query string) bool {
+	return strings.HasPrefix(strings.ToUpper(strings.TrimSpace(query)), "SELECT")
+}
+
+// isTransient сообщает, можно ли повторить запрос. Транзакции, прерванные из-за
+// конфликта, откатываются целиком, их можно повторять всегда. После обрыва
+// соединения неизвестно, успел ли выполниться запрос, поэтому повторяются только
+// чтения и запросы, которые точно не дошли до сервера.
+func isTransient(err error, readOnly bool) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == "40001", pgErr.Code == "40P01": // serialization_failure, deadlock_detected
+			return true
+		case pgErr.Code == "57P03": // cannot_connect_now: база ещё запускается
+			return true
+		case pgErr.Code == "57P01", pgErr.Code == "57P02", strings.HasPrefix(pgErr.Code, "08"):
+			return readOnly // соединение закрыто сервером
+		}
+		return false
+	}
+
+	var safe interface{ SafeToRetry() bool }
+	if errors.As(err, &safe) && safe.SafeToRetry() {
+		return true
+	}
+	if errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+	if !readOnly {
+		return false
+	}
+	var netErr net.Error
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		(errors.As(err, &netErr) && !netErr.Timeout())
+}
@@ -0,0 +1,108 @@
+package sqlstorage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"mycalendar/internal/storage"
+)
+
+// eventColumns - колонки события в порядке, который ожидает scanEvent.
+// Все запросы событий выбирают их целиком, чтобы любое чтение заполняло
+// одни и те же поля.
+const eventColumns = `id, user_id, title, description, start_date_time, duration, notice_before, created_at,
+	COALESCE(calendar_id, 0), deleted_at`
+
+// Все запросы событий ограничены тенантом, он всегда $1.
+const (
+	queryGetEvents = `SELECT ` + eventColumns + `
+		FROM events
+		WHERE tenant_id = $1 AND deleted_at IS NULL`
+
+	queryGetEvent = `SELECT ` + eventColumns + `
+		FROM events
+		WHERE tenant_id = $1 AND id = $2 AND deleted_at IS NULL`
+
+	queryUpcomingEvents = `SELECT ` + eventColumns + `
+		FROM events
+		WHERE tenant_id = $1 AND start_date_time >= $2 AND deleted_at IS NULL
+		AND start_date_time - notice_before * INTERVAL '1 day' <= $2
+		ORDER BY start_date_time ASC`
+
+	// queryEventsInRange обслуживает день, неделю и месяц: условие по диапазону
+	// start_date_time использует индекс events_time_range_idx.
+	queryEventsInRange = `SELECT ` + eventColumns + `
+		FROM events
+		WHERE tenant_id = $1 AND start_date_time >= $2 AND start_date_time < $3 AND deleted_at IS NULL
+		ORDER BY start_date_time`
+
+	queryBusyEvents = `SELECT ` + eventColumns + `
+		FROM events
+		WHERE tenant_id = $1 AND start_date_time < $3 AND end_date_time > $2 AND deleted_at IS NULL
+		  AND (user_id = ANY($4) OR EXISTS (
+			SELECT 1 FROM event_attendees a
+			WHERE a.event_id = events.id AND a.user_id = ANY($4) AND a.status <> $5))
+		ORDER BY start_date_time`
+
+	queryAllTrash = `SELECT ` + eventColumns + `
+		FROM events
+		WHERE tenant_id = $1 AND deleted_at IS NOT NULL
+		ORDER BY deleted_at`
+
+	queryUserTrash = `SELECT ` + eventColumns + `
+		FROM events
+		WHERE tenant_id = $1 AND deleted_at IS NOT NULL AND user_id = $2
+		ORDER BY deleted_at`
+
+	queryAttendees = `SELECT event_id, user_id, status
+		FROM event_attendees
+		WHERE event_id = ANY($1)
+		ORDER BY event_id, invited_at`
+)
+
+// scanner - общее у *sql.Row и *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanEvent читает строку, выбранную с eventColumns.
+func scanEvent(sc scanner) (storage.Event, error) {
+	var (
+		e         storage.Event
+		deletedAt sql.NullTime
+	)
+	err := sc.Scan(
+		&e.EventID, &e.UserID, &e.Title, &e.Description, &e.StartDateTime,
+		&e.Duration, &e.NoticeBefore, &e.CreatedAt, &e.CalendarID, &deletedAt,
+	)
+	if err != nil {
+		return storage.Event{}, fmt.Errorf("cannot scan: %w", err)
+	}
+	if deletedAt.Valid {
+		e.DeletedAt = deletedAt.Time
+	}
+	return e, nil
+}
+
+// queryEvents выполняет запрос событий и подгружает их участников.
+func (s *Storage) queryEvents(ctx context.Context, query string, args ...any) ([]storage.Event, error) {
+	rows, err := s.q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("cannot select: %w", err)
+	}
+	defer rows.Close()
+
+	var events []storage.Event
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return events, s.loadAttendees(ctx, events)
+}
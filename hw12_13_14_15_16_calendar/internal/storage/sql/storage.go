@@ -7,29 +7,48 @@ import (
 	"fmt"
 	"time"
 
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgconn"
 	_ "github.com/jackc/pgx/v4/stdlib" // так надо
 	"github.com/lib/pq"
 	"github.com/pressly/goose/v3"
 	"mycalendar/internal/storage"
+	"mycalendar/internal/tenant"
 )
 
 var _ storage.BaseStorage = (*Storage)(nil) // interface assertion at compile time
 
 type Storage struct {
-	db *sql.DB
+	db   *retryDB
+	q    querier   // db или транзакция InTx
+	conn *sql.Conn // соединение транзакции, nil вне её
+	opts Options
 }
 
-func New() *Storage {
-	return &Storage{}
+func New(opts Options) *Storage {
+	return &Storage{opts: opts}
 }
 
-func (s *Storage) Connect(ctx context.Context, dsn string) (err error) {
-	s.db, err = sql.Open("pgx", dsn)
+// Connect открывает пул соединений и ждёт, пока база начнёт отвечать.
+func (s *Storage) Connect(ctx context.Context, dsn string) error {
+	db, err := sql.Open("pgx", dsn)
 	if err != nil {
 		return fmt.Errorf("cannot open pgx driver: %w", err)
 	}
-
-	return s.db.PingContext(ctx)
+	db.SetMaxOpenConns(s.opts.MaxOpenConns)
+	if s.opts.MaxIdleConns > 0 { // 0 у database/sql - без простаивающих соединений
+		db.SetMaxIdleConns(s.opts.MaxIdleConns)
+	}
+	db.SetConnMaxLifetime(s.opts.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(s.opts.ConnMaxIdleTime)
+	s.db = &retryDB{DB: db, opts: s.opts}
+	s.q = s.db
+
+	if err := s.db.waitReady(ctx); err != nil {
+		_ = db.Close()
+		return fmt.Errorf("database is not ready: %w", err)
+	}
+	return nil
 }
 
 func (s *Storage) Close() error {
@@ -46,33 +65,45 @@ func (s *Storage) Migrate(ctx context.Context, migrate string) (err error) {
 		return fmt.Errorf("cannot set dialect: %w", err)
 	}
 
-	if err := goose.Up(s.db, migrate); err != nil {
+	if err := goose.Up(s.db.DB, migrate); err != nil {
 		return fmt.Errorf("cannot do up migration: %w", err)
 	}
 
 	return nil
 }
 
-func (s *Storage) AddEvent(ctx context.Context, e storage.Event) error {
-	_, err := s.db.ExecContext(ctx, `
-		INSERT INTO events (user_id, title, description, start_date_time, duration, notice_before, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-	`, e.UserID, e.Title, e.Description, e.StartDateTime, e.Duration, e.NoticeBefore, e.CreatedAt)
+func (s *Storage) AddEvent(ctx context.Context, e storage.Event) (int64, error) {
+	if e.CalendarID == 0 {
+		c, err := s.DefaultCalendar(ctx, e.UserID)
+		if err != nil {
+			return 0, err
+		}
+		e.CalendarID = c.ID
+	}
+
+	var id int64
+	err := s.q.QueryRowContext(ctx, `
+		INSERT INTO events (user_id, title, description, start_date_time, duration, notice_before, created_at,
+			end_date_time, calendar_id, tenant_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id
+	`, e.UserID, e.Title, e.Description, e.StartDateTime, e.Duration, e.NoticeBefore, e.CreatedAt,
+		e.End(), e.CalendarID, tenant.FromContext(ctx)).Scan(&id)
 	if err != nil {
 		if isUniqueViolation(err) {
-			return storage.ErrDateBusy
+			return 0, storage.ErrDateBusy
 		}
-		return fmt.Errorf("cannot insert: %w", err)
+		return 0, fmt.Errorf("cannot insert: %w", err)
 	}
-	return nil
+	return id, nil
 }
 
 func (s *Storage) UpdateEvent(ctx context.Context, e storage.Event) error {
-	res, err := s.db.ExecContext(ctx, `
+	res, err := s.q.ExecContext(ctx, `
 		UPDATE events
-		SET title = $1, description = $2, duration = $3, notice_before = $4
-		WHERE user_id = $5 AND start_date_time = $6
-	`, e.Title, e.Description, e.Duration, e.NoticeBefore, e.UserID, e.StartDateTime)
+		SET title = $1, description = $2, duration = $3, notice_before = $4, end_date_time = $7
+		WHERE tenant_id = $8 AND user_id = $5 AND start_date_time = $6 AND deleted_at IS NULL
+	`, e.Title, e.Description, e.Duration, e.NoticeBefore, e.UserID, e.StartDateTime, e.End(), tenant.FromContext(ctx))
 	if err != nil {
 		return fmt.Errorf("cannot update: %w", err)
 	}
@@ -85,10 +116,26 @@ func (s *Storage) UpdateEvent(ctx context.Context, e storage.Event) error {
 }
 
 func (s *Storage) DeleteEvent(ctx context.Context, userID string, start time.Time) error {
-	res, err := s.db.ExecContext(ctx, `
-		DELETE FROM events
-		WHERE user_id = $1 AND start_date_time = $2
-	`, userID, start)
+	res, err := s.q.ExecContext(ctx, `
+		UPDATE events SET deleted_at = now()
+		WHERE tenant_id = $3 AND user_id = $1 AND start_date_time = $2 AND deleted_at IS NULL
+	`, userID, start, tenant.FromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("cannot delete: %w", err)
+	}
+
+	affected, _ := res.RowsAffected()
+	if affected == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+func (s *Storage) DeleteEventByID(ctx context.Context, id int64) error {
+	res, err := s.q.ExecContext(ctx, `
+		UPDATE events SET deleted_at = now()
+		WHERE tenant_id = $2 AND id = $1 AND deleted_at IS NULL
+	`, id, tenant.FromContext(ctx))
 	if err != nil {
 		return fmt.Errorf("cannot delete: %w", err)
 	}
@@ -101,10 +148,10 @@ func (s *Storage) DeleteEvent(ctx context.Context, userID string, start time.Tim
 }
 
 func (s *Storage) DeleteOldEvents(ctx context.Context, before time.Time) error {
-	res, err := s.db.ExecContext(ctx, `
-		DELETE FROM events
-		WHERE start_date_time < $1
-	`, before)
+	res, err := s.q.ExecContext(ctx, `
+		UPDATE events SET deleted_at = now()
+		WHERE tenant_id = $2 AND start_date_time < $1 AND deleted_at IS NULL
+	`, before, tenant.FromContext(ctx))
 	if err != nil {
 		return fmt.Errorf("cannot delete old events: %w", err)
 	}
@@ -117,95 +164,57 @@ func (s *Storage) DeleteOldEvents(ctx context.Context, before time.Time) error {
 }
 
 func (s *Storage) GetEvents(ctx context.Context) ([]storage.Event, error) {
-	rows, err := s.db.QueryContext(ctx, `
-		SELECT user_id, title, description, start_date_time, duration, notice_before, created_at FROM Events
-	`)
+	return s.queryEvents(ctx, queryGetEvents, tenant.FromContext(ctx))
+}
+
+func (s *Storage) GetEvent(ctx context.Context, id int64) (storage.Event, error) {
+	events, err := s.queryEvents(ctx, queryGetEvent, tenant.FromContext(ctx), id)
 	if err != nil {
-		return nil, fmt.Errorf("cannot select: %w", err)
+		return storage.Event{}, err
 	}
-	defer rows.Close()
-
-	var Events []storage.Event
-
-	for rows.Next() {
-		var e storage.Event
-
-		if err := rows.Scan(
-			&e.UserID,
-			&e.Title,
-			&e.Description,
-			&e.StartDateTime,
-			&e.Duration,
-			&e.NoticeBefore,
-			&e.CreatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("cannot scan: %w", err)
-		}
-
-		Events = append(Events, e)
+	if len(events) == 0 {
+		return storage.Event{}, storage.ErrNotFound
 	}
-	return Events, rows.Err()
+	return events[0], nil
 }
 
-func (s *Storage) GetUpcomingEvents(ctx context.Context, from time.Time) ([]storage.Event, error) {
-	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, user_id, title, description, start_date_time, duration, notice_before, created_at
-		FROM events
-		WHERE start_date_time >= $1
-		AND start_date_time - notice_before * INTERVAL '1 day' <= $2
-		ORDER BY start_date_time ASC`,
-		from, from)
-	if err != nil {
-		return nil, fmt.Errorf("cannot select events to notify: %w", err)
+func (s *Storage) ListEvents(ctx context.Context, f storage.EventFilter) ([]storage.Event, error) {
+	q := sq.Select(eventColumns).
+		From("events").
+		Where(sq.Eq{"tenant_id": tenant.FromContext(ctx)}).
+		Where("deleted_at IS NULL").
+		OrderBy("start_date_time").
+		PlaceholderFormat(sq.Dollar)
+	if f.UserID != "" {
+		q = q.Where(sq.Or{
+			sq.Eq{"user_id": f.UserID},
+			sq.Expr(`EXISTS (SELECT 1 FROM event_attendees a
+				WHERE a.event_id = events.id AND a.user_id = ? AND a.status <> ?)`, f.UserID, storage.RSVPDeclined),
+		})
 	}
-	defer rows.Close()
-
-	var events []storage.Event
-
-	for rows.Next() {
-		var e storage.Event
-
-		if err := rows.Scan(
-			&e.EventID,
-			&e.UserID,
-			&e.Title,
-			&e.Description,
-			&e.StartDateTime,
-			&e.Duration,
-			&e.NoticeBefore,
-			&e.CreatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("cannot scan: %w", err)
-		}
-
-		events = append(events, e)
+	if !f.From.IsZero() {
+		q = q.Where(sq.GtOrEq{"start_date_time": f.From})
+	}
+	if !f.To.IsZero() {
+		q = q.Where(sq.Lt{"start_date_time": f.To})
+	}
+	if f.CalendarID != 0 {
+		q = q.Where(sq.Eq{"calendar_id": f.CalendarID})
 	}
-	return events, rows.Err()
-}
 
-func (s *Storage) GetEventsByDay(ctx context.Context, date time.Time) ([]storage.Event, error) {
-	rows, err := s.db.QueryContext(ctx, `
-		SELECT user_id, title, description, start_date_time, duration, notice_before, created_at
-		FROM Events
-		WHERE start_date_time >= $1 AND start_date_time < $2
-	`, date.Truncate(24*time.Hour), date.AddDate(0, 0, 1).Truncate(24*time.Hour))
+	query, args, err := q.ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("cannot select: %w", err)
+		return nil, fmt.Errorf("cannot build query: %w", err)
 	}
-	defer rows.Close()
+	return s.queryEvents(ctx, query, args...)
+}
 
-	var events []storage.Event
-	for rows.Next() {
-		var e storage.Event
-		if err := rows.Scan(
-			&e.UserID, &e.Title, &e.Description, &e.StartDateTime,
-			&e.Duration, &e.NoticeBefore, &e.CreatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("cannot scan: %w", err)
-		}
-		events = append(events, e)
-	}
-	return events, rows.Err()
+func (s *Storage) GetUpcomingEvents(ctx context.Context, from time.Time) ([]storage.Event, error) {
+	return s.queryEvents(ctx, queryUpcomingEvents, tenant.FromContext(ctx), from)
+}
+
+func (s *Storage) GetEventsByDay(ctx context.Context, date time.Time) ([]storage.Event, error) {
+	return s.queryEvents(ctx, queryEventsInRange, tenant.FromContext(ctx), date.Truncate(24*time.Hour), date.AddDate(0, 0, 1).Truncate(24*time.Hour))
 }
 
 func (s *Storage) GetEventsByWeek(ctx context.Context, date time.Time) ([]storage.Event, error) {
@@ -216,62 +225,160 @@ func (s *Storage) GetEventsByWeek(ctx context.Context, date time.Time) ([]storag
 	}
 	startOfWeek := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC).
 		AddDate(0, 0, -isoWeekday+1)
-	endOfWeek := startOfWeek.AddDate(0, 0, 7)
+	return s.queryEvents(ctx, queryEventsInRange, tenant.FromContext(ctx), startOfWeek, startOfWeek.AddDate(0, 0, 7))
+}
+
+// GetEventsByMonth выбирает месяц диапазоном, а не EXTRACT по полям даты,
+// чтобы работал индекс events_time_range_idx.
+func (s *Storage) GetEventsByMonth(ctx context.Context, date time.Time) ([]storage.Event, error) {
+	first := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return s.queryEvents(ctx, queryEventsInRange, tenant.FromContext(ctx), first, first.AddDate(0, 1, 0))
+}
 
-	rows, err := s.db.QueryContext(ctx, `
-		SELECT user_id, title, description, start_date_time, duration, notice_before, created_at
-		FROM events
-		WHERE start_date_time >= $1 AND start_date_time < $2
-	`, startOfWeek, endOfWeek)
+func (s *Storage) InviteAttendees(ctx context.Context, eventID int64, userIDs []string) error {
+	return s.inTx(ctx, func(ctx context.Context, tx *Storage) error {
+		return tx.inviteAttendees(ctx, eventID, userIDs)
+	})
+}
+
+func (s *Storage) inviteAttendees(ctx context.Context, eventID int64, userIDs []string) error {
+	var owner string
+	err := s.q.QueryRowContext(ctx, `
+		SELECT user_id FROM events WHERE tenant_id = $2 AND id = $1 AND deleted_at IS NULL
+	`, eventID, tenant.FromContext(ctx)).Scan(&owner)
+	if errors.Is(err, sql.ErrNoRows) {
+		return storage.ErrNotFound
+	}
 	if err != nil {
-		return nil, fmt.Errorf("cannot select by week: %w", err)
+		return fmt.Errorf("cannot select event: %w", err)
 	}
-	defer rows.Close()
 
-	var events []storage.Event
-	for rows.Next() {
-		var e storage.Event
-		if err := rows.Scan(
-			&e.UserID, &e.Title, &e.Description, &e.StartDateTime,
-			&e.Duration, &e.NoticeBefore, &e.CreatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("cannot scan: %w", err)
+	for _, id := range userIDs {
+		if id == "" || id == owner {
+			continue
+		}
+		_, err = s.q.ExecContext(ctx, `
+			INSERT INTO event_attendees (event_id, user_id, status)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (event_id, user_id) DO NOTHING
+		`, eventID, id, storage.RSVPNeedsAction)
+		if err != nil {
+			return fmt.Errorf("cannot invite %s: %w", id, err)
 		}
-		events = append(events, e)
 	}
-	return events, rows.Err()
+	return nil
 }
 
-func (s *Storage) GetEventsByMonth(ctx context.Context, date time.Time) ([]storage.Event, error) {
-	year := date.Year()
-	month := int(date.Month())
-
-	rows, err := s.db.QueryContext(ctx, `
-		SELECT user_id, title, description, start_date_time, duration, notice_before, created_at
-		FROM Events
-		WHERE EXTRACT(MONTH FROM start_date_time) = $1
-		  AND EXTRACT(YEAR FROM start_date_time) = $2
-	`, month, year)
+func (s *Storage) SetAttendeeStatus(ctx context.Context, eventID int64, userID string, status storage.RSVPStatus) error {
+	if !status.Valid() {
+		return storage.ErrInvalidStatus
+	}
+	res, err := s.q.ExecContext(ctx, `
+		UPDATE event_attendees SET status = $3
+		WHERE event_id = $1 AND user_id = $2
+		  AND EXISTS (SELECT 1 FROM events e WHERE e.id = event_id AND e.tenant_id = $4)
+	`, eventID, userID, status, tenant.FromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("cannot update status: %w", err)
+	}
+	n, err := res.RowsAffected()
 	if err != nil {
-		return nil, fmt.Errorf("cannot select by month: %w", err)
+		return err
+	}
+	if n > 0 {
+		return nil
+	}
+	if _, err := s.GetEvent(ctx, eventID); err != nil {
+		return err
+	}
+	return storage.ErrNotAttendee
+}
+
+// GetBusyEvents выбирает пересекающиеся с [from, to) события по индексу
+// (start_date_time, end_date_time).
+func (s *Storage) GetBusyEvents(ctx context.Context, userIDs []string, from, to time.Time) ([]storage.Event, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	return s.queryEvents(ctx, queryBusyEvents, tenant.FromContext(ctx), from, to, userIDs, storage.RSVPDeclined)
+}
+
+func (s *Storage) ListTrash(ctx context.Context, userID string) ([]storage.Event, error) {
+	if userID == "" {
+		return s.queryEvents(ctx, queryAllTrash, tenant.FromContext(ctx))
+	}
+	return s.queryEvents(ctx, queryUserTrash, tenant.FromContext(ctx), userID)
+}
+
+func (s *Storage) RestoreEvent(ctx context.Context, id int64) error {
+	res, err := s.q.ExecContext(ctx, `
+		UPDATE events SET deleted_at = NULL
+		WHERE tenant_id = $2 AND id = $1 AND deleted_at IS NOT NULL
+	`, id, tenant.FromContext(ctx))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return storage.ErrDateBusy
+		}
+		return fmt.Errorf("cannot restore: %w", err)
+	}
+
+	affected, _ := res.RowsAffected()
+	if affected == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+func (s *Storage) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	res, err := s.q.ExecContext(ctx, `
+		DELETE FROM events
+		WHERE tenant_id = $2 AND deleted_at < $1
+	`, before, tenant.FromContext(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("cannot purge trash: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// loadAttendees заполняет участников событий одним запросом.
+func (s *Storage) loadAttendees(ctx context.Context, events []storage.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	byID := make(map[int64]int, len(events))
+	ids := make([]int64, 0, len(events))
+	for i, e := range events {
+		byID[e.EventID] = i
+		ids = append(ids, e.EventID)
+	}
+
+	rows, err := s.q.QueryContext(ctx, queryAttendees, ids)
+	if err != nil {
+		return fmt.Errorf("cannot select attendees: %w", err)
 	}
 	defer rows.Close()
 
-	var events []storage.Event
 	for rows.Next() {
-		var e storage.Event
-		if err := rows.Scan(
-			&e.UserID, &e.Title, &e.Description, &e.StartDateTime,
-			&e.Duration, &e.NoticeBefore, &e.CreatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("cannot scan: %w", err)
+		var (
+			eventID int64
+			a       storage.Attendee
+		)
+		if err := rows.Scan(&eventID, &a.UserID, &a.Status); err != nil {
+			return fmt.Errorf("cannot scan attendee: %w", err)
+		}
+		if i, ok := byID[eventID]; ok {
+			events[i].Attendees = append(events[i].Attendees, a)
 		}
-		events = append(events, e)
 	}
-	return events, rows.Err()
+	return rows.Err()
 }
 
+// isUniqueViolation понимает ошибки и pgx (используемый драйвер), и lib/pq.
 func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "23505"
+	}
 	pqErr := &pq.Error{}
 	if errors.As(err, &pqErr) {
 		return pqErr.Code == "23505"
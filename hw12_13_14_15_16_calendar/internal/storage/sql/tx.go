@@ -0,0 +1,96 @@
+package sqlstorage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"mycalendar/internal/storage"
+)
+
+// querier - запросы, которые хранилище выполняет через пул (retryDB) или в транзакции (txDB).
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *row
+}
+
+// txDB выполняет запросы в транзакции с таймаутом QueryTimeout на каждый.
+// Повторять отдельные запросы транзакции бессмысленно: после ошибки она
+// прервана, поэтому их не повторяет.
+type txDB struct {
+	tx   *sql.Tx
+	opts Options
+}
+
+func (t *txDB) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if t.opts.QueryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, t.opts.QueryTimeout)
+}
+
+func (t *txDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, cancel := t.withTimeout(ctx)
+	defer cancel()
+	return t.tx.ExecContext(ctx, query, args...)
+}
+
+func (t *txDB) QueryContext(ctx context.Context, query string, args ...any) (*rows, error) {
+	ctx, cancel := t.withTimeout(ctx)
+	r, err := t.tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &rows{Rows: r, cancel: cancel}, nil
+}
+
+func (t *txDB) QueryRowContext(ctx context.Context, query string, args ...any) *row {
+	return &row{scan: func(dest ...any) error {
+		ctx, cancel := t.withTimeout(ctx)
+		defer cancel()
+		return t.tx.QueryRowContext(ctx, query, args...).Scan(dest...)
+	}}
+}
+
+// InTx выполняет fn в транзакции на отдельном соединении. Общего таймаута у
+// транзакции нет, её ограничивает ctx, а каждый запрос - QueryTimeout.
+// Транзакция, прерванная конфликтом (40001, 40P01), повторяется целиком до
+// Retries раз, поэтому fn должна быть готова к повторному вызову.
+func (s *Storage) InTx(ctx context.Context, fn func(ctx context.Context, tx storage.Storage) error) error {
+	return s.inTx(ctx, func(ctx context.Context, tx *Storage) error { return fn(ctx, tx) })
+}
+
+func (s *Storage) inTx(ctx context.Context, fn func(ctx context.Context, tx *Storage) error) error {
+	if s.conn != nil { // уже в транзакции, повторять будет внешняя
+		return fn(ctx, s)
+	}
+	// пустой запрос - не чтение: повторяется, только если транзакция откатилась
+	return s.db.retry(ctx, "", func(ctx context.Context) error { return s.runTx(ctx, fn) })
+}
+
+func (s *Storage) runTx(ctx context.Context, fn func(ctx context.Context, tx *Storage) error) (err error) {
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot get connection: %w", err)
+	}
+	defer conn.Close()
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("cannot begin: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if err := fn(ctx, &Storage{db: s.db, q: &txDB{tx: tx, opts: s.opts}, conn: conn, opts: s.opts}); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("cannot commit: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,299 @@
+package sqlstorage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"mycalendar/internal/storage"
+	"mycalendar/internal/tenant"
+)
+
+// типы уведомлений читаются строкой: database/sql не сканирует text[] в срез
+const webhookColumns = `id, owner_id, url, secret, array_to_string(events, ','), active, failures,
+	disabled_at, created_at`
+
+// тенант доставки - тенант её вебхука
+const deliveryColumns = `id, webhook_id, COALESCE((SELECT w.tenant_id FROM webhooks w WHERE w.id = webhook_id), ''),
+	event, COALESCE(key, ''), payload, state, attempts, next_attempt,
+	last_status, last_error, created_at, delivered_at`
+
+func scanWebhook(sc scanner) (storage.Webhook, error) {
+	var (
+		w          storage.Webhook
+		events     string
+		disabledAt sql.NullTime
+	)
+	err := sc.Scan(&w.ID, &w.OwnerID, &w.URL, &w.Secret, &events, &w.Active, &w.Failures, &disabledAt, &w.CreatedAt)
+	if err != nil {
+		return storage.Webhook{}, err
+	}
+	if events != "" {
+		for _, e := range strings.Split(events, ",") {
+			w.Events = append(w.Events, storage.WebhookEvent(e))
+		}
+	}
+	if disabledAt.Valid {
+		w.DisabledAt = disabledAt.Time
+	}
+	return w, nil
+}
+
+func scanDelivery(sc scanner) (storage.Delivery, error) {
+	var (
+		d           storage.Delivery
+		deliveredAt sql.NullTime
+	)
+	err := sc.Scan(&d.ID, &d.WebhookID, &d.Tenant, &d.Event, &d.Key, &d.Payload, &d.State, &d.Attempts, &d.NextAttempt,
+		&d.LastStatus, &d.LastError, &d.CreatedAt, &deliveredAt)
+	if err != nil {
+		return storage.Delivery{}, err
+	}
+	if deliveredAt.Valid {
+		d.DeliveredAt = deliveredAt.Time
+	}
+	return d, nil
+}
+
+func (s *Storage) CreateWebhook(ctx context.Context, w storage.Webhook) (int64, error) {
+	events := make([]string, 0, len(w.Events))
+	for _, e := range w.Events {
+		events = append(events, string(e))
+	}
+	var id int64
+	err := s.q.QueryRowContext(ctx, `
+		INSERT INTO webhooks (owner_id, url, secret, events, active, tenant_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`, w.OwnerID, w.URL, w.Secret, events, w.Active, tenant.FromContext(ctx)).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("cannot insert webhook: %w", err)
+	}
+	return id, nil
+}
+
+func (s *Storage) GetWebhook(ctx context.Context, id int64) (storage.Webhook, error) {
+	w, err := scanWebhook(s.q.QueryRowContext(ctx, `
+		SELECT `+webhookColumns+` FROM webhooks WHERE tenant_id = $2 AND id = $1
+	`, id, tenant.FromContext(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return storage.Webhook{}, storage.ErrWebhookNotFound
+	}
+	if err != nil {
+		return storage.Webhook{}, fmt.Errorf("cannot select webhook: %w", err)
+	}
+	return w, nil
+}
+
+func (s *Storage) ListWebhooks(ctx context.Context, ownerID string) ([]storage.Webhook, error) {
+	rows, err := s.q.QueryContext(ctx, `
+		SELECT `+webhookColumns+`
+		FROM webhooks
+		WHERE tenant_id = $2 AND owner_id = $1
+		ORDER BY id
+	`, ownerID, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("cannot select webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	var webhooks []storage.Webhook
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("cannot scan webhook: %w", err)
+		}
+		webhooks = append(webhooks, w)
+	}
+	return webhooks, rows.Err()
+}
+
+// DeleteWebhook - доставки удаляются каскадом.
+func (s *Storage) DeleteWebhook(ctx context.Context, id int64) error {
+	res, err := s.q.ExecContext(ctx, `DELETE FROM webhooks WHERE tenant_id = $2 AND id = $1`, id, tenant.FromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("cannot delete webhook: %w", err)
+	}
+	return webhookAffected(res)
+}
+
+func (s *Storage) EnableWebhook(ctx context.Context, id int64) error {
+	res, err := s.q.ExecContext(ctx, `
+		UPDATE webhooks SET active = true, failures = 0, disabled_at = NULL
+		WHERE tenant_id = $2 AND id = $1
+	`, id, tenant.FromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("cannot enable webhook: %w", err)
+	}
+	return webhookAffected(res)
+}
+
+// RecordWebhookResult считает неудачи одним UPDATE, поэтому параллельные
+// обработчики не теряют друг у друга попытки.
+func (s *Storage) RecordWebhookResult(ctx context.Context, id int64, ok bool, disableAfter int) (bool, error) {
+	var active bool
+	err := s.q.QueryRowContext(ctx, `
+		UPDATE webhooks SET
+			failures = CASE WHEN $2 THEN 0 ELSE failures + 1 END,
+			active = active AND ($2 OR $3 <= 0 OR failures + 1 < $3),
+			disabled_at = CASE
+				WHEN active AND NOT $2 AND $3 > 0 AND failures + 1 >= $3 THEN now()
+				ELSE disabled_at
+			END
+		WHERE tenant_id = $4 AND id = $1
+		RETURNING active
+	`, id, ok, disableAfter, tenant.FromContext(ctx)).Scan(&active)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, storage.ErrWebhookNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("cannot update webhook: %w", err)
+	}
+	return active, nil
+}
+
+// EnqueueDeliveries добавляет доставки одним запросом, повторы ключа отсекает
+// уникальный индекс webhook_deliveries_key_idx.
+func (s *Storage) EnqueueDeliveries(ctx context.Context, ds []storage.Delivery) error {
+	if len(ds) == 0 {
+		return nil
+	}
+	now := time.Now()
+	q := sq.Insert("webhook_deliveries").
+		Columns("webhook_id", "event", "key", "payload", "state", "next_attempt", "created_at").
+		Suffix("ON CONFLICT (webhook_id, key) WHERE key IS NOT NULL DO NOTHING").
+		PlaceholderFormat(sq.Dollar)
+	for _, d := range ds {
+		var key sql.NullString
+		if d.Key != "" {
+			key = sql.NullString{String: d.Key, Valid: true}
+		}
+		state := d.State
+		if state == "" {
+			state = storage.DeliveryPending
+		}
+		createdAt := d.CreatedAt
+		if createdAt.IsZero() {
+			createdAt = now
+		}
+		next := d.NextAttempt
+		if next.IsZero() {
+			next = createdAt
+		}
+		q = q.Values(d.WebhookID, d.Event, key, d.Payload, state, next, createdAt)
+	}
+	query, args, err := q.ToSql()
+	if err != nil {
+		return fmt.Errorf("cannot build query: %w", err)
+	}
+	if _, err := s.q.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("cannot insert deliveries: %w", err)
+	}
+	return nil
+}
+
+// ClaimDeliveries сдвигает next_attempt забранных доставок на lease. SKIP LOCKED
+// даёт нескольким обработчикам разбирать очередь, не дожидаясь друг друга.
+// Очередь общая для всех тенантов, тенант возвращается в каждой доставке.
+func (s *Storage) ClaimDeliveries(ctx context.Context, now time.Time, lease time.Duration,
+	limit int,
+) ([]storage.Delivery, error) {
+	rows, err := s.q.QueryContext(ctx, `
+		UPDATE webhook_deliveries SET next_attempt = $2
+		WHERE id IN (
+			SELECT d.id
+			FROM webhook_deliveries d
+			JOIN webhooks w ON w.id = d.webhook_id AND w.active
+			WHERE d.state = $3 AND d.next_attempt <= $1
+			ORDER BY d.next_attempt, d.id
+			LIMIT $4
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		RETURNING `+deliveryColumns,
+		now, now.Add(lease), storage.DeliveryPending, limit)
+	if err != nil {
+		return nil, fmt.Errorf("cannot claim deliveries: %w", err)
+	}
+	defer rows.Close()
+	return collectDeliveries(rows)
+}
+
+func (s *Storage) UpdateDelivery(ctx context.Context, d storage.Delivery) error {
+	var deliveredAt sql.NullTime
+	if !d.DeliveredAt.IsZero() {
+		deliveredAt = sql.NullTime{Time: d.DeliveredAt, Valid: true}
+	}
+	res, err := s.q.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET state = $2, attempts = $3, next_attempt = $4, last_status = $5, last_error = $6, delivered_at = $7
+		WHERE id = $1 AND webhook_id IN (SELECT id FROM webhooks WHERE tenant_id = $8)
+	`, d.ID, d.State, d.Attempts, d.NextAttempt, d.LastStatus, d.LastError, deliveredAt, tenant.FromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("cannot update delivery: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+func (s *Storage) ListDeliveries(ctx context.Context, webhookID int64, limit int) ([]storage.Delivery, error) {
+	if _, err := s.GetWebhook(ctx, webhookID); err != nil {
+		return nil, err
+	}
+	rows, err := s.q.QueryContext(ctx, `
+		SELECT `+deliveryColumns+`
+		FROM webhook_deliveries
+		WHERE webhook_id = $1
+		ORDER BY id DESC
+		LIMIT $2
+	`, webhookID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("cannot select deliveries: %w", err)
+	}
+	defer rows.Close()
+	return collectDeliveries(rows)
+}
+
+// PruneDeliveries удаляет старые доставки всех тенантов; доставки удалённых
+// подписок удаляются каскадом вместе с ними.
+func (s *Storage) PruneDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	res, err := s.q.ExecContext(ctx, `
+		DELETE FROM webhook_deliveries WHERE created_at < $1 AND state <> $2
+	`, before, storage.DeliveryPending)
+	if err != nil {
+		return 0, fmt.Errorf("cannot prune deliveries: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+func collectDeliveries(rows *rows) ([]storage.Delivery, error) {
+	var ds []storage.Delivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("cannot scan delivery: %w", err)
+		}
+		ds = append(ds, d)
+	}
+	return ds, rows.Err()
+}
+
+func webhookAffected(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return storage.ErrWebhookNotFound
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package storage
+
+import "context"
+
+// TxStorage выполняет несколько изменений атомарно.
+type TxStorage interface {
+	// InTx вызывает fn с хранилищем tx, изменения через которое попадают в одну
+	// транзакцию: она фиксируется, если fn вернула nil, и откатывается иначе.
+	// Внутри fn работать можно только с tx и переданным ctx. Вложенный InTx
+	// выполняется в той же транзакции.
+	InTx(ctx context.Context, fn func(ctx context.Context, tx Storage) error) error
+}
@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// WebhookStorage - подписки на изменения событий и очередь их доставки.
+type WebhookStorage interface {
+	CreateWebhook(ctx context.Context, w Webhook) (int64, error)
+	GetWebhook(ctx context.Context, id int64) (Webhook, error)
+	// ListWebhooks возвращает подписки пользователя.
+	ListWebhooks(ctx context.Context, ownerID string) ([]Webhook, error)
+	// DeleteWebhook удаляет подписку вместе с историей доставок.
+	DeleteWebhook(ctx context.Context, id int64) error
+	// EnableWebhook включает подписку и сбрасывает счётчик неудач.
+	EnableWebhook(ctx context.Context, id int64) error
+	// RecordWebhookResult учитывает попытку доставки: успех сбрасывает счётчик
+	// неудач подряд, после disableAfter неудач подписка выключается. Возвращает,
+	// включена ли подписка после этого.
+	RecordWebhookResult(ctx context.Context, id int64, ok bool, disableAfter int) (bool, error)
+
+	// EnqueueDeliveries ставит доставки в очередь. Доставка с непустым Key, который
+	// уже есть у подписки, пропускается.
+	EnqueueDeliveries(ctx context.Context, ds []Delivery) error
+	// ClaimDeliveries забирает до limit доставок включённых подписок, которым пора
+	// отправляться, и откладывает их на lease: если обработчик упадёт, доставку
+	// заберут снова.
+	ClaimDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]Delivery, error)
+	// UpdateDelivery записывает результат попытки: состояние, число попыток,
+	// время следующей и ответ получателя.
+	UpdateDelivery(ctx context.Context, d Delivery) error
+	// ListDeliveries возвращает последние limit доставок подписки, новые первыми.
+	ListDeliveries(ctx context.Context, webhookID int64, limit int) ([]Delivery, error)
+	// PruneDeliveries удаляет доставки всех тенантов, созданные до before, кроме
+	// ещё ожидающих, и возвращает их число.
+	PruneDeliveries(ctx context.Context, before time.Time) (int64, error)
+}
+
+// WebhookEvent - тип уведомления, на который можно подписаться.
+type WebhookEvent string
+
+const (
+	WebhookEventCreated  WebhookEvent = "event.created"
+	WebhookEventUpdated  WebhookEvent = "event.updated"
+	WebhookEventDeleted  WebhookEvent = "event.deleted"
+	WebhookEventReminder WebhookEvent = "event.reminder"
+)
+
+func (e WebhookEvent) Valid() bool {
+	switch e {
+	case WebhookEventCreated, WebhookEventUpdated, WebhookEventDeleted, WebhookEventReminder:
+		return true
+	}
+	return false
+}
+
+// Webhook - подписка пользователя на изменения событий, в которых он участвует.
+type Webhook struct {
+	ID       int64
+	OwnerID  string
+	URL      string
+	Secret   string         // ключ HMAC подписи, клиенту показывается только при создании
+	Events   []WebhookEvent // пустой - все типы
+	Active   bool
+	Failures int // неудачных попыток подряд
+	// DisabledAt - когда подписку выключили из-за неудач, нулевое у включённых.
+	DisabledAt time.Time
+	CreatedAt  time.Time
+}
+
+// Wants - подписка включена и ждёт уведомления этого типа.
+func (w Webhook) Wants(e WebhookEvent) bool {
+	if !w.Active {
+		return false
+	}
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, want := range w.Events {
+		if want == e {
+			return true
+		}
+	}
+	return false
+}
+
+type DeliveryState string
+
+const (
+	DeliveryPending   DeliveryState = "pending"
+	DeliveryDelivered DeliveryState = "delivered"
+	DeliveryFailed    DeliveryState = "failed" // попытки кончились
+)
+
+// Delivery - одно уведомление подписчику со всеми попытками его отправить.
+type Delivery struct {
+	ID          int64
+	WebhookID   int64
+	Tenant      string // тенант вебхука, заполняет хранилище
+	Event       WebhookEvent
+	Key         string // для уведомлений, которые не должны повторяться, например напоминаний
+	Payload     []byte // тело запроса, JSON
+	State       DeliveryState
+	Attempts    int
+	NextAttempt time.Time
+	LastStatus  int    // HTTP статус последней попытки, 0 - ответа не было
+	LastError   string // пустая после успешной попытки
+	CreatedAt   time.Time
+	DeliveredAt time.Time
+}
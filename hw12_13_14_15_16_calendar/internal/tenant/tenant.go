@@ -0,0 +1,119 @@
+// Package tenant - чьи данные затрагивает запрос. Тенант определяется по
+// клиентскому сертификату (или заголовку X-Tenant-ID, если тенант один),
+// передаётся в контексте, и хранилища видят только данные тенанта из контекста.
+package tenant
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+const (
+	// Header - заголовок HTTP (и ключ метаданных gRPC в нижнем регистре) с ID тенанта.
+	Header = "X-Tenant-ID"
+	// Default - тенант запросов без заголовка и данных, созданных до разделения.
+	Default = "default"
+)
+
+var (
+	ErrInvalid  = errors.New("invalid tenant")
+	ErrUnknown  = errors.New("unknown tenant")
+	ErrMismatch = errors.New("tenant does not match client certificate")
+	// ErrUnauthenticated - тенант назван заголовком, но клиент не предъявил
+	// сертификат, который к нему допущен.
+	ErrUnauthenticated = errors.New("tenant requires a client certificate")
+)
+
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// Valid проверяет ID: строчные латинские буквы, цифры, '-' и '_', до 63 символов,
+// чтобы ID годился и для имени очереди.
+func Valid(id string) bool {
+	return idPattern.MatchString(id)
+}
+
+type tenantKey struct{}
+
+// With кладёт ID тенанта в контекст.
+func With(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// FromContext достаёт ID тенанта из контекста, без него - Default.
+func FromContext(ctx context.Context) string {
+	if id, _ := ctx.Value(tenantKey{}).(string); id != "" {
+		return id
+	}
+	return Default
+}
+
+// Set - тенанты, которых обслуживает сервис, и клиенты, которым они доступны.
+// Пустой набор пускает любой допустимый ID.
+type Set struct {
+	names      map[string]bool
+	principals map[string]string // CN клиентского сертификата без O -> тенант
+}
+
+// NewSet - набор тенантов names. principals сопоставляет клиентам, у сертификатов
+// которых нет организации (O), их тенантов по CN.
+func NewSet(names []string, principals map[string]string) Set {
+	s := Set{names: make(map[string]bool, len(names)), principals: principals}
+	for _, n := range names {
+		s.names[n] = true
+	}
+	return s
+}
+
+// Resolve определяет тенанта запроса. Тенант клиента с сертификатом - его
+// организация (O) или тенант из principals по CN, заголовок может только
+// повторить его. Без сертификата заголовку верим, только если тенант один:
+// иначе любой клиент мог бы назваться чужим тенантом, поэтому доступен только
+// Default. Без того и другого - Default.
+func (s Set) Resolve(header string, certs []*x509.Certificate) (string, error) {
+	if header != "" && !Valid(header) {
+		return "", fmt.Errorf("%w: %q", ErrInvalid, header)
+	}
+	id, authenticated := s.principal(certs)
+	switch {
+	case authenticated && header != "" && header != id:
+		return "", fmt.Errorf("%w: %q", ErrMismatch, header)
+	case !authenticated && header != "" && header != Default && len(s.names) != 1:
+		return "", fmt.Errorf("%w: %q", ErrUnauthenticated, header)
+	case !authenticated:
+		id = header
+	}
+	if id == "" {
+		id = Default
+	}
+	if !Valid(id) {
+		return "", fmt.Errorf("%w: %q", ErrInvalid, id)
+	}
+	if len(s.names) > 0 && !s.names[id] {
+		return "", fmt.Errorf("%w: %q", ErrUnknown, id)
+	}
+	return id, nil
+}
+
+// principal - тенант клиента по сертификату и признак, что клиента удалось опознать.
+func (s Set) principal(certs []*x509.Certificate) (string, bool) {
+	if len(certs) == 0 {
+		return "", false
+	}
+	if org := certs[0].Subject.Organization; len(org) > 0 {
+		return org[0], true
+	}
+	id, ok := s.principals[certs[0].Subject.CommonName]
+	return id, ok
+}
+
+// Queue - очередь тенанта: у Default - сама base, у остальных base.<tenant>,
+// так что однотенантная установка продолжает работать с прежней очередью.
+func Queue(base, id string) string {
+	if id == Default || id == "" {
+		return base
+	}
+	return base + "." + id
+}
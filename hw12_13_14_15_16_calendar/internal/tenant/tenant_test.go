@@ -0,0 +1,80 @@
+package tenant_test
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"mycalendar/internal/tenant"
+)
+
+func TestFromContext(t *testing.T) {
+	ctx := context.Background()
+	require.Equal(t, tenant.Default, tenant.FromContext(ctx))
+	require.Equal(t, "acme", tenant.FromContext(tenant.With(ctx, "acme")))
+}
+
+func TestSet_Resolve(t *testing.T) {
+	set := tenant.NewSet([]string{tenant.Default, "acme"}, map[string]string{"billing": "acme"})
+	acmeCert := []*x509.Certificate{{Subject: pkix.Name{CommonName: "alice", Organization: []string{"acme"}}}}
+	plainCert := []*x509.Certificate{{Subject: pkix.Name{CommonName: "alice"}}}
+	billingCert := []*x509.Certificate{{Subject: pkix.Name{CommonName: "billing"}}}
+
+	tests := []struct {
+		name   string
+		header string
+		certs  []*x509.Certificate
+		want   string
+		err    error
+	}{
+		{name: "no header", want: tenant.Default},
+		{name: "default header", header: tenant.Default, want: tenant.Default},
+		{name: "header without certificate", header: "acme", err: tenant.ErrUnauthenticated},
+		{name: "certificate", certs: acmeCert, want: "acme"},
+		{name: "certificate and same header", header: "acme", certs: acmeCert, want: "acme"},
+		{name: "principal", certs: billingCert, want: "acme"},
+		{name: "principal and same header", header: "acme", certs: billingCert, want: "acme"},
+		{name: "principal against header", header: tenant.Default, certs: billingCert, err: tenant.ErrMismatch},
+		{name: "certificate without organization", header: "acme", certs: plainCert, err: tenant.ErrUnauthenticated},
+		{name: "header against certificate", header: tenant.Default, certs: acmeCert, err: tenant.ErrMismatch},
+		{name: "unknown", header: "globex", err: tenant.ErrUnauthenticated},
+		{name: "unknown certificate", certs: []*x509.Certificate{{Subject: pkix.Name{Organization: []string{"globex"}}}},
+			err: tenant.ErrUnknown},
+		{name: "invalid", header: "Acme Corp", err: tenant.ErrInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := set.Resolve(tt.header, tt.certs)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	// единственному тенанту заголовок можно назвать и без сертификата
+	got, err := tenant.NewSet([]string{"acme"}, nil).Resolve("acme", nil)
+	require.NoError(t, err)
+	require.Equal(t, "acme", got)
+	_, err = tenant.NewSet([]string{"acme"}, nil).Resolve("Acme Corp", nil)
+	require.ErrorIs(t, err, tenant.ErrInvalid)
+	_, err = tenant.NewSet([]string{"acme"}, nil).Resolve("globex", nil)
+	require.ErrorIs(t, err, tenant.ErrUnknown)
+
+	// пустой набор пускает любой допустимый ID, но только с сертификатом
+	globexCert := []*x509.Certificate{{Subject: pkix.Name{Organization: []string{"globex"}}}}
+	got, err = tenant.Set{}.Resolve("", globexCert)
+	require.NoError(t, err)
+	require.Equal(t, "globex", got)
+	_, err = tenant.Set{}.Resolve("globex", nil)
+	require.ErrorIs(t, err, tenant.ErrUnauthenticated)
+}
+
+func TestQueue(t *testing.T) {
+	require.Equal(t, "notifications", tenant.Queue("notifications", tenant.Default))
+	require.Equal(t, "notifications.acme", tenant.Queue("notifications", "acme"))
+}
@@ -0,0 +1,295 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"mycalendar/internal/storage"
+	"mycalendar/internal/tenant"
+)
+
+// Options - параметры отправки. Нулевые поля заменяются значениями по умолчанию.
+type Options struct {
+	Interval    time.Duration // как часто проверять очередь
+	Timeout     time.Duration // на одну попытку
+	MaxAttempts int           // после стольких неудач доставка помечается failed
+	Backoff     time.Duration // пауза перед второй попыткой, дальше удваивается
+	MaxBackoff  time.Duration
+	// DisableAfter - после стольких неудачных попыток подряд подписка выключается, 0 - никогда.
+	DisableAfter int
+	Workers      int // параллельных запросов
+	Batch        int // доставок за одну выборку из очереди
+	// Retention - сколько хранить отправленные и неудавшиеся доставки. Должно
+	// быть больше самого раннего напоминания, иначе оно может прийти повторно.
+	Retention    time.Duration
+	AllowPrivate bool // отправлять и во внутреннюю сеть, см. Guard
+}
+
+// pruneEvery - как часто удалять старые доставки.
+const pruneEvery = time.Hour
+
+func (o Options) withDefaults() Options {
+	if o.Interval <= 0 {
+		o.Interval = time.Second
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = 10 * time.Second
+	}
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = 8
+	}
+	if o.Backoff <= 0 {
+		o.Backoff = 30 * time.Second
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = time.Hour
+	}
+	if o.Workers <= 0 {
+		o.Workers = 4
+	}
+	if o.Batch <= 0 {
+		o.Batch = 50
+	}
+	if o.Retention <= 0 {
+		o.Retention = 30 * 24 * time.Hour
+	}
+	return o
+}
+
+// Dispatcher разбирает очередь доставок. Несколько процессов с одним хранилищем
+// не мешают друг другу: ClaimDeliveries выдаёт доставку только одному из них.
+type Dispatcher struct {
+	storage storage.WebhookStorage
+	client  *http.Client
+	opts    Options
+	logger  Logger
+	now     func() time.Time
+
+	lastPrune time.Time
+}
+
+func NewDispatcher(s storage.WebhookStorage, opts Options, l Logger) *Dispatcher {
+	opts = opts.withDefaults()
+	return &Dispatcher{
+		storage: s,
+		client:  newClient(opts),
+		opts:    opts,
+		logger:  l,
+		now:     time.Now,
+	}
+}
+
+// newClient - клиент, который соединяется только с адресами, разрешёнными Guard,
+// и не ходит по перенаправлениям: ответ 3xx считается неудачей. Прокси из
+// окружения не используется, иначе проверялся бы адрес прокси, а не получателя.
+func newClient(opts Options) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   opts.Timeout,
+		KeepAlive: 30 * time.Second,
+		Control:   Guard{AllowPrivate: opts.AllowPrivate}.control,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   opts.Timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// Run отправляет доставки, пока не отменён ctx.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	t := time.NewTicker(d.opts.Interval)
+	defer t.Stop()
+	for {
+		// полная выборка - в очереди может быть ещё, не ждём следующего тика
+		d.prune(ctx)
+		for {
+			n, err := d.ProcessDue(ctx)
+			if err != nil {
+				d.logger.Error("cannot process webhook deliveries", "err", err)
+			}
+			if err != nil || n < d.opts.Batch || ctx.Err() != nil {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// prune раз в pruneEvery удаляет доставки старше Retention.
+func (d *Dispatcher) prune(ctx context.Context) {
+	now := d.now()
+	if now.Sub(d.lastPrune) < pruneEvery {
+		return
+	}
+	d.lastPrune = now
+	n, err := d.storage.PruneDeliveries(ctx, now.Add(-d.opts.Retention))
+	if err != nil {
+		d.logger.Error("cannot prune webhook deliveries", "err", err)
+		return
+	}
+	if n > 0 {
+		d.logger.Info("old webhook deliveries pruned", "count", n)
+	}
+}
+
+// ProcessDue отправляет одну выборку доставок, которым пора, и возвращает их число.
+func (d *Dispatcher) ProcessDue(ctx context.Context) (int, error) {
+	// аренды хватает на всю выборку, даже если все попытки упрутся в таймаут
+	lease := time.Duration(d.opts.Batch/d.opts.Workers+1)*d.opts.Timeout + time.Minute
+	ds, err := d.storage.ClaimDeliveries(ctx, d.now(), lease, d.opts.Batch)
+	if err != nil {
+		return 0, err
+	}
+
+	hooks := make(map[int64]*storage.Webhook, len(ds))
+	for _, dl := range ds {
+		if _, ok := hooks[dl.WebhookID]; ok {
+			continue
+		}
+		w, err := d.storage.GetWebhook(tenant.With(ctx, dl.Tenant), dl.WebhookID)
+		switch {
+		case errors.Is(err, storage.ErrWebhookNotFound):
+			hooks[dl.WebhookID] = nil // удалили после выборки
+		case err != nil:
+			return 0, err
+		default:
+			hooks[dl.WebhookID] = &w
+		}
+	}
+
+	jobs := make(chan storage.Delivery)
+	var wg sync.WaitGroup
+	for range min(d.opts.Workers, len(ds)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for dl := range jobs {
+				if w := hooks[dl.WebhookID]; w != nil {
+					d.deliver(tenant.With(ctx, dl.Tenant), *w, dl)
+				}
+			}
+		}()
+	}
+	for _, dl := range ds {
+		jobs <- dl
+	}
+	close(jobs)
+	wg.Wait()
+	return len(ds), nil
+}
+
+// deliver делает одну попытку и записывает её результат.
+func (d *Dispatcher) deliver(ctx context.Context, w storage.Webhook, dl storage.Delivery) {
+	status, err := d.send(ctx, w, dl)
+	if ctx.Err() != nil {
+		return // остановка сервиса, а не неудача получателя: доставку заберут после аренды
+	}
+
+	now := d.now()
+	dl.Attempts++
+	dl.LastStatus = status
+	if err == nil {
+		dl.State, dl.LastError, dl.DeliveredAt = storage.DeliveryDelivered, "", now
+	} else {
+		dl.LastError = publicError(err)
+		if dl.Attempts >= d.opts.MaxAttempts {
+			dl.State = storage.DeliveryFailed
+		} else {
+			dl.NextAttempt = now.Add(d.backoff(dl.Attempts))
+		}
+	}
+	if err := d.storage.UpdateDelivery(ctx, dl); err != nil {
+		d.logger.Error("cannot update webhook delivery", "delivery_id", dl.ID, "err", err)
+	}
+
+	active, rerr := d.storage.RecordWebhookResult(ctx, w.ID, err == nil, d.opts.DisableAfter)
+	if rerr != nil {
+		d.logger.Error("cannot update webhook", "webhook_id", w.ID, "err", rerr)
+		return
+	}
+	if err != nil {
+		d.logger.Info("webhook delivery failed", "webhook_id", w.ID, "delivery_id", dl.ID,
+			"attempt", dl.Attempts, "status", status, "err", err)
+		if !active && w.Active {
+			d.logger.Info("webhook disabled after repeated failures", "webhook_id", w.ID, "url", w.URL)
+		}
+	}
+}
+
+// send возвращает HTTP статус ответа, 0 - если ответа не было. Доставленным
+// считается только ответ 2xx.
+func (d *Dispatcher) send(ctx context.Context, w storage.Webhook, dl storage.Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(dl.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "mycalendar-webhook/1")
+	req.Header.Set(HeaderEvent, string(dl.Event))
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(dl.ID, 10))
+	now := d.now()
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(HeaderSignature, Sign(w.Secret, now, dl.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// тело не нужно, но дочитываем немного, чтобы соединение вернулось в пул
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, &statusError{code: resp.StatusCode}
+	}
+	return resp.StatusCode, nil
+}
+
+// publicError - описание неудачи для владельца подписки. Подробности ошибки
+// соединения (адреса, ответы резолвера) остаются только в журнале сервиса.
+func publicError(err error) string {
+	var (
+		status  *statusError
+		timeout interface{ Timeout() bool }
+	)
+	switch {
+	case errors.As(err, &status):
+		return status.Error()
+	case errors.Is(err, ErrBlockedAddress):
+		return ErrBlockedAddress.Error()
+	case errors.As(err, &timeout) && timeout.Timeout():
+		return "timeout"
+	default:
+		return "connection failed"
+	}
+}
+
+// statusError - получатель ответил не 2xx.
+type statusError struct{ code int }
+
+func (e *statusError) Error() string { return fmt.Sprintf("unexpected status %d", e.code) }
+
+// backoff - пауза после attempts неудачных попыток.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	b := d.opts.Backoff
+	for i := 1; i < attempts && b < d.opts.MaxBackoff; i++ {
+		b *= 2
+	}
+	return min(b, d.opts.MaxBackoff)
+}
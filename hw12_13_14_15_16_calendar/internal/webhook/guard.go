@@ -0,0 +1,78 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"syscall"
+)
+
+// ErrBlockedAddress - адрес подписки ведёт во внутреннюю сеть.
+var ErrBlockedAddress = errors.New("webhook address is not allowed")
+
+// sharedAddressSpace - 100.64.0.0/10, адреса провайдерского NAT. IsPrivate их не считает.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// Guard не даёт отправлять уведомления во внутреннюю сеть: на loopback, частные,
+// link-local адреса (в том числе 169.254.169.254 облачных метаданных) и прочие
+// неглобальные. Адрес проверяется при создании подписки, но имя могут позже
+// перенаправить на внутренний адрес, поэтому Dispatcher проверяет его ещё раз
+// при каждом соединении.
+type Guard struct {
+	AllowPrivate bool // разрешить любые адреса, для разработки и тестов
+	Resolver     *net.Resolver
+}
+
+// CheckURL разрешает имя хоста из rawURL и возвращает ErrBlockedAddress, если
+// хотя бы один из его адресов запрещён.
+func (g Guard) CheckURL(ctx context.Context, rawURL string) error {
+	if g.AllowPrivate {
+		return nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	host := u.Hostname()
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return g.check(addr)
+	}
+	resolver := g.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	addrs, err := resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("cannot resolve %s: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if err := g.check(addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// control - net.Dialer.Control: проверяет адрес, к которому уже разрешено имя,
+// прямо перед соединением.
+func (g Guard) control(_, address string, _ syscall.RawConn) error {
+	if g.AllowPrivate {
+		return nil
+	}
+	ap, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	return g.check(ap.Addr())
+}
+
+func (g Guard) check(addr netip.Addr) error {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() || sharedAddressSpace.Contains(addr) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, addr)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Заголовки запроса доставки.
+const (
+	HeaderSignature = "X-Calendar-Signature" // sha256=<hex HMAC-SHA256(secret, timestamp + "." + body)>
+	HeaderTimestamp = "X-Calendar-Timestamp" // unix время отправки
+	HeaderEvent     = "X-Calendar-Event"
+	HeaderDelivery  = "X-Calendar-Delivery" // ID доставки, одинаковый у всех попыток
+)
+
+var (
+	ErrBadSignature = errors.New("webhook signature mismatch")
+	ErrStale        = errors.New("webhook timestamp is too old")
+)
+
+// Sign подписывает тело вместе с временем отправки, чтобы перехваченный запрос
+// нельзя было повторить позже.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify проверяет подпись запроса на стороне получателя. Запросы старше maxAge
+// отклоняются, 0 - не проверять время.
+func Verify(secret string, h http.Header, body []byte, maxAge time.Duration, now time.Time) error {
+	sec, err := strconv.ParseInt(h.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		return ErrBadSignature
+	}
+	ts := time.Unix(sec, 0)
+	if maxAge > 0 && now.Sub(ts) > maxAge {
+		return ErrStale
+	}
+	got := h.Get(HeaderSignature)
+	if !strings.HasPrefix(got, "sha256=") || !hmac.Equal([]byte(got), []byte(Sign(secret, ts, body))) {
+		return ErrBadSignature
+	}
+	return nil
+}
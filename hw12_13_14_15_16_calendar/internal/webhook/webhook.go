@@ -0,0 +1,152 @@
+// Package webhook уведомляет внешние системы об изменениях событий: Service ставит
+// уведомления в очередь хранилища, Dispatcher отправляет их подписчикам
+// подписанными POST запросами, повторяя неудачные попытки.
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"mycalendar/internal/storage"
+	"mycalendar/internal/tenant"
+)
+
+type Logger interface {
+	Info(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// Payload - тело уведомления.
+type Payload struct {
+	Type       storage.WebhookEvent `json:"type"`
+	Tenant     string               `json:"tenant"`
+	OccurredAt time.Time            `json:"occurredAt"`
+	Event      Event                `json:"event"`
+	Previous   *Event               `json:"previous,omitempty"` // состояние до изменения, у event.updated
+}
+
+// Event - событие в уведомлении. Формат отделён от storage.Event, чтобы
+// получатели не зависели от внутренней модели.
+type Event struct {
+	ID           int64      `json:"id"`
+	UserID       string     `json:"userId"`
+	CalendarID   int64      `json:"calendarId,omitempty"`
+	Title        string     `json:"title"`
+	Description  string     `json:"description,omitempty"`
+	StartAt      time.Time  `json:"startAt"`
+	Duration     string     `json:"duration,omitempty"`
+	NoticeBefore int32      `json:"noticeBefore,omitempty"`
+	Attendees    []Attendee `json:"attendees,omitempty"`
+}
+
+type Attendee struct {
+	UserID string `json:"userId"`
+	Status string `json:"status"`
+}
+
+func toEvent(e storage.Event) Event {
+	r := Event{
+		ID:           e.EventID,
+		UserID:       e.UserID,
+		CalendarID:   e.CalendarID,
+		Title:        e.Title,
+		Description:  e.Description,
+		StartAt:      e.StartDateTime,
+		Duration:     e.Duration,
+		NoticeBefore: e.NoticeBefore,
+	}
+	for _, a := range e.Attendees {
+		r.Attendees = append(r.Attendees, Attendee{UserID: a.UserID, Status: string(a.Status)})
+	}
+	return r
+}
+
+// Service ставит уведомления в очередь подписчикам, которые участвуют в событии:
+// владельцу и приглашённым.
+type Service struct {
+	storage storage.WebhookStorage
+	now     func() time.Time
+}
+
+func New(s storage.WebhookStorage) *Service {
+	return &Service{storage: s, now: time.Now}
+}
+
+// Notify ставит в очередь уведомление об изменении события. previous - состояние
+// до изменения, nil при создании; при удалении e - удалённое событие.
+func (s *Service) Notify(ctx context.Context, t storage.WebhookEvent, e, previous *storage.Event) error {
+	if e == nil {
+		return nil
+	}
+	p := Payload{Type: t, OccurredAt: s.now(), Event: toEvent(*e)}
+	if previous != nil {
+		prev := toEvent(*previous)
+		p.Previous = &prev
+	}
+	// об изменении узнают и те, кого из события убрали
+	users := involved(e)
+	if previous != nil {
+		for _, u := range involved(previous) {
+			if !slices.Contains(users, u) {
+				users = append(users, u)
+			}
+		}
+	}
+	return s.enqueue(ctx, p, "", users)
+}
+
+// Remind ставит в очередь напоминание о событии. Планировщик находит событие
+// на каждом запуске, пока оно не начнётся, поэтому напоминание уходит один раз:
+// ключ включает время начала, и после переноса события придёт новое.
+func (s *Service) Remind(ctx context.Context, e storage.Event) error {
+	p := Payload{Type: storage.WebhookEventReminder, OccurredAt: s.now(), Event: toEvent(e)}
+	key := "reminder:" + strconv.FormatInt(e.EventID, 10) + ":" + strconv.FormatInt(e.StartDateTime.Unix(), 10)
+	return s.enqueue(ctx, p, key, e.Recipients())
+}
+
+func (s *Service) enqueue(ctx context.Context, p Payload, key string, users []string) error {
+	var (
+		ds   []storage.Delivery
+		body []byte
+	)
+	p.Tenant = tenant.FromContext(ctx)
+	for _, userID := range users {
+		hooks, err := s.storage.ListWebhooks(ctx, userID)
+		if err != nil {
+			return err
+		}
+		for _, w := range hooks {
+			if !w.Wants(p.Type) {
+				continue
+			}
+			if body == nil {
+				if body, err = json.Marshal(p); err != nil {
+					return fmt.Errorf("cannot marshal payload: %w", err)
+				}
+			}
+			ds = append(ds, storage.Delivery{
+				WebhookID: w.ID,
+				Event:     p.Type,
+				Key:       key,
+				Payload:   body,
+				CreatedAt: p.OccurredAt,
+			})
+		}
+	}
+	return s.storage.EnqueueDeliveries(ctx, ds)
+}
+
+// involved - владелец события и все приглашённые, в том числе отказавшиеся.
+func involved(e *storage.Event) []string {
+	users := []string{e.UserID}
+	for _, a := range e.Attendees {
+		if !slices.Contains(users, a.UserID) {
+			users = append(users, a.UserID)
+		}
+	}
+	return users
+}
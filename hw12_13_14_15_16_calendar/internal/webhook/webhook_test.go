@@ -0,0 +1,347 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"mycalendar/internal/storage"
+	memorystorage "mycalendar/internal/storage/memory"
+	"mycalendar/internal/tenant"
+)
+
+// receiver - получатель уведомлений, отвечающий статусами из statuses по очереди,
+// а когда они кончатся - 200.
+type receiver struct {
+	t        *testing.T
+	secret   string
+	mu       sync.Mutex
+	statuses []int
+	got      []Payload
+	headers  []http.Header
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	require.NoError(rc.t, err)
+	require.NoError(rc.t, Verify(rc.secret, r.Header, body, time.Hour, time.Now()))
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	var p Payload
+	require.NoError(rc.t, json.Unmarshal(body, &p))
+	rc.got = append(rc.got, p)
+	rc.headers = append(rc.headers, r.Header)
+	status := http.StatusOK
+	if len(rc.statuses) > 0 {
+		status, rc.statuses = rc.statuses[0], rc.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func setup(t *testing.T, statuses ...int) (*memorystorage.Storage, *receiver, storage.Webhook) {
+	t.Helper()
+	store := memorystorage.New()
+	rc := &receiver{t: t, secret: "s3cret", statuses: statuses}
+	srv := httptest.NewServer(rc)
+	t.Cleanup(srv.Close)
+
+	w := storage.Webhook{OwnerID: "alice", URL: srv.URL, Secret: rc.secret, Active: true}
+	id, err := store.CreateWebhook(context.Background(), w)
+	require.NoError(t, err)
+	w.ID = id
+	return store, rc, w
+}
+
+// newDispatcher - рассыльщик с часами clock; получатели в тестах слушают на
+// loopback, поэтому внутренние адреса разрешены.
+func newDispatcher(store storage.WebhookStorage, opts Options, clock *time.Time) *Dispatcher {
+	opts.AllowPrivate = true
+	d := NewDispatcher(store, opts, slog.New(slog.DiscardHandler))
+	d.now = func() time.Time { return *clock }
+	return d
+}
+
+var event = storage.Event{
+	EventID:       7,
+	UserID:        "alice",
+	Title:         "standup",
+	StartDateTime: time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC),
+	Duration:      "15m",
+	Attendees:     []storage.Attendee{{UserID: "bob", Status: storage.RSVPAccepted}},
+}
+
+func TestSignVerify(t *testing.T) {
+	now := time.Now()
+	body := []byte(`{"type":"event.created"}`)
+	h := http.Header{}
+	h.Set(HeaderTimestamp, "1")
+	h.Set(HeaderSignature, Sign("k", time.Unix(1, 0), body))
+	require.NoError(t, Verify("k", h, body, 0, now))
+	require.ErrorIs(t, Verify("other", h, body, 0, now), ErrBadSignature)
+	require.ErrorIs(t, Verify("k", h, []byte(`{}`), 0, now), ErrBadSignature)
+	require.ErrorIs(t, Verify("k", h, body, time.Minute, now), ErrStale)
+}
+
+func TestDispatcher_Delivers(t *testing.T) {
+	ctx := context.Background()
+	store, rc, w := setup(t)
+	updated := event
+	updated.Title = "daily standup"
+	require.NoError(t, New(store).Notify(ctx, storage.WebhookEventUpdated, &updated, &event))
+	clock := time.Now()
+	d := newDispatcher(store, Options{}, &clock)
+
+	n, err := d.ProcessDue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Len(t, rc.got, 1)
+	require.Equal(t, storage.WebhookEventUpdated, rc.got[0].Type)
+	require.Equal(t, "daily standup", rc.got[0].Event.Title)
+	require.Equal(t, "standup", rc.got[0].Previous.Title)
+	require.Equal(t, "event.updated", rc.headers[0].Get(HeaderEvent))
+	require.Equal(t, tenant.Default, rc.got[0].Tenant)
+
+	ds, err := store.ListDeliveries(ctx, w.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, ds, 1)
+	require.Equal(t, storage.DeliveryDelivered, ds[0].State)
+	require.Equal(t, 1, ds[0].Attempts)
+	require.Equal(t, http.StatusOK, ds[0].LastStatus)
+
+	n, err = d.ProcessDue(ctx)
+	require.NoError(t, err)
+	require.Zero(t, n)
+}
+
+func TestDispatcher_Tenants(t *testing.T) {
+	store, rc, _ := setup(t)
+	acme := tenant.With(context.Background(), "acme")
+	w := storage.Webhook{OwnerID: "alice", URL: "http://127.0.0.1:1", Secret: rc.secret, Active: true}
+	id, err := store.CreateWebhook(acme, w)
+	require.NoError(t, err)
+
+	// у alice из acme своя подписка, подписка тенанта по умолчанию ничего не получает
+	require.NoError(t, New(store).Notify(acme, storage.WebhookEventCreated, &event, nil))
+	ds, err := store.ListDeliveries(acme, id, 10)
+	require.NoError(t, err)
+	require.Len(t, ds, 1)
+	require.Equal(t, "acme", ds[0].Tenant)
+
+	clock := time.Now()
+	d := newDispatcher(store, Options{}, &clock)
+	n, err := d.ProcessDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Empty(t, rc.got)
+
+	ds, err = store.ListDeliveries(acme, id, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, ds[0].Attempts) // результат записан в тенант вебхука
+	require.Equal(t, "connection failed", ds[0].LastError, "подробности соединения владельцу не показываются")
+}
+
+func TestDispatcher_RetriesWithBackoff(t *testing.T) {
+	ctx := context.Background()
+	store, rc, w := setup(t, http.StatusInternalServerError, http.StatusBadGateway)
+	require.NoError(t, New(store).Notify(ctx, storage.WebhookEventCreated, &event, nil))
+	clock := time.Now()
+	d := newDispatcher(store, Options{Backoff: time.Minute}, &clock)
+
+	_, err := d.ProcessDue(ctx)
+	require.NoError(t, err)
+	ds, err := store.ListDeliveries(ctx, w.ID, 10)
+	require.NoError(t, err)
+	require.Equal(t, storage.DeliveryPending, ds[0].State)
+	require.Equal(t, http.StatusInternalServerError, ds[0].LastStatus)
+	require.Equal(t, "unexpected status 500", ds[0].LastError)
+	require.Equal(t, clock.Add(time.Minute), ds[0].NextAttempt)
+
+	// до следующей попытки рано
+	n, err := d.ProcessDue(ctx)
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	clock = clock.Add(time.Minute)
+	_, err = d.ProcessDue(ctx)
+	require.NoError(t, err)
+	ds, err = store.ListDeliveries(ctx, w.ID, 10)
+	require.NoError(t, err)
+	require.Equal(t, clock.Add(2*time.Minute), ds[0].NextAttempt, "пауза удваивается")
+
+	clock = clock.Add(2 * time.Minute)
+	_, err = d.ProcessDue(ctx)
+	require.NoError(t, err)
+	ds, err = store.ListDeliveries(ctx, w.ID, 10)
+	require.NoError(t, err)
+	require.Equal(t, storage.DeliveryDelivered, ds[0].State)
+	require.Equal(t, 3, ds[0].Attempts)
+	require.Empty(t, ds[0].LastError)
+	require.Len(t, rc.got, 3)
+
+	w, err = store.GetWebhook(ctx, w.ID)
+	require.NoError(t, err)
+	require.Zero(t, w.Failures)
+}
+
+func TestDispatcher_DisablesAfterFailures(t *testing.T) {
+	ctx := context.Background()
+	statuses := make([]int, 10)
+	for i := range statuses {
+		statuses[i] = http.StatusServiceUnavailable
+	}
+	store, rc, w := setup(t, statuses...)
+	svc := New(store)
+	require.NoError(t, svc.Notify(ctx, storage.WebhookEventCreated, &event, nil))
+	require.NoError(t, svc.Notify(ctx, storage.WebhookEventDeleted, &event, nil))
+	clock := time.Now()
+	// по одной доставке за выборку, чтобы порядок попыток был известен
+	opts := Options{Backoff: time.Second, MaxAttempts: 2, DisableAfter: 3, Batch: 1}
+	d := newDispatcher(store, opts, &clock)
+
+	for range 5 {
+		_, err := d.ProcessDue(ctx)
+		require.NoError(t, err)
+		clock = clock.Add(time.Hour)
+	}
+	require.Len(t, rc.got, 3)
+
+	w, err := store.GetWebhook(ctx, w.ID)
+	require.NoError(t, err)
+	require.False(t, w.Active)
+	require.False(t, w.DisabledAt.IsZero())
+
+	ds, err := store.ListDeliveries(ctx, w.ID, 10)
+	require.NoError(t, err)
+	states := map[storage.DeliveryState]int{}
+	for _, dl := range ds {
+		states[dl.State]++
+	}
+	require.Equal(t, map[storage.DeliveryState]int{storage.DeliveryFailed: 1, storage.DeliveryPending: 1}, states)
+
+	// после включения оставшаяся доставка уходит
+	rc.statuses = nil
+	require.NoError(t, store.EnableWebhook(ctx, w.ID))
+	n, err := d.ProcessDue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Len(t, rc.got, 4)
+}
+
+func TestService_FiltersAndDeduplicates(t *testing.T) {
+	ctx := context.Background()
+	store := memorystorage.New()
+	svc := New(store)
+	onlyReminders, err := store.CreateWebhook(ctx, storage.Webhook{
+		OwnerID: "bob", URL: "http://bob", Active: true, Events: []storage.WebhookEvent{storage.WebhookEventReminder},
+	})
+	require.NoError(t, err)
+	all, err := store.CreateWebhook(ctx, storage.Webhook{OwnerID: "alice", URL: "http://alice", Active: true})
+	require.NoError(t, err)
+	_, err = store.CreateWebhook(ctx, storage.Webhook{OwnerID: "carol", URL: "http://carol", Active: true})
+	require.NoError(t, err)
+
+	require.NoError(t, svc.Notify(ctx, storage.WebhookEventCreated, &event, nil))
+	require.NoError(t, svc.Remind(ctx, event))
+	require.NoError(t, svc.Remind(ctx, event)) // следующий запуск планировщика
+
+	ds, err := store.ListDeliveries(ctx, all, 10)
+	require.NoError(t, err)
+	require.Len(t, ds, 2)
+	ds, err = store.ListDeliveries(ctx, onlyReminders, 10)
+	require.NoError(t, err)
+	require.Len(t, ds, 1)
+	require.Equal(t, storage.WebhookEventReminder, ds[0].Event)
+
+	moved := event
+	moved.StartDateTime = moved.StartDateTime.Add(time.Hour)
+	require.NoError(t, svc.Remind(ctx, moved))
+	ds, err = store.ListDeliveries(ctx, onlyReminders, 10)
+	require.NoError(t, err)
+	require.Len(t, ds, 2)
+}
+
+func TestDispatcher_BlocksInternalAddresses(t *testing.T) {
+	ctx := context.Background()
+	store, rc, w := setup(t)
+	require.NoError(t, New(store).Notify(ctx, storage.WebhookEventCreated, &event, nil))
+
+	// адрес проверяется при соединении, даже если подписку создали в обход CheckURL
+	d := NewDispatcher(store, Options{}, slog.New(slog.DiscardHandler))
+	_, err := d.ProcessDue(ctx)
+	require.NoError(t, err)
+	require.Empty(t, rc.got)
+
+	ds, err := store.ListDeliveries(ctx, w.ID, 10)
+	require.NoError(t, err)
+	require.Zero(t, ds[0].LastStatus)
+	require.Equal(t, ErrBlockedAddress.Error(), ds[0].LastError)
+}
+
+func TestDispatcher_DoesNotFollowRedirects(t *testing.T) {
+	ctx := context.Background()
+	var internalHits int
+	internal := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { internalHits++ }))
+	t.Cleanup(internal.Close)
+	redirect := httptest.NewServer(http.RedirectHandler(internal.URL, http.StatusTemporaryRedirect))
+	t.Cleanup(redirect.Close)
+
+	store := memorystorage.New()
+	id, err := store.CreateWebhook(ctx, storage.Webhook{OwnerID: "alice", URL: redirect.URL, Secret: "s", Active: true})
+	require.NoError(t, err)
+	require.NoError(t, New(store).Notify(ctx, storage.WebhookEventCreated, &event, nil))
+	clock := time.Now()
+	_, err = newDispatcher(store, Options{}, &clock).ProcessDue(ctx)
+	require.NoError(t, err)
+
+	require.Zero(t, internalHits)
+	ds, err := store.ListDeliveries(ctx, id, 10)
+	require.NoError(t, err)
+	require.Equal(t, storage.DeliveryPending, ds[0].State)
+	require.Equal(t, http.StatusTemporaryRedirect, ds[0].LastStatus)
+}
+
+func TestDispatcher_PrunesOldDeliveries(t *testing.T) {
+	ctx := context.Background()
+	store, _, w := setup(t, http.StatusInternalServerError)
+	svc := New(store)
+	clock := time.Now()
+	svc.now = func() time.Time { return clock }
+	require.NoError(t, svc.Notify(ctx, storage.WebhookEventCreated, &event, nil)) // не доставится с первого раза
+	require.NoError(t, svc.Notify(ctx, storage.WebhookEventDeleted, &event, nil))
+	d := newDispatcher(store, Options{Retention: 24 * time.Hour, Backoff: 72 * time.Hour}, &clock)
+	_, err := d.ProcessDue(ctx)
+	require.NoError(t, err)
+
+	clock = clock.Add(48 * time.Hour)
+	d.prune(ctx)
+	ds, err := store.ListDeliveries(ctx, w.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, ds, 1, "ожидающая доставка остаётся")
+	require.Equal(t, storage.DeliveryPending, ds[0].State)
+}
+
+func TestGuard(t *testing.T) {
+	ctx := context.Background()
+	var g Guard
+	for _, u := range []string{
+		"http://127.0.0.1/", "http://localhost:8080/", "http://10.0.0.5/", "http://172.16.1.1/",
+		"http://192.168.0.1/", "http://169.254.169.254/latest/meta-data/", "http://[::1]/",
+		"http://[fe80::1]/", "http://[fd00::1]/", "http://[::ffff:127.0.0.1]/", "http://0.0.0.0/", "http://100.64.0.1/",
+	} {
+		require.ErrorIs(t, g.CheckURL(ctx, u), ErrBlockedAddress, u)
+	}
+	require.NoError(t, g.CheckURL(ctx, "https://203.0.113.7/hook"))
+	require.NoError(t, g.CheckURL(ctx, "https://[2001:db8::1]/hook"))
+	require.NoError(t, Guard{AllowPrivate: true}.CheckURL(ctx, "http://127.0.0.1/"))
+
+	require.ErrorIs(t, g.control("tcp", "169.254.169.254:80", nil), ErrBlockedAddress)
+	require.NoError(t, g.control("tcp", "203.0.113.7:443", nil))
+}
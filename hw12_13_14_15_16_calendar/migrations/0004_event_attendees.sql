@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE event_attendees (
+    event_id   int not null references events(id) on delete cascade,
+    user_id    text not null,
+    status     text not null default 'needs-action',
+    invited_at timestamptz not null default now(),
+    primary key (event_id, user_id)
+);
+CREATE INDEX event_attendees_user_id_idx ON event_attendees (user_id);
+
+-- +goose Down
+drop table event_attendees;
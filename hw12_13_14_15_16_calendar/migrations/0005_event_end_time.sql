@@ -0,0 +1,14 @@
+-- +goose Up
+ALTER TABLE events ADD COLUMN end_date_time timestamptz;
+-- длительности вида 1h, 30m, 1d, 1h30m переводим в interval ('1 h 30 m'),
+-- остальные считаем нулевыми, как storage.Event.End
+UPDATE events SET end_date_time = CASE
+    WHEN duration ~ '^([0-9]+[dhms])+$'
+        THEN start_date_time + regexp_replace(duration, '([0-9]+)([dhms])', '\1 \2 ', 'g')::interval
+    ELSE start_date_time
+END;
+CREATE INDEX events_time_range_idx ON events (start_date_time, end_date_time);
+
+-- +goose Down
+DROP INDEX events_time_range_idx;
+ALTER TABLE events DROP COLUMN end_date_time;
@@ -0,0 +1,36 @@
+-- +goose Up
+CREATE TABLE calendars (
+    id         serial primary key,
+    owner_id   text not null,
+    name       text not null,
+    color      text not null default '',
+    time_zone  text not null default 'UTC',
+    is_default boolean not null default false,
+    created_at timestamptz not null default now()
+);
+CREATE INDEX calendars_owner_id_idx ON calendars (owner_id);
+-- у пользователя один календарь по умолчанию
+CREATE UNIQUE INDEX calendars_default_idx ON calendars (owner_id) WHERE is_default;
+
+CREATE TABLE calendar_shares (
+    calendar_id int not null references calendars(id) on delete cascade,
+    user_id     text not null,
+    permission  text not null,
+    primary key (calendar_id, user_id)
+);
+CREATE INDEX calendar_shares_user_id_idx ON calendar_shares (user_id);
+
+-- существующие события переносим в календари владельцев по умолчанию
+INSERT INTO calendars (owner_id, name, is_default)
+SELECT DISTINCT user_id, 'Default', true FROM events WHERE user_id IS NOT NULL;
+
+ALTER TABLE events ADD COLUMN calendar_id int references calendars(id) on delete cascade;
+UPDATE events SET calendar_id = c.id
+FROM calendars c
+WHERE c.owner_id = events.user_id AND c.is_default;
+CREATE INDEX events_calendar_id_idx ON events (calendar_id);
+
+-- +goose Down
+ALTER TABLE events DROP COLUMN calendar_id;
+DROP TABLE calendar_shares;
+DROP TABLE calendars;
@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE events ADD COLUMN deleted_at timestamptz;
+-- события в корзине не занимают время, уникальность - только среди действующих
+ALTER TABLE events DROP CONSTRAINT unique_user_start_time;
+CREATE UNIQUE INDEX events_user_start_active_idx ON events (user_id, start_date_time) WHERE deleted_at IS NULL;
+CREATE INDEX events_deleted_at_idx ON events (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- +goose Down
+DELETE FROM events WHERE deleted_at IS NOT NULL;
+DROP INDEX events_deleted_at_idx;
+DROP INDEX events_user_start_active_idx;
+ALTER TABLE events ADD CONSTRAINT unique_user_start_time UNIQUE (user_id, start_date_time);
+ALTER TABLE events DROP COLUMN deleted_at;
@@ -0,0 +1,19 @@
+-- +goose Up
+-- без внешнего ключа на events: история нужна и после очистки корзины
+CREATE TABLE audit_log (
+    id         bigserial primary key,
+    event_id   bigint not null,
+    actor      text not null default '',
+    action     text not null,
+    before     jsonb,
+    after      jsonb,
+    request_id text not null default '',
+    at         timestamptz not null default now()
+);
+CREATE INDEX audit_log_event_id_idx ON audit_log (event_id, id);
+-- журнал только дописывается
+CREATE RULE audit_log_no_update AS ON UPDATE TO audit_log DO INSTEAD NOTHING;
+CREATE RULE audit_log_no_delete AS ON DELETE TO audit_log DO INSTEAD NOTHING;
+
+-- +goose Down
+DROP TABLE audit_log;
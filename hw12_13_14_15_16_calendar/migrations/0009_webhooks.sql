@@ -0,0 +1,37 @@
+-- +goose Up
+CREATE TABLE webhooks (
+    id          bigserial primary key,
+    owner_id    text not null,
+    url         text not null,
+    secret      text not null,
+    events      text[] not null default '{}', -- пустой - все типы
+    active      boolean not null default true,
+    failures    int not null default 0,       -- неудачных попыток подряд
+    disabled_at timestamptz,
+    created_at  timestamptz not null default now()
+);
+CREATE INDEX webhooks_owner_id_idx ON webhooks (owner_id);
+
+CREATE TABLE webhook_deliveries (
+    id           bigserial primary key,
+    webhook_id   bigint not null references webhooks(id) on delete cascade,
+    event        text not null,
+    key          text,
+    payload      jsonb not null,
+    state        text not null default 'pending',
+    attempts     int not null default 0,
+    next_attempt timestamptz not null default now(),
+    last_status  int not null default 0,
+    last_error   text not null default '',
+    created_at   timestamptz not null default now(),
+    delivered_at timestamptz
+);
+-- очередь: ожидающие доставки по времени следующей попытки
+CREATE INDEX webhook_deliveries_pending_idx ON webhook_deliveries (next_attempt) WHERE state = 'pending';
+CREATE INDEX webhook_deliveries_webhook_id_idx ON webhook_deliveries (webhook_id, id);
+-- напоминание об одном событии доставляется один раз
+CREATE UNIQUE INDEX webhook_deliveries_key_idx ON webhook_deliveries (webhook_id, key) WHERE key IS NOT NULL;
+
+-- +goose Down
+DROP TABLE webhook_deliveries;
+DROP TABLE webhooks;
@@ -0,0 +1,36 @@
+-- +goose Up
+-- существующие данные принадлежат тенанту по умолчанию; участники, права
+-- и доставки наследуют тенанта от события, календаря и вебхука
+ALTER TABLE events ADD COLUMN tenant_id text not null default 'default';
+ALTER TABLE calendars ADD COLUMN tenant_id text not null default 'default';
+ALTER TABLE audit_log ADD COLUMN tenant_id text not null default 'default';
+ALTER TABLE webhooks ADD COLUMN tenant_id text not null default 'default';
+
+-- tenant_id первым в индексах: каждый запрос ограничен тенантом
+DROP INDEX events_user_start_active_idx;
+CREATE UNIQUE INDEX events_user_start_active_idx ON events (tenant_id, user_id, start_date_time) WHERE deleted_at IS NULL;
+DROP INDEX events_time_range_idx;
+CREATE INDEX events_time_range_idx ON events (tenant_id, start_date_time, end_date_time);
+DROP INDEX calendars_default_idx;
+CREATE UNIQUE INDEX calendars_default_idx ON calendars (tenant_id, owner_id) WHERE is_default;
+DROP INDEX calendars_owner_id_idx;
+CREATE INDEX calendars_owner_id_idx ON calendars (tenant_id, owner_id);
+DROP INDEX webhooks_owner_id_idx;
+CREATE INDEX webhooks_owner_id_idx ON webhooks (tenant_id, owner_id);
+
+-- +goose Down
+DROP INDEX webhooks_owner_id_idx;
+CREATE INDEX webhooks_owner_id_idx ON webhooks (owner_id);
+DROP INDEX calendars_owner_id_idx;
+CREATE INDEX calendars_owner_id_idx ON calendars (owner_id);
+DROP INDEX calendars_default_idx;
+CREATE UNIQUE INDEX calendars_default_idx ON calendars (owner_id) WHERE is_default;
+DROP INDEX events_time_range_idx;
+CREATE INDEX events_time_range_idx ON events (start_date_time, end_date_time);
+DROP INDEX events_user_start_active_idx;
+CREATE UNIQUE INDEX events_user_start_active_idx ON events (user_id, start_date_time) WHERE deleted_at IS NULL;
+
+ALTER TABLE webhooks DROP COLUMN tenant_id;
+ALTER TABLE audit_log DROP COLUMN tenant_id;
+ALTER TABLE calendars DROP COLUMN tenant_id;
+ALTER TABLE events DROP COLUMN tenant_id;
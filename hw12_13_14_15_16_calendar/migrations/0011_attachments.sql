@@ -0,0 +1,18 @@
+-- +goose Up
+-- вложения наследуют тенанта от события; содержимое файлов хранится вне базы,
+-- у ссылок blob_key пустой
+CREATE TABLE event_attachments (
+    id           bigserial primary key,
+    event_id     int not null references events(id) on delete cascade,
+    name         text not null,
+    content_type text not null default '',
+    size         bigint not null default 0,
+    blob_key     text not null default '',
+    url          text not null default '',
+    created_by   text not null default '',
+    created_at   timestamptz not null default now()
+);
+CREATE INDEX event_attachments_event_id_idx ON event_attachments (event_id, id);
+
+-- +goose Down
+DROP TABLE event_attachments;
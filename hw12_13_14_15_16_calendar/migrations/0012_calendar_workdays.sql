@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE calendars ADD COLUMN holiday_country text not null default '';
+ALTER TABLE calendars ADD COLUMN defer_reminders boolean not null default false;
+
+-- +goose Down
+ALTER TABLE calendars DROP COLUMN defer_reminders;
+ALTER TABLE calendars DROP COLUMN holiday_country;
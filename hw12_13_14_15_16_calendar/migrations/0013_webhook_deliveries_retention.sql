@@ -0,0 +1,6 @@
+-- +goose Up
+-- удаление старых доставок, см. PruneDeliveries
+CREATE INDEX webhook_deliveries_created_at_idx ON webhook_deliveries (created_at) WHERE state <> 'pending';
+
+-- +goose Down
+DROP INDEX webhook_deliveries_created_at_idx;